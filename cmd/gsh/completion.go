@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bashCompletionScript completes gsh's own flags and subcommands in bash.
+// It can be sourced directly or dropped into a bash-completion directory.
+const bashCompletionScript = `# bash completion for gsh
+_gsh() {
+    local cur prev cmd i
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    COMPREPLY=()
+
+    case "$prev" in
+        -c|--eval)
+            return 0
+            ;;
+        --repl-config|--command-file|--log-file)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return 0
+            ;;
+        --check)
+            COMPREPLY=($(compgen -f -X '!*.gsh' -- "$cur") $(compgen -d -- "$cur"))
+            return 0
+            ;;
+        --show-completion|--install-completion)
+            COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
+            return 0
+            ;;
+    esac
+
+    # Find the subcommand, skipping flags and the values they take
+    cmd=""
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            -c|--eval|--repl-config|--command-file|--log-file|--check)
+                ((i++))
+                ;;
+            -*)
+                ;;
+            *)
+                cmd="${COMP_WORDS[i]}"
+                break
+                ;;
+        esac
+    done
+
+    case "$cmd" in
+        run)
+            if ((COMP_CWORD - i == 1)); then
+                COMPREPLY=($(compgen -f -X '!*.gsh' -- "$cur") $(compgen -f -X '!*.sh' -- "$cur") $(compgen -d -- "$cur"))
+            else
+                COMPREPLY=($(compgen -f -- "$cur"))
+            fi
+            ;;
+        telemetry)
+            ((COMP_CWORD - i == 1)) && COMPREPLY=($(compgen -W "status on off" -- "$cur"))
+            ;;
+        keyring)
+            ((COMP_CWORD - i == 1)) && COMPREPLY=($(compgen -W "set get" -- "$cur"))
+            ;;
+        trust)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            ;;
+        "")
+            if [[ "$cur" == -* ]]; then
+                COMPREPLY=($(compgen -W "{{FLAGS}}" -- "$cur"))
+            else
+                COMPREPLY=($(compgen -W "{{COMMANDS}}" -- "$cur"))
+            fi
+            ;;
+    esac
+}
+complete -o filenames -F _gsh gsh
+`
+
+// zshCompletionScript completes gsh's own flags and subcommands in zsh. It works both
+// as an autoloaded _gsh file on $fpath and when sourced after compinit.
+const zshCompletionScript = `#compdef gsh
+
+_gsh() {
+    local curcontext="$curcontext" state line
+    typeset -A opt_args
+
+    _arguments -C \
+        '-c[execute a command string and exit]:command string:' \
+        '--command-file[execute commands from a file and exit]:file:_files' \
+        '--keep-going[with --command-file, continue after a failing command]' \
+        '(-h --help)'{-h,--help}'[display help information]' \
+        '(-v --version)'{-v,--version}'[display version]' \
+        '--json[with --version, print build details as JSON]' \
+        '(-l --login)'{-l,--login}'[run as a login shell]' \
+        '--repl-config[use a custom REPL config]:file:_files' \
+        '--no-update-check[skip the automatic update check on startup]' \
+        '--acp[run as an Agent Client Protocol agent over stdio]' \
+        '--stdin-prompt[send all of stdin to the default agent and print its reply]' \
+        '--clear-cache[delete cached model responses and exit]' \
+        '--eval[evaluate a gsh expression and print the result]:expression:' \
+        '--check[validate a .gsh script without running it]:script:_files -g "*.gsh"' \
+        '--log-file[write logs to a file]:file:_files' \
+        '--show-completion[print the completion script for a shell]:shell:(bash zsh)' \
+        '--install-completion[install the completion script for a shell]:shell:(bash zsh)' \
+        '1: :->command' \
+        '*:: :->args'
+
+    case $state in
+        command)
+            _values 'command' \
+                'run[execute a script file]' \
+                'telemetry[manage anonymous usage telemetry]' \
+                'trust[allow a directory'"'"'s .gsh/config.gsh to run]' \
+                'keyring[manage secrets in the OS keyring]'
+            ;;
+        args)
+            case $line[1] in
+                run)
+                    _arguments '1:script:_files -g "*.(gsh|sh)"' '*::argument:_files'
+                    ;;
+                telemetry)
+                    _arguments '1:action:(status on off)'
+                    ;;
+                keyring)
+                    _arguments '1:action:(set get)'
+                    ;;
+                trust)
+                    _files -/
+                    ;;
+            esac
+            ;;
+    esac
+}
+
+if [ "$funcstack[1]" = "_gsh" ]; then
+    _gsh "$@"
+else
+    compdef _gsh gsh
+fi
+`
+
+// completionFlags are the flags offered when completing "gsh -"
+var completionFlags = []string{
+	"-c", "--command-file", "--keep-going", "-h", "--help", "-v", "--version", "--json",
+	"-l", "--login", "--repl-config", "--no-update-check", "--acp", "--stdin-prompt", "--clear-cache",
+	"--eval", "--check", "--log-file", "--show-completion", "--install-completion",
+}
+
+// completionCommands are the subcommands offered when completing "gsh "
+var completionCommands = []string{"run", "telemetry", "trust", "keyring"}
+
+// completionScript returns the completion script for shell, which is "bash" or "zsh"
+func completionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return strings.NewReplacer(
+			"{{FLAGS}}", strings.Join(completionFlags, " "),
+			"{{COMMANDS}}", strings.Join(completionCommands, " "),
+		).Replace(bashCompletionScript), nil
+	case "zsh":
+		return zshCompletionScript, nil
+	default:
+		return "", fmt.Errorf("unsupported shell for completion: %q (expected bash or zsh)", shell)
+	}
+}
+
+// completionShell returns the shell to generate completion for: the one named on the
+// command line, or else the user's login shell from $SHELL
+func completionShell(shell string) (string, error) {
+	if shell != "" {
+		return shell, nil
+	}
+	switch detected := filepath.Base(os.Getenv("SHELL")); detected {
+	case "bash", "zsh":
+		return detected, nil
+	}
+	return "", fmt.Errorf("could not detect your shell from $SHELL, pass bash or zsh")
+}
+
+// runShowCompletion prints the completion script for shell to stdout and returns the
+// process exit code
+func runShowCompletion(shell string, stdout, stderr io.Writer) int {
+	shell, err := completionShell(shell)
+	if err == nil {
+		var script string
+		if script, err = completionScript(shell); err == nil {
+			fmt.Fprint(stdout, script)
+			return 0
+		}
+	}
+	fmt.Fprintf(stderr, "gsh: %v\n", err)
+	return 1
+}
+
+// runInstallCompletion writes the completion script for shell into dir and prints the
+// line to add to the shell's rc file. It returns the process exit code.
+func runInstallCompletion(shell, dir string, stdout, stderr io.Writer) int {
+	shell, err := completionShell(shell)
+	if err != nil {
+		fmt.Fprintf(stderr, "gsh: %v\n", err)
+		return 1
+	}
+	script, err := completionScript(shell)
+	if err != nil {
+		fmt.Fprintf(stderr, "gsh: %v\n", err)
+		return 1
+	}
+
+	// zsh autoloads completion functions from files named after the function
+	name := "gsh.bash"
+	if shell == "zsh" {
+		name = "_gsh"
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(stderr, "gsh: failed to create %s: %v\n", dir, err)
+		return 1
+	}
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		fmt.Fprintf(stderr, "gsh: failed to write %s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Installed %s completion for gsh to %s\n", shell, path)
+	if shell == "zsh" {
+		fmt.Fprintf(stdout, "To enable it, add this line to ~/.zshrc before compinit runs:\n\n  fpath=(%s $fpath)\n", dir)
+	} else {
+		fmt.Fprintf(stdout, "To enable it, add this line to ~/.bashrc:\n\n  source %s\n", path)
+	}
+	return 0
+}
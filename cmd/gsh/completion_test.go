@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseREPLOptions_Completion(t *testing.T) {
+	tests := []struct {
+		args    []string
+		show    bool
+		install bool
+		shell   string
+	}{
+		{[]string{"--show-completion", "bash"}, true, false, "bash"},
+		{[]string{"--show-completion=zsh"}, true, false, "zsh"},
+		{[]string{"--install-completion"}, false, true, ""},
+		{[]string{"--install-completion", "zsh"}, false, true, "zsh"},
+		{[]string{"--install-completion", "--no-update-check"}, false, true, ""},
+	}
+	for _, tt := range tests {
+		opts := parseREPLOptions(tt.args)
+		if opts.showCompletion != tt.show || opts.installCompletion != tt.install || opts.completionShell != tt.shell {
+			t.Errorf("%v: expected show=%v install=%v shell=%q, got %+v", tt.args, tt.show, tt.install, tt.shell, opts)
+		}
+	}
+}
+
+// TestCompletionFlagsDocumented keeps the completed flags in sync with the help text
+func TestCompletionFlagsDocumented(t *testing.T) {
+	for _, flag := range completionFlags {
+		if !strings.Contains(mainHelpText, flag) {
+			t.Errorf("completion offers %s, which the help text doesn't document", flag)
+		}
+	}
+	for _, command := range completionCommands {
+		if !strings.Contains(mainHelpText, "  "+command+" ") {
+			t.Errorf("completion offers the %s command, which the help text doesn't document", command)
+		}
+	}
+}
+
+func TestRunShowCompletion(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runShowCompletion("bash", &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	script := stdout.String()
+	if !strings.Contains(script, "complete -o filenames -F _gsh gsh") || !strings.Contains(script, "--install-completion") {
+		t.Errorf("unexpected bash script:\n%s", script)
+	}
+	if strings.Contains(script, "{{") {
+		t.Error("expected all placeholders to be filled in")
+	}
+	if bash, err := exec.LookPath("bash"); err == nil {
+		cmd := exec.Command(bash, "-n")
+		cmd.Stdin = strings.NewReader(script)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("bash script has a syntax error: %v\n%s", err, out)
+		}
+	}
+
+	stdout.Reset()
+	if code := runShowCompletion("zsh", &stdout, &stderr); code != 0 || !strings.HasPrefix(stdout.String(), "#compdef gsh") {
+		t.Errorf("expected a zsh script, got exit code %d:\n%s", code, stdout.String())
+	}
+
+	stderr.Reset()
+	if code := runShowCompletion("fish", &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "expected bash or zsh") {
+		t.Errorf("expected an unsupported shell error, got exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestRunShowCompletion_DetectsShell(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	t.Setenv("SHELL", "/usr/bin/zsh")
+	if code := runShowCompletion("", &stdout, &stderr); code != 0 || !strings.HasPrefix(stdout.String(), "#compdef gsh") {
+		t.Errorf("expected the zsh script from $SHELL, got exit code %d: %s", code, stderr.String())
+	}
+
+	t.Setenv("SHELL", "/bin/fish")
+	if code := runShowCompletion("", &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "could not detect your shell") {
+		t.Errorf("expected a detection error, got exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestRunInstallCompletion(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "completions")
+
+	for shell, name := range map[string]string{"bash": "gsh.bash", "zsh": "_gsh"} {
+		var stdout, stderr bytes.Buffer
+		if code := runInstallCompletion(shell, dir, &stdout, &stderr); code != 0 {
+			t.Fatalf("%s: expected exit code 0, got %d: %s", shell, code, stderr.String())
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: expected the script at %s: %v", shell, path, err)
+		}
+		want, _ := completionScript(shell)
+		if string(data) != want {
+			t.Errorf("%s: installed script doesn't match --show-completion", shell)
+		}
+		if !strings.Contains(stdout.String(), path) && !strings.Contains(stdout.String(), dir) {
+			t.Errorf("%s: expected instructions naming the install location, got %q", shell, stdout.String())
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
 	"strings"
 	"time"
 
@@ -17,9 +22,13 @@ import (
 	"github.com/kunchenguid/gsh/internal/environment"
 	"github.com/kunchenguid/gsh/internal/filesystem"
 	"github.com/kunchenguid/gsh/internal/history"
+	"github.com/kunchenguid/gsh/internal/keyring"
 	"github.com/kunchenguid/gsh/internal/repl"
 	"github.com/kunchenguid/gsh/internal/repl/completion"
+	"github.com/kunchenguid/gsh/internal/repl/config"
 	"github.com/kunchenguid/gsh/internal/script/interpreter"
+	"github.com/kunchenguid/gsh/internal/script/lexer"
+	"github.com/kunchenguid/gsh/internal/script/parser"
 	"go.uber.org/zap"
 	"golang.org/x/term"
 	"mvdan.cc/sh/v3/expand"
@@ -28,6 +37,13 @@ import (
 
 var BUILD_VERSION = "dev"
 
+// BUILD_COMMIT and BUILD_DATE are set at release time via -ldflags. When they
+// are empty, the VCS details Go stamps into the binary are used instead.
+var (
+	BUILD_COMMIT = ""
+	BUILD_DATE   = ""
+)
+
 //go:embed defaults/*
 var defaultConfigFS embed.FS
 
@@ -39,27 +55,50 @@ const mainHelpText = `gsh - A battery-included, POSIX-compatible, generative she
 USAGE:
   gsh [options]
   gsh -c <command>
+  gsh --command-file <path> [--keep-going]
+  gsh --acp
+  gsh --eval <expression>
+  gsh --check <script>
   gsh <command> [options] [args...]
 
 COMMANDS:
   run <script> [args...]        Execute a script file (.gsh or .sh)
   telemetry [status|on|off]     Manage anonymous usage telemetry
+  trust [dir]                   Allow a directory's .gsh/config.gsh to run
+  keyring [set|get] <name>      Manage secrets stored in the OS keyring
 
 OPTIONS:
   -c <command>                  Execute a command string and exit
+      --command-file <path>     Execute newline-separated commands from a file and exit
+      --keep-going              With --command-file, continue after a failing command
   -h, --help                    Display help information
-  -v, --version                 Display version
+  -v, --version                 Display version (with --json, print build details as JSON)
   -l, --login                   Run as a login shell
       --repl-config <path>      Use custom REPL config (default: ~/.gsh/repl.gsh)
+      --no-update-check         Skip the automatic update check on startup
+      --acp                     Run as an Agent Client Protocol (ACP) agent over stdio
+      --stdin-prompt            Send all of stdin to the default agent and print its reply
+      --clear-cache             Delete cached model responses and exit
+      --eval <expression>       Evaluate a gsh expression, print the result and exit
+      --check <script>          Validate a .gsh script without running it and exit
+      --log-file <path>         Write logs to path instead of ~/.gsh/gsh.log
+      --install-completion      Set up bash/zsh completion for gsh (--show-completion prints it)
 
 EXAMPLES:
   gsh                           Start interactive shell
   gsh --login                   Start as login shell
   gsh -c "echo hello"           Execute a command string
-  gsh -l -c "echo hello"        Execute as login shell
+  gsh --command-file cmds.txt   Execute each line of cmds.txt as a command
   gsh run script.gsh            Execute a gsh script
   gsh run deploy.sh             Execute a bash script
   gsh telemetry status          Check telemetry status
+  gsh trust                     Trust the project config for the current directory
+  gsh keyring set MY_API_KEY    Store an API key in the OS keyring
+  gsh --acp                     Serve the default agent to an ACP client (e.g. an editor)
+  gsh --eval '1 + 2 * 3'        Evaluate a gsh expression
+  gsh --check workflow.gsh      Check a script for problems, e.g. in CI
+  gsh --log-file /tmp/gsh.log   Keep this session's logs separate
+  gsh --version --json          Print version, Go version, OS, arch, build date and commit
 `
 
 // Help text for the run subcommand
@@ -70,6 +109,12 @@ USAGE:
 
 OPTIONS:
   -h, --help                    Display help information
+      --trace                   Log the messages sent to and received from models at
+                                debug level (same as GSH_TRACE_MODEL=1)
+      --record <file>           Save the agents' model responses and tool results to file
+      --replay <file>           Re-run a recorded session, serving model responses and
+                                tool results from file (the script defaults to the
+                                recorded one)
 
 ARGUMENTS:
   <script>                      Path to the script file (.gsh or .sh)
@@ -79,6 +124,10 @@ EXAMPLES:
   gsh run script.gsh            Execute a gsh script
   gsh run deploy.sh             Execute a bash script
   gsh run agent.gsh --verbose   Execute with arguments
+  gsh run --record session.json triage.gsh
+                                Record a run of triage.gsh
+  gsh run --replay session.json Replay it without calling models or tools
+  gsh run --trace agent.gsh     Log every model request and response to the log file
 
 SCRIPTING:
   Files with .gsh extension use the gsh scripting language for agentic
@@ -87,6 +136,45 @@ SCRIPTING:
   For documentation and examples, see: https://github.com/kunchenguid/gsh
 `
 
+// Help text for the trust subcommand
+const trustHelpText = `Trust a per-directory gsh config.
+
+When the REPL starts or changes directory, gsh looks for a .gsh/config.gsh in
+the current directory and its parents (up to the repository root). The file is
+only loaded after you trust it. Editing the file revokes trust until you run
+this command again.
+
+USAGE:
+  gsh trust [dir]
+
+ARGUMENTS:
+  [dir]                         Directory to search from (default: current directory)
+
+OPTIONS:
+  -h, --help                    Display help information
+`
+
+// Help text for the keyring subcommand
+const keyringHelpText = `Manage secrets, such as model API keys, in the OS keyring.
+
+Secrets are stored under the "gsh" service in the macOS Keychain, in the
+Secret Service (GNOME Keyring, KWallet) on Linux via secret-tool, or in the
+Windows Credential Manager. Scripts read them with the keyring object, e.g.
+apiKey: keyring.OPENAI_API_KEY.
+
+USAGE:
+  gsh keyring set <name> [value]
+  gsh keyring get <name>
+
+COMMANDS:
+  set <name> [value]            Store a secret. If value is omitted, it is read
+                                from stdin (without echo on a terminal)
+  get <name>                    Print a stored secret
+
+OPTIONS:
+  -h, --help                    Display help information
+`
+
 // Help text for the telemetry subcommand
 const telemetryHelpText = `Manage anonymous usage telemetry for gsh.
 
@@ -103,6 +191,7 @@ OPTIONS:
 
 ENVIRONMENT VARIABLES:
   GSH_NO_TELEMETRY=1            Disable telemetry via environment
+  GSH_TELEMETRY_ENDPOINT=<url>  Send events to this HTTP endpoint instead
 
 WHAT WE COLLECT:
   - gsh version, OS, CPU architecture
@@ -125,6 +214,25 @@ type replOptions struct {
 	login      bool
 	replConfig string
 	command    string // -c command string
+
+	noUpdateCheck bool // --no-update-check: skip the startup self-update check
+	acp           bool // --acp: serve the default agent over the Agent Client Protocol
+	stdinPrompt   bool // --stdin-prompt: send all of stdin to the default agent as one prompt
+	clearCache    bool // --clear-cache: delete cached model responses and exit
+
+	eval    string // --eval expression
+	hasEval bool   // whether --eval was given, so an empty expression is still an error
+
+	check string // --check script path
+
+	commandFile string // --command-file path
+	keepGoing   bool   // --keep-going: don't stop on the first failing command
+
+	logFile string // --log-file path, overriding GSH_LOG_FILE and ~/.gsh/gsh.log
+
+	showCompletion    bool   // --show-completion: print the completion script for gsh's own flags
+	installCompletion bool   // --install-completion: write the completion script under ~/.gsh
+	completionShell   string // bash or zsh for the completion flags, detected from $SHELL if empty
 }
 
 func main() {
@@ -141,17 +249,48 @@ func main() {
 	}
 
 	if containsVersionFlag(args) {
-		fmt.Println(BUILD_VERSION)
+		if err := printVersion(os.Stdout, containsFlag(args, "--json")); err != nil {
+			fmt.Fprintf(os.Stderr, "gsh: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
 	// No args or only flags = REPL mode (or -c command mode)
 	if len(args) == 0 || (len(args) > 0 && strings.HasPrefix(args[0], "-")) {
 		opts := parseREPLOptions(args)
+		if opts.clearCache {
+			runClearCache()
+			return
+		}
+		if opts.showCompletion {
+			os.Exit(runShowCompletion(opts.completionShell, os.Stdout, os.Stderr))
+		}
+		if opts.installCompletion {
+			os.Exit(runInstallCompletion(opts.completionShell, filepath.Join(core.DataDir(), "completions"), os.Stdout, os.Stderr))
+		}
+		if opts.hasEval {
+			os.Exit(runEval(opts.eval, os.Stdout, os.Stderr))
+		}
+		if opts.check != "" {
+			os.Exit(runCheck(opts.check, os.Stdout, os.Stderr))
+		}
 		if opts.command != "" {
 			runDashCCommand(startTime, opts)
 			return
 		}
+		if opts.commandFile != "" {
+			runCommandFile(startTime, opts)
+			return
+		}
+		if opts.acp {
+			runACPMode(startTime, opts)
+			return
+		}
+		if opts.stdinPrompt {
+			runStdinPromptMode(startTime, opts)
+			return
+		}
 		runREPLMode(startTime, opts)
 		return
 	}
@@ -165,6 +304,10 @@ func main() {
 		runRunCommand(startTime, subargs)
 	case "telemetry":
 		runTelemetryCommand(subargs)
+	case "trust":
+		runTrustCommand(subargs)
+	case "keyring":
+		runKeyringCommand(subargs)
 	default:
 		fmt.Fprintf(os.Stderr, "gsh: unknown command: %s\n", subcommand)
 		fmt.Fprintf(os.Stderr, "Run 'gsh --help' for usage.\n")
@@ -202,6 +345,72 @@ func containsVersionFlag(args []string) bool {
 	return false
 }
 
+// containsFlag checks if args contain the given flag (case insensitive).
+// Stops scanning after -c since the next arg is a command string, not a flag.
+func containsFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == "-c" {
+			break
+		}
+		if strings.EqualFold(arg, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionInfo is the machine-readable form of `gsh --version --json`.
+type versionInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	BuildDate string `json:"buildDate"`
+	Commit    string `json:"commit"`
+}
+
+// buildVersionInfo collects version details, filling in the commit and build
+// date from Go's embedded VCS info when they weren't set via -ldflags.
+func buildVersionInfo() versionInfo {
+	info := versionInfo{
+		Version:   BUILD_VERSION,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		BuildDate: BUILD_DATE,
+		Commit:    BUILD_COMMIT,
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+// printVersion writes the version string, or the full build details as JSON.
+func printVersion(w io.Writer, asJSON bool) error {
+	if !asJSON {
+		_, err := fmt.Fprintln(w, BUILD_VERSION)
+		return err
+	}
+	data, err := json.MarshalIndent(buildVersionInfo(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
 // printContextualHelp prints help based on the subcommand context
 func printContextualHelp(args []string) {
 	// Find the subcommand (first non-flag arg)
@@ -216,6 +425,12 @@ func printContextualHelp(args []string) {
 		case "telemetry":
 			fmt.Print(telemetryHelpText)
 			return
+		case "trust":
+			fmt.Print(trustHelpText)
+			return
+		case "keyring":
+			fmt.Print(keyringHelpText)
+			return
 		}
 	}
 	// No subcommand found, show main help
@@ -241,6 +456,75 @@ func parseREPLOptions(args []string) replOptions {
 			}
 		case strings.HasPrefix(strings.ToLower(arg), "--repl-config="):
 			opts.replConfig = strings.SplitN(arg, "=", 2)[1]
+		case strings.ToLower(arg) == "--command-file":
+			if i+1 < len(args) {
+				i++
+				opts.commandFile = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "gsh: --command-file requires a path argument\n")
+				os.Exit(1)
+			}
+		case strings.HasPrefix(strings.ToLower(arg), "--command-file="):
+			opts.commandFile = strings.SplitN(arg, "=", 2)[1]
+		case strings.ToLower(arg) == "--no-update-check":
+			opts.noUpdateCheck = true
+		case strings.ToLower(arg) == "--clear-cache":
+			opts.clearCache = true
+		case strings.ToLower(arg) == "--acp":
+			opts.acp = true
+		case strings.ToLower(arg) == "--stdin-prompt":
+			opts.stdinPrompt = true
+		case strings.ToLower(arg) == "--eval":
+			if i+1 < len(args) {
+				i++
+				opts.eval = args[i]
+				opts.hasEval = true
+			} else {
+				fmt.Fprintf(os.Stderr, "gsh: --eval requires an expression argument\n")
+				os.Exit(1)
+			}
+		case strings.HasPrefix(strings.ToLower(arg), "--eval="):
+			opts.eval = strings.SplitN(arg, "=", 2)[1]
+			opts.hasEval = true
+		case strings.ToLower(arg) == "--check":
+			if i+1 < len(args) {
+				i++
+				opts.check = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "gsh: --check requires a script path argument\n")
+				os.Exit(1)
+			}
+		case strings.HasPrefix(strings.ToLower(arg), "--check="):
+			opts.check = strings.SplitN(arg, "=", 2)[1]
+		case strings.ToLower(arg) == "--log-file":
+			if i+1 < len(args) {
+				i++
+				opts.logFile = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "gsh: --log-file requires a path argument\n")
+				os.Exit(1)
+			}
+		case strings.HasPrefix(strings.ToLower(arg), "--log-file="):
+			opts.logFile = strings.SplitN(arg, "=", 2)[1]
+		case strings.ToLower(arg) == "--keep-going":
+			opts.keepGoing = true
+		case strings.ToLower(arg) == "--show-completion" || strings.ToLower(arg) == "--install-completion":
+			if strings.ToLower(arg) == "--show-completion" {
+				opts.showCompletion = true
+			} else {
+				opts.installCompletion = true
+			}
+			// The shell is optional, so only a following bash or zsh is taken as its value
+			if i+1 < len(args) && (args[i+1] == "bash" || args[i+1] == "zsh") {
+				i++
+				opts.completionShell = args[i]
+			}
+		case strings.HasPrefix(strings.ToLower(arg), "--show-completion="):
+			opts.showCompletion = true
+			opts.completionShell = strings.SplitN(arg, "=", 2)[1]
+		case strings.HasPrefix(strings.ToLower(arg), "--install-completion="):
+			opts.installCompletion = true
+			opts.completionShell = strings.SplitN(arg, "=", 2)[1]
 		case arg == "-c":
 			if i+1 < len(args) {
 				i++
@@ -258,6 +542,10 @@ func parseREPLOptions(args []string) replOptions {
 		}
 		i++
 	}
+	if opts.command != "" && opts.commandFile != "" {
+		fmt.Fprintf(os.Stderr, "gsh: -c and --command-file cannot be used together\n")
+		os.Exit(1)
+	}
 	return opts
 }
 
@@ -304,7 +592,8 @@ func runREPLMode(startTime time.Time, opts replOptions) {
 	}
 	syncRunnerEnvToOS(runner)
 
-	logger, _, err := initializeLogger(runner)
+	logFile := resolveLogFile(runner, opts.logFile)
+	logger, _, err := initializeLogger(runner, logFile)
 	if err != nil {
 		panic(err)
 	}
@@ -312,13 +601,17 @@ func runREPLMode(startTime time.Time, opts replOptions) {
 
 	logger.Info("-------- new gsh session --------", zap.Any("args", os.Args))
 
-	// Check for updates in background
-	appupdate.HandleSelfUpdate(
-		BUILD_VERSION,
-		logger,
-		filesystem.DefaultFileSystem{},
-		appupdate.DefaultUpdater{},
-	)
+	// Check for updates in background, unless disabled by flag or GSH_NO_UPDATE
+	if opts.noUpdateCheck || environment.ShouldSkipUpdateCheck(runner) {
+		logger.Debug("self-update check disabled")
+	} else {
+		appupdate.HandleSelfUpdate(
+			BUILD_VERSION,
+			logger,
+			filesystem.DefaultFileSystem{},
+			appupdate.DefaultUpdater{},
+		)
+	}
 
 	ctx := context.Background()
 
@@ -334,10 +627,147 @@ func runREPLMode(startTime time.Time, opts replOptions) {
 		telemetryClient.TrackSessionStart("repl")
 	}
 
-	err = runInteractiveShell(ctx, logger, runner, startTime, telemetryClient, opts.replConfig)
+	err = runInteractiveShell(ctx, logger, logFile, runner, startTime, telemetryClient, opts.replConfig)
 	handleExitError(err, logger)
 }
 
+// runClearCache deletes the model responses cached when GSH_RESPONSE_CACHE is enabled
+func runClearCache() {
+	removed, err := interpreter.ClearResponseCache(core.ResponseCacheDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to clear response cache: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Cleared %d cached responses\n", removed)
+}
+
+// runEval evaluates a single gsh expression and prints its value to stdout.
+// It returns the process exit code: non-zero if the expression doesn't parse,
+// fails at runtime, or evaluates to an error value.
+func runEval(expression string, stdout, stderr io.Writer) int {
+	p := parser.New(lexer.New(expression))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		fmt.Fprintf(stderr, "Parse error: %s\n", strings.Join(errs, "; "))
+		return 1
+	}
+	if len(program.Statements) != 1 {
+		fmt.Fprintf(stderr, "gsh: --eval expects a single expression\n")
+		return 1
+	}
+	if _, ok := program.Statements[0].(*parser.ExpressionStatement); !ok {
+		fmt.Fprintf(stderr, "gsh: --eval expects an expression, got a statement\n")
+		return 1
+	}
+
+	gshInterp := interpreter.New(&interpreter.Options{Version: BUILD_VERSION})
+	defer gshInterp.Close()
+
+	result, err := gshInterp.Eval(program)
+	if err != nil {
+		fmt.Fprintf(stderr, "Runtime error: %v\n", err)
+		return 1
+	}
+	if errVal, ok := result.Value().(*interpreter.ErrorValue); ok {
+		fmt.Fprintln(stderr, errVal.String())
+		return 1
+	}
+	fmt.Fprintln(stdout, result.Value().String())
+	return 0
+}
+
+// runACPMode serves the REPL's default agent over the Agent Client Protocol on stdio.
+// Stdout carries the protocol, so anything else gsh would print there (tool output,
+// event handlers, config errors) is sent to stderr instead.
+func runACPMode(startTime time.Time, opts replOptions) {
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+
+	r, logger := newHeadlessREPL(startTime, opts, "gsh --acp")
+	defer logger.Sync()
+	defer r.Close()
+
+	if err := r.ServeACP(context.Background(), os.Stdin, protocolOut); err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: %v\n", err)
+		logger.Error("ACP server failed", zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+// newHeadlessREPL loads the REPL config without starting the interactive UI, for modes
+// such as --acp that run the default agent. mode names the session in the log.
+func newHeadlessREPL(startTime time.Time, opts replOptions, mode string) (*repl.REPL, *zap.Logger) {
+	historyManager, _ := initializeHistoryManager()
+	completionManager := initializeCompletionManager()
+
+	runner, err := initializeRunner(historyManager, completionManager, opts.login)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	syncRunnerEnvToOS(runner)
+
+	logFile := resolveLogFile(runner, opts.logFile)
+	logger, _, err := initializeLogger(runner, logFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	logger.Info(fmt.Sprintf("-------- new %s session --------", mode))
+
+	defaultContent, err := defaultConfigFS.ReadFile(defaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to read embedded default config: %v\n", err)
+		os.Exit(1)
+	}
+
+	r, err := repl.NewREPL(repl.Options{
+		Logger:                logger,
+		LogFile:               logFile,
+		ConfigPath:            opts.replConfig,
+		DefaultConfigContent:  string(defaultContent),
+		DefaultConfigFS:       defaultConfigFS,
+		DefaultConfigBasePath: "defaults",
+		BuildVersion:          BUILD_VERSION,
+		Runner:                runner,
+		StartTime:             startTime,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	return r, logger
+}
+
+// runStdinPromptMode reads all of stdin, sends it to the REPL's default agent as one
+// prompt and prints the reply. Only the reply goes to stdout, so the rest of what gsh
+// would print there (tool output, event handlers, config errors) is sent to stderr.
+func runStdinPromptMode(startTime time.Time, opts replOptions) {
+	replyOut := os.Stdout
+	os.Stdout = os.Stderr
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to read stdin: %v\n", err)
+		os.Exit(1)
+	}
+	prompt := strings.TrimSpace(string(input))
+	if prompt == "" {
+		fmt.Fprintf(os.Stderr, "gsh: --stdin-prompt needs a prompt on stdin\n")
+		os.Exit(1)
+	}
+
+	r, logger := newHeadlessREPL(startTime, opts, "gsh --stdin-prompt")
+	defer logger.Sync()
+	defer r.Close()
+
+	if err := r.RunPrompt(context.Background(), prompt, replyOut); err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: %v\n", err)
+		logger.Error("stdin prompt failed", zap.Error(err))
+		os.Exit(1)
+	}
+}
+
 // runDashCCommand handles the -c command execution mode
 func runDashCCommand(startTime time.Time, opts replOptions) {
 	// Initialize managers (minimal for command execution)
@@ -351,7 +781,8 @@ func runDashCCommand(startTime time.Time, opts replOptions) {
 	}
 	syncRunnerEnvToOS(runner)
 
-	logger, _, err := initializeLogger(runner)
+	logFile := resolveLogFile(runner, opts.logFile)
+	logger, _, err := initializeLogger(runner, logFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "gsh: failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -365,6 +796,82 @@ func runDashCCommand(startTime time.Time, opts replOptions) {
 	handleExitError(err, logger)
 }
 
+// runCommandFile handles the --command-file execution mode
+func runCommandFile(startTime time.Time, opts replOptions) {
+	f, err := os.Open(opts.commandFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	// Initialize managers (minimal for command execution)
+	historyManager, _ := initializeHistoryManager()
+	completionManager := initializeCompletionManager()
+
+	runner, err := initializeRunner(historyManager, completionManager, opts.login)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	syncRunnerEnvToOS(runner)
+
+	logFile := resolveLogFile(runner, opts.logFile)
+	logger, _, err := initializeLogger(runner, logFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh: failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	logger.Info("-------- new gsh --command-file session --------",
+		zap.String("file", opts.commandFile), zap.Bool("keepGoing", opts.keepGoing))
+
+	ctx := context.Background()
+	err = runCommandLines(ctx, runner, f, opts.commandFile, opts.keepGoing)
+	handleExitError(err, logger)
+}
+
+// runCommandLines runs each non-empty line read from reader as a separate command.
+// Execution stops at the first failing command unless keepGoing is set, in which
+// case every line runs and the exit status of the last failing command is returned.
+// A command that calls `exit` always stops execution.
+func runCommandLines(ctx context.Context, runner *interp.Runner, reader io.Reader, name string, keepGoing bool) error {
+	var lastErr error
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		err := bash.RunBashScriptFromReader(ctx, runner, strings.NewReader(line), fmt.Sprintf("%s:%d", name, lineNum))
+		if runner.Exited() {
+			return err
+		}
+		if err == nil {
+			continue
+		}
+
+		var exitStatus interp.ExitStatus
+		if !errors.As(err, &exitStatus) {
+			// Parse errors and other failures are reported and treated as exit status 1
+			fmt.Fprintf(os.Stderr, "gsh: %v\n", err)
+			err = interp.ExitStatus(1)
+		}
+		if !keepGoing {
+			return err
+		}
+		lastErr = err
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return lastErr
+}
+
 // runRunCommand handles the "run" subcommand
 func runRunCommand(startTime time.Time, args []string) {
 	// Check for help flag in subcommand args
@@ -373,22 +880,40 @@ func runRunCommand(startTime time.Time, args []string) {
 		return
 	}
 
-	// Find the script file (first non-flag argument)
-	var scriptPath string
-	var scriptArgs []string
-	for i, arg := range args {
-		if !strings.HasPrefix(arg, "-") {
-			scriptPath = arg
-			scriptArgs = args[i+1:]
-			break
+	opts, err := parseRunOptions(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsh run: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A replay re-runs the recorded script unless another script is given
+	var session *interpreter.Session
+	if opts.replay != "" {
+		session, err = interpreter.LoadReplaySession(opts.replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gsh run: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.scriptPath == "" {
+			opts.scriptPath = session.Script()
 		}
 	}
 
+	scriptPath, scriptArgs := opts.scriptPath, opts.scriptArgs
 	if scriptPath == "" {
 		fmt.Fprintf(os.Stderr, "gsh run: missing script path\n")
 		fmt.Fprintf(os.Stderr, "Run 'gsh run --help' for usage.\n")
 		os.Exit(1)
 	}
+	if session != nil || opts.record != "" {
+		if !isGshScript(scriptPath) {
+			fmt.Fprintf(os.Stderr, "gsh run: --record and --replay only work with .gsh scripts\n")
+			os.Exit(1)
+		}
+		if opts.record != "" {
+			session = interpreter.NewRecordingSession(scriptPath)
+		}
+	}
 
 	// Initialize telemetry
 	telemetryClient, err := telemetry.NewClient(telemetry.Config{
@@ -401,6 +926,11 @@ func runRunCommand(startTime time.Time, args []string) {
 		defer telemetryClient.Close()
 	}
 
+	// --trace works through GSH_TRACE_MODEL so the runner and the interpreter both see it
+	if opts.trace {
+		os.Setenv("GSH_TRACE_MODEL", "1")
+	}
+
 	// Initialize managers (minimal for script execution)
 	historyManager, _ := initializeHistoryManager()
 	completionManager := initializeCompletionManager()
@@ -412,7 +942,8 @@ func runRunCommand(startTime time.Time, args []string) {
 	}
 	syncRunnerEnvToOS(runner)
 
-	logger, logLevel, err := initializeLogger(runner)
+	logFile := resolveLogFile(runner, "")
+	logger, logLevel, err := initializeLogger(runner, logFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "gsh: failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -431,7 +962,20 @@ func runRunCommand(startTime time.Time, args []string) {
 			startupMs := time.Since(startTime).Milliseconds()
 			telemetryClient.TrackStartupTime(startupMs)
 		}
-		if err := runGshScript(ctx, scriptPath, logger, logLevel, runner); err != nil {
+		err := runGshScript(ctx, scriptPath, logger, logLevel, logFile, runner, session)
+		if opts.record != "" {
+			// Save even when the script failed, so the failure can be replayed
+			if saveErr := session.Save(opts.record); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "gsh run: failed to save session: %v\n", saveErr)
+				os.Exit(1)
+			}
+		}
+		if err == nil && opts.replay != "" {
+			if err = session.Unused(); err != nil {
+				fmt.Fprintf(os.Stderr, "gsh run: %v\n", err)
+			}
+		}
+		if err != nil {
 			if telemetryClient != nil {
 				telemetryClient.TrackError(telemetry.ErrorCategoryScript)
 			}
@@ -444,6 +988,49 @@ func runRunCommand(startTime time.Time, args []string) {
 	}
 }
 
+// runOptions holds the parsed arguments of the run subcommand
+type runOptions struct {
+	record     string // --record: save the model responses and tool results to this file
+	trace      bool   // --trace: log model requests and responses
+	replay     string // --replay: serve model responses and tool results from this file
+	scriptPath string
+	scriptArgs []string
+}
+
+// parseRunOptions parses the run subcommand's flags, which come before the script path
+func parseRunOptions(args []string) (runOptions, error) {
+	opts := runOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.ToLower(arg) == "--record" || strings.ToLower(arg) == "--replay":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a session file argument", arg)
+			}
+			i++
+			if strings.ToLower(arg) == "--record" {
+				opts.record = args[i]
+			} else {
+				opts.replay = args[i]
+			}
+		case strings.ToLower(arg) == "--trace":
+			opts.trace = true
+		case strings.HasPrefix(strings.ToLower(arg), "--record="):
+			opts.record = strings.SplitN(arg, "=", 2)[1]
+		case strings.HasPrefix(strings.ToLower(arg), "--replay="):
+			opts.replay = strings.SplitN(arg, "=", 2)[1]
+		case !strings.HasPrefix(arg, "-"):
+			opts.scriptPath = arg
+			opts.scriptArgs = args[i+1:]
+			i = len(args)
+		}
+	}
+	if opts.record != "" && opts.replay != "" {
+		return opts, fmt.Errorf("--record and --replay can't be used together")
+	}
+	return opts, nil
+}
+
 // runTelemetryCommand handles the "telemetry" subcommand
 func runTelemetryCommand(args []string) {
 	// Check for help flag
@@ -480,6 +1067,89 @@ func runTelemetryCommand(args []string) {
 	}
 }
 
+// runTrustCommand handles the trust subcommand
+func runTrustCommand(args []string) {
+	if containsHelpFlag(args) {
+		fmt.Print(trustHelpText)
+		return
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	configPath := config.FindLocalConfig(dir)
+	if configPath == "" {
+		fmt.Fprintf(os.Stderr, "gsh trust: no %s/%s found in %s or its parents\n", config.LocalConfigDir, config.LocalConfigFile, dir)
+		os.Exit(1)
+	}
+
+	if err := config.TrustLocalConfig(config.TrustFile(), configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "gsh trust: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Trusted %s\n", configPath)
+}
+
+// runKeyringCommand handles the keyring subcommand
+func runKeyringCommand(args []string) {
+	if containsHelpFlag(args) || len(args) < 2 {
+		fmt.Print(keyringHelpText)
+		if len(args) < 2 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	subcommand, name := args[0], args[1]
+	switch subcommand {
+	case "set":
+		var value string
+		if len(args) > 2 {
+			value = args[2]
+		} else {
+			var err error
+			if value, err = readSecret(name); err != nil {
+				fmt.Fprintf(os.Stderr, "gsh keyring: failed to read secret: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := keyring.Set(name, value); err != nil {
+			fmt.Fprintf(os.Stderr, "gsh keyring: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Stored %s in the keyring\n", name)
+	case "get":
+		value, err := keyring.Get(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gsh keyring: %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	default:
+		fmt.Fprintf(os.Stderr, "gsh keyring: unknown command: %s\n", subcommand)
+		fmt.Fprintf(os.Stderr, "Run 'gsh keyring --help' for usage.\n")
+		os.Exit(1)
+	}
+}
+
+// readSecret reads a secret from stdin, without echo when stdin is a terminal
+func readSecret(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Secret for %s: ", name)
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(secret), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
 // handleExitError handles exit status and errors
 func handleExitError(err error, logger *zap.Logger) {
 	if err == nil {
@@ -498,7 +1168,7 @@ func handleExitError(err error, logger *zap.Logger) {
 }
 
 // runInteractiveShell starts the new REPL implementation.
-func runInteractiveShell(ctx context.Context, logger *zap.Logger, runner *interp.Runner, startTime time.Time, startupTracker repl.StartupTimeTracker, replConfigPath string) error {
+func runInteractiveShell(ctx context.Context, logger *zap.Logger, logFile string, runner *interp.Runner, startTime time.Time, startupTracker repl.StartupTimeTracker, replConfigPath string) error {
 	// Read default config content from embedded FS
 	defaultContent, err := defaultConfigFS.ReadFile(defaultConfigPath)
 	if err != nil {
@@ -507,6 +1177,7 @@ func runInteractiveShell(ctx context.Context, logger *zap.Logger, runner *interp
 
 	r, err := repl.NewREPL(repl.Options{
 		Logger:                logger,
+		LogFile:               logFile,
 		ConfigPath:            replConfigPath, // Custom config path (empty = default ~/.gsh/repl.gsh)
 		DefaultConfigContent:  string(defaultContent),
 		DefaultConfigFS:       defaultConfigFS,
@@ -530,25 +1201,10 @@ func isGshScript(filePath string) bool {
 }
 
 // runGshScript executes a .gsh script file
-func runGshScript(ctx context.Context, filePath string, logger *zap.Logger, logLevel zap.AtomicLevel, runner *interp.Runner) error {
-	// Get absolute path for proper import resolution
-	absPath, err := filepath.Abs(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to resolve script path: %w", err)
-	}
-
-	// Read the script file
-	content, err := os.ReadFile(absPath)
+func runGshScript(ctx context.Context, filePath string, logger *zap.Logger, logLevel zap.AtomicLevel, logFile string, runner *interp.Runner, session *interpreter.Session) error {
+	absPath, script, err := readGshScript(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read script file: %w", err)
-	}
-
-	// Skip shebang line if present
-	script := string(content)
-	if strings.HasPrefix(script, "#!") {
-		if idx := strings.Index(script, "\n"); idx >= 0 {
-			script = script[idx+1:]
-		}
+		return err
 	}
 
 	// Create interpreter with the shared runner, logger, and log level
@@ -558,6 +1214,8 @@ func runGshScript(ctx context.Context, filePath string, logger *zap.Logger, logL
 		Runner:   runner,
 		Version:  BUILD_VERSION,
 		LogLevel: logLevel,
+		LogFile:  logFile,
+		Session:  session,
 	})
 	defer gshInterp.Close()
 
@@ -567,7 +1225,16 @@ func runGshScript(ctx context.Context, filePath string, logger *zap.Logger, logL
 		BasePath: filepath.Dir(absPath),
 	})
 	if err != nil {
-		// Print the error to stderr for better user experience
+		// Print the error to stderr for better user experience. Parse errors,
+		// including those in imported files, are shown with the offending line.
+		var parseErr *interpreter.ParseError
+		if errors.As(err, &parseErr) {
+			if parseErr.Path == "" {
+				parseErr.Path = filePath
+			}
+			fmt.Fprintf(os.Stderr, "Parse error:\n%s", parseErr.Render())
+			return fmt.Errorf("parse error: %w", err)
+		}
 		fmt.Fprintf(os.Stderr, "Runtime error: %v\n", err)
 		return fmt.Errorf("runtime error: %w", err)
 	}
@@ -575,25 +1242,105 @@ func runGshScript(ctx context.Context, filePath string, logger *zap.Logger, logL
 	return nil
 }
 
-func initializeLogger(runner *interp.Runner) (*zap.Logger, zap.AtomicLevel, error) {
+// readGshScript reads a .gsh script, returning its absolute path (for import resolution)
+// and its source with any shebang line blanked out
+func readGshScript(filePath string) (string, string, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve script path: %w", err)
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read script file: %w", err)
+	}
+
+	return absPath, interpreter.StripShebang(string(content)), nil
+}
+
+// runCheck implements --check: it parses and statically validates a .gsh script without
+// running it, so it needs no API keys or MCP servers. Problems are listed on stderr and
+// the exit code is 1 if there are any.
+func runCheck(filePath string, stdout, stderr io.Writer) int {
+	absPath, script, err := readGshScript(filePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gsh: %v\n", err)
+		return 1
+	}
+
+	gshInterp := interpreter.New(&interpreter.Options{Version: BUILD_VERSION})
+	defer gshInterp.Close()
+
+	problems, err := gshInterp.CheckString(script, &interpreter.ScriptOrigin{
+		Type:     interpreter.OriginFilesystem,
+		BasePath: filepath.Dir(absPath),
+	})
+	if err != nil {
+		var parseErr *interpreter.ParseError
+		if errors.As(err, &parseErr) {
+			if parseErr.Path == "" {
+				parseErr.Path = filePath
+			}
+			fmt.Fprintf(stderr, "Parse error:\n%s", parseErr.Render())
+			return 1
+		}
+		fmt.Fprintf(stderr, "gsh: %v\n", err)
+		return 1
+	}
+
+	for _, problem := range problems {
+		if problem.Path == "" {
+			problem.Path = filePath
+		}
+		fmt.Fprintln(stderr, problem.String())
+	}
+	if len(problems) > 0 {
+		noun := "problems"
+		if len(problems) == 1 {
+			noun = "problem"
+		}
+		fmt.Fprintf(stderr, "%d %s found\n", len(problems), noun)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: no problems found\n", filePath)
+	return 0
+}
+
+// resolveLogFile returns the absolute path logs should go to: the --log-file flag if
+// given, then GSH_LOG_FILE, then the default ~/.gsh/gsh.log.
+func resolveLogFile(runner *interp.Runner, flagValue string) string {
+	logFile := flagValue
+	if logFile == "" {
+		logFile = environment.GetLogFile(runner)
+	}
+	if logFile == "" {
+		return core.LogFile()
+	}
+	if abs, err := filepath.Abs(logFile); err == nil {
+		return abs
+	}
+	return logFile
+}
+
+func initializeLogger(runner *interp.Runner, logFile string) (*zap.Logger, zap.AtomicLevel, error) {
 	logLevel := environment.GetLogLevel(runner)
-	if BUILD_VERSION == "dev" {
+	if BUILD_VERSION == "dev" || environment.ShouldTraceModel(runner) {
 		logLevel = zap.NewAtomicLevelAt(zap.DebugLevel)
 	}
 
 	if environment.ShouldCleanLogFile(runner) {
-		os.Remove(core.LogFile())
+		os.Remove(logFile)
 	}
 
 	// Initialize the logger
 	loggerConfig := zap.NewProductionConfig()
 	loggerConfig.Level = logLevel
 	loggerConfig.OutputPaths = []string{
-		core.LogFile(),
+		logFile,
 	}
 
 	// In dev builds, logs only go to file to avoid interfering with Bubble Tea UI
-	// Use `tail -f ~/.gsh/gsh.log` to monitor logs in real-time
+	// Use `tail -f ~/.gsh/gsh.log` (or the --log-file path) to monitor logs in real-time
 
 	logger, err := loggerConfig.Build()
 	if err != nil {
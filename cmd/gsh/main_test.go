@@ -3,12 +3,20 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/kunchenguid/gsh/internal/core"
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
 	"go.uber.org/zap"
 	"mvdan.cc/sh/v3/expand"
 	"mvdan.cc/sh/v3/interp"
@@ -102,7 +110,13 @@ func TestHelpText(t *testing.T) {
 		// Commands
 		{"has run command", "run <script>", "Should document run command"},
 		{"has telemetry command", "telemetry", "Should document telemetry command"},
+		{"has trust command", "trust [dir]", "Should document trust command"},
 		{"has login shell", "--login", "Should document login shell flag"},
+		{"has acp flag", "--acp", "Should document ACP server flag"},
+		{"has stdin-prompt flag", "--stdin-prompt", "Should document stdin-prompt flag"},
+		{"has clear-cache flag", "--clear-cache", "Should document clear-cache flag"},
+		{"has eval flag", "--eval <expression>", "Should document eval flag"},
+		{"has log-file flag", "--log-file <path>", "Should document log-file flag"},
 
 		// Examples
 		{"has examples section", "EXAMPLES:", "Should have examples section"},
@@ -158,6 +172,288 @@ func TestParseREPLOptions_DashC(t *testing.T) {
 	})
 }
 
+// TestParseREPLOptions_NoUpdateCheck tests --no-update-check flag parsing
+func TestParseREPLOptions_NoUpdateCheck(t *testing.T) {
+	if opts := parseREPLOptions([]string{}); opts.noUpdateCheck {
+		t.Error("noUpdateCheck should default to false")
+	}
+	if opts := parseREPLOptions([]string{"--no-update-check", "-l"}); !opts.noUpdateCheck || !opts.login {
+		t.Errorf("expected noUpdateCheck and login to be true, got %+v", opts)
+	}
+}
+
+// TestParseREPLOptions_ACP tests --acp flag parsing
+func TestParseREPLOptions_ACP(t *testing.T) {
+	if opts := parseREPLOptions([]string{}); opts.acp {
+		t.Error("acp should default to false")
+	}
+	if opts := parseREPLOptions([]string{"--acp", "--repl-config", "cfg.gsh"}); !opts.acp || opts.replConfig != "cfg.gsh" {
+		t.Errorf("expected acp with replConfig cfg.gsh, got %+v", opts)
+	}
+}
+
+// TestParseREPLOptions_StdinPrompt tests --stdin-prompt flag parsing
+func TestParseREPLOptions_StdinPrompt(t *testing.T) {
+	if opts := parseREPLOptions([]string{}); opts.stdinPrompt {
+		t.Error("stdinPrompt should default to false")
+	}
+	if opts := parseREPLOptions([]string{"--stdin-prompt", "--repl-config", "cfg.gsh"}); !opts.stdinPrompt || opts.replConfig != "cfg.gsh" {
+		t.Errorf("expected stdinPrompt with replConfig cfg.gsh, got %+v", opts)
+	}
+}
+
+// TestParseRunOptions tests the run subcommand's --record and --replay flags
+func TestParseRunOptions(t *testing.T) {
+	opts, err := parseRunOptions([]string{"--record", "s.json", "triage.gsh", "--replay", "x"})
+	if err != nil || opts.record != "s.json" || opts.scriptPath != "triage.gsh" || opts.replay != "" {
+		t.Errorf("expected record s.json for triage.gsh, got %+v (%v)", opts, err)
+	}
+	if len(opts.scriptArgs) != 2 || opts.scriptArgs[0] != "--replay" {
+		t.Errorf("flags after the script should be script args, got %v", opts.scriptArgs)
+	}
+
+	opts, err = parseRunOptions([]string{"--replay=s.json"})
+	if err != nil || opts.replay != "s.json" || opts.scriptPath != "" {
+		t.Errorf("expected replay s.json without a script, got %+v (%v)", opts, err)
+	}
+
+	opts, err = parseRunOptions([]string{"--trace", "agent.gsh", "--trace"})
+	if err != nil || !opts.trace || opts.scriptPath != "agent.gsh" || len(opts.scriptArgs) != 1 {
+		t.Errorf("expected trace for agent.gsh with --trace as a script arg, got %+v (%v)", opts, err)
+	}
+
+	if _, err := parseRunOptions([]string{"--record"}); err == nil {
+		t.Error("expected an error for --record without a file")
+	}
+	if _, err := parseRunOptions([]string{"--record", "a.json", "--replay", "b.json", "x.gsh"}); err == nil {
+		t.Error("expected an error for --record with --replay")
+	}
+}
+
+// TestParseREPLOptions_ClearCache tests --clear-cache flag parsing
+func TestParseREPLOptions_ClearCache(t *testing.T) {
+	if opts := parseREPLOptions([]string{}); opts.clearCache {
+		t.Error("clearCache should default to false")
+	}
+	if opts := parseREPLOptions([]string{"--clear-cache"}); !opts.clearCache {
+		t.Error("expected clearCache to be true")
+	}
+}
+
+// TestParseREPLOptions_Eval tests --eval flag parsing
+func TestParseREPLOptions_Eval(t *testing.T) {
+	if opts := parseREPLOptions([]string{}); opts.hasEval {
+		t.Error("hasEval should default to false")
+	}
+	if opts := parseREPLOptions([]string{"--eval", "1 + 2"}); !opts.hasEval || opts.eval != "1 + 2" {
+		t.Errorf("expected eval %q, got %+v", "1 + 2", opts)
+	}
+	if opts := parseREPLOptions([]string{"--eval=Math.PI"}); !opts.hasEval || opts.eval != "Math.PI" {
+		t.Errorf("expected eval %q, got %+v", "Math.PI", opts)
+	}
+}
+
+func TestParseREPLOptions_LogFile(t *testing.T) {
+	if opts := parseREPLOptions([]string{"--log-file", "/tmp/a.log"}); opts.logFile != "/tmp/a.log" {
+		t.Errorf("expected log file %q, got %q", "/tmp/a.log", opts.logFile)
+	}
+	if opts := parseREPLOptions([]string{"--log-file=/tmp/b.log", "-c", "true"}); opts.logFile != "/tmp/b.log" {
+		t.Errorf("expected log file %q, got %q", "/tmp/b.log", opts.logFile)
+	}
+}
+
+// TestResolveLogFile tests that --log-file wins over GSH_LOG_FILE, which wins over the default
+func TestResolveLogFile(t *testing.T) {
+	runner := newTestRunner(t)
+	if runner.Vars == nil {
+		runner.Vars = make(map[string]expand.Variable)
+	}
+
+	if got := resolveLogFile(runner, ""); got != core.LogFile() {
+		t.Errorf("expected default log file %q, got %q", core.LogFile(), got)
+	}
+
+	runner.Vars["GSH_LOG_FILE"] = expand.Variable{Kind: expand.String, Str: "/tmp/env.log"}
+	if got := resolveLogFile(runner, ""); got != "/tmp/env.log" {
+		t.Errorf("expected GSH_LOG_FILE path, got %q", got)
+	}
+	if got := resolveLogFile(runner, "/tmp/flag.log"); got != "/tmp/flag.log" {
+		t.Errorf("expected --log-file path, got %q", got)
+	}
+
+	wd, _ := os.Getwd()
+	if got := resolveLogFile(runner, "session.log"); got != filepath.Join(wd, "session.log") {
+		t.Errorf("expected relative path to be made absolute, got %q", got)
+	}
+}
+
+// TestRunEval tests evaluating a single expression with --eval
+func TestRunEval(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{"arithmetic", "1 + 2 * Math.sqrt(16)", 0, "9\n", ""},
+		{"string", `"a" + "b"`, 0, "ab\n", ""},
+		{"null", "null", 0, "null\n", ""},
+		{"runtime error", "undefinedVar", 1, "", "Runtime error: undefined variable"},
+		{"parse error", "1 +", 1, "", "Parse error:"},
+		{"statement", "x = 1", 1, "", "expects an expression"},
+		{"multiple expressions", "1\n2", 1, "", "single expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			code := runEval(tt.expression, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.wantCode, code, stderr.String())
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("expected stdout %q, got %q", tt.wantStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestParseREPLOptions_Check(t *testing.T) {
+	if opts := parseREPLOptions([]string{"--check", "a.gsh"}); opts.check != "a.gsh" {
+		t.Errorf("expected check %q, got %q", "a.gsh", opts.check)
+	}
+	if opts := parseREPLOptions([]string{"--check=b.gsh"}); opts.check != "b.gsh" {
+		t.Errorf("expected check %q, got %q", "b.gsh", opts.check)
+	}
+}
+
+// TestRunCheck tests validating a script with --check
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		script     string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{"valid", "#!/usr/bin/env gsh\nx = 1\nprint(x)\n", 0, "no problems found", ""},
+		{"problems", "#!/usr/bin/env gsh\nprint(y)\n", 1, "", "undefined variable: y\n1 problem found"},
+		{"parse error", "x = (1 + )\n", 1, "", "Parse error:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".gsh")
+			if err := os.WriteFile(path, []byte(tt.script), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr strings.Builder
+			code := runCheck(path, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.wantCode, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tt.wantStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		if code := runCheck(filepath.Join(dir, "missing.gsh"), &stdout, &stderr); code != 1 {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+	})
+}
+
+// TestParseREPLOptions_CommandFile tests --command-file and --keep-going flag parsing
+func TestParseREPLOptions_CommandFile(t *testing.T) {
+	t.Run("--command-file path", func(t *testing.T) {
+		opts := parseREPLOptions([]string{"--command-file", "cmds.txt"})
+		if opts.commandFile != "cmds.txt" {
+			t.Errorf("expected commandFile %q, got %q", "cmds.txt", opts.commandFile)
+		}
+		if opts.keepGoing {
+			t.Error("keepGoing should be false")
+		}
+	})
+
+	t.Run("--command-file= with --keep-going", func(t *testing.T) {
+		opts := parseREPLOptions([]string{"--keep-going", "--command-file=cmds.txt"})
+		if opts.commandFile != "cmds.txt" {
+			t.Errorf("expected commandFile %q, got %q", "cmds.txt", opts.commandFile)
+		}
+		if !opts.keepGoing {
+			t.Error("keepGoing should be true")
+		}
+	})
+}
+
+// TestRunCommandLines tests running newline-separated commands
+func TestRunCommandLines(t *testing.T) {
+	run := func(t *testing.T, script string, keepGoing bool) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		runner, err := interp.New(interp.StdIO(nil, &stdout, &stdout))
+		if err != nil {
+			t.Fatalf("failed to create runner: %v", err)
+		}
+		err = runCommandLines(context.Background(), runner, strings.NewReader(script), "cmds.txt", keepGoing)
+		return stdout.String(), err
+	}
+
+	t.Run("runs every line and keeps state", func(t *testing.T) {
+		out, err := run(t, "X=hello\n\necho $X\n# comment\necho done\n", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "hello\ndone\n" {
+			t.Errorf("unexpected output %q", out)
+		}
+	})
+
+	t.Run("stops on first failure", func(t *testing.T) {
+		out, err := run(t, "echo one\n(exit 3)\necho two\n", false)
+		var exitStatus interp.ExitStatus
+		if !errors.As(err, &exitStatus) || exitStatus != 3 {
+			t.Fatalf("expected exit status 3, got %v", err)
+		}
+		if out != "one\n" {
+			t.Errorf("unexpected output %q", out)
+		}
+	})
+
+	t.Run("keep going returns last failure", func(t *testing.T) {
+		out, err := run(t, "(exit 3)\necho one\n(exit 4)\necho two\n", true)
+		var exitStatus interp.ExitStatus
+		if !errors.As(err, &exitStatus) || exitStatus != 4 {
+			t.Fatalf("expected exit status 4, got %v", err)
+		}
+		if out != "one\ntwo\n" {
+			t.Errorf("unexpected output %q", out)
+		}
+	})
+
+	t.Run("exit stops execution", func(t *testing.T) {
+		out, err := run(t, "echo one\nexit 0\necho two\n", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "one\n" {
+			t.Errorf("unexpected output %q", out)
+		}
+	})
+}
+
 // TestContainsHelpFlag_DashC tests that -c stops help flag scanning
 func TestContainsHelpFlag_DashC(t *testing.T) {
 	t.Run("--help before -c", func(t *testing.T) {
@@ -188,6 +484,57 @@ func TestContainsVersionFlag_DashC(t *testing.T) {
 	})
 }
 
+// TestPrintVersion tests plain and JSON version output
+func TestPrintVersion(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := printVersion(&out, false); err != nil {
+			t.Fatalf("printVersion failed: %v", err)
+		}
+		if out.String() != BUILD_VERSION+"\n" {
+			t.Errorf("output = %q, want %q", out.String(), BUILD_VERSION+"\n")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		oldCommit, oldDate := BUILD_COMMIT, BUILD_DATE
+		BUILD_COMMIT, BUILD_DATE = "abc123", "2024-01-02T03:04:05Z"
+		defer func() { BUILD_COMMIT, BUILD_DATE = oldCommit, oldDate }()
+
+		var out bytes.Buffer
+		if err := printVersion(&out, true); err != nil {
+			t.Fatalf("printVersion failed: %v", err)
+		}
+
+		var info map[string]string
+		if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+		}
+		want := map[string]string{
+			"version":   BUILD_VERSION,
+			"goVersion": runtime.Version(),
+			"os":        runtime.GOOS,
+			"arch":      runtime.GOARCH,
+			"buildDate": "2024-01-02T03:04:05Z",
+			"commit":    "abc123",
+		}
+		for key, value := range want {
+			if info[key] != value {
+				t.Errorf("%s = %q, want %q", key, info[key], value)
+			}
+		}
+	})
+
+	t.Run("--json after -c is command not flag", func(t *testing.T) {
+		if !containsFlag([]string{"--version", "--JSON"}, "--json") {
+			t.Error("should find --json")
+		}
+		if containsFlag([]string{"-c", "--json"}, "--json") {
+			t.Error("should not treat --json after -c as a flag")
+		}
+	})
+}
+
 // TestHelpTextStructure tests the overall structure and formatting of help text
 func TestHelpTextStructure(t *testing.T) {
 	t.Run("sections are in logical order", func(t *testing.T) {
@@ -304,6 +651,276 @@ func captureStdout(fn func()) string {
 	return buf.String()
 }
 
+func TestDefaultReadyHandler_WelcomeMessage(t *testing.T) {
+	readyScript, err := defaultConfigFS.ReadFile("defaults/events/ready.gsh")
+	if err != nil {
+		t.Fatalf("failed to read ready.gsh: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "default shows a tip",
+			config:   "",
+			contains: []string{"The G Shell", "tip: "},
+		},
+		{
+			name:     "string message replaces the tip",
+			config:   `gsh.welcomeMessage = "Remember to stand up"`,
+			contains: []string{"The G Shell", "Remember to stand up"},
+			excludes: []string{"tip: "},
+		},
+		{
+			name: "tool message receives the ready context",
+			config: `
+tool motd(ctx) {
+	return "Working in " + ctx.cwd + " on " + ctx.hostname
+}
+gsh.welcomeMessage = motd`,
+			contains: []string{"Working in /work/project on devbox"},
+			excludes: []string{"tip: "},
+		},
+		{
+			name:     "welcome can be turned off",
+			config:   `gsh.showWelcome = false`,
+			excludes: []string{"The G Shell", "tip: "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := interpreter.New(nil)
+			defer interp.Close()
+			interp.SDKConfig().SetREPLContext(&interpreter.REPLContext{
+				LastCommand: &interpreter.REPLLastCommand{},
+				ShowWelcome: true,
+			})
+
+			if _, err := interp.EvalString(string(readyScript)+"\n"+tt.config, nil); err != nil {
+				t.Fatalf("failed to evaluate ready.gsh: %v", err)
+			}
+
+			output := captureStdout(func() {
+				interp.EmitEvent(interpreter.EventReplReady, interpreter.CreateReplReadyContext("/work/project", "devbox"))
+			})
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("expected output not to contain %q, got:\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultAgentHandlers_RenderMarkdown(t *testing.T) {
+	agentScript, err := defaultConfigFS.ReadFile("defaults/events/agent.gsh")
+	if err != nil {
+		t.Fatalf("failed to read agent.gsh: %v", err)
+	}
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	interp.SDKConfig().SetREPLContext(&interpreter.REPLContext{LastCommand: &interpreter.REPLLastCommand{}})
+
+	setup := `
+gsh.renderMarkdown = true
+tool noop(ctx) { return null }
+chunk = { agent: { name: "gsh", metadata: {} }, content: "" }
+`
+	if _, err := interp.EvalString(string(agentScript)+"\n"+setup, nil); err != nil {
+		t.Fatalf("failed to evaluate agent.gsh: %v", err)
+	}
+
+	eval := func(code string) string {
+		return captureStdout(func() {
+			if _, err := interp.EvalString(code, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// Chunks are held back while the response streams
+	output := eval(`
+chunk.content = "# Title\n\n"
+onChunk(chunk, noop)
+chunk.content = "- **item**\n"
+onChunk(chunk, noop)
+`)
+	if output != "" {
+		t.Errorf("expected chunks to be held back, got %q", output)
+	}
+
+	// Stdout isn't a terminal here, so the response is printed as Markdown source
+	if output := eval(`flushMarkdown()`); output != "# Title\n\n- **item**\n" {
+		t.Errorf("expected the held back response, got %q", output)
+	}
+	if output := eval(`flushMarkdown()`); output != "" {
+		t.Errorf("expected nothing left to print, got %q", output)
+	}
+}
+
+func TestDefaultAgentHandlers_ShowThinking(t *testing.T) {
+	agentScript, err := defaultConfigFS.ReadFile("defaults/events/agent.gsh")
+	if err != nil {
+		t.Fatalf("failed to read agent.gsh: %v", err)
+	}
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	replCtx := &interpreter.REPLContext{LastCommand: &interpreter.REPLLastCommand{}}
+	interp.SDKConfig().SetREPLContext(replCtx)
+
+	setup := `
+tool noop(ctx) { return null }
+chunk = { agent: { name: "gsh", metadata: {} }, content: "" }
+`
+	if _, err := interp.EvalString(string(agentScript)+"\n"+setup, nil); err != nil {
+		t.Fatalf("failed to evaluate agent.gsh: %v", err)
+	}
+
+	eval := func(code string) string {
+		return captureStdout(func() {
+			if _, err := interp.EvalString(code, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+	think := `
+onIterationStart(chunk, noop)
+chunk.content = "\nThe user wants "
+onThinking(chunk, noop)
+chunk.content = "a greeting."
+onThinking(chunk, noop)
+chunk.content = "Hello!"
+onChunk(chunk, noop)
+`
+
+	// Collapsed by default: only the answer is printed
+	if output := eval(think); output != "Hello!" {
+		t.Errorf("expected reasoning to stay hidden, got %q", output)
+	}
+
+	// Stdout isn't a terminal here, so the reasoning is printed unstyled
+	replCtx.ShowThinking = true
+	if output := eval(think); output != "thinking\nThe user wants a greeting.\n\nHello!" {
+		t.Errorf("expected reasoning above the answer, got %q", output)
+	}
+}
+
+func TestDefaultPromptHandler_KeepsPromptTool(t *testing.T) {
+	starshipScript, err := defaultConfigFS.ReadFile("defaults/starship.gsh")
+	if err != nil {
+		t.Fatalf("failed to read starship.gsh: %v", err)
+	}
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	replCtx := &interpreter.REPLContext{LastCommand: &interpreter.REPLLastCommand{}}
+	interp.SDKConfig().SetREPLContext(replCtx)
+
+	if _, err := interp.EvalString(string(starshipScript), nil); err != nil {
+		t.Fatalf("failed to evaluate starship.gsh: %v", err)
+	}
+	interp.EmitEvent(interpreter.EventReplPrompt, interpreter.CreateReplPromptContext(0, 0))
+	if _, ok := replCtx.PromptValue.(*interpreter.StringValue); !ok {
+		t.Fatalf("expected the default handler to set a prompt string, got %v", replCtx.PromptValue)
+	}
+
+	if _, err := interp.EvalString(`tool myPrompt() { return "mine> " }
+gsh.prompt = myPrompt`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interp.EmitEvent(interpreter.EventReplPrompt, interpreter.CreateReplPromptContext(0, 0))
+	if _, ok := replCtx.PromptValue.(*interpreter.ToolValue); !ok {
+		t.Errorf("expected the prompt tool to be kept, got %v", replCtx.PromptValue)
+	}
+}
+
+func TestDefaultAgentMiddleware_PromptSuffix(t *testing.T) {
+	t.Setenv("GSH_RESPONSE_CACHE", "")
+	middlewareScript, err := defaultConfigFS.ReadFile("defaults/middleware/agent.gsh")
+	if err != nil {
+		t.Fatalf("failed to read middleware/agent.gsh: %v", err)
+	}
+
+	var systemPrompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		for _, message := range body.Messages {
+			if message.Role == "system" && len(message.Content) > 0 {
+				systemPrompts = append(systemPrompts, message.Content[0].Text)
+			}
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	interp.SDKConfig().SetREPLContext(&interpreter.REPLContext{})
+
+	setup := fmt.Sprintf(`
+model testModel {
+	provider: "openai",
+	apiKey: "test",
+	model: "test",
+	baseURL: %q,
+}
+gsh.models.workhorse = testModel
+`, server.URL)
+	if _, err := interp.EvalString(setup+string(middlewareScript), nil); err != nil {
+		t.Fatalf("failed to evaluate middleware/agent.gsh: %v", err)
+	}
+
+	turn := `
+tool next(ctx) { return null }
+__defaultAgentMiddleware({ input: "# hi" }, next)
+`
+	if _, err := interp.EvalString(turn, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := interp.EvalString(`gsh.defaultAgentPromptSuffix = "  Answer in French.  "`+"\n"+turn, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Addressing a model keeps the default agent's prompt, suffix included
+	if _, err := interp.EvalString(`__defaultAgentMiddleware({ input: "# @testModel hi" }, next)`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(systemPrompts) != 3 {
+		t.Fatalf("expected 3 requests with a system prompt, got %d", len(systemPrompts))
+	}
+	if strings.Contains(systemPrompts[0], "French") {
+		t.Errorf("expected no suffix before it is set, got %q", systemPrompts[0])
+	}
+	if want := systemPrompts[0] + "\n\nAnswer in French."; systemPrompts[1] != want {
+		t.Errorf("expected the built-in prompt with the suffix appended, got %q", systemPrompts[1])
+	}
+	if systemPrompts[2] != systemPrompts[1] {
+		t.Errorf("expected the model mention to use the same prompt, got %q", systemPrompts[2])
+	}
+}
+
 func TestIsGshScript(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -357,7 +974,7 @@ print("Result: " + z)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -384,7 +1001,7 @@ print(greeting)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -412,7 +1029,7 @@ print(count)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -442,7 +1059,7 @@ print(result)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -469,7 +1086,7 @@ print(x)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -493,7 +1110,7 @@ y = 10
 		}
 
 		ctx := context.Background()
-		err := runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+		err := runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		if err == nil {
 			t.Error("Expected error for syntax error, got nil")
 		}
@@ -502,7 +1119,7 @@ y = 10
 	t.Run("nonexistent file", func(t *testing.T) {
 		scriptPath := filepath.Join(tmpDir, "nonexistent.gsh")
 		ctx := context.Background()
-		err := runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+		err := runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent file, got nil")
 		}
@@ -517,7 +1134,7 @@ y = 10
 		}
 
 		ctx := context.Background()
-		err := runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+		err := runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		if err == nil {
 			t.Error("Expected runtime error, got nil")
 		}
@@ -541,7 +1158,7 @@ print(result)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -569,7 +1186,7 @@ print(obj.name)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -595,7 +1212,7 @@ print(obj.name)
 		ctx := context.Background()
 		var err error
 		output := captureStdout(func() {
-			err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if err != nil {
@@ -678,7 +1295,7 @@ func TestShebangSupport(t *testing.T) {
 			ctx := context.Background()
 			var err error
 			output := captureStdout(func() {
-				err = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+				err = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 			})
 
 			if (err != nil) != tt.wantErr {
@@ -909,7 +1526,7 @@ result = outer()`,
 			ctx := context.Background()
 			var execErr error
 			stderr := captureStderr(func() {
-				execErr = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+				execErr = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 			})
 
 			if tt.expectError && execErr == nil {
@@ -955,7 +1572,7 @@ func TestRunInteractiveShell(t *testing.T) {
 
 		// Should return immediately with context error
 		// Pass zero time, nil tracker, and empty config path since we don't need telemetry for this test
-		err = runInteractiveShell(ctx, logger, runner, time.Time{}, nil, "")
+		err = runInteractiveShell(ctx, logger, "", runner, time.Time{}, nil, "")
 		if err == nil {
 			t.Error("Expected context cancellation error, got nil")
 		}
@@ -992,7 +1609,7 @@ w = 20`
 		ctx := context.Background()
 		var execErr error
 		stderr := captureStderr(func() {
-			execErr = runGshScript(ctx, scriptPath, logger, logLevel, newTestRunner(t))
+			execErr = runGshScript(ctx, scriptPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if execErr == nil {
@@ -1007,6 +1624,32 @@ w = 20`
 		}
 	})
 
+	t.Run("parse error points at the offending source", func(t *testing.T) {
+		script := "#!/usr/bin/env gsh\nx = (1 + )\n"
+		scriptPath := filepath.Join(tmpDir, "caret.gsh")
+		if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+			t.Fatalf("Failed to write test script: %v", err)
+		}
+
+		var execErr error
+		stderr := captureStderr(func() {
+			execErr = runGshScript(context.Background(), scriptPath, logger, logLevel, "", newTestRunner(t), nil)
+		})
+		if execErr == nil {
+			t.Fatal("Expected parse error")
+		}
+
+		if !strings.Contains(stderr, scriptPath+":2:") {
+			t.Errorf("Expected file:line:column prefix pointing at line 2. Stderr: %s", stderr)
+		}
+		if !strings.Contains(stderr, " 2 | x = (1 + )\n") || !strings.Contains(stderr, "^") {
+			t.Errorf("Expected source snippet with caret. Stderr: %s", stderr)
+		}
+		if strings.Contains(stderr, "Runtime error") {
+			t.Errorf("Parse error should not be labeled as a runtime error. Stderr: %s", stderr)
+		}
+	})
+
 	t.Run("runtime error is distinguishable from parse error", func(t *testing.T) {
 		parseErrorScript := `x = `
 		parseErrorPath := filepath.Join(tmpDir, "parse_err.gsh")
@@ -1025,13 +1668,13 @@ w = 20`
 		// Parse error
 		var parseErr error
 		parseStderr := captureStderr(func() {
-			parseErr = runGshScript(ctx, parseErrorPath, logger, logLevel, newTestRunner(t))
+			parseErr = runGshScript(ctx, parseErrorPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		// Runtime error
 		var runtimeErr error
 		runtimeStderr := captureStderr(func() {
-			runtimeErr = runGshScript(ctx, runtimeErrorPath, logger, logLevel, newTestRunner(t))
+			runtimeErr = runGshScript(ctx, runtimeErrorPath, logger, logLevel, "", newTestRunner(t), nil)
 		})
 
 		if parseErr == nil || runtimeErr == nil {
@@ -1056,7 +1699,7 @@ w = 20`
 		nonExistentPath := filepath.Join(tmpDir, "does_not_exist.gsh")
 
 		ctx := context.Background()
-		err := runGshScript(ctx, nonExistentPath, logger, logLevel, newTestRunner(t))
+		err := runGshScript(ctx, nonExistentPath, logger, logLevel, "", newTestRunner(t), nil)
 
 		if err == nil {
 			t.Error("Expected file read error")
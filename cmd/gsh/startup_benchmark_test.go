@@ -51,7 +51,7 @@ func BenchmarkStartupInitialization(b *testing.B) {
 			b.Fatalf("failed to initialize runner: %v", err)
 		}
 
-		logger, _, err := initializeLogger(runner)
+		logger, _, err := initializeLogger(runner, resolveLogFile(runner, ""))
 		if err != nil {
 			b.Fatalf("failed to initialize logger: %v", err)
 		}
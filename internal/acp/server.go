@@ -0,0 +1,284 @@
+package acp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// MethodSessionCancel is the notification a client sends to cancel an in-flight prompt.
+const MethodSessionCancel = "session/cancel"
+
+// JSON-RPC 2.0 error codes used by the server.
+const (
+	ErrorCodeParse          = -32700
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+	ErrorCodeInternal       = -32603
+)
+
+// Agent is implemented by whatever the Server exposes over ACP.
+type Agent interface {
+	// NewSession starts a session rooted at cwd and returns its ID.
+	NewSession(ctx context.Context, cwd string) (string, error)
+
+	// Prompt runs one prompt turn. Progress is reported through send, which
+	// the server forwards to the client as session/update notifications.
+	Prompt(ctx context.Context, sessionID string, prompt []PromptContent, send func(SessionUpdatePayload)) (StopReason, error)
+}
+
+// Server serves an Agent over the Agent Client Protocol, reading newline-delimited
+// JSON-RPC messages from a client and writing responses and notifications back.
+// It is the counterpart of Client: Client drives ACP agents, Server makes gsh one.
+type Server struct {
+	agent  Agent
+	logger *zap.Logger
+
+	// writeMu serializes writes so responses and notifications never interleave
+	writeMu sync.Mutex
+	out     io.Writer
+
+	// cancels holds the cancel function of the running prompt turn per session
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// serverRequest is an incoming request or notification. The ID is kept raw since
+// clients may use numbers or strings; a missing ID marks a notification.
+type serverRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// serverResponse is an outgoing response echoing the request's raw ID.
+type serverResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+// sessionCancelParams represents the parameters for the session/cancel notification.
+type sessionCancelParams struct {
+	SessionID string `json:"sessionId"`
+}
+
+// NewServer creates a server exposing agent. If logger is nil, a no-op logger is used.
+func NewServer(agent Agent, logger *zap.Logger) *Server {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Server{
+		agent:   agent,
+		logger:  logger,
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Serve handles messages from in until it reaches EOF or ctx is cancelled.
+// Prompt turns run concurrently so that session/cancel can interrupt them;
+// Serve lets running turns finish and respond before returning.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	defer s.wg.Wait()
+
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			s.handleLine(ctx, line)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("read error: %w", err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// handleLine dispatches a single JSON-RPC message
+func (s *Server) handleLine(ctx context.Context, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	var req serverRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		s.logger.Debug("ACP server received malformed line", zap.ByteString("line", line))
+		s.sendError(json.RawMessage("null"), ErrorCodeParse, "parse error")
+		return
+	}
+
+	s.logger.Debug("ACP server received message", zap.String("method", req.Method))
+
+	switch req.Method {
+	case MethodInitialize:
+		s.sendResult(req.ID, InitializeResult{
+			ProtocolVersion: 1,
+			AgentCapabilities: AgentCapabilities{
+				PromptCapabilities: &PromptCapabilities{EmbeddedContext: true},
+			},
+		})
+	case MethodSessionNew:
+		var params SessionNewParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.sendError(req.ID, ErrorCodeInvalidParams, fmt.Sprintf("invalid session/new params: %v", err))
+			return
+		}
+		sessionID, err := s.agent.NewSession(ctx, params.Cwd)
+		if err != nil {
+			s.sendError(req.ID, ErrorCodeInternal, err.Error())
+			return
+		}
+		s.sendResult(req.ID, SessionNewResult{SessionID: sessionID})
+	case MethodSessionPrompt:
+		var params SessionPromptParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.sendError(req.ID, ErrorCodeInvalidParams, fmt.Sprintf("invalid session/prompt params: %v", err))
+			return
+		}
+		s.startPrompt(ctx, req.ID, params)
+	case MethodSessionCancel:
+		var params sessionCancelParams
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			s.mu.Lock()
+			if cancel, ok := s.cancels[params.SessionID]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+		}
+	default:
+		// Notifications never get a response, even for unknown methods
+		if len(req.ID) > 0 {
+			s.sendError(req.ID, ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method))
+		}
+	}
+}
+
+// startPrompt runs a prompt turn in the background and responds when it ends
+func (s *Server) startPrompt(ctx context.Context, id json.RawMessage, params SessionPromptParams) {
+	promptCtx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	if _, busy := s.cancels[params.SessionID]; busy {
+		s.mu.Unlock()
+		cancel()
+		s.sendError(id, ErrorCodeInvalidParams, fmt.Sprintf("session %s already has a prompt in progress", params.SessionID))
+		return
+	}
+	s.cancels[params.SessionID] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.cancels, params.SessionID)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		send := func(update SessionUpdatePayload) {
+			s.sendNotification(MethodSessionUpdate, SessionUpdateParams{
+				SessionID: params.SessionID,
+				Update:    update,
+			})
+		}
+
+		stopReason, err := s.agent.Prompt(promptCtx, params.SessionID, params.Prompt, send)
+		if promptCtx.Err() != nil && ctx.Err() == nil {
+			// Cancelled by the client: report it as a normal stop, per the protocol
+			s.sendResult(id, SessionPromptResult{StopReason: string(StopReasonCancelled)})
+			return
+		}
+		if err != nil && (stopReason == "" || stopReason == StopReasonError) {
+			s.sendError(id, ErrorCodeInternal, err.Error())
+			return
+		}
+		s.sendResult(id, SessionPromptResult{StopReason: string(stopReason)})
+	}()
+}
+
+func (s *Server) sendResult(id json.RawMessage, result interface{}) {
+	s.write(serverResponse{JSONRPC: JSONRPCVersion, ID: id, Result: result})
+}
+
+func (s *Server) sendError(id json.RawMessage, code int, message string) {
+	s.write(serverResponse{JSONRPC: JSONRPCVersion, ID: id, Error: &JSONRPCError{Code: code, Message: message}})
+}
+
+func (s *Server) sendNotification(method string, params interface{}) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		s.logger.Debug("ACP server failed to marshal notification", zap.Error(err))
+		return
+	}
+	s.write(JSONRPCNotification{JSONRPC: JSONRPCVersion, Method: method, Params: raw})
+}
+
+// write encodes msg as a single line
+func (s *Server) write(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		s.logger.Debug("ACP server failed to marshal message", zap.Error(err))
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		s.logger.Debug("ACP server write failed", zap.Error(err))
+	}
+}
+
+// NewMessageChunkUpdate creates an agent_message_chunk update carrying text.
+func NewMessageChunkUpdate(text string) SessionUpdatePayload {
+	content, _ := json.Marshal(MessageContent{Type: "text", Text: text})
+	return SessionUpdatePayload{
+		SessionUpdate: SessionUpdateAgentMessageChunk,
+		Content:       content,
+	}
+}
+
+// NewToolCallUpdate creates a tool_call update announcing toolCall.
+func NewToolCallUpdate(toolCall ToolCall) SessionUpdatePayload {
+	return SessionUpdatePayload{
+		SessionUpdate: SessionUpdateToolCall,
+		ToolCallID:    toolCall.ID,
+		Name:          toolCall.Name,
+		Title:         toolCall.Name,
+		Kind:          string(toolCall.Kind),
+		Status:        string(toolCall.Status),
+		RawInput:      toolCall.Arguments,
+	}
+}
+
+// NewToolCallResultUpdate creates a tool_call_update reporting a finished tool call and its output.
+func NewToolCallResultUpdate(update ToolCallUpdate) SessionUpdatePayload {
+	payload := SessionUpdatePayload{
+		SessionUpdate: SessionUpdateToolCallUpdate,
+		ToolCallID:    update.ID,
+		Status:        string(update.Status),
+	}
+	if update.Content != "" {
+		payload.Content, _ = json.Marshal([]ToolCallContent{
+			{Type: "content", Content: &MessageContent{Type: "text", Text: update.Content}},
+		})
+		payload.RawOutput = update.Content
+	}
+	return payload
+}
@@ -0,0 +1,229 @@
+package acp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeAgent echoes prompts back as a tool call and a message chunk
+type fakeAgent struct {
+	sessions int
+	block    bool
+}
+
+func (a *fakeAgent) NewSession(ctx context.Context, cwd string) (string, error) {
+	if cwd == "" {
+		return "", fmt.Errorf("cwd is required")
+	}
+	a.sessions++
+	return fmt.Sprintf("s%d", a.sessions), nil
+}
+
+func (a *fakeAgent) Prompt(ctx context.Context, sessionID string, prompt []PromptContent, send func(SessionUpdatePayload)) (StopReason, error) {
+	if a.block {
+		<-ctx.Done()
+		return StopReasonCancelled, ctx.Err()
+	}
+	send(NewToolCallUpdate(ToolCall{ID: "call_1", Name: "exec", Kind: ToolKindExecute, Status: ToolCallStatusPending}))
+	send(NewToolCallResultUpdate(ToolCallUpdate{ID: "call_1", Status: ToolCallStatusCompleted, Content: "ok"}))
+	send(NewMessageChunkUpdate("echo: " + prompt[0].Text))
+	return StopReasonEndTurn, nil
+}
+
+// serverHarness runs a Server over pipes and decodes its output line by line
+type serverHarness struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	done   chan error
+	cancel context.CancelFunc
+}
+
+func newServerHarness(t *testing.T, agent Agent) *serverHarness {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h := &serverHarness{t: t, in: inW, out: bufio.NewScanner(outR), done: make(chan error, 1), cancel: cancel}
+	go func() {
+		h.done <- NewServer(agent, nil).Serve(ctx, inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		cancel()
+	})
+	return h
+}
+
+func (h *serverHarness) send(msg string) {
+	if _, err := io.WriteString(h.in, msg+"\n"); err != nil {
+		h.t.Fatalf("write failed: %v", err)
+	}
+}
+
+func (h *serverHarness) read() map[string]json.RawMessage {
+	lines := make(chan []byte, 1)
+	go func() {
+		if h.out.Scan() {
+			lines <- h.out.Bytes()
+		}
+		close(lines)
+	}()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			h.t.Fatal("server output closed")
+		}
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			h.t.Fatalf("invalid JSON from server: %s", line)
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		h.t.Fatal("timed out waiting for server output")
+		return nil
+	}
+}
+
+func TestServer_Initialize(t *testing.T) {
+	h := newServerHarness(t, &fakeAgent{})
+	h.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":1}}`)
+
+	msg := h.read()
+	if string(msg["id"]) != "1" {
+		t.Errorf("expected id 1, got %s", msg["id"])
+	}
+	var result InitializeResult
+	if err := json.Unmarshal(msg["result"], &result); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if result.ProtocolVersion != 1 {
+		t.Errorf("expected protocol version 1, got %d", result.ProtocolVersion)
+	}
+}
+
+func TestServer_SessionPrompt(t *testing.T) {
+	h := newServerHarness(t, &fakeAgent{})
+
+	h.send(`{"jsonrpc":"2.0","id":"new","method":"session/new","params":{"cwd":"/tmp","mcpServers":[]}}`)
+	msg := h.read()
+	if string(msg["id"]) != `"new"` {
+		t.Errorf("expected string id to be echoed, got %s", msg["id"])
+	}
+	var session SessionNewResult
+	if err := json.Unmarshal(msg["result"], &session); err != nil || session.SessionID != "s1" {
+		t.Fatalf("expected session s1, got %s (%v)", msg["result"], err)
+	}
+
+	h.send(`{"jsonrpc":"2.0","id":2,"method":"session/prompt","params":{"sessionId":"s1","prompt":[{"type":"text","text":"hi"}]}}`)
+
+	var updates []SessionUpdatePayload
+	for i := 0; i < 3; i++ {
+		msg := h.read()
+		var method string
+		_ = json.Unmarshal(msg["method"], &method)
+		if method != MethodSessionUpdate {
+			t.Fatalf("expected session/update, got %s", method)
+		}
+		var params SessionUpdateParams
+		if err := json.Unmarshal(msg["params"], &params); err != nil {
+			t.Fatalf("failed to parse update: %v", err)
+		}
+		if params.SessionID != "s1" {
+			t.Errorf("expected session s1, got %s", params.SessionID)
+		}
+		updates = append(updates, params.Update)
+	}
+
+	if updates[0].SessionUpdate != SessionUpdateToolCall || updates[0].ToolCallID != "call_1" || updates[0].Kind != "execute" {
+		t.Errorf("unexpected tool_call update: %+v", updates[0])
+	}
+	if updates[1].SessionUpdate != SessionUpdateToolCallUpdate || updates[1].Status != "completed" {
+		t.Errorf("unexpected tool_call_update: %+v", updates[1])
+	}
+	if content := updates[1].GetToolCallContent(); len(content) != 1 || content[0].Content.Text != "ok" {
+		t.Errorf("unexpected tool output: %+v", content)
+	}
+	if content := updates[2].GetMessageContent(); content == nil || content.Text != "echo: hi" {
+		t.Errorf("unexpected message chunk: %+v", content)
+	}
+
+	msg = h.read()
+	var result SessionPromptResult
+	if err := json.Unmarshal(msg["result"], &result); err != nil {
+		t.Fatalf("failed to parse prompt result: %v", err)
+	}
+	if result.StopReason != "end_turn" {
+		t.Errorf("expected end_turn, got %s", result.StopReason)
+	}
+}
+
+func TestServer_Cancel(t *testing.T) {
+	h := newServerHarness(t, &fakeAgent{block: true})
+
+	h.send(`{"jsonrpc":"2.0","id":1,"method":"session/new","params":{"cwd":"/tmp","mcpServers":[]}}`)
+	h.read()
+
+	h.send(`{"jsonrpc":"2.0","id":2,"method":"session/prompt","params":{"sessionId":"s1","prompt":[{"type":"text","text":"hi"}]}}`)
+	h.send(`{"jsonrpc":"2.0","method":"session/cancel","params":{"sessionId":"s1"}}`)
+
+	msg := h.read()
+	if string(msg["id"]) != "2" {
+		t.Fatalf("expected prompt response, got %v", msg)
+	}
+	var result SessionPromptResult
+	if err := json.Unmarshal(msg["result"], &result); err != nil {
+		t.Fatalf("failed to parse prompt result: %v", err)
+	}
+	if result.StopReason != "cancelled" {
+		t.Errorf("expected cancelled, got %s", result.StopReason)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	h := newServerHarness(t, &fakeAgent{})
+
+	tests := []struct {
+		name    string
+		request string
+		code    int
+	}{
+		{"parse error", `{not json`, ErrorCodeParse},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"session/unknown"}`, ErrorCodeMethodNotFound},
+		{"invalid params", `{"jsonrpc":"2.0","id":2,"method":"session/new","params":[]}`, ErrorCodeInvalidParams},
+		{"agent error", `{"jsonrpc":"2.0","id":3,"method":"session/new","params":{"cwd":"","mcpServers":[]}}`, ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.send(tt.request)
+			msg := h.read()
+			var rpcErr JSONRPCError
+			if err := json.Unmarshal(msg["error"], &rpcErr); err != nil {
+				t.Fatalf("expected error response, got %v", msg)
+			}
+			if rpcErr.Code != tt.code {
+				t.Errorf("expected code %d, got %d (%s)", tt.code, rpcErr.Code, rpcErr.Message)
+			}
+		})
+	}
+}
+
+func TestServer_ExitsOnEOF(t *testing.T) {
+	h := newServerHarness(t, &fakeAgent{})
+	h.in.Close()
+
+	select {
+	case err := <-h.done:
+		if err != nil {
+			t.Errorf("expected nil error on EOF, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit on EOF")
+	}
+}
@@ -0,0 +1,138 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/posthog/posthog-go"
+)
+
+const (
+	// Number of events buffered before new events are dropped
+	sinkBufferSize = 256
+
+	// Maximum time Close waits for buffered events to be delivered
+	sinkFlushTimeout = 2 * time.Second
+)
+
+// Event is a single telemetry event delivered to a Sink
+type Event struct {
+	Name        string         `json:"event"`
+	AnonymousID string         `json:"anonymous_id"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Properties  map[string]any `json:"properties"`
+}
+
+// Sink is a destination for telemetry events (e.g. PostHog, an HTTP endpoint, statsd).
+// Record is always called from a background goroutine, so implementations may block.
+type Sink interface {
+	Record(event Event) error
+}
+
+// posthogSink is the default sink, sending events to PostHog
+type posthogSink struct {
+	client posthog.Client
+}
+
+func (s *posthogSink) Record(event Event) error {
+	return s.client.Enqueue(posthog.Capture{
+		DistinctId: event.AnonymousID,
+		Event:      event.Name,
+		Timestamp:  event.Timestamp,
+		Properties: event.Properties,
+	})
+}
+
+// HTTPSink posts each event as a JSON object to an HTTP endpoint
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPSink creates a sink that posts events to the given URL
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		URL:    url,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Record posts the event to the configured URL
+func (s *HTTPSink) Record(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// bufferedSink queues events and delivers them to a Sink from a background goroutine,
+// so recording an event never blocks the caller
+type bufferedSink struct {
+	mu     sync.Mutex
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+	closed bool
+}
+
+func newBufferedSink(sink Sink) *bufferedSink {
+	b := &bufferedSink{
+		sink:   sink,
+		events: make(chan Event, sinkBufferSize),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *bufferedSink) run() {
+	defer close(b.done)
+	for event := range b.events {
+		// Silent failure - analytics should never break the app
+		_ = b.sink.Record(event)
+	}
+}
+
+// enqueue adds an event to the buffer, dropping it if the buffer is full or closed
+func (b *bufferedSink) enqueue(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.events <- event:
+	default:
+	}
+}
+
+// close stops accepting events and waits (up to sinkFlushTimeout) for buffered
+// events to be delivered
+func (b *bufferedSink) close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.events)
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(sinkFlushTimeout):
+	}
+}
@@ -32,8 +32,9 @@ const (
 	firstRunMarkerFile = "first_run_complete"
 
 	// Environment variables
-	envNoTelemetry    = "GSH_NO_TELEMETRY"
-	envTelemetryDebug = "GSH_TELEMETRY_DEBUG"
+	envNoTelemetry       = "GSH_NO_TELEMETRY"
+	envTelemetryDebug    = "GSH_TELEMETRY_DEBUG"
+	envTelemetryEndpoint = "GSH_TELEMETRY_ENDPOINT"
 )
 
 // Event names - what we track
@@ -61,6 +62,7 @@ const (
 type Client struct {
 	mu          sync.Mutex
 	client      posthog.Client
+	sink        *bufferedSink
 	anonymousID string
 	enabled     bool
 	debugMode   bool
@@ -76,6 +78,10 @@ type Client struct {
 type Config struct {
 	Version string
 	Enabled bool // Override enabled state (for testing)
+
+	// Sink overrides where events are delivered. If nil, events go to the HTTP
+	// endpoint in GSH_TELEMETRY_ENDPOINT if set, otherwise to PostHog.
+	Sink Sink
 }
 
 // NewClient creates a new telemetry client
@@ -97,8 +103,20 @@ func NewClient(cfg Config) (*Client, error) {
 	// Generate or load anonymous ID
 	c.anonymousID = c.getOrCreateAnonymousID()
 
-	// Only create PostHog client if enabled and not in debug mode
-	if c.enabled && !c.debugMode {
+	if !c.enabled || c.debugMode {
+		return c, nil
+	}
+
+	// Use a custom sink if one was provided or configured via environment
+	sink := cfg.Sink
+	if sink == nil {
+		if endpoint := os.Getenv(envTelemetryEndpoint); endpoint != "" {
+			sink = NewHTTPSink(endpoint)
+		}
+	}
+
+	// Otherwise create the default PostHog sink
+	if sink == nil {
 		client, err := posthog.NewWithConfig(
 			posthogAPIKey,
 			posthog.Config{
@@ -115,16 +133,22 @@ func NewClient(cfg Config) (*Client, error) {
 			return c, nil
 		}
 		c.client = client
+		sink = &posthogSink{client: client}
 	}
 
+	c.sink = newBufferedSink(sink)
 	return c, nil
 }
 
 // Close flushes pending events and closes the client
 func (c *Client) Close() error {
+	if c.sink == nil {
+		return nil
+	}
+	// Send session end event with duration, then drain buffered events
+	c.TrackSessionEnd()
+	c.sink.close()
 	if c.client != nil {
-		// Send session end event with duration
-		c.TrackSessionEnd()
 		return c.client.Close()
 	}
 	return nil
@@ -260,7 +284,7 @@ func generateSessionID() string {
 	return hex.EncodeToString(randomBytes)
 }
 
-// track sends an event to PostHog (internal helper)
+// track sends an event to the configured sink (internal helper)
 func (c *Client) track(event string, properties map[string]any) {
 	if !c.enabled {
 		return
@@ -280,15 +304,16 @@ func (c *Client) track(event string, properties map[string]any) {
 		return
 	}
 
-	if c.client == nil {
+	if c.sink == nil {
 		return
 	}
 
-	// Enqueue event (non-blocking)
-	_ = c.client.Enqueue(posthog.Capture{
-		DistinctId: c.anonymousID,
-		Event:      event,
-		Properties: properties,
+	// Enqueue event (non-blocking, delivered in the background)
+	c.sink.enqueue(Event{
+		Name:        event,
+		AnonymousID: c.anonymousID,
+		Timestamp:   time.Now(),
+		Properties:  properties,
 	})
 }
 
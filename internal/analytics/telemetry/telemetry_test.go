@@ -2,8 +2,12 @@ package telemetry
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -136,3 +140,67 @@ func TestCounters(t *testing.T) {
 
 	assert.Equal(t, int64(3), count)
 }
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Record(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestClientCustomSink(t *testing.T) {
+	sink := &recordingSink{}
+	client, err := NewClient(Config{Version: "test", Enabled: true, Sink: sink})
+	require.NoError(t, err)
+	assert.Nil(t, client.client, "PostHog client should not be created when a sink is provided")
+
+	client.TrackSessionStart("repl")
+	client.TrackError(ErrorCategoryParse)
+	require.NoError(t, client.Close())
+
+	// Close drains buffered events, including the session end event
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	require.Len(t, sink.events, 3)
+	assert.Equal(t, EventSessionStart, sink.events[0].Name)
+	assert.Equal(t, "repl", sink.events[0].Properties["mode"])
+	assert.Equal(t, "test", sink.events[0].Properties["gsh_version"])
+	assert.Equal(t, EventError, sink.events[1].Name)
+	assert.Equal(t, EventSessionEnd, sink.events[2].Name)
+
+	// Tracking after close is a no-op
+	client.TrackSessionStart("repl")
+	assert.Len(t, sink.events, 3)
+}
+
+func TestClientHTTPSinkFromEnv(t *testing.T) {
+	received := make(chan Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			received <- event
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	os.Setenv(envTelemetryEndpoint, server.URL)
+	defer os.Unsetenv(envTelemetryEndpoint)
+
+	client, err := NewClient(Config{Version: "test", Enabled: true})
+	require.NoError(t, err)
+	assert.Nil(t, client.client)
+
+	client.TrackScriptExecution()
+	require.NoError(t, client.Close())
+
+	require.Len(t, received, 2)
+	event := <-received
+	assert.Equal(t, EventScriptExecution, event.Name)
+	assert.Equal(t, client.anonymousID, event.AnonymousID)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"mvdan.cc/sh/v3/interp"
@@ -51,42 +52,96 @@ func RunBashCommandInSubShell(ctx context.Context, runner *interp.Runner, comman
 // Returns stdout, stderr, exit code, and any execution error.
 // A non-zero exit code is NOT treated as an error - check the exit code separately.
 func RunBashCommandInSubShellWithExitCode(ctx context.Context, runner *interp.Runner, command string) (string, string, int, error) {
+	return RunBashCommandInSubShellWithEnv(ctx, runner, command, nil)
+}
+
+// RunBashCommandInSubShellWithEnv is like RunBashCommandInSubShellWithExitCode, but exports
+// env into the subshell before running the command. The overrides only apply to this
+// command; the parent runner's environment is left untouched.
+func RunBashCommandInSubShellWithEnv(ctx context.Context, runner *interp.Runner, command string, env map[string]string) (string, string, int, error) {
 	subShell := runner.Subshell()
+	if err := exportEnv(ctx, subShell, env); err != nil {
+		return "", "", 1, err
+	}
 
 	outBuf := &threadSafeBuffer{}
 	errBuf := &threadSafeBuffer{}
 	interp.StdIO(nil, outBuf, errBuf)(subShell) //nolint:errcheck
 
+	exitCode, err := runStmt(ctx, subShell, command)
+	return outBuf.String(), errBuf.String(), exitCode, err
+}
+
+// RunBashCommandInSubShellStreaming is like RunBashCommandInSubShellWithEnv, but the
+// command reads and writes the runner's own stdio instead of having its output
+// captured, so output reaches the terminal as it is produced. Returns the exit code
+// and any execution error.
+func RunBashCommandInSubShellStreaming(ctx context.Context, runner *interp.Runner, command string, env map[string]string) (int, error) {
+	subShell := runner.Subshell()
+	if err := exportEnv(ctx, subShell, env); err != nil {
+		return 1, err
+	}
+	return runStmt(ctx, subShell, command)
+}
+
+// runStmt parses the first statement of command and runs it in runner.
+// A non-zero exit code is returned as the exit code, not as an error.
+func runStmt(ctx context.Context, runner *interp.Runner, command string) (int, error) {
 	var prog *syntax.Stmt
 	err := syntax.NewParser().Stmts(strings.NewReader(command), func(stmt *syntax.Stmt) bool {
 		prog = stmt
 		return false
 	})
 	if err != nil {
-		return "", "", 1, fmt.Errorf("failed to parse bash command: %w", err)
+		return 1, fmt.Errorf("failed to parse bash command: %w", err)
 	}
 
 	if prog == nil {
 		// Empty command
-		return "", "", 0, nil
+		return 0, nil
 	}
 
-	err = subShell.Run(ctx, prog)
-
-	// Extract exit code
-	exitCode := 0
+	err = runner.Run(ctx, prog)
 	if err != nil {
 		var exitStatus interp.ExitStatus
 		if errors.As(err, &exitStatus) {
-			exitCode = int(exitStatus)
 			// Non-zero exit code is not an execution error
-			return outBuf.String(), errBuf.String(), exitCode, nil
+			return int(exitStatus), nil
 		}
 		// Real execution error
-		return outBuf.String(), errBuf.String(), 1, err
+		return 1, err
 	}
 
-	return outBuf.String(), errBuf.String(), exitCode, nil
+	return 0, nil
+}
+
+// exportEnv exports each variable in env into runner
+func exportEnv(ctx context.Context, runner *interp.Runner, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(env))
+	for name := range env {
+		if !syntax.ValidName(name) {
+			return fmt.Errorf("invalid environment variable name: %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var script strings.Builder
+	for _, name := range names {
+		value, err := syntax.Quote(env[name], syntax.LangBash)
+		if err != nil {
+			return fmt.Errorf("invalid value for environment variable %s: %w", name, err)
+		}
+		fmt.Fprintf(&script, "export %s=%s\n", name, value)
+	}
+	prog, err := syntax.NewParser().Parse(strings.NewReader(script.String()), "")
+	if err != nil {
+		return fmt.Errorf("failed to parse environment overrides: %w", err)
+	}
+	return runner.Run(ctx, prog)
 }
 
 // RunBashCommand runs a bash command in the main runner and captures stdout/stderr.
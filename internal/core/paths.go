@@ -12,6 +12,7 @@ type Paths struct {
 	HistoryFile       string
 	LatestVersionFile string
 	VersionMarkerFile string
+	ResponseCacheDir  string
 }
 
 var defaultPaths *Paths
@@ -30,6 +31,7 @@ func ensureDefaultPaths() {
 			HistoryFile:       filepath.Join(homeDir, ".gsh", "history.db"),
 			LatestVersionFile: filepath.Join(homeDir, ".gsh", "latest_version.txt"),
 			VersionMarkerFile: filepath.Join(homeDir, ".gsh", "version_marker"),
+			ResponseCacheDir:  filepath.Join(homeDir, ".gsh", "cache", "responses"),
 		}
 
 		err = os.MkdirAll(defaultPaths.DataDir, 0755)
@@ -69,6 +71,13 @@ func VersionMarkerFile() string {
 	return defaultPaths.VersionMarkerFile
 }
 
+// ResponseCacheDir returns the directory holding cached model responses.
+// The directory is created on first write, not here.
+func ResponseCacheDir() string {
+	ensureDefaultPaths()
+	return defaultPaths.ResponseCacheDir
+}
+
 // ResetPaths clears the cached paths, forcing them to be reinitialized.
 // This is primarily used for testing purposes.
 func ResetPaths() {
@@ -63,11 +63,41 @@ func GetLogLevel(runner *interp.Runner) zap.AtomicLevel {
 	return logLevel
 }
 
+// GetLogFile returns the log file path set via GSH_LOG_FILE, or "" to use the default.
+// Unlike most settings it also checks the inherited environment, so that
+// `GSH_LOG_FILE=/tmp/x.log gsh` works without touching ~/.gshrc.
+func GetLogFile(runner *interp.Runner) string {
+	logFile := runner.Vars["GSH_LOG_FILE"]
+	if logFile.String() == "" && runner.Env != nil {
+		logFile = runner.Env.Get("GSH_LOG_FILE")
+	}
+	return strings.TrimSpace(logFile.String())
+}
+
 func ShouldCleanLogFile(runner *interp.Runner) bool {
 	cleanLogFile := strings.ToLower(runner.Vars["GSH_CLEAN_LOG_FILE"].String())
 	return cleanLogFile == "1" || cleanLogFile == "true"
 }
 
+// ShouldSkipUpdateCheck reports whether the startup self-update check is disabled
+// via GSH_NO_UPDATE (e.g. `export GSH_NO_UPDATE=1` in the environment or ~/.gshrc).
+func ShouldSkipUpdateCheck(runner *interp.Runner) bool {
+	noUpdate := strings.ToLower(runner.Vars["GSH_NO_UPDATE"].String())
+	return noUpdate == "1" || noUpdate == "true"
+}
+
+// ShouldTraceModel reports whether GSH_TRACE_MODEL asks for model requests and
+// responses to be logged, which also needs debug logging. Like GSH_LOG_FILE it also
+// checks the inherited environment, which is how `gsh run --trace` sets it.
+func ShouldTraceModel(runner *interp.Runner) bool {
+	traceModel := runner.Vars["GSH_TRACE_MODEL"]
+	if traceModel.String() == "" && runner.Env != nil {
+		traceModel = runner.Env.Get("GSH_TRACE_MODEL")
+	}
+	value := strings.ToLower(traceModel.String())
+	return value == "1" || value == "true"
+}
+
 func GetPwd(runner *interp.Runner) string {
 	return runner.Vars["PWD"].String()
 }
@@ -623,6 +623,13 @@ func TestEnvironmentHelperFunctions(t *testing.T) {
 	cleanLog := ShouldCleanLogFile(runner)
 	assert.False(t, cleanLog)
 
+	assert.Equal(t, "", GetLogFile(runner))
+
+	skipUpdate := ShouldSkipUpdateCheck(runner)
+	assert.False(t, skipUpdate)
+
+	assert.False(t, ShouldTraceModel(runner))
+
 	pwd := GetPwd(runner)
 	// PWD may be empty in test environment without shell initialization
 	assert.IsType(t, "", pwd)
@@ -675,6 +682,9 @@ func TestEnvironmentHelperFunctionsWithCustomValues(t *testing.T) {
 	runner.Vars["GSH_PAST_COMMANDS_CONTEXT_LIMIT"] = expand.Variable{Kind: expand.String, Str: "50"}
 	runner.Vars["GSH_LOG_LEVEL"] = expand.Variable{Kind: expand.String, Str: "debug"}
 	runner.Vars["GSH_CLEAN_LOG_FILE"] = expand.Variable{Kind: expand.String, Str: "true"}
+	runner.Vars["GSH_LOG_FILE"] = expand.Variable{Kind: expand.String, Str: "/tmp/session.log"}
+	runner.Vars["GSH_NO_UPDATE"] = expand.Variable{Kind: expand.String, Str: "1"}
+	runner.Vars["GSH_TRACE_MODEL"] = expand.Variable{Kind: expand.String, Str: "true"}
 	runner.Vars["GSH_AGENT_CONTEXT_WINDOW_TOKENS"] = expand.Variable{Kind: expand.String, Str: "16384"}
 	runner.Vars["GSH_MINIMUM_HEIGHT"] = expand.Variable{Kind: expand.String, Str: "12"}
 	runner.Vars["GSH_AGENT_MACROS"] = expand.Variable{Kind: expand.String, Str: "{\"test\": \"echo test\"}"}
@@ -692,6 +702,13 @@ func TestEnvironmentHelperFunctionsWithCustomValues(t *testing.T) {
 	cleanLog := ShouldCleanLogFile(runner)
 	assert.True(t, cleanLog)
 
+	assert.Equal(t, "/tmp/session.log", GetLogFile(runner))
+
+	skipUpdate := ShouldSkipUpdateCheck(runner)
+	assert.True(t, skipUpdate)
+
+	assert.True(t, ShouldTraceModel(runner))
+
 	contextWindow := GetAgentContextWindowTokens(runner, logger)
 	assert.Equal(t, 16384, contextWindow)
 
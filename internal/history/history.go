@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -18,6 +19,15 @@ import (
 
 type HistoryManager struct {
 	db *gorm.DB
+
+	// maxEntries caps the number of stored entries, pruning the oldest; 0 means no cap
+	maxEntries int
+	// ignoreSpace skips commands typed with a leading space
+	ignoreSpace bool
+	// ignorePatterns skips commands matching any of the patterns
+	ignorePatterns []*regexp.Regexp
+	// ignoreDups skips a command identical to the most recently recorded one
+	ignoreDups bool
 }
 
 type HistoryEntry struct {
@@ -109,7 +119,53 @@ func schemaVersionPath() string {
 	return filepath.Join(core.DataDir(), "history_schema_version")
 }
 
+// SetMaxEntries caps the history at maxEntries, pruning the oldest entries as new ones
+// are recorded. 0 removes the cap.
+func (historyManager *HistoryManager) SetMaxEntries(maxEntries int) {
+	historyManager.maxEntries = maxEntries
+}
+
+// SetIgnore sets which commands are not recorded: commands with a leading space when
+// ignoreSpace is true, and commands matching any of patterns.
+func (historyManager *HistoryManager) SetIgnore(ignoreSpace bool, patterns []*regexp.Regexp) {
+	historyManager.ignoreSpace = ignoreSpace
+	historyManager.ignorePatterns = patterns
+}
+
+// SetIgnoreDups sets whether a command identical to the most recently recorded one is
+// not recorded again, like HISTCONTROL=ignoredups in bash.
+func (historyManager *HistoryManager) SetIgnoreDups(ignoreDups bool) {
+	historyManager.ignoreDups = ignoreDups
+}
+
+// Ignores reports whether command, as typed, should be left out of the history
+func (historyManager *HistoryManager) Ignores(command string) bool {
+	if historyManager.ignoreSpace && strings.HasPrefix(command, " ") {
+		return true
+	}
+	trimmed := strings.TrimSpace(command)
+	for _, pattern := range historyManager.ignorePatterns {
+		if pattern.MatchString(trimmed) {
+			return true
+		}
+	}
+	if historyManager.ignoreDups {
+		var last HistoryEntry
+		result := historyManager.db.Order("id desc").Limit(1).Find(&last)
+		if result.Error == nil && result.RowsAffected == 1 && last.Command == trimmed {
+			return true
+		}
+	}
+	return false
+}
+
+// StartCommand records command. It returns a nil entry and no error when the command
+// is ignored.
 func (historyManager *HistoryManager) StartCommand(command string, directory string) (*HistoryEntry, error) {
+	if historyManager.Ignores(command) {
+		return nil, nil
+	}
+
 	entry := HistoryEntry{
 		Command:   command,
 		Directory: directory,
@@ -120,9 +176,25 @@ func (historyManager *HistoryManager) StartCommand(command string, directory str
 		return nil, result.Error
 	}
 
+	if err := historyManager.prune(); err != nil {
+		return nil, err
+	}
+
 	return &entry, nil
 }
 
+// prune deletes the oldest entries beyond the max entries cap
+func (historyManager *HistoryManager) prune() error {
+	if historyManager.maxEntries <= 0 {
+		return nil
+	}
+	result := historyManager.db.Exec(
+		"DELETE FROM history_entries WHERE id NOT IN (SELECT id FROM history_entries ORDER BY id DESC LIMIT ?)",
+		historyManager.maxEntries,
+	)
+	return result.Error
+}
+
 func (historyManager *HistoryManager) FinishCommand(entry *HistoryEntry, exitCode int) (*HistoryEntry, error) {
 	entry.ExitCode = sql.NullInt32{Int32: int32(exitCode), Valid: true}
 
@@ -2,6 +2,8 @@ package history
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -364,3 +366,75 @@ func TestHistoryManager_SearchHistory(t *testing.T) {
 		assert.Len(t, entries, 6)
 	})
 }
+
+func TestMaxEntries(t *testing.T) {
+	historyManager, err := NewHistoryManager(":memory:")
+	assert.NoError(t, err, "Failed to create history manager")
+
+	historyManager.SetMaxEntries(3)
+	for i := 1; i <= 5; i++ {
+		_, err := historyManager.StartCommand(fmt.Sprintf("command%d", i), "/")
+		assert.NoError(t, err)
+	}
+
+	entries, err := historyManager.GetRecentEntries("", 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3, "Expected the oldest entries to be pruned")
+	assert.Equal(t, "command3", entries[0].Command)
+	assert.Equal(t, "command5", entries[2].Command)
+
+	historyManager.SetMaxEntries(0)
+	_, err = historyManager.StartCommand("command6", "/")
+	assert.NoError(t, err)
+	entries, err = historyManager.GetRecentEntries("", 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4, "Expected no pruning without a cap")
+}
+
+func TestIgnore(t *testing.T) {
+	historyManager, err := NewHistoryManager(":memory:")
+	assert.NoError(t, err, "Failed to create history manager")
+
+	historyManager.SetIgnore(true, []*regexp.Regexp{regexp.MustCompile(`^export \w*TOKEN=`)})
+
+	assert.True(t, historyManager.Ignores(" ls -la"), "Expected a leading space to be ignored")
+	assert.True(t, historyManager.Ignores("export GH_TOKEN=abc"), "Expected a matching command to be ignored")
+	assert.False(t, historyManager.Ignores("ls -la"))
+
+	entry, err := historyManager.StartCommand("export GH_TOKEN=abc", "/")
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "Expected no entry for an ignored command")
+
+	_, err = historyManager.StartCommand("echo hello", "/")
+	assert.NoError(t, err)
+
+	entries, err := historyManager.GetRecentEntries("", 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "echo hello", entries[0].Command)
+
+	historyManager.SetIgnore(false, nil)
+	assert.False(t, historyManager.Ignores(" ls -la"))
+}
+
+func TestIgnoreDups(t *testing.T) {
+	historyManager, err := NewHistoryManager(":memory:")
+	assert.NoError(t, err, "Failed to create history manager")
+
+	historyManager.SetIgnoreDups(true)
+	for _, command := range []string{"ls", "ls", "git status", "ls", "ls"} {
+		_, err := historyManager.StartCommand(command, "/")
+		assert.NoError(t, err)
+	}
+
+	entries, err := historyManager.GetRecentEntries("", 10)
+	assert.NoError(t, err)
+	commands := make([]string, len(entries))
+	for i, entry := range entries {
+		commands[i] = entry.Command
+	}
+	assert.Equal(t, []string{"ls", "git status", "ls"}, commands)
+
+	historyManager.SetIgnoreDups(false)
+	assert.False(t, historyManager.Ignores("ls"))
+}
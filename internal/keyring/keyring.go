@@ -0,0 +1,211 @@
+// Package keyring stores secrets such as model API keys in the operating
+// system's keyring instead of environment variables or files.
+//
+// It drives the platform's own command line tools: "security" for the macOS
+// Keychain and "secret-tool" for the Secret Service (GNOME Keyring, KWallet)
+// on Linux. On Windows it calls the Credential Manager API directly. Other
+// platforms, or systems missing the tool, report ErrUnsupported.
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Service is the service name gsh secrets are stored under.
+const Service = "gsh"
+
+var (
+	// ErrNotFound is returned when no secret is stored under a name.
+	ErrNotFound = errors.New("secret not found in keyring")
+
+	// ErrUnsupported is returned when no keyring backend is available.
+	ErrUnsupported = errors.New("no keyring backend available")
+)
+
+// Backend reads and writes secrets in a keyring.
+type Backend interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = newSystemBackend(runtime.GOOS)
+)
+
+// Get returns the secret stored under name.
+func Get(name string) (string, error) {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend.Get(name)
+}
+
+// Set stores value as the secret under name, replacing any existing value.
+func Set(name, value string) error {
+	if name == "" {
+		return fmt.Errorf("secret name cannot be empty")
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("secret value cannot contain a newline")
+	}
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend.Set(name, value)
+}
+
+// MockInit replaces the system keyring with an in-memory one, for tests.
+func MockInit() {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = &memoryBackend{secrets: make(map[string]string)}
+}
+
+// memoryBackend keeps secrets in memory
+type memoryBackend struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (m *memoryBackend) Get(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *memoryBackend) Set(name, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[name] = value
+	return nil
+}
+
+// commandBackend stores secrets through a platform command line tool
+type commandBackend struct {
+	tool string
+	// getArgs and setArgs build the tool's arguments; setArgs also returns
+	// what to write to stdin, for tools that read the secret from there
+	getArgs func(name string) []string
+	setArgs func(name, value string) (args []string, stdin string)
+	// notFoundCode is the exit code the tool uses for a missing secret
+	notFoundCode int
+	// run executes the tool; replaced in tests
+	run func(stdin string, name string, args ...string) (stdout string, exitCode int, err error)
+}
+
+// newSystemBackend returns the backend for goos
+func newSystemBackend(goos string) Backend {
+	switch goos {
+	case "darwin":
+		return &commandBackend{
+			tool: "security",
+			getArgs: func(name string) []string {
+				return []string{"find-generic-password", "-s", Service, "-a", name, "-w"}
+			},
+			// The secret would be visible to other processes as an argument, so the
+			// command is fed to security's interactive mode on stdin instead
+			setArgs: func(name, value string) ([]string, string) {
+				command := []string{"add-generic-password", "-U", "-s", securityQuote(Service), "-a", securityQuote(name), "-w", securityQuote(value)}
+				return []string{"-i"}, strings.Join(command, " ") + "\n"
+			},
+			notFoundCode: 44,
+			run:          runCommand,
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return &commandBackend{
+			tool: "secret-tool",
+			getArgs: func(name string) []string {
+				return []string{"lookup", "service", Service, "account", name}
+			},
+			setArgs: func(name, value string) ([]string, string) {
+				return []string{"store", "--label", Service + ": " + name, "service", Service, "account", name}, value
+			},
+			notFoundCode: 1,
+			run:          runCommand,
+		}
+	case "windows":
+		return newCredentialBackend()
+	default:
+		return unsupportedBackend{}
+	}
+}
+
+// securityQuote quotes s as one argument of a command read by "security -i"
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (c *commandBackend) Get(name string) (string, error) {
+	out, exitCode, err := c.run("", c.tool, c.getArgs(name)...)
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return "", err
+		}
+		if exitCode == c.notFoundCode && out == "" {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("%s failed: %w", c.tool, err)
+	}
+	value := strings.TrimSuffix(out, "\n")
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *commandBackend) Set(name, value string) error {
+	args, stdin := c.setArgs(name, value)
+	if _, _, err := c.run(stdin, c.tool, args...); err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return err
+		}
+		return fmt.Errorf("%s failed: %w", c.tool, err)
+	}
+	return nil
+}
+
+// unsupportedBackend is used on platforms without a supported keyring tool
+type unsupportedBackend struct{}
+
+func (unsupportedBackend) Get(string) (string, error) {
+	return "", fmt.Errorf("%w on %s", ErrUnsupported, runtime.GOOS)
+}
+
+func (unsupportedBackend) Set(string, string) error {
+	return fmt.Errorf("%w on %s", ErrUnsupported, runtime.GOOS)
+}
+
+// runCommand runs a command with stdin and returns its stdout and exit code.
+// On failure the error includes whatever the command wrote to stderr.
+// A missing command is reported as ErrUnsupported.
+func runCommand(stdin string, name string, args ...string) (string, int, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", -1, fmt.Errorf("%w: %s not found", ErrUnsupported, name)
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return stdout.String(), exitCode, err
+	}
+	return stdout.String(), 0, nil
+}
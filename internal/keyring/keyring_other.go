@@ -0,0 +1,9 @@
+//go:build !windows
+
+package keyring
+
+// newCredentialBackend falls back to unsupportedBackend, since the Windows
+// Credential Manager can only be reached from Windows builds.
+func newCredentialBackend() Backend {
+	return unsupportedBackend{}
+}
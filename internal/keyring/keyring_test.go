@@ -0,0 +1,93 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTool records invocations and answers like a keyring command line tool
+type fakeTool struct {
+	stdout   string
+	exitCode int
+	calls    [][]string
+	stdin    string
+}
+
+func (f *fakeTool) run(stdin string, name string, args ...string) (string, int, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	f.stdin = stdin
+	if f.exitCode != 0 {
+		return f.stdout, f.exitCode, fmt.Errorf("exit status %d", f.exitCode)
+	}
+	return f.stdout, 0, nil
+}
+
+func linuxBackend(tool *fakeTool) *commandBackend {
+	b := newSystemBackend("linux").(*commandBackend)
+	b.run = tool.run
+	return b
+}
+
+func TestCommandBackend_Get(t *testing.T) {
+	tool := &fakeTool{stdout: "sk-secret\n"}
+	value, err := linuxBackend(tool).Get("OPENAI_API_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-secret", value)
+	assert.Equal(t, []string{"secret-tool", "lookup", "service", "gsh", "account", "OPENAI_API_KEY"}, tool.calls[0])
+}
+
+func TestCommandBackend_GetNotFound(t *testing.T) {
+	_, err := linuxBackend(&fakeTool{exitCode: 1}).Get("MISSING")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Other failures are reported as errors, not as a missing secret
+	_, err = linuxBackend(&fakeTool{exitCode: 2}).Get("MISSING")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestCommandBackend_Set(t *testing.T) {
+	tool := &fakeTool{}
+	require.NoError(t, linuxBackend(tool).Set("OPENAI_API_KEY", "sk-secret"))
+	assert.Equal(t, "sk-secret", tool.stdin, "secret-tool reads the secret from stdin")
+	assert.NotContains(t, tool.calls[0], "sk-secret")
+
+	darwin := newSystemBackend("darwin").(*commandBackend)
+	darwinTool := &fakeTool{}
+	darwin.run = darwinTool.run
+	require.NoError(t, darwin.Set("OPENAI_API_KEY", `sk-"secret"`))
+	assert.Equal(t, []string{"security", "-i"}, darwinTool.calls[0], "security reads the command from stdin")
+	assert.Equal(t, `add-generic-password -U -s "gsh" -a "OPENAI_API_KEY" -w "sk-\"secret\""`+"\n", darwinTool.stdin)
+	assert.Contains(t, darwinTool.stdin, "-U", "existing secrets are updated in place")
+}
+
+func TestUnsupportedPlatform(t *testing.T) {
+	backend := newSystemBackend("plan9")
+	_, err := backend.Get("KEY")
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorIs(t, backend.Set("KEY", "value"), ErrUnsupported)
+}
+
+func TestMissingTool(t *testing.T) {
+	_, _, err := runCommand("", "gsh-no-such-keyring-tool")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestMockInit(t *testing.T) {
+	MockInit()
+
+	_, err := Get("KEY")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, Set("KEY", "value"))
+	value, err := Get("KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	assert.Error(t, Set("", "value"))
+	assert.Error(t, Set("KEY", "two\nlines"))
+}
@@ -0,0 +1,156 @@
+// Package notify shows desktop notifications, such as when a long agent run
+// finishes while the user is in another window.
+//
+// It drives the platform's own command line tools: "osascript" on macOS and
+// "notify-send" (libnotify) on Linux. Platforms without a supported tool report
+// ErrUnsupported.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned when no desktop notifier is available.
+var ErrUnsupported = errors.New("no desktop notifier available")
+
+// commandTimeout bounds how long a notifier tool may take, so a hung
+// notification daemon can't stall the shell
+const commandTimeout = 5 * time.Second
+
+// Notifier shows desktop notifications.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+var (
+	notifierMu sync.RWMutex
+	notifier   Notifier = newSystemNotifier(runtime.GOOS)
+)
+
+// Send shows a desktop notification with the given title and message.
+func Send(title, message string) error {
+	notifierMu.RLock()
+	defer notifierMu.RUnlock()
+	return notifier.Notify(title, message)
+}
+
+// Notification is a notification captured by a Recorder.
+type Notification struct {
+	Title   string
+	Message string
+}
+
+// Recorder keeps notifications in memory instead of showing them.
+type Recorder struct {
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+func (r *Recorder) Notify(title, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, Notification{Title: title, Message: message})
+	return nil
+}
+
+// Notifications returns the notifications recorded so far.
+func (r *Recorder) Notifications() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.notifications...)
+}
+
+// MockInit replaces the system notifier with a Recorder, for tests.
+func MockInit() *Recorder {
+	notifierMu.Lock()
+	defer notifierMu.Unlock()
+	recorder := &Recorder{}
+	notifier = recorder
+	return recorder
+}
+
+// commandNotifier shows notifications through a platform command line tool
+type commandNotifier struct {
+	tool string
+	args func(title, message string) []string
+	// run executes the tool; replaced in tests
+	run func(name string, args ...string) error
+}
+
+// newSystemNotifier returns the notifier for goos
+func newSystemNotifier(goos string) Notifier {
+	switch goos {
+	case "darwin":
+		return &commandNotifier{
+			tool: "osascript",
+			args: func(title, message string) []string {
+				script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+				return []string{"-e", script}
+			},
+			run: runCommand,
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return &commandNotifier{
+			tool: "notify-send",
+			args: func(title, message string) []string {
+				// "--" keeps a title starting with "-" from being read as an option
+				return []string{"--app-name=gsh", "--", title, message}
+			},
+			run: runCommand,
+		}
+	default:
+		return unsupportedNotifier{}
+	}
+}
+
+func (c *commandNotifier) Notify(title, message string) error {
+	if err := c.run(c.tool, c.args(title, message)...); err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return err
+		}
+		return fmt.Errorf("%s failed: %w", c.tool, err)
+	}
+	return nil
+}
+
+// unsupportedNotifier is used on platforms without a supported notifier tool
+type unsupportedNotifier struct{}
+
+func (unsupportedNotifier) Notify(string, string) error {
+	return fmt.Errorf("%w on %s", ErrUnsupported, runtime.GOOS)
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// runCommand runs a command, including whatever it wrote to stderr in the error.
+// A missing command is reported as ErrUnsupported.
+func runCommand(name string, args ...string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrUnsupported, name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
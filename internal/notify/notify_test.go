@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTool records invocations like a notifier command line tool
+type fakeTool struct {
+	err   error
+	calls [][]string
+}
+
+func (f *fakeTool) run(name string, args ...string) error {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	return f.err
+}
+
+func systemNotifier(t *testing.T, goos string, tool *fakeTool) *commandNotifier {
+	t.Helper()
+	n, ok := newSystemNotifier(goos).(*commandNotifier)
+	require.True(t, ok)
+	n.run = tool.run
+	return n
+}
+
+func TestCommandNotifier_Linux(t *testing.T) {
+	tool := &fakeTool{}
+	require.NoError(t, systemNotifier(t, "linux", tool).Notify("-gsh", "Agent finished"))
+	assert.Equal(t, []string{"notify-send", "--app-name=gsh", "--", "-gsh", "Agent finished"}, tool.calls[0])
+}
+
+func TestCommandNotifier_Darwin(t *testing.T) {
+	tool := &fakeTool{}
+	require.NoError(t, systemNotifier(t, "darwin", tool).Notify(`Say "hi"`, `C:\path`))
+	assert.Equal(t, []string{"osascript", "-e", `display notification "C:\\path" with title "Say \"hi\""`}, tool.calls[0])
+}
+
+func TestCommandNotifier_Failure(t *testing.T) {
+	err := systemNotifier(t, "linux", &fakeTool{err: fmt.Errorf("exit status 1")}).Notify("title", "message")
+	assert.ErrorContains(t, err, "notify-send failed")
+	assert.NotErrorIs(t, err, ErrUnsupported)
+}
+
+func TestUnsupportedPlatform(t *testing.T) {
+	assert.ErrorIs(t, newSystemNotifier("plan9").Notify("title", "message"), ErrUnsupported)
+}
+
+func TestMissingTool(t *testing.T) {
+	assert.ErrorIs(t, runCommand("gsh-no-such-notifier-tool"), ErrUnsupported)
+}
+
+func TestMockInit(t *testing.T) {
+	recorder := MockInit()
+	require.NoError(t, Send("title", "message"))
+	assert.Equal(t, []Notification{{Title: "title", Message: "message"}}, recorder.Notifications())
+}
@@ -0,0 +1,178 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kunchenguid/gsh/internal/acp"
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+	"go.uber.org/zap"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// defaultAgentName is the agent declared by the default config for "#" chat
+const defaultAgentName = "__defaultAgent"
+
+// defaultAgent returns the default agent, with gsh.defaultAgentPromptSuffix appended to
+// its system prompt the way the "#" middleware does
+func (r *REPL) defaultAgent() (*interpreter.AgentValue, error) {
+	agent := r.config.GetAgent(defaultAgentName)
+	if agent == nil {
+		return nil, fmt.Errorf("no default agent configured (expected agent %s)", defaultAgentName)
+	}
+
+	replCtx := r.executor.Interpreter().SDKConfig().GetREPLContext()
+	if replCtx == nil {
+		return agent, nil
+	}
+	suffix := strings.TrimSpace(replCtx.DefaultAgentPromptSuffix)
+	if suffix == "" {
+		return agent, nil
+	}
+
+	config := make(map[string]interpreter.Value, len(agent.Config))
+	for key, value := range agent.Config {
+		config[key] = value
+	}
+	prompt := suffix
+	if existing, ok := config["systemPrompt"].(*interpreter.StringValue); ok && existing.Value != "" {
+		prompt = existing.Value + "\n\n" + suffix
+	}
+	config["systemPrompt"] = &interpreter.StringValue{Value: prompt}
+	return &interpreter.AgentValue{Name: agent.Name, Config: config}, nil
+}
+
+// ServeACP runs gsh headlessly as an ACP agent, speaking the Agent Client Protocol
+// over in/out until in is closed. Each ACP session is a conversation with the same
+// default agent (and tools) that "#" uses in the REPL.
+func (r *REPL) ServeACP(ctx context.Context, in io.Reader, out io.Writer) error {
+	agent, err := r.defaultAgent()
+	if err != nil {
+		return err
+	}
+
+	server := acp.NewServer(&acpAgent{
+		repl:     r,
+		agent:    agent,
+		sessions: make(map[string]*acpSession),
+	}, r.logger)
+	return server.Serve(ctx, in, out)
+}
+
+// acpAgent adapts the REPL's default agent to acp.Agent
+type acpAgent struct {
+	repl  *REPL
+	agent *interpreter.AgentValue
+
+	mu       sync.Mutex
+	sessions map[string]*acpSession
+	nextID   int
+
+	// runMu serializes prompt turns: sessions share one interpreter and shell
+	runMu sync.Mutex
+}
+
+// acpSession holds the conversation of one ACP session
+type acpSession struct {
+	cwd          string
+	conversation *interpreter.ConversationValue
+}
+
+// NewSession starts an empty conversation rooted at cwd
+func (a *acpAgent) NewSession(ctx context.Context, cwd string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextID++
+	id := fmt.Sprintf("gsh-%d", a.nextID)
+	a.sessions[id] = &acpSession{
+		cwd:          cwd,
+		conversation: &interpreter.ConversationValue{},
+	}
+	return id, nil
+}
+
+// Prompt runs the default agent on the session's conversation, forwarding
+// streamed text and tool calls as session updates
+func (a *acpAgent) Prompt(ctx context.Context, sessionID string, prompt []acp.PromptContent, send func(acp.SessionUpdatePayload)) (acp.StopReason, error) {
+	a.mu.Lock()
+	session, ok := a.sessions[sessionID]
+	a.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown session: %s", sessionID)
+	}
+
+	a.runMu.Lock()
+	defer a.runMu.Unlock()
+
+	message := promptText(prompt)
+	if session.cwd != "" && session.cwd != a.repl.executor.GetPwd() {
+		if _, err := a.repl.executor.ExecuteBash(ctx, "cd "+quoteShellWord(session.cwd)); err != nil {
+			a.repl.logger.Warn("failed to change to ACP session directory", zap.String("cwd", session.cwd), zap.Error(err))
+		}
+	}
+	if len(session.conversation.Messages) == 0 {
+		// Same directory hint the "#" middleware gives the agent
+		message = fmt.Sprintf("<current_directory>%s</current_directory>\n\n%s", a.repl.executor.GetPwd(), message)
+	}
+
+	conv := &interpreter.ConversationValue{
+		Messages: append(append([]interpreter.ChatMessage{}, session.conversation.Messages...), interpreter.ChatMessage{
+			Role:      "user",
+			Content:   message,
+			Timestamp: time.Now(),
+		}),
+	}
+
+	var stopReason acp.StopReason
+	callbacks := &interpreter.AgentCallbacks{
+		OnChunk: func(content string) {
+			send(acp.NewMessageChunkUpdate(content))
+		},
+		OnToolCallStart: func(toolCall acp.ToolCall) {
+			send(acp.NewToolCallUpdate(toolCall))
+		},
+		OnToolCallEnd: func(_ acp.ToolCall, update acp.ToolCallUpdate) {
+			send(acp.NewToolCallResultUpdate(update))
+		},
+		OnComplete: func(result acp.AgentResult) {
+			stopReason = result.StopReason
+		},
+	}
+
+	result, err := a.repl.executor.Interpreter().ExecuteAgentWithCallbacks(ctx, conv, a.agent, true, callbacks)
+	if updated, ok := result.(*interpreter.ConversationValue); ok {
+		session.conversation = updated
+	}
+	if stopReason == "" {
+		stopReason = acp.StopReasonEndTurn
+	}
+	return stopReason, err
+}
+
+// promptText flattens ACP prompt content blocks into a single user message.
+// Embedded resources are wrapped in <resource> tags so the agent can tell them apart.
+func promptText(prompt []acp.PromptContent) string {
+	var parts []string
+	for _, block := range prompt {
+		switch {
+		case block.Type == "text" && block.Text != "":
+			parts = append(parts, block.Text)
+		case block.Resource != nil && block.Resource.Text != "":
+			parts = append(parts, fmt.Sprintf("<resource uri=%q>\n%s\n</resource>", block.Resource.URI, block.Resource.Text))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// quoteShellWord quotes s for use as a single shell word
+func quoteShellWord(s string) string {
+	if quoted, err := syntax.Quote(s, syntax.LangBash); err == nil {
+		return quoted
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
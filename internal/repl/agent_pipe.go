@@ -0,0 +1,53 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+)
+
+// maxAgentPipeOutputLen caps how much of a command's output "<command> | # <message>"
+// sends to the agent. Longer output loses lines from the middle.
+const maxAgentPipeOutputLen = 20000
+
+// defaultAgentPipeMessage is sent when "<command> | #" (or "<command> | # @name") has no message of its own.
+const defaultAgentPipeMessage = "Explain this output."
+
+// agentPipeInput runs command, capturing its output, and returns the agent input for
+// "<command> | # <message>": the message followed by the command and its output, so the
+// agent middleware handles it like any other "#" message.
+func (r *REPL) agentPipeInput(ctx context.Context, command, message string) string {
+	// Braces make the subshell run every statement of the command, not just the first
+	stdout, stderr, exitCode, err := r.executor.ExecuteBashInSubshell(ctx, "{ "+command+"\n}")
+	if err != nil && stderr == "" {
+		stderr = err.Error()
+	}
+
+	// A bare "@name" still needs a message after the mention
+	if message == "" {
+		message = defaultAgentPipeMessage
+	} else if strings.HasPrefix(message, "@") && !strings.ContainsAny(message, " \t") {
+		message += " " + defaultAgentPipeMessage
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", message)
+	fmt.Fprintf(&sb, "<command exit_code=\"%d\">%s</command>\n", exitCode, command)
+	fmt.Fprintf(&sb, "<output>\n%s\n</output>", limitAgentPipeOutput(stdout))
+	if strings.TrimSpace(stderr) != "" {
+		fmt.Fprintf(&sb, "\n<stderr>\n%s\n</stderr>", limitAgentPipeOutput(stderr))
+	}
+	return sb.String()
+}
+
+// limitAgentPipeOutput trims trailing newlines and truncates output longer than
+// maxAgentPipeOutputLen from the middle, keeping its start and end.
+func limitAgentPipeOutput(output string) string {
+	output = strings.TrimRight(output, "\n")
+	if len(output) <= maxAgentPipeOutputLen {
+		return output
+	}
+	return interpreter.TruncateFromMiddle(strings.Split(output, "\n"), maxAgentPipeOutputLen)
+}
@@ -0,0 +1,34 @@
+package completers
+
+import (
+	"strings"
+)
+
+// MentionCompleter provides completions for @mentions of agents and models in agent chat
+// (e.g. "# @analyst summarize this").
+type MentionCompleter struct {
+	names func() []string
+}
+
+// NewMentionCompleter creates a new MentionCompleter. names returns the names that can be
+// mentioned, such as the agents and models declared in the REPL config.
+func NewMentionCompleter(names func() []string) *MentionCompleter {
+	return &MentionCompleter{names: names}
+}
+
+// GetCompletions returns "@name" completions for a word starting with "@".
+// Returns nil if the word is not a mention.
+func (c *MentionCompleter) GetCompletions(word string) []string {
+	if c.names == nil || !strings.HasPrefix(word, "@") {
+		return nil
+	}
+	prefix := strings.TrimPrefix(word, "@")
+
+	var completions []string
+	for _, name := range c.names() {
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, "@"+name)
+		}
+	}
+	return completions
+}
@@ -0,0 +1,49 @@
+package completers
+
+import (
+	"strings"
+)
+
+// sdkRoot is the global whose members SDKCompleter completes
+const sdkRoot = "gsh"
+
+// SDKCompleter provides completions for gsh SDK member paths (e.g. gsh.tools.exec).
+type SDKCompleter struct {
+	complete func(path string) []string
+}
+
+// NewSDKCompleter creates a new SDKCompleter. complete resolves a dotted path such as
+// "gsh.tools.ex" to the full paths of the matching members.
+func NewSDKCompleter(complete func(path string) []string) *SDKCompleter {
+	return &SDKCompleter{complete: complete}
+}
+
+// GetCompletions returns completions for a word ending in a gsh SDK member path,
+// such as "gsh.ui." or "(gsh.tools.ex". Any text before the path is kept.
+// Returns nil if the word does not end in a gsh member path.
+func (c *SDKCompleter) GetCompletions(word string) []string {
+	if c.complete == nil {
+		return nil
+	}
+
+	// Find the trailing run of identifier characters and dots
+	start := len(word)
+	for start > 0 && isMemberPathChar(word[start-1]) {
+		start--
+	}
+	path := word[start:]
+	if !strings.HasPrefix(path, sdkRoot+".") {
+		return nil
+	}
+
+	completions := c.complete(path)
+	for i, completion := range completions {
+		completions[i] = word[:start] + completion
+	}
+	return completions
+}
+
+func isMemberPathChar(b byte) bool {
+	return b == '.' || b == '_' ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
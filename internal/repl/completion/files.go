@@ -9,8 +9,17 @@ import (
 // OsReadDir is a variable that can be overridden for testing.
 var OsReadDir = os.ReadDir
 
-// GetFileCompletions returns file completions for the given prefix in the current directory.
+// GetFileCompletions returns file completions for the given prefix in the current directory,
+// including hidden files.
 func GetFileCompletions(prefix string, currentDirectory string) []string {
+	return GetPathCompletions(prefix, currentDirectory, true)
+}
+
+// GetPathCompletions returns the filesystem entries matching prefix, resolved relative to
+// currentDirectory, with "~" expanded to the home directory. Directories get a trailing
+// slash. When showHidden is false, entries starting with "." are only offered once the
+// name being completed starts with ".".
+func GetPathCompletions(prefix string, currentDirectory string, showHidden bool) []string {
 	if prefix == "" {
 		// If prefix is empty, use current directory
 		entries, err := OsReadDir(currentDirectory)
@@ -21,6 +30,9 @@ func GetFileCompletions(prefix string, currentDirectory string) []string {
 		matches := make([]string, 0, len(entries))
 		for _, entry := range entries {
 			name := entry.Name()
+			if !showHidden && strings.HasPrefix(name, ".") {
+				continue
+			}
 			if entry.IsDir() {
 				name += "/"
 			}
@@ -36,6 +48,11 @@ func GetFileCompletions(prefix string, currentDirectory string) []string {
 	var prefixDir string  // directory part of the prefix
 	var homeDir string    // user's home directory if needed
 
+	// A bare "~" completes to the home directory itself
+	if prefix == "~" {
+		return []string{"~/"}
+	}
+
 	// Check if path starts with "~"
 	if strings.HasPrefix(prefix, "~") {
 		pathType = "home"
@@ -45,8 +62,9 @@ func GetFileCompletions(prefix string, currentDirectory string) []string {
 			return []string{}
 		}
 		// Replace "~" with actual home directory for searching
+		// Take the directory before joining, since joining would resolve a trailing "." or ".."
 		searchPath := filepath.Join(homeDir, prefix[1:])
-		dir = filepath.Dir(searchPath)
+		dir = filepath.Join(homeDir, filepath.Dir(prefix[1:]))
 		filePrefix = filepath.Base(prefix)
 		prefixDir = filepath.Dir(prefix)
 
@@ -73,7 +91,7 @@ func GetFileCompletions(prefix string, currentDirectory string) []string {
 		// Relative path
 		pathType = "rel"
 		fullPath := filepath.Join(currentDirectory, prefix)
-		dir = filepath.Dir(fullPath)
+		dir = filepath.Join(currentDirectory, filepath.Dir(prefix))
 		filePrefix = filepath.Base(prefix)
 		prefixDir = filepath.Dir(prefix)
 
@@ -98,6 +116,9 @@ func GetFileCompletions(prefix string, currentDirectory string) []string {
 		if !strings.HasPrefix(name, filePrefix) {
 			continue
 		}
+		if !showHidden && strings.HasPrefix(name, ".") && !strings.HasPrefix(filePrefix, ".") {
+			continue
+		}
 
 		// Build path based on type
 		var completionPath string
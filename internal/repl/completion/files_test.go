@@ -412,3 +412,31 @@ func TestFileCompletionsNonExistentDirectory(t *testing.T) {
 	results := GetFileCompletions("nonexistent/path/", "/tmp")
 	assert.Empty(t, results)
 }
+
+// TestPathCompletions_HiddenFiles verifies hidden entries are only offered once a "."
+// is typed when showHidden is false.
+func TestPathCompletions_HiddenFiles(t *testing.T) {
+	tmpDir := setupTestDirectory(t)
+	defer os.RemoveAll(tmpDir)
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"", []string{"file1.txt", "file2.txt", "folder1/", "folder2/"}},
+		{"./", []string{"./file1.txt", "./file2.txt", "./folder1/", "./folder2/"}},
+		{".", []string{".hidden"}},
+		{"./.h", []string{"./.hidden"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.expected, GetPathCompletions(tt.prefix, tmpDir, false))
+		})
+	}
+
+	assert.Contains(t, GetPathCompletions("", tmpDir, true), ".hidden")
+}
+
+func TestPathCompletions_BareTilde(t *testing.T) {
+	assert.Equal(t, []string{"~/"}, GetPathCompletions("~", "/tmp", true))
+}
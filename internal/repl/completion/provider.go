@@ -6,6 +6,7 @@ import (
 	"unicode"
 
 	"github.com/kunchenguid/gsh/internal/repl/completion/completers"
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
 	"mvdan.cc/sh/v3/interp"
 )
 
@@ -27,6 +28,15 @@ type Provider struct {
 	macroCompleter   *completers.MacroCompleter
 	builtinCompleter *completers.BuiltinCompleter
 	commandCompleter *completers.CommandCompleter
+	sdkCompleter     *completers.SDKCompleter
+	mentionCompleter *completers.MentionCompleter
+	showHiddenFiles  func() bool
+}
+
+// InterpreterProvider is optionally implemented by a RunnerProvider that also runs
+// gsh scripts. It enables completion of gsh SDK member paths such as gsh.tools.exec.
+type InterpreterProvider interface {
+	Interpreter() *interpreter.Interpreter
 }
 
 // NewProvider creates a new completion Provider.
@@ -36,13 +46,38 @@ func NewProvider(runnerProvider RunnerProvider) *Provider {
 		runner = runnerProvider.Runner()
 	}
 
-	return &Provider{
+	var completeMemberPath func(string) []string
+	var mentionNames func() []string
+	showHiddenFiles := func() bool { return true }
+	if ip, ok := runnerProvider.(InterpreterProvider); ok && ip.Interpreter() != nil {
+		interp := ip.Interpreter()
+		completeMemberPath = interp.CompleteMemberPath
+		mentionNames = interp.MentionNames
+		showHiddenFiles = func() bool {
+			replCtx := interp.SDKConfig().GetREPLContext()
+			return replCtx == nil || replCtx.CompleteHiddenFiles
+		}
+	}
+
+	p := &Provider{
 		specRegistry:     NewSpecRegistry(),
 		runnerProvider:   runnerProvider,
 		macroCompleter:   completers.NewMacroCompleter(runner),
 		builtinCompleter: completers.NewBuiltinCompleter(),
-		commandCompleter: completers.NewCommandCompleter(runner, runnerProvider.GetPwd, GetFileCompletions),
+		sdkCompleter:     completers.NewSDKCompleter(completeMemberPath),
+		mentionCompleter: completers.NewMentionCompleter(mentionNames),
+		showHiddenFiles:  showHiddenFiles,
 	}
+	p.commandCompleter = completers.NewCommandCompleter(runner, runnerProvider.GetPwd, func(prefix, currentDirectory string) []string {
+		return GetPathCompletions(prefix, currentDirectory, p.showHiddenFiles())
+	})
+	return p
+}
+
+// fileCompletions returns path completions for prefix relative to the working directory,
+// honoring gsh.completeHiddenFiles
+func (p *Provider) fileCompletions(prefix string) []string {
+	return GetPathCompletions(prefix, p.runnerProvider.GetPwd(), p.showHiddenFiles())
 }
 
 // RegisterSpec adds or updates a completion specification for a command.
@@ -62,6 +97,18 @@ func (p *Provider) GetCompletions(line string, pos int) []string {
 		return completion
 	}
 
+	// Complete @mentions of agents and models in agent chat ("# @analyst ...")
+	if completions := p.checkMention(line, pos); completions != nil {
+		return completions
+	}
+
+	// Complete gsh SDK member paths (gsh.tools.exec) anywhere on the line
+	if start, _ := p.getCurrentWordBoundary(line, pos); start >= 0 {
+		if completions := p.sdkCompleter.GetCompletions(line[start:pos]); len(completions) > 0 {
+			return completions
+		}
+	}
+
 	// Split the line into words, preserving quotes
 	line = line[:pos]
 	words := SplitPreservingQuotes(line)
@@ -124,7 +171,7 @@ func (p *Provider) GetCompletions(line string, pos int) []string {
 		return make([]string, 0)
 	}
 
-	completions := GetFileCompletions(prefix, p.runnerProvider.GetPwd())
+	completions := p.fileCompletions(prefix)
 
 	// Quote completions that contain spaces, but don't add command prefix
 	// The completion handler will replace only the current word (file path)
@@ -153,7 +200,7 @@ func (p *Provider) checkSpecialPrefixes(line string, pos int) []string {
 		if len(completions) == 0 {
 			// No macro matches found, fall back to path completion
 			pathPrefix := strings.TrimPrefix(currentWord, "#/")
-			completions := GetFileCompletions(pathPrefix, p.runnerProvider.GetPwd())
+			completions := p.fileCompletions(pathPrefix)
 
 			// Build the proper prefix for the current line context
 			var linePrefix string
@@ -173,7 +220,7 @@ func (p *Provider) checkSpecialPrefixes(line string, pos int) []string {
 		if len(completions) == 0 {
 			// No builtin command matches found, fall back to path completion
 			pathPrefix := strings.TrimPrefix(currentWord, "#!")
-			completions := GetFileCompletions(pathPrefix, p.runnerProvider.GetPwd())
+			completions := p.fileCompletions(pathPrefix)
 
 			// Build the proper prefix for the current line context
 			var linePrefix string
@@ -205,7 +252,7 @@ func (p *Provider) checkSpecialPrefixes(line string, pos int) []string {
 			if len(completions) == 0 {
 				// No macro matches found, fall back to path completion
 				pathPrefix := strings.TrimPrefix(potentialWord, "#/")
-				completions := GetFileCompletions(pathPrefix, p.runnerProvider.GetPwd())
+				completions := p.fileCompletions(pathPrefix)
 
 				// Build the proper prefix for the current line context
 				var linePrefix string
@@ -225,7 +272,7 @@ func (p *Provider) checkSpecialPrefixes(line string, pos int) []string {
 			if len(completions) == 0 {
 				// No builtin command matches found, fall back to path completion
 				pathPrefix := strings.TrimPrefix(potentialWord, "#!")
-				completions := GetFileCompletions(pathPrefix, p.runnerProvider.GetPwd())
+				completions := p.fileCompletions(pathPrefix)
 
 				// Build the proper prefix for the current line context
 				var linePrefix string
@@ -246,6 +293,29 @@ func (p *Provider) checkSpecialPrefixes(line string, pos int) []string {
 	return nil
 }
 
+// checkMention returns @mention completions when the line is an agent message ("#...")
+// and the word at the cursor starts with "@". Returns nil otherwise.
+func (p *Provider) checkMention(line string, pos int) []string {
+	if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return nil
+	}
+	start, _ := p.getCurrentWordBoundary(line, pos)
+	if start < 0 {
+		return nil
+	}
+	// The mention may directly follow the "#", as in "#@analyst"
+	word := line[start:pos]
+	lead := ""
+	if strings.HasPrefix(word, "#") {
+		lead, word = "#", word[1:]
+	}
+	completions := p.mentionCompleter.GetCompletions(word)
+	for i, completion := range completions {
+		completions[i] = lead + completion
+	}
+	return completions
+}
+
 // getCurrentWordBoundary finds the start and end of the current word at cursor position.
 func (p *Provider) getCurrentWordBoundary(line string, pos int) (int, int) {
 	if len(line) == 0 || pos > len(line) {
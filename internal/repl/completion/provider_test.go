@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"mvdan.cc/sh/v3/interp"
@@ -166,6 +167,79 @@ func TestProviderGetCompletionsMacros(t *testing.T) {
 	assert.NotContains(t, completions, "#/hello")
 }
 
+// mockInterpreterProvider is a RunnerProvider that also exposes a gsh interpreter.
+type mockInterpreterProvider struct {
+	mockRunnerProvider
+	interp *interpreter.Interpreter
+}
+
+func (m *mockInterpreterProvider) Interpreter() *interpreter.Interpreter {
+	return m.interp
+}
+
+func TestProviderGetCompletionsSDKMembers(t *testing.T) {
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	p := NewProvider(&mockInterpreterProvider{mockRunnerProvider: mockRunnerProvider{pwd: "/tmp"}, interp: interp})
+
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"gsh.tools.", []string{"gsh.tools.edit_file", "gsh.tools.exec", "gsh.tools.grep", "gsh.tools.view_file"}},
+		{"# what does gsh.tools.ex", []string{"gsh.tools.exec"}},
+		{"echo (gsh.ui.sp", []string{"(gsh.ui.spinner"}},
+		{"gsh.models.w", []string{"gsh.models.workhorse"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.GetCompletions(tt.line, len(tt.line)))
+		})
+	}
+
+	completions := p.GetCompletions("gsh.", 4)
+	assert.Contains(t, completions, "gsh.terminal")
+	assert.Contains(t, completions, "gsh.logging")
+	assert.Contains(t, completions, "gsh.tools")
+
+	// Without an interpreter, gsh paths fall through to regular completion
+	plain := NewProvider(&mockRunnerProvider{pwd: t.TempDir()})
+	assert.Empty(t, plain.GetCompletions("echo gsh.tools.", 15))
+}
+
+func TestProviderGetCompletionsMentions(t *testing.T) {
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	_, err := interp.EvalString(`
+model fast { provider: "openai", model: "gpt-5-mini" }
+agent analyst { model: fast }
+agent architect { model: fast }
+agent __hidden { model: fast }
+notAnAgent = "x"
+`, nil)
+	require.NoError(t, err)
+	p := NewProvider(&mockInterpreterProvider{mockRunnerProvider: mockRunnerProvider{pwd: t.TempDir()}, interp: interp})
+
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"# @", []string{"@analyst", "@architect", "@fast"}},
+		{"# @an", []string{"@analyst"}},
+		{"#@ar", []string{"#@architect"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.GetCompletions(tt.line, len(tt.line)))
+		})
+	}
+
+	assert.Empty(t, p.GetCompletions("# @nothing", 10))
+
+	// Outside agent chat, @ is not a mention
+	assert.NotContains(t, p.GetCompletions("echo @an", 8), "@analyst")
+}
+
 func TestProviderGetHelpInfoBuiltinCommands(t *testing.T) {
 	rp := &mockRunnerProvider{pwd: "/tmp"}
 	p := NewProvider(rp)
@@ -313,3 +387,21 @@ func TestProviderImplementsInterface(t *testing.T) {
 	_ = p.GetCompletions("test", 4)
 	_ = p.GetHelpInfo("test", 4)
 }
+
+func TestProviderGetCompletionsHiddenFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".env"), nil, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "src"), 0755))
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+	replCtx := &interpreter.REPLContext{CompleteHiddenFiles: true}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	p := NewProvider(&mockInterpreterProvider{mockRunnerProvider: mockRunnerProvider{pwd: tmpDir}, interp: interp})
+
+	assert.ElementsMatch(t, []string{".env", "src/"}, p.GetCompletions("cat ", 4))
+
+	replCtx.CompleteHiddenFiles = false
+	assert.Equal(t, []string{"src/"}, p.GetCompletions("cat ", 4))
+	assert.Equal(t, []string{".env"}, p.GetCompletions("cat .e", 6))
+}
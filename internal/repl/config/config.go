@@ -71,6 +71,14 @@ func (c *Config) GetMCPServer(name string) *mcp.MCPServer {
 	return c.MCPServers[name]
 }
 
+// remove drops any declaration with the given name.
+func (c *Config) remove(name string) {
+	delete(c.MCPServers, name)
+	delete(c.Models, name)
+	delete(c.Agents, name)
+	delete(c.Tools, name)
+}
+
 // Clone creates a deep copy of the Config.
 func (c *Config) Clone() *Config {
 	clone := &Config{
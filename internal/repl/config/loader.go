@@ -196,6 +196,15 @@ func (l *Loader) LoadDefaultConfigPathInto(interp *interpreter.Interpreter, defa
 		}
 	}
 
+	// gsh-language config is only read from ~/.gsh/repl.gsh. Point out a ~/.gshrc.gsh, since
+	// the name suggests it is loaded alongside ~/.gshrc and it would otherwise be silently ignored.
+	misplaced := filepath.Join(core.HomeDir(), ".gshrc.gsh")
+	if _, err := os.Stat(misplaced); err == nil {
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"%s is not loaded: gsh-language configuration belongs in %s (~/.gshrc is for bash syntax only)",
+			misplaced, userConfigPath))
+	}
+
 	// 3. Extract declarations (models, agents, tools, MCP servers)
 	l.ExtractConfigFromInterpreter(interp, result)
 
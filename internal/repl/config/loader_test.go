@@ -569,3 +569,24 @@ myModel = DEFAULT_MODEL
 	_, isModelValue := myModel.(*interpreter.ModelValue)
 	assert.True(t, isModelValue, "myModel should be a ModelValue")
 }
+
+func TestLoader_LoadDefaultConfigPathInto_GshrcGshNotLoaded(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	core.ResetPaths()
+	t.Cleanup(core.ResetPaths)
+
+	// gsh syntax in ~/.gshrc.gsh is easy to mistake for a supported config file
+	err := os.WriteFile(filepath.Join(tmpDir, ".gshrc.gsh"), []byte(`model Stray { provider: "openai", model: "gpt-4" }`), 0644)
+	require.NoError(t, err)
+
+	loader := NewLoader(nil)
+	interp := interpreter.New(nil)
+	result, err := loader.LoadDefaultConfigPathInto(interp, EmbeddedDefaults{})
+	require.NoError(t, err)
+
+	assert.Nil(t, result.Config.Models["Stray"], "~/.gshrc.gsh should not be evaluated")
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), ".gshrc.gsh is not loaded")
+	assert.Contains(t, result.Errors[0].Error(), filepath.Join(tmpDir, ".gsh", "repl.gsh"))
+}
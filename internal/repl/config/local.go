@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kunchenguid/gsh/internal/core"
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+	"go.uber.org/zap"
+)
+
+const (
+	// LocalConfigDir is the per-directory config directory name (e.g. <project>/.gsh)
+	LocalConfigDir = ".gsh"
+
+	// LocalConfigFile is the per-directory config file inside LocalConfigDir
+	LocalConfigFile = "config.gsh"
+
+	// trustFileName stores the local config files the user has allowed to run
+	trustFileName = "trusted_configs"
+)
+
+// TrustFile returns the path of the file recording trusted local configs (~/.gsh/trusted_configs).
+func TrustFile() string {
+	return filepath.Join(core.DataDir(), trustFileName)
+}
+
+// FindLocalConfig looks for a .gsh/config.gsh starting at dir and walking up to the
+// enclosing repository root (the first directory containing .git) or the filesystem root.
+// The user's own ~/.gsh directory is never treated as a local config.
+// Returns the absolute path of the config file, or "" if none was found.
+func FindLocalConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	userConfigDir := filepath.Clean(core.DataDir())
+
+	for {
+		configDir := filepath.Join(dir, LocalConfigDir)
+		if configDir != userConfigDir {
+			candidate := filepath.Join(configDir, LocalConfigFile)
+			if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() {
+				return candidate
+			}
+		}
+
+		// Stop at the repository root
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// IsLocalConfigTrusted reports whether configPath has been trusted with content, the
+// file's contents as read by the caller. Editing a trusted config invalidates the trust,
+// so changes must be reviewed again.
+func IsLocalConfigTrusted(trustFile, configPath string, content []byte) (bool, error) {
+	entries, err := readTrustEntries(trustFile)
+	if err != nil {
+		return false, err
+	}
+	return entries[configPath] == hashContent(content), nil
+}
+
+// TrustLocalConfig records configPath (with its current content) as trusted.
+func TrustLocalConfig(trustFile, configPath string) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	hash := hashContent(content)
+
+	entries, err := readTrustEntries(trustFile)
+	if err != nil {
+		return err
+	}
+	entries[configPath] = hash
+
+	var sb strings.Builder
+	for path, h := range entries {
+		fmt.Fprintf(&sb, "%s %s\n", h, path)
+	}
+	return os.WriteFile(trustFile, []byte(sb.String()), 0600)
+}
+
+// readTrustEntries parses the trust file into a map of config path to content hash.
+// Each line has the form "<sha256> <absolute path>".
+func readTrustEntries(trustFile string) (map[string]string, error) {
+	entries := make(map[string]string)
+
+	f, err := os.Open(trustFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", trustFile, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		hash, path, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		entries[path] = hash
+	}
+	return entries, scanner.Err()
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// LocalConfig records what a per-directory config changed when it was loaded, so
+// UnloadLocalConfig can undo it once the user leaves the config's directory.
+type LocalConfig struct {
+	// Path is the absolute path of the config file
+	Path string
+
+	added      []string                     // globals it introduced
+	shadowed   map[string]interpreter.Value // globals it replaced, with their previous values
+	handlers   map[string]string            // event handlers it registered (ID -> event)
+	mcpServers []*interpreter.MCPProxyValue // MCP servers it started
+}
+
+// LoadLocalConfigInto evaluates content, read from the per-directory config at configPath,
+// into an existing interpreter on top of whatever the global config already declared.
+// Models, agents, tools and MCP servers it declares are added to cfg; SDK assignments
+// (gsh.*) override global values. Callers are responsible for checking trust with the
+// same content before calling this, so the file can't change in between.
+// The returned LocalConfig is non-nil even on error, since evaluation may have stopped
+// partway through after declaring something.
+func (l *Loader) LoadLocalConfigInto(interp *interpreter.Interpreter, configPath string, content []byte, cfg *Config) (*LocalConfig, error) {
+	before := interp.GetVariables()
+	handlersBefore := interp.EventHandlerIDs()
+
+	// Imports resolve relative to the .gsh directory containing the config
+	_, evalErr := interp.EvalString(string(content), &interpreter.ScriptOrigin{
+		Type:     interpreter.OriginFilesystem,
+		BasePath: filepath.Dir(configPath),
+	})
+
+	// Extract whatever was declared, even if evaluation stopped partway through
+	l.ExtractConfigFromInterpreter(interp, &LoadResult{Config: cfg})
+
+	local := &LocalConfig{
+		Path:     configPath,
+		shadowed: make(map[string]interpreter.Value),
+		handlers: make(map[string]string),
+	}
+	globalServers := make(map[string]bool)
+	for _, value := range before {
+		if proxy, ok := value.(*interpreter.MCPProxyValue); ok {
+			globalServers[proxy.ServerName] = true
+		}
+	}
+	for name, value := range interp.GetVariables() {
+		prev, existed := before[name]
+		switch {
+		case !existed:
+			local.added = append(local.added, name)
+		case prev != value:
+			local.shadowed[name] = prev
+		default:
+			continue
+		}
+		if proxy, ok := value.(*interpreter.MCPProxyValue); ok && !globalServers[proxy.ServerName] {
+			local.mcpServers = append(local.mcpServers, proxy)
+		}
+	}
+	for id, eventName := range interp.EventHandlerIDs() {
+		if _, existed := handlersBefore[id]; !existed {
+			local.handlers[id] = eventName
+		}
+	}
+
+	if evalErr != nil {
+		return local, fmt.Errorf("%s: %w", configPath, evalErr)
+	}
+	if l.logger != nil {
+		l.logger.Debug("loaded local configuration", zap.String("path", configPath))
+	}
+	return local, nil
+}
+
+// UnloadLocalConfig undoes a config loaded by LoadLocalConfigInto: the globals it
+// introduced are removed, the ones it replaced get their previous values back, its
+// event handlers are unregistered and its MCP servers are stopped. cfg is updated to
+// match. SDK assignments (gsh.*) are not reverted.
+func (l *Loader) UnloadLocalConfig(interp *interpreter.Interpreter, local *LocalConfig, cfg *Config) {
+	env := interp.GlobalEnv()
+	for _, name := range local.added {
+		env.Delete(name)
+		cfg.remove(name)
+	}
+	for name, prev := range local.shadowed {
+		env.Set(name, prev)
+		cfg.remove(name)
+	}
+	l.ExtractConfigFromInterpreter(interp, &LoadResult{Config: cfg})
+
+	for id, eventName := range local.handlers {
+		interp.RemoveEventHandler(eventName, id)
+	}
+
+	for _, proxy := range local.mcpServers {
+		if err := proxy.Manager.RemoveServer(proxy.ServerName); err != nil && l.logger != nil {
+			l.logger.Warn("failed to stop MCP server", zap.String("server", proxy.ServerName), zap.Error(err))
+		}
+	}
+
+	if l.logger != nil {
+		l.logger.Debug("unloaded local configuration", zap.String("path", local.Path))
+	}
+}
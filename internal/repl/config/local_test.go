@@ -0,0 +1,187 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLocalConfig creates <dir>/.gsh/config.gsh with the given content and returns its path.
+func writeLocalConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	configDir := filepath.Join(dir, LocalConfigDir)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	configPath := filepath.Join(configDir, LocalConfigFile)
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	return configPath
+}
+
+func TestFindLocalConfig(t *testing.T) {
+	t.Run("finds config in the current directory", func(t *testing.T) {
+		root := t.TempDir()
+		configPath := writeLocalConfig(t, root, "")
+
+		assert.Equal(t, configPath, FindLocalConfig(root))
+	})
+
+	t.Run("walks up to the repository root", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+		configPath := writeLocalConfig(t, root, "")
+		nested := filepath.Join(root, "a", "b")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+
+		assert.Equal(t, configPath, FindLocalConfig(nested))
+	})
+
+	t.Run("does not look above the repository root", func(t *testing.T) {
+		outer := t.TempDir()
+		writeLocalConfig(t, outer, "")
+		repo := filepath.Join(outer, "repo")
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+
+		assert.Equal(t, "", FindLocalConfig(repo))
+	})
+
+	t.Run("nearest config wins", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+		writeLocalConfig(t, root, "")
+		sub := filepath.Join(root, "sub")
+		subConfig := writeLocalConfig(t, sub, "")
+
+		assert.Equal(t, subConfig, FindLocalConfig(sub))
+	})
+}
+
+func TestTrustLocalConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	trustFile := filepath.Join(tmpDir, "trusted_configs")
+	configPath := writeLocalConfig(t, tmpDir, `gsh.prompt = "local> "`)
+	otherPath := writeLocalConfig(t, filepath.Join(tmpDir, "other"), "")
+
+	content := func(path string) []byte {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return data
+	}
+
+	trusted, err := IsLocalConfigTrusted(trustFile, configPath, content(configPath))
+	require.NoError(t, err)
+	assert.False(t, trusted, "configs should be untrusted by default")
+
+	require.NoError(t, TrustLocalConfig(trustFile, configPath))
+	require.NoError(t, TrustLocalConfig(trustFile, otherPath))
+
+	trusted, err = IsLocalConfigTrusted(trustFile, configPath, content(configPath))
+	require.NoError(t, err)
+	assert.True(t, trusted)
+
+	// Editing the config revokes trust
+	require.NoError(t, os.WriteFile(configPath, []byte(`gsh.prompt = "changed> "`), 0644))
+	trusted, err = IsLocalConfigTrusted(trustFile, configPath, content(configPath))
+	require.NoError(t, err)
+	assert.False(t, trusted, "modified configs should need to be trusted again")
+
+	// Other entries are preserved
+	trusted, err = IsLocalConfigTrusted(trustFile, otherPath, content(otherPath))
+	require.NoError(t, err)
+	assert.True(t, trusted)
+}
+
+func TestLoader_LoadLocalConfigInto(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := writeLocalConfig(t, tmpDir, `
+model projectModel {
+	provider: "openai",
+	model: "gpt-4o",
+}
+`)
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+
+	loader := NewLoader(nil)
+	result, err := loader.LoadFromStringInto(interp, `
+model globalModel {
+	provider: "openai",
+	model: "gpt-4o-mini",
+}
+`)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	_, err = loader.LoadLocalConfigInto(interp, configPath, content, result.Config)
+	require.NoError(t, err)
+	assert.NotNil(t, result.Config.GetModel("globalModel"), "global declarations should be kept")
+	assert.NotNil(t, result.Config.GetModel("projectModel"), "local declarations should be added")
+}
+
+func TestLoader_LoadLocalConfigInto_Error(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := writeLocalConfig(t, tmpDir, `undefinedFunction()`)
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	local, err := NewLoader(nil).LoadLocalConfigInto(interp, configPath, content, DefaultConfig())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), configPath)
+	assert.NotNil(t, local, "a partially evaluated config can still be unloaded")
+}
+
+func TestLoader_UnloadLocalConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := writeLocalConfig(t, tmpDir, `
+model sharedModel {
+	provider: "openai",
+	model: "gpt-4o",
+}
+
+model projectModel {
+	provider: "openai",
+	model: "gpt-4o",
+}
+
+tool onPrompt(ctx, next) {
+	return next(ctx)
+}
+
+gsh.use("repl.prompt", onPrompt)
+`)
+
+	interp := interpreter.New(nil)
+	defer interp.Close()
+
+	loader := NewLoader(nil)
+	result, err := loader.LoadFromStringInto(interp, `
+model sharedModel {
+	provider: "openai",
+	model: "gpt-4o-mini",
+}
+`)
+	require.NoError(t, err)
+	globalModel := result.Config.GetModel("sharedModel")
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	local, err := loader.LoadLocalConfigInto(interp, configPath, content, result.Config)
+	require.NoError(t, err)
+	assert.NotSame(t, globalModel, result.Config.GetModel("sharedModel"), "local config should shadow the global model")
+	assert.Len(t, interp.GetEventHandlers("repl.prompt"), 1)
+
+	loader.UnloadLocalConfig(interp, local, result.Config)
+	assert.Same(t, globalModel, result.Config.GetModel("sharedModel"), "global model should be restored")
+	assert.Nil(t, result.Config.GetModel("projectModel"))
+	assert.Nil(t, result.Config.GetTool("onPrompt"))
+	_, ok := interp.GetVariables()["projectModel"]
+	assert.False(t, ok, "local declarations should be removed from the interpreter")
+	assert.Empty(t, interp.GetEventHandlers("repl.prompt"))
+}
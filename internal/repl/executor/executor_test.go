@@ -439,6 +439,77 @@ func TestREPLExecutor_AliasExists(t *testing.T) {
 	})
 }
 
+func TestREPLExecutor_AliasExpansion(t *testing.T) {
+	run := func(t *testing.T, exec *REPLExecutor, command string) (string, int) {
+		t.Helper()
+		outFile := filepath.Join(t.TempDir(), "out.txt")
+		exitCode, err := exec.ExecuteBash(context.Background(), "{ "+command+"; } > "+outFile+" 2>&1")
+		if err != nil {
+			t.Fatalf("ExecuteBash(%q) error = %v", command, err)
+		}
+		out, _ := os.ReadFile(outFile)
+		return string(out), exitCode
+	}
+
+	t.Run("expands the first word", func(t *testing.T) {
+		exec := newTestExecutor(t, nil)
+		defer exec.Close()
+
+		run(t, exec, `alias gs="echo git status"`)
+		out, _ := run(t, exec, "gs --short")
+		if out != "git status --short\n" {
+			t.Errorf("alias output = %q, want %q", out, "git status --short\n")
+		}
+	})
+
+	t.Run("does not expand arguments or quoted words", func(t *testing.T) {
+		exec := newTestExecutor(t, nil)
+		defer exec.Close()
+
+		run(t, exec, `alias gs="echo git status"`)
+		out, _ := run(t, exec, "echo gs")
+		if out != "gs\n" {
+			t.Errorf("argument output = %q, want %q", out, "gs\n")
+		}
+		// Quoting the first word bypasses the alias, so the command is looked up in PATH
+		_, err := exec.ExecuteBash(context.Background(), "'gs'")
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("ExecuteBash('gs') error = %v, want command not found", err)
+		}
+	})
+
+	t.Run("alias lists and unalias removes", func(t *testing.T) {
+		exec := newTestExecutor(t, nil)
+		defer exec.Close()
+
+		run(t, exec, `alias gs="git status"`)
+		out, _ := run(t, exec, "alias")
+		if out != "alias gs='git status'\n" {
+			t.Errorf("alias listing = %q", out)
+		}
+
+		run(t, exec, "unalias gs")
+		if exec.AliasExists("gs") {
+			t.Error("AliasExists() should return false after unalias")
+		}
+	})
+
+	t.Run("applies to scripts run from a reader", func(t *testing.T) {
+		exec := newTestExecutor(t, nil)
+		defer exec.Close()
+
+		outFile := filepath.Join(t.TempDir(), "out.txt")
+		script := "alias greet='echo hello'\ngreet world > " + outFile + "\n"
+		if err := exec.RunBashScriptFromReader(context.Background(), strings.NewReader(script), "test.sh"); err != nil {
+			t.Fatalf("RunBashScriptFromReader() error = %v", err)
+		}
+		out, _ := os.ReadFile(outFile)
+		if string(out) != "hello world\n" {
+			t.Errorf("script output = %q, want %q", out, "hello world\n")
+		}
+	})
+}
+
 func TestREPLExecutor_FunctionExists(t *testing.T) {
 	t.Run("returns false for undefined function", func(t *testing.T) {
 		exec := newTestExecutor(t, nil)
@@ -0,0 +1,226 @@
+package repl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+)
+
+// helpTopics lists the topics accepted by "help <topic>", in display order.
+var helpTopics = []string{"agent", "models", "agents", "config"}
+
+const generalHelpText = `gsh - the generative shell
+
+Type shell commands as you would in bash. Lines starting with # are sent to
+the agent instead of the shell.
+
+BUILTINS:
+  help, ?                Show this help
+  help <topic>           Show help on a topic (%s)
+  :models                List the model tiers and declared models
+  :agents                List the declared agents
+  exit                   Exit gsh (Ctrl+D on an empty line also works)
+
+AGENT:
+  # <message>            Chat with the agent
+  # @<name> <message>    Send one message to a declared agent or model
+  <cmd> | # <message>    Send a command's output to the agent with a message
+  # /clear               Start a new conversation
+  # /export <file>       Save the conversation as Markdown (--system adds the system prompt)
+  # /checkpoint <name>   Save the conversation to return to with "# /restore <name>"
+
+KEYS:
+  Tab                    Complete commands and file paths
+  Up/Down, Ctrl+R        Browse and search history
+  Ctrl+F                 Accept the command prediction
+
+Learn more: https://github.com/kunchenguid/gsh
+`
+
+const agentHelpText = `Talking to the agent
+
+Start a line with # to send it to the agent. The conversation continues
+across messages until you clear it:
+
+  # what is taking up space in this directory?
+  # now delete the largest log file
+  # /clear
+
+Use "# /export <file>" to save the conversation as a Markdown transcript.
+
+Save a checkpoint before trying something, and go back to it if it doesn't
+work out. "# /checkpoints" lists the checkpoints you have saved:
+
+  # /checkpoint before-refactor
+  # /restore before-refactor
+
+End a shell command with "| #" to send its output to the agent:
+
+  kubectl get pods | # why are these pending?
+
+Start a message with @ and the name of an agent or model declared in your
+config to send just that message to it. Tab completes the names:
+
+  # @reviewer look over my staged changes
+  # @opus explain why this test is flaky
+
+An agent answers with its own prompt and tools; a model answers as the
+default agent. Either way it sees the whole conversation, and the next
+message goes back to the default agent.
+
+The agent can run shell commands, search and view files, and edit files.
+It is told the current directory whenever it changes between messages.
+
+The agent uses the model in gsh.models.workhorse. Set it in ~/.gsh/repl.gsh,
+or run "help models" to see what is configured.
+`
+
+const configHelpText = `Configuration files
+
+  ~/.gshrc, ~/.gshenv    Bash-compatible aliases, functions, and variables
+  ~/.gsh/repl.gsh        gsh script: models, agents, tools, MCP servers, gsh.* settings
+  .gsh/config.gsh        Per-project config, loaded after 'gsh trust'
+
+Changes take effect in a new gsh session.
+`
+
+// helpText returns the help text for a topic ("" for general help).
+// Returns false if the topic is unknown.
+func (r *REPL) helpText(topic string) (string, bool) {
+	switch topic {
+	case "":
+		return fmt.Sprintf(generalHelpText, strings.Join(helpTopics, ", ")), true
+	case "agent":
+		return agentHelpText, true
+	case "models":
+		return r.modelsHelpText(), true
+	case "agents":
+		return r.agentsHelpText(), true
+	case "config":
+		return configHelpText, true
+	default:
+		return "", false
+	}
+}
+
+// modelsHelpText describes the model tiers and declared models.
+func (r *REPL) modelsHelpText() string {
+	var sb strings.Builder
+	sb.WriteString("Model tiers (gsh.models)\n\n")
+
+	models := r.executor.Interpreter().SDKConfig().GetModels()
+	tiers := []struct {
+		name  string
+		model *interpreter.ModelValue
+	}{
+		{"lite", models.Lite},
+		{"workhorse", models.Workhorse},
+		{"premium", models.Premium},
+	}
+	for _, tier := range tiers {
+		fmt.Fprintf(&sb, "  %-12s %s\n", tier.name, describeModel(tier.model))
+	}
+
+	names := visibleNames(r.config.Models)
+	if len(names) > 0 {
+		sb.WriteString("\nDeclared models\n\n")
+		tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  NAME\tPROVIDER\tMODEL")
+		for _, name := range names {
+			m := r.config.Models[name]
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, configString(m.Config, "provider"), configString(m.Config, "model"))
+		}
+		tw.Flush()
+	}
+
+	sb.WriteString("\nAssign a tier in ~/.gsh/repl.gsh, e.g. gsh.models.workhorse = myModel\n")
+	return sb.String()
+}
+
+// agentsHelpText lists the agents declared in the configuration.
+func (r *REPL) agentsHelpText() string {
+	names := visibleNames(r.config.Agents)
+	if len(names) == 0 {
+		return "No agents declared. Declare one in ~/.gsh/repl.gsh with: agent myAgent { ... }\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Declared agents\n\n")
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NAME\tMODEL\tTOOLS")
+	for _, name := range names {
+		agent := r.config.Agents[name]
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, agentModelName(agent), agentToolCount(agent))
+	}
+	tw.Flush()
+	return sb.String()
+}
+
+// agentModelName returns the name of an agent's model, or the tier it uses (e.g. gsh.models.workhorse).
+func agentModelName(agent *interpreter.AgentValue) string {
+	switch m := agent.Config["model"].(type) {
+	case *interpreter.ModelValue:
+		return m.Name
+	case *interpreter.SDKModelRef:
+		return m.String()
+	}
+	return "(no model)"
+}
+
+// agentToolCount describes how many tools an agent has. Tools computed by a tool
+// at run time can't be counted up front, so the computing tool is named instead.
+func agentToolCount(agent *interpreter.AgentValue) string {
+	switch tools := agent.Config["tools"].(type) {
+	case *interpreter.ArrayValue:
+		return fmt.Sprintf("%d", len(tools.Elements))
+	case *interpreter.ToolValue:
+		return fmt.Sprintf("computed by %s", tools.Name)
+	}
+	return "0"
+}
+
+// configString returns a string config field, or "-" if it isn't set.
+func configString(config map[string]interpreter.Value, key string) string {
+	if v, ok := config[key].(*interpreter.StringValue); ok && v.Value != "" {
+		return v.Value
+	}
+	return "-"
+}
+
+// describeModel formats a model as "name (provider/model)".
+func describeModel(m *interpreter.ModelValue) string {
+	if m == nil {
+		return "(not set)"
+	}
+	if details := modelDetails(m); details != "" {
+		return fmt.Sprintf("%s (%s)", m.Name, details)
+	}
+	return m.Name
+}
+
+// modelDetails formats a model's provider and model id as "provider/model".
+func modelDetails(m *interpreter.ModelValue) string {
+	var details []string
+	for _, key := range []string{"provider", "model"} {
+		if v, ok := m.Config[key].(*interpreter.StringValue); ok && v.Value != "" {
+			details = append(details, v.Value)
+		}
+	}
+	return strings.Join(details, "/")
+}
+
+// visibleNames returns the sorted keys of a declaration map, skipping internal
+// declarations (names starting with "__", such as the default agent).
+func visibleNames[T any](decls map[string]T) []string {
+	names := make([]string, 0, len(decls))
+	for name := range decls {
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
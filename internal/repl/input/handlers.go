@@ -180,7 +180,8 @@ func (m Model) handleInsertRunes(runes []rune) (tea.Model, tea.Cmd) {
 	return m.onTextChanged()
 }
 
-// handlePaste handles pasted text.
+// handlePaste handles pasted text, from a bracketed paste or Ctrl+V.
+// Newlines are kept so a multi-line paste is only run when the user presses Enter.
 func (m Model) handlePaste(text string) (tea.Model, tea.Cmd) {
 	// Sanitize pasted text
 	sanitized := sanitizeRunes([]rune(text))
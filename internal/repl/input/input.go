@@ -5,6 +5,9 @@
 package input
 
 import (
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"go.uber.org/zap"
 )
@@ -31,6 +34,18 @@ type Result struct {
 	Value string
 }
 
+// pasteBurstWindow is how soon after the previous key an Enter must arrive to be held back
+// as a possible pasted newline, and how long it is then held. Keys sent by a terminal for
+// pasted text arrive back to back, while even the fastest typing leaves tens of
+// milliseconds between keys.
+const pasteBurstWindow = 10 * time.Millisecond
+
+// pendingEnterMsg is sent once a held back Enter has waited pasteBurstWindow without
+// more input arriving behind it.
+type pendingEnterMsg struct {
+	seq int
+}
+
 // HistorySearchFunc is a function type for searching history.
 // It takes a query string and returns matching commands.
 type HistorySearchFunc func(query string) []string
@@ -46,6 +61,8 @@ type Model struct {
 	// Prompt
 	prompt             string
 	continuationPrompt string
+	agentPrompt        string
+	refreshPrompt      func() string
 
 	// History navigation
 	historyValues       []string
@@ -74,6 +91,12 @@ type Model struct {
 	// Info panel content (help text, etc.)
 	infoContent InfoPanelContent
 
+	// Paste detection for terminals without bracketed paste
+	now             func() time.Time
+	lastKeyAt       time.Time
+	pendingEnters   int
+	pendingEnterSeq int
+
 	// Result state
 	result Result
 
@@ -126,6 +149,14 @@ type Config struct {
 	// If empty, defaults to "> ".
 	ContinuationPrompt string
 
+	// AgentPrompt replaces Prompt while the input is an agent message (starts with "#").
+	// If empty, Prompt is always used.
+	AgentPrompt string
+
+	// RefreshPrompt renders the prompt again when a PromptRefreshMsg arrives. It is called
+	// from Update, so it never runs concurrently with completion.
+	RefreshPrompt func() string
+
 	// MinHeight is the minimum number of lines to render.
 	MinHeight int
 
@@ -174,6 +205,8 @@ func New(cfg Config) Model {
 		focused:            true,
 		prompt:             cfg.Prompt,
 		continuationPrompt: continuationPrompt,
+		agentPrompt:        cfg.AgentPrompt,
+		refreshPrompt:      cfg.RefreshPrompt,
 		historyValues:      cfg.HistoryValues,
 		historyIndex:       0,
 		historySearch:      NewHistorySearchState(),
@@ -184,6 +217,7 @@ func New(cfg Config) Model {
 		renderer:           renderer,
 		width:              width,
 		minHeight:          cfg.MinHeight,
+		now:                time.Now,
 		result:             Result{Type: ResultNone},
 		logger:             logger,
 	}
@@ -211,6 +245,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.hasReceivedResize = true
 		m.width = msg.Width
 		m.renderer.SetWidth(msg.Width)
+		m.renderer.SetHeight(msg.Height)
 		// Only clear the screen on actual resize to prevent duplicate prompt rendering.
 		// Don't clear on the initial WindowSizeMsg to preserve the welcome screen.
 		if isActualResize {
@@ -226,6 +261,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case pasteMsg:
 		return m.handlePaste(string(msg))
+
+	case pendingEnterMsg:
+		// Nothing followed the held back Enter, so the user pressed it
+		if msg.seq != m.pendingEnterSeq || m.pendingEnters == 0 {
+			return m, nil
+		}
+		m.pendingEnters = 0
+		m.completion.Reset()
+		return m.handleSubmit()
+
+	case PromptRefreshMsg:
+		if m.refreshPrompt != nil {
+			m.prompt = m.refreshPrompt()
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -239,7 +289,7 @@ func (m Model) View() string {
 	}
 
 	// Use history search prompt when in search mode
-	prompt := m.prompt
+	prompt := m.ActivePrompt()
 	showBufferCursor := m.focused
 	if m.historySearch.IsActive() {
 		// Show cursor in search prompt, not in the buffer
@@ -290,6 +340,10 @@ func (m Model) Focused() bool {
 	return m.focused
 }
 
+// PromptRefreshMsg asks a running input to render its prompt again with
+// Config.RefreshPrompt, e.g. after a background git status finishes.
+type PromptRefreshMsg struct{}
+
 // SetPrompt updates the prompt string.
 func (m *Model) SetPrompt(prompt string) {
 	m.prompt = prompt
@@ -300,6 +354,15 @@ func (m Model) Prompt() string {
 	return m.prompt
 }
 
+// ActivePrompt returns the prompt for the current input: the agent prompt while
+// the input is an agent message, otherwise the regular prompt.
+func (m Model) ActivePrompt() string {
+	if m.agentPrompt != "" && strings.HasPrefix(strings.TrimSpace(m.buffer.Text()), "#") {
+		return m.agentPrompt
+	}
+	return m.prompt
+}
+
 // ContinuationPrompt returns the continuation prompt for multi-line input.
 func (m Model) ContinuationPrompt() string {
 	return m.continuationPrompt
@@ -363,6 +426,41 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleHistorySearchKey(msg, action)
 	}
 
+	// Without bracketed paste, each pasted newline reaches us as an Enter key right behind
+	// the text before it. Keys typed while a command was running arrive the same way, so
+	// timing alone can't tell them apart. Such an Enter is held back instead: if more input
+	// is already queued behind it, it was a pasted newline, otherwise it submits once
+	// pendingEnterMsg arrives.
+	now := m.now()
+	burst := !m.lastKeyAt.IsZero() && now.Sub(m.lastKeyAt) < pasteBurstWindow
+	if action == ActionSubmit && !msg.Paste && (burst || m.pendingEnters > 0) {
+		m.lastKeyAt = now
+		m.pendingEnters++
+		if m.pendingEnters > 1 {
+			return m, nil
+		}
+		m.pendingEnterSeq++
+		seq := m.pendingEnterSeq
+		return m, tea.Tick(pasteBurstWindow, func(time.Time) tea.Msg {
+			return pendingEnterMsg{seq: seq}
+		})
+	}
+	if m.pendingEnters > 0 {
+		m.buffer.InsertRunes([]rune(strings.Repeat("\n", m.pendingEnters)))
+		m.pendingEnters = 0
+		m.historyIndex = 0
+		m.hasNavigatedHistory = false
+		m.completion.Reset()
+	}
+
+	// Text from a bracketed paste is inserted literally, newlines included. It must never
+	// reach the keymap, where a pasted newline would otherwise submit the input.
+	if msg.Paste {
+		m.completion.Reset()
+		return m.handlePaste(string(msg.Runes))
+	}
+	m.lastKeyAt = now
+
 	// When completion is active, handle navigation keys specially
 	if m.completion.IsActive() {
 		switch action {
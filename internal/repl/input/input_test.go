@@ -1,7 +1,9 @@
 package input
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -106,6 +108,48 @@ func TestModelPrompt(t *testing.T) {
 	}
 }
 
+func TestModelPromptRefreshMsg(t *testing.T) {
+	m := New(Config{Prompt: "$ ", RefreshPrompt: func() string { return "main $ " }})
+	m.SetValue("ls")
+
+	updated, _ := m.Update(PromptRefreshMsg{})
+	m = updated.(Model)
+	if m.Prompt() != "main $ " {
+		t.Errorf("expected 'main $ ', got '%s'", m.Prompt())
+	}
+	if m.Value() != "ls" {
+		t.Errorf("expected the input to be kept, got '%s'", m.Value())
+	}
+}
+
+func TestModelAgentPrompt(t *testing.T) {
+	m := New(Config{Prompt: "$ ", AgentPrompt: "🤖 "})
+
+	if m.ActivePrompt() != "$ " {
+		t.Errorf("expected '$ ' for empty input, got '%s'", m.ActivePrompt())
+	}
+
+	m.SetValue("ls -la")
+	if m.ActivePrompt() != "$ " {
+		t.Errorf("expected '$ ' for a shell command, got '%s'", m.ActivePrompt())
+	}
+
+	m.SetValue("  # what does this repo do?")
+	if m.ActivePrompt() != "🤖 " {
+		t.Errorf("expected agent prompt for '#' input, got '%s'", m.ActivePrompt())
+	}
+	if !strings.Contains(m.View(), "🤖 ") {
+		t.Error("expected view to render the agent prompt")
+	}
+
+	// Without an agent prompt, "#" input keeps the regular prompt
+	m = New(Config{Prompt: "$ "})
+	m.SetValue("# hello")
+	if m.ActivePrompt() != "$ " {
+		t.Errorf("expected '$ ' without an agent prompt, got '%s'", m.ActivePrompt())
+	}
+}
+
 func TestModelReset(t *testing.T) {
 	m := New(Config{
 		Prompt:        "$ ",
@@ -179,6 +223,176 @@ func TestSubmit(t *testing.T) {
 	}
 }
 
+func TestBracketedPaste(t *testing.T) {
+	t.Run("multi-line paste is inserted without submitting", func(t *testing.T) {
+		m := New(Config{})
+		m.SetValue("# ")
+
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo one\r\necho two\rdone"), Paste: true}
+		newModel, cmd := m.Update(msg)
+		m = newModel.(Model)
+
+		if m.result.Type != ResultNone {
+			t.Errorf("expected paste not to complete input, got %v", m.result.Type)
+		}
+		if cmd != nil {
+			if _, quit := cmd().(tea.QuitMsg); quit {
+				t.Error("expected paste not to quit the input")
+			}
+		}
+		if m.Value() != "# echo one\necho two\ndone" {
+			t.Errorf("expected pasted newlines to be kept, got %q", m.Value())
+		}
+
+		// Enter submits the whole paste as one input
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = newModel.(Model)
+		if m.result.Type != ResultSubmit || m.result.Value != "# echo one\necho two\ndone" {
+			t.Errorf("expected the pasted text to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+
+	t.Run("pasted text never triggers key bindings", func(t *testing.T) {
+		provider := &mockCompletionProvider{completions: []string{"file1.txt", "file2.txt"}}
+		m := New(Config{CompletionProvider: provider})
+		m.SetValue("fil")
+
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = newModel.(Model)
+		if !m.completion.IsActive() {
+			t.Fatal("expected completion to be active")
+		}
+
+		// A pasted newline must not accept the completion and submit
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("\r"), Paste: true})
+		m = newModel.(Model)
+		if m.result.Type != ResultNone {
+			t.Errorf("expected paste not to submit, got %v", m.result.Type)
+		}
+		if m.completion.IsActive() {
+			t.Error("expected paste to end completion")
+		}
+		if m.Value() != "file1.txt\n" {
+			t.Errorf("expected the newline to be inserted, got %q", m.Value())
+		}
+	})
+}
+
+func TestUnbracketedPaste(t *testing.T) {
+	clock := time.Unix(0, 0)
+	send := func(m Model, spacing time.Duration, msgs ...tea.KeyMsg) Model {
+		for _, msg := range msgs {
+			clock = clock.Add(spacing)
+			newModel, _ := m.Update(msg)
+			m = newModel.(Model)
+		}
+		return m
+	}
+	// settle delivers the timeout for a held back Enter, as if no more input arrived
+	settle := func(m Model) Model {
+		newModel, _ := m.Update(pendingEnterMsg{seq: m.pendingEnterSeq})
+		return newModel.(Model)
+	}
+
+	t.Run("pasted newlines are kept", func(t *testing.T) {
+		m := New(Config{})
+		m.now = func() time.Time { return clock }
+
+		// A terminal without bracketed paste sends the text and its newlines as separate keys, back to back
+		m = send(m, time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo")},
+			tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("one")},
+			tea.KeyMsg{Type: tea.KeyEnter},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo")},
+			tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("two")},
+		)
+		if m.result.Type != ResultNone {
+			t.Fatalf("expected pasted Enter not to submit, got %v with %q", m.result.Type, m.Value())
+		}
+		if m.Value() != "echo one\necho two" {
+			t.Errorf("expected pasted newlines to be kept, got %q", m.Value())
+		}
+
+		// An Enter pressed by the user afterwards submits the whole paste
+		m = send(m, time.Second, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.result.Type != ResultSubmit || m.result.Value != "echo one\necho two" {
+			t.Errorf("expected the pasted text to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+
+	t.Run("trailing pasted newline submits the whole paste", func(t *testing.T) {
+		m := New(Config{})
+		m.now = func() time.Time { return clock }
+		m = send(m, time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo one")},
+			tea.KeyMsg{Type: tea.KeyEnter},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo two")},
+			tea.KeyMsg{Type: tea.KeyEnter},
+		)
+		if m.result.Type != ResultNone {
+			t.Fatalf("expected the last Enter to be held back, got %v", m.result.Type)
+		}
+		m = settle(m)
+		if m.result.Type != ResultSubmit || m.result.Value != "echo one\necho two" {
+			t.Errorf("expected the pasted text to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+
+	t.Run("typeahead still submits", func(t *testing.T) {
+		// Keys typed while a command ran are delivered back to back once the prompt returns
+		m := New(Config{})
+		m.now = func() time.Time { return clock }
+		clock = clock.Add(time.Second)
+		m = send(m, time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}},
+			tea.KeyMsg{Type: tea.KeyEnter},
+		)
+		m = settle(m)
+		if m.result.Type != ResultSubmit || m.result.Value != "ls" {
+			t.Errorf("expected typeahead to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+
+	t.Run("stale timeout is ignored", func(t *testing.T) {
+		m := New(Config{})
+		m.now = func() time.Time { return clock }
+		m = send(m, time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")},
+			tea.KeyMsg{Type: tea.KeyEnter},
+		)
+		stale := pendingEnterMsg{seq: m.pendingEnterSeq}
+		m = send(m, time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")},
+			tea.KeyMsg{Type: tea.KeyEnter},
+		)
+		newModel, _ := m.Update(stale)
+		m = newModel.(Model)
+		if m.result.Type != ResultNone {
+			t.Fatalf("expected a stale timeout not to submit, got %v", m.result.Type)
+		}
+		m = settle(m)
+		if m.result.Type != ResultSubmit || m.result.Value != "a\nb" {
+			t.Errorf("expected the pasted text to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+
+	t.Run("typing at human speed submits on Enter", func(t *testing.T) {
+		m := New(Config{})
+		m.now = func() time.Time { return clock }
+		m = send(m, 80*time.Millisecond,
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
+			tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}},
+			tea.KeyMsg{Type: tea.KeyEnter},
+		)
+		if m.result.Type != ResultSubmit || m.result.Value != "ls" {
+			t.Errorf("expected typed input to be submitted, got %v %q", m.result.Type, m.result.Value)
+		}
+	})
+}
+
 func TestInterrupt(t *testing.T) {
 	m := New(Config{})
 	m.SetValue("partial input")
@@ -538,6 +752,9 @@ func TestWindowResize(t *testing.T) {
 	if m.width != 120 {
 		t.Errorf("expected width 120, got %d", m.width)
 	}
+	if m.renderer.Height() != 40 {
+		t.Errorf("expected renderer height 40, got %d", m.renderer.Height())
+	}
 
 	if cmd != nil {
 		t.Error("expected nil command on initial window size, got non-nil")
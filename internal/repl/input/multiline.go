@@ -16,10 +16,13 @@ func IsInputComplete(input string) bool {
 		return true
 	}
 
-	// gsh agent commands (start with #) are always complete
+	// gsh agent commands (start with #, or pipe a command into one) are always complete
 	if strings.HasPrefix(trimmed, "#") {
 		return true
 	}
+	if _, _, ok := SplitAgentPipe(trimmed); ok {
+		return true
+	}
 
 	// Append a newline before parsing to properly detect heredocs and other
 	// constructs that require a newline to trigger IsIncomplete in mvdan/sh.
@@ -30,3 +33,38 @@ func IsInputComplete(input string) bool {
 	}
 	return !syntax.IsIncomplete(err)
 }
+
+// SplitAgentPipe splits input of the form "<command> | # <message>", which sends the
+// command's output to the agent, into the command and the message.
+// The pipe must be outside quotes and not part of "||". Returns false for any other input.
+func SplitAgentPipe(input string) (command, message string, ok bool) {
+	var quote byte
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\\' && quote != '\'':
+			i++ // skip the escaped character
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || input[i-1] == ' ' || input[i-1] == '\t'):
+			// The rest of the line is a shell comment
+			return "", "", false
+		case c == '|':
+			if i+1 < len(input) && input[i+1] == '|' {
+				i++ // "||" is a shell operator, not a pipe
+				continue
+			}
+			rest := strings.TrimLeft(input[i+1:], " \t")
+			command = strings.TrimSpace(input[:i])
+			if !strings.HasPrefix(rest, "#") || command == "" {
+				continue
+			}
+			return command, strings.TrimSpace(rest[1:]), true
+		}
+	}
+	return "", "", false
+}
@@ -80,6 +80,7 @@ func TestIsInputComplete(t *testing.T) {
 		// gsh agent commands (always complete)
 		{"agent command", "#hello", true},
 		{"agent command with space", "# send a message", true},
+		{"command piped to agent", "kubectl get pods | # why are these pending", true},
 	}
 
 	for _, tt := range tests {
@@ -92,6 +93,40 @@ func TestIsInputComplete(t *testing.T) {
 	}
 }
 
+func TestSplitAgentPipe(t *testing.T) {
+	tests := []struct {
+		input   string
+		command string
+		message string
+		ok      bool
+	}{
+		{"kubectl get pods | # why are these pending", "kubectl get pods", "why are these pending", true},
+		{"git diff|#review this", "git diff", "review this", true},
+		{"cat a.log | grep ERROR | #explain", "cat a.log | grep ERROR", "explain", true},
+		{"ls |#", "ls", "", true},
+		{"echo 'a | # b' | # what", "echo 'a | # b'", "what", true},
+
+		{"ls | grep x", "", "", false},
+		{"ls |", "", "", false},
+		{"| # no command", "", "", false},
+		{"false || # comment", "", "", false},
+		{`echo "a | # b"`, "", "", false},
+		{`echo a \| # b`, "", "", false},
+		{"echo hi # comment | # not a pipe", "", "", false},
+		{"# hello | # world", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			command, message, ok := SplitAgentPipe(tt.input)
+			if ok != tt.ok || command != tt.command || message != tt.message {
+				t.Errorf("SplitAgentPipe(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.input, command, message, ok, tt.command, tt.message, tt.ok)
+			}
+		})
+	}
+}
+
 func TestSubmitIncompleteInput(t *testing.T) {
 	m := New(Config{})
 	m.SetValue(`echo "test`)
@@ -360,10 +360,10 @@ func (ps *PredictionState) PredictionSuggestion(input string) string {
 }
 
 // LLMPredictionProvider implements PredictionProvider using an LLM model.
+// Requests go through the model, so they fire model.request and model.response.
 type LLMPredictionProvider struct {
-	model    *interpreter.ModelValue
-	provider interpreter.ModelProvider
-	logger   *zap.Logger
+	model  *interpreter.ModelValue
+	logger *zap.Logger
 
 	// Context text for predictions (e.g., cwd, git status)
 	contextText   string
@@ -373,16 +373,14 @@ type LLMPredictionProvider struct {
 // NewLLMPredictionProvider creates a new LLM prediction provider.
 func NewLLMPredictionProvider(
 	model *interpreter.ModelValue,
-	provider interpreter.ModelProvider,
 	logger *zap.Logger,
 ) *LLMPredictionProvider {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &LLMPredictionProvider{
-		model:    model,
-		provider: provider,
-		logger:   logger,
+		model:  model,
+		logger: logger,
 	}
 }
 
@@ -401,7 +399,7 @@ type predictedCommandResponse struct {
 // Predict implements PredictionProvider.
 // Note: LLMPredictionProvider ignores the trigger and existing prediction parameters as it always does LLM-based prediction.
 func (p *LLMPredictionProvider) Predict(ctx context.Context, input string, trigger interpreter.PredictTrigger, existingPrediction string) (string, error) {
-	if p.model == nil || p.provider == nil {
+	if p.model == nil || p.model.Provider == nil {
 		return "", nil
 	}
 
@@ -456,7 +454,7 @@ Respond with JSON in this format: {"predicted_command": "your prediction here"}
 		},
 	}
 
-	response, err := p.provider.ChatCompletion(ctx, request)
+	response, err := p.model.ChatCompletion(ctx, request)
 	if err != nil {
 		return "", err
 	}
@@ -203,7 +203,7 @@ func (m Model) renderFinalView() string {
 	}
 
 	// Render final input line without cursor/prediction
-	return m.renderer.RenderInputLine(m.prompt, m.buffer, "", false)
+	return m.renderer.RenderInputLine(m.ActivePrompt(), m.buffer, "", false)
 }
 
 // predictionResultMsg wraps a PredictionResult for the tea.Msg interface.
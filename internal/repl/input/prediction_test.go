@@ -499,14 +499,14 @@ func TestPredictionState_ProviderError(t *testing.T) {
 
 func TestLLMPredictionProvider(t *testing.T) {
 	t.Run("nil model returns empty", func(t *testing.T) {
-		provider := NewLLMPredictionProvider(nil, nil, nil)
+		provider := NewLLMPredictionProvider(nil, nil)
 		prediction, err := provider.Predict(context.Background(), "test", interpreter.PredictTriggerDebounced, "")
 		assert.NoError(t, err)
 		assert.Equal(t, "", prediction)
 	})
 
 	t.Run("update context", func(t *testing.T) {
-		provider := NewLLMPredictionProvider(nil, nil, nil)
+		provider := NewLLMPredictionProvider(nil, nil)
 		provider.UpdateContext("cwd: /home/user")
 
 		provider.contextTextMu.RLock()
@@ -544,9 +544,12 @@ func (m *mockModelProvider) StreamingChatCompletion(ctx context.Context, request
 }
 
 func TestLLMPredictionProvider_WithMockProvider(t *testing.T) {
-	model := &interpreter.ModelValue{
-		Name:   "test-model",
-		Config: map[string]interpreter.Value{},
+	model := func(provider interpreter.ModelProvider) *interpreter.ModelValue {
+		return &interpreter.ModelValue{
+			Name:     "test-model",
+			Config:   map[string]interpreter.Value{},
+			Provider: provider,
+		}
 	}
 
 	t.Run("prefix prediction", func(t *testing.T) {
@@ -556,7 +559,7 @@ func TestLLMPredictionProvider_WithMockProvider(t *testing.T) {
 			},
 		}
 
-		provider := NewLLMPredictionProvider(model, mockProvider, nil)
+		provider := NewLLMPredictionProvider(model(mockProvider), nil)
 		prediction, err := provider.Predict(context.Background(), "git", interpreter.PredictTriggerDebounced, "")
 
 		assert.NoError(t, err)
@@ -570,7 +573,7 @@ func TestLLMPredictionProvider_WithMockProvider(t *testing.T) {
 			},
 		}
 
-		provider := NewLLMPredictionProvider(model, mockProvider, nil)
+		provider := NewLLMPredictionProvider(model(mockProvider), nil)
 		prediction, err := provider.Predict(context.Background(), "", interpreter.PredictTriggerDebounced, "")
 
 		assert.NoError(t, err)
@@ -582,7 +585,7 @@ func TestLLMPredictionProvider_WithMockProvider(t *testing.T) {
 			err: errors.New("API error"),
 		}
 
-		provider := NewLLMPredictionProvider(model, mockProvider, nil)
+		provider := NewLLMPredictionProvider(model(mockProvider), nil)
 		_, err := provider.Predict(context.Background(), "test", interpreter.PredictTriggerDebounced, "")
 
 		assert.Error(t, err)
@@ -595,7 +598,7 @@ func TestLLMPredictionProvider_WithMockProvider(t *testing.T) {
 			},
 		}
 
-		provider := NewLLMPredictionProvider(model, mockProvider, nil)
+		provider := NewLLMPredictionProvider(model(mockProvider), nil)
 		prediction, err := provider.Predict(context.Background(), "test", interpreter.PredictTriggerDebounced, "")
 
 		assert.NoError(t, err)
@@ -54,6 +54,7 @@ func DefaultRenderConfig() RenderConfig {
 type Renderer struct {
 	config             RenderConfig
 	width              int
+	height             int
 	highlighter        *Highlighter
 	continuationPrompt string
 }
@@ -96,6 +97,19 @@ func (r *Renderer) Width() int {
 	return r.width
 }
 
+// SetHeight sets the terminal height. When set, RenderFullView scrolls the input
+// region so the full view never exceeds it. Zero disables the limit.
+func (r *Renderer) SetHeight(height int) {
+	if height >= 0 {
+		r.height = height
+	}
+}
+
+// Height returns the terminal height, or 0 if unknown.
+func (r *Renderer) Height() int {
+	return r.height
+}
+
 // Config returns the current render configuration.
 func (r *Renderer) Config() RenderConfig {
 	return r.config
@@ -711,6 +725,9 @@ func (r *Renderer) RenderHistorySearchPrompt(state *HistorySearchState, showCurs
 // - Input line with prompt, text, cursor, and prediction
 // - Completion box (if active)
 // - Info/help panel (if available)
+//
+// If a terminal height is set and the view would exceed it, the input region is
+// scrolled to keep the cursor line visible, with indicators for hidden lines.
 func (r *Renderer) RenderFullView(
 	prompt string,
 	buffer *Buffer,
@@ -726,22 +743,36 @@ func (r *Renderer) RenderFullView(
 	// This handles cases where log output may have left the cursor mid-line
 	result.WriteString("\r\033[K")
 
-	// Render input line
-	result.WriteString(r.RenderInputLine(prompt, buffer, prediction, focused))
+	// Render the panels below the input first so we know how much room the input has
+	var below strings.Builder
 
 	// Render completion box if active
 	if completion != nil && completion.IsVisible() {
-		result.WriteString("\n")
-		result.WriteString(r.RenderCompletionBox(completion, 4))
+		below.WriteString("\n")
+		below.WriteString(r.RenderCompletionBox(completion, 4))
 	}
 
 	// Render info panel content
 	if infoContent != nil && infoContent.IsVisible() {
-		result.WriteString("\n")
-		result.WriteString(r.RenderInfoPanel(infoContent))
+		below.WriteString("\n")
+		below.WriteString(r.RenderInfoPanel(infoContent))
 	}
 
-	// Ensure minimum height
+	// Render input line, scrolled if it doesn't fit in the terminal
+	input := r.RenderInputLine(prompt, buffer, prediction, focused)
+	if r.height > 0 {
+		maxInputLines := maxInt(1, r.height-strings.Count(below.String(), "\n"))
+		if strings.Count(input, "\n") >= maxInputLines {
+			input = r.scrollInputLines(input, r.cursorLine(prompt, buffer), maxInputLines)
+		}
+	}
+	result.WriteString(input)
+	result.WriteString(below.String())
+
+	// Ensure minimum height, without growing past the terminal
+	if r.height > 0 && minHeight > r.height-1 {
+		minHeight = r.height - 1
+	}
 	output := result.String()
 	numLines := strings.Count(output, "\n")
 	if numLines < minHeight {
@@ -751,6 +782,68 @@ func (r *Renderer) RenderFullView(
 	return output
 }
 
+// cursorLine returns the index of the rendered line holding the cursor. Line breaks
+// depend only on character widths, so rendering the text up to the cursor is enough.
+func (r *Renderer) cursorLine(prompt string, buffer *Buffer) int {
+	runes := buffer.Runes()
+	pos := buffer.Pos()
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(runes) {
+		pos = len(runes)
+	}
+	return strings.Count(r.RenderInputLine(prompt, NewBufferWithText(string(runes[:pos])), "", true), "\n")
+}
+
+// scrollInputLines trims rendered input to at most maxLines lines around the cursor
+// line, replacing the hidden lines above and below with scroll indicators.
+func (r *Renderer) scrollInputLines(rendered string, cursorLine, maxLines int) string {
+	lines := strings.Split(rendered, "\n")
+	total := len(lines)
+	if total <= maxLines {
+		return rendered
+	}
+
+	cursorLine = maxInt(0, minInt(cursorLine, total-1))
+	if maxLines < 2 {
+		return lines[cursorLine] + "\x1b[0m"
+	}
+
+	// Each indicator takes a line, so the window shrinks by one per hidden side
+	indicatorStyle := lipgloss.NewStyle().Foreground(render.ColorGray)
+	var start, end int
+	switch oneSide := maxLines - 1; {
+	case cursorLine < oneSide:
+		start, end = 0, oneSide
+	case cursorLine >= total-oneSide:
+		start, end = total-oneSide, total
+	default:
+		bothSides := maxInt(1, maxLines-2)
+		start = cursorLine - bothSides/2
+		end = start + bothSides
+	}
+
+	var out []string
+	if start > 0 {
+		out = append(out, indicatorStyle.Render(formatHiddenLines("↑", start)))
+	}
+	out = append(out, lines[start:end]...)
+	if end < total && len(out) < maxLines {
+		out = append(out, indicatorStyle.Render(formatHiddenLines("↓", total-end)))
+	}
+	// Reset styling so highlighting from a cut-off line can't bleed into the panels below
+	return strings.Join(out, "\n") + "\x1b[0m"
+}
+
+// formatHiddenLines formats a scroll indicator for lines hidden above or below.
+func formatHiddenLines(arrow string, count int) string {
+	if count == 1 {
+		return arrow + " 1 more line"
+	}
+	return arrow + " " + itoa(count) + " more lines"
+}
+
 // GetPredictionSuffix returns the portion of the prediction that extends beyond
 // the current input text. Returns empty string if no valid prediction.
 func GetPredictionSuffix(text, prediction string) string {
@@ -847,6 +940,14 @@ func itoa(n int) string {
 	return string(digits)
 }
 
+// minInt returns the smaller of two integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // maxInt returns the larger of two integers.
 func maxInt(a, b int) int {
 	if a > b {
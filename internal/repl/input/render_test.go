@@ -1,6 +1,7 @@
 package input
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -306,6 +307,90 @@ func TestRenderFullViewMinHeight(t *testing.T) {
 	}
 }
 
+func TestRenderFullViewScrollsToTerminalHeight(t *testing.T) {
+	renderer := NewRenderer(DefaultRenderConfig(), nil)
+	renderer.SetWidth(80)
+	renderer.SetHeight(5)
+
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("line%02d", i))
+	}
+	buffer := NewBufferWithText(strings.Join(lines, "\n"))
+
+	tests := []struct {
+		name     string
+		pos      int
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "cursor at end",
+			pos:      buffer.Len(),
+			contains: []string{"↑ 16 more lines", "line16", "line19"},
+			excludes: []string{"line15"},
+		},
+		{
+			name:     "cursor at start",
+			pos:      0,
+			contains: []string{"line01", "line03", "↓ 16 more lines"},
+			excludes: []string{"↑", "line04"},
+		},
+		{
+			name:     "cursor in the middle",
+			pos:      strings.Index(buffer.Text(), "line10"),
+			contains: []string{"↑ 9 more lines", "line09", "line11", "↓ 8 more lines"},
+			excludes: []string{"line08", "line12"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer.SetPos(tt.pos)
+			result := renderer.RenderFullView("$ ", buffer, "", true, nil, nil, 0)
+			plain := ansi.Strip(result)
+			if n := strings.Count(result, "\n") + 1; n > 5 {
+				t.Errorf("expected at most 5 lines, got %d:\n%s", n, plain)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(plain, want) {
+					t.Errorf("expected %q in view:\n%s", want, plain)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(plain, unwanted) {
+					t.Errorf("did not expect %q in view:\n%s", unwanted, plain)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderFullViewScrollLeavesRoomForPanels(t *testing.T) {
+	renderer := NewRenderer(DefaultRenderConfig(), nil)
+	renderer.SetWidth(80)
+	renderer.SetHeight(8)
+	buffer := NewBufferWithText(strings.Repeat("echo hi\n", 10) + "echo done")
+
+	help := NewHelpContent("Command help")
+	result := renderer.RenderFullView("$ ", buffer, "", true, nil, help, 0)
+	plain := ansi.Strip(result)
+	if n := strings.Count(result, "\n") + 1; n > 8 {
+		t.Errorf("expected at most 8 lines, got %d:\n%s", n, plain)
+	}
+	for _, want := range []string{"more lines", "echo done", "Command help"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in view:\n%s", want, plain)
+		}
+	}
+
+	// Without a known height, nothing is scrolled
+	renderer.SetHeight(0)
+	result = renderer.RenderFullView("$ ", buffer, "", true, nil, help, 0)
+	if strings.Contains(ansi.Strip(result), "more lines") {
+		t.Errorf("expected no scroll indicator without a terminal height")
+	}
+}
+
 func TestGetPredictionSuffix(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,208 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kunchenguid/gsh/internal/keyring"
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// defaultOllamaURL is where the key setup looks for local models
+const defaultOllamaURL = "http://localhost:11434"
+
+// keySetup asks, at the start of an interactive session, for API keys that the model
+// tiers read from an env var or keyring secret that isn't set
+type keySetup struct {
+	in         io.Reader // read a byte at a time, leaving typeahead for the input
+	out        io.Writer
+	readSecret func() (string, error) // reads a key without echoing it
+	ollamaURL  string
+}
+
+// newTerminalKeySetup returns a keySetup that talks to the user on stdin and stdout
+func newTerminalKeySetup() *keySetup {
+	return &keySetup{
+		in:  os.Stdin,
+		out: os.Stdout,
+		readSecret: func() (string, error) {
+			key, err := term.ReadPassword(int(os.Stdin.Fd()))
+			return string(key), err
+		},
+		ollamaURL: defaultOllamaURL,
+	}
+}
+
+// setupMissingAPIKeys offers, for each model tier whose API key is missing, to store the
+// key in the OS keyring or to use a local Ollama model instead for this session.
+// Setting gsh.setupMissingKeys = false turns it off.
+func (r *REPL) setupMissingAPIKeys(ctx context.Context, s *keySetup) {
+	if replCtx := r.executor.Interpreter().SDKConfig().GetREPLContext(); replCtx != nil && !replCtx.SetupMissingKeys {
+		return
+	}
+	models := r.executor.Interpreter().SDKConfig().GetModels()
+	if models == nil {
+		return
+	}
+
+	// Group the tiers by model, so a model used by several tiers is asked about once
+	var missing []*interpreter.ModelValue
+	tiers := map[*interpreter.ModelValue][]string{}
+	for _, tier := range []struct {
+		name  string
+		model *interpreter.ModelValue
+	}{
+		{"lite", models.Lite},
+		{"workhorse", models.Workhorse},
+		{"premium", models.Premium},
+	} {
+		if tier.model == nil || tier.model.MissingAPIKey == "" {
+			continue
+		}
+		if _, seen := tiers[tier.model]; !seen {
+			missing = append(missing, tier.model)
+		}
+		tiers[tier.model] = append(tiers[tier.model], tier.name)
+	}
+
+	for _, model := range missing {
+		source := model.MissingAPIKey
+		name := source[strings.Index(source, ".")+1:]
+
+		// A key saved here in an earlier session is used even if the config still reads env
+		if strings.HasPrefix(source, "env.") {
+			if stored, err := keyring.Get(name); err == nil {
+				model.SetAPIKey(stored)
+				continue
+			}
+		}
+
+		fmt.Fprintf(s.out, "Model %s (%s) reads its API key from %s, which isn't set.\n", model.Name, tierList(tiers[model]), source)
+		fmt.Fprintf(s.out, "Paste the key to save it in the OS keyring, or press Enter to skip: ")
+		key, err := s.readSecret()
+		fmt.Fprintln(s.out)
+		if err != nil {
+			return
+		}
+
+		if key = strings.TrimSpace(key); key != "" {
+			model.SetAPIKey(key)
+			if err := keyring.Set(name, key); err != nil {
+				r.logger.Warn("failed to store API key in keyring", zap.String("name", name), zap.Error(err))
+				fmt.Fprintf(s.out, "Couldn't save it to the OS keyring (%v), so it's only used for this session.\n\n", err)
+			} else if strings.HasPrefix(source, "keyring.") {
+				fmt.Fprintf(s.out, "Saved %s to the OS keyring.\n\n", name)
+			} else {
+				fmt.Fprintf(s.out, "Saved %s to the OS keyring, where later sessions find it. To use it in scripts too, change %s's apiKey to keyring.%s.\n\n", name, model.Name, name)
+			}
+			continue
+		}
+
+		if !r.pickLocalModel(ctx, s, tiers[model]) {
+			fmt.Fprintf(s.out, "Skipped. %s won't work until %s is set. To stop being asked, set gsh.setupMissingKeys = false.\n\n", tierList(tiers[model]), source)
+		}
+	}
+}
+
+// tierList formats tier names for messages, e.g. "gsh.models.lite and gsh.models.workhorse"
+func tierList(tiers []string) string {
+	return "gsh.models." + strings.Join(tiers, " and gsh.models.")
+}
+
+// pickLocalModel lists the models a local Ollama server has and, if the user picks one,
+// assigns it to tiers for this session. It reports whether a model was picked.
+func (r *REPL) pickLocalModel(ctx context.Context, s *keySetup, tiers []string) bool {
+	names, err := listOllamaModels(ctx, s.ollamaURL)
+	if err != nil || len(names) == 0 {
+		if err != nil {
+			r.logger.Debug("no local Ollama models available", zap.Error(err))
+		}
+		return false
+	}
+
+	fmt.Fprintln(s.out, "Local models available from Ollama:")
+	for idx, name := range names {
+		fmt.Fprintf(s.out, "  %d) %s\n", idx+1, name)
+	}
+	fmt.Fprintf(s.out, "Pick one to use instead for this session, or press Enter to skip: ")
+	line := readLine(s.in)
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(names) {
+		return false
+	}
+
+	var source strings.Builder
+	fmt.Fprintf(&source, "model __localModel {\n    provider: \"openai\",\n    apiKey: \"ollama\",\n    model: %s,\n    baseURL: %s,\n}\n",
+		strconv.Quote(names[choice-1]), strconv.Quote(s.ollamaURL+"/v1"))
+	for _, tier := range tiers {
+		fmt.Fprintf(&source, "gsh.models.%s = __localModel\n", tier)
+	}
+	if _, err := r.executor.Interpreter().EvalString(source.String(), nil); err != nil {
+		r.logger.Warn("failed to configure local model", zap.Error(err))
+		return false
+	}
+	fmt.Fprintf(s.out, "Using %s for %s in this session.\n\n", names[choice-1], tierList(tiers))
+	return true
+}
+
+// readLine reads up to the next newline one byte at a time, so nothing typed after it
+// is consumed before the input takes over stdin
+func readLine(in io.Reader) string {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			break
+		}
+	}
+	return string(line)
+}
+
+// listOllamaModels returns the names of the models an Ollama server at baseURL has pulled
+func listOllamaModels(ctx context.Context, baseURL string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tags.Models))
+	for _, model := range tags.Models {
+		names = append(names, model.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,186 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Markdown styles. Lip Gloss drops the colors when output isn't a color terminal or
+// NO_COLOR is set, leaving the layout.
+var (
+	markdownHeadingStyle = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+	markdownCodeStyle    = lipgloss.NewStyle().Foreground(ColorCyan)
+	markdownDimStyle     = lipgloss.NewStyle().Foreground(ColorGray)
+	markdownBoldStyle    = lipgloss.NewStyle().Bold(true)
+	markdownItalicStyle  = lipgloss.NewStyle().Italic(true)
+	markdownLinkStyle    = lipgloss.NewStyle().Underline(true)
+)
+
+var (
+	markdownHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+	markdownRuleRe    = regexp.MustCompile(`^\s*((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	markdownQuoteRe   = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	markdownListRe    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownFenceRe   = regexp.MustCompile("^\\s*(```|~~~)")
+
+	markdownCodeSpanRe = regexp.MustCompile("`([^`]+)`")
+	markdownLinkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBoldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalicRe   = regexp.MustCompile(`\*([^*\s][^*]*)\*|(^|[^\w])_([^_\s][^_]*)_([^\w]|$)`)
+)
+
+// RenderMarkdown renders Markdown for the terminal: headings, lists, block quotes,
+// horizontal rules, fenced code blocks, and bold, italic, code and links inside text.
+// Paragraphs, list items and quotes are word-wrapped to width; code blocks are not.
+// A width <= 0 disables wrapping.
+func RenderMarkdown(text string, width int) string {
+	var out []string
+	var paragraph []string
+	inCode := false
+	fence := ""
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			out = append(out, wrapMarkdown(renderMarkdownInline(strings.Join(paragraph, " ")), width, "", ""))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if inCode {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				inCode = false
+				continue
+			}
+			out = append(out, markdownCodeStyle.Render("  "+line))
+			continue
+		}
+
+		if match := markdownFenceRe.FindStringSubmatch(line); match != nil {
+			flush()
+			inCode = true
+			fence = match[1]
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			// Keep a single blank line between blocks
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+
+		if match := markdownHeadingRe.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			out = append(out, markdownHeadingStyle.Render(stripMarkdownInline(match[2])))
+			continue
+		}
+
+		if markdownRuleRe.MatchString(line) {
+			flush()
+			ruleWidth := width
+			if ruleWidth <= 0 || ruleWidth > 80 {
+				ruleWidth = 80
+			}
+			out = append(out, markdownDimStyle.Render(strings.Repeat("─", ruleWidth)))
+			continue
+		}
+
+		if match := markdownQuoteRe.FindStringSubmatch(line); match != nil {
+			flush()
+			bar := markdownDimStyle.Render("│ ")
+			out = append(out, wrapMarkdown(markdownItalicStyle.Render(renderMarkdownInline(match[1])), width, bar, bar))
+			continue
+		}
+
+		if match := markdownListRe.FindStringSubmatch(line); match != nil {
+			flush()
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(match[1], "\t", "  ")))
+			marker := match[2]
+			if marker == "-" || marker == "*" || marker == "+" {
+				marker = "•"
+			}
+			first := indent + markdownHeadingStyle.UnsetBold().Render(marker) + " "
+			rest := indent + strings.Repeat(" ", lipgloss.Width(marker)+1)
+			out = append(out, wrapMarkdown(renderMarkdownInline(match[3]), width, first, rest))
+			continue
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+	flush()
+
+	// Drop blank lines at the end
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// wrapMarkdown wraps styled text to width, starting the first line with first and the
+// rest with rest
+func wrapMarkdown(text string, width int, first, rest string) string {
+	if width > 0 {
+		if available := width - lipgloss.Width(first); available > 0 {
+			text = ansi.Wordwrap(text, available, "")
+		}
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else {
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderMarkdownInline styles code spans, links, bold and italic text
+func renderMarkdownInline(text string) string {
+	var sb strings.Builder
+	last := 0
+	for _, span := range markdownCodeSpanRe.FindAllStringSubmatchIndex(text, -1) {
+		sb.WriteString(renderMarkdownEmphasis(text[last:span[0]]))
+		sb.WriteString(markdownCodeStyle.Render(text[span[2]:span[3]]))
+		last = span[1]
+	}
+	sb.WriteString(renderMarkdownEmphasis(text[last:]))
+	return sb.String()
+}
+
+// renderMarkdownEmphasis styles links, bold and italic text outside code spans
+func renderMarkdownEmphasis(text string) string {
+	text = markdownLinkRe.ReplaceAllStringFunc(text, func(link string) string {
+		match := markdownLinkRe.FindStringSubmatch(link)
+		if match[1] == match[2] {
+			return markdownLinkStyle.Render(match[2])
+		}
+		return markdownLinkStyle.Render(match[1]) + " " + markdownDimStyle.Render("("+match[2]+")")
+	})
+	text = markdownBoldRe.ReplaceAllStringFunc(text, func(bold string) string {
+		match := markdownBoldRe.FindStringSubmatch(bold)
+		return markdownBoldStyle.Render(match[1] + match[2])
+	})
+	return markdownItalicRe.ReplaceAllStringFunc(text, func(italic string) string {
+		match := markdownItalicRe.FindStringSubmatch(italic)
+		if match[1] != "" {
+			return markdownItalicStyle.Render(match[1])
+		}
+		return match[2] + markdownItalicStyle.Render(match[3]) + match[4]
+	})
+}
+
+// stripMarkdownInline removes inline markers, for headings that are styled as a whole
+func stripMarkdownInline(text string) string {
+	text = markdownCodeSpanRe.ReplaceAllString(text, "$1")
+	text = markdownLinkRe.ReplaceAllString(text, "$1")
+	text = markdownBoldRe.ReplaceAllString(text, "$1$2")
+	return markdownItalicRe.ReplaceAllString(text, "$1$2$3$4")
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	// Without colors only the layout is left, which is what these cases check
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{
+			name:     "heading",
+			input:    "## Setup **steps** ##",
+			expected: "Setup steps",
+		},
+		{
+			name:     "bullets and numbers",
+			input:    "- one\n* two\n  + nested\n1. first",
+			expected: "• one\n• two\n  • nested\n1. first",
+		},
+		{
+			name:     "list item wraps with a hanging indent",
+			input:    "- alpha beta gamma delta",
+			width:    14,
+			expected: "• alpha beta\n  gamma delta",
+		},
+		{
+			name:     "paragraph lines are joined and wrapped",
+			input:    "one two\nthree four five",
+			width:    10,
+			expected: "one two\nthree four\nfive",
+		},
+		{
+			name:     "code block is indented and not wrapped",
+			input:    "Run:\n```bash\nls -la | grep `x`\n```\nDone",
+			width:    5,
+			expected: "Run:\n  ls -la | grep `x`\nDone",
+		},
+		{
+			name:     "inline markers are removed",
+			input:    "Use `go test` with **care** and *patience*, see [docs](https://go.dev)",
+			expected: "Use go test with care and patience, see docs (https://go.dev)",
+		},
+		{
+			name:     "underscores inside words are kept",
+			input:    "set my_var_name to _this_",
+			expected: "set my_var_name to this",
+		},
+		{
+			name:     "block quote",
+			input:    "> note this",
+			expected: "│ note this",
+		},
+		{
+			name:     "horizontal rule uses the width",
+			input:    "a\n\n---\n\nb",
+			width:    5,
+			expected: "a\n\n─────\n\nb",
+		},
+		{
+			name:     "blank lines collapse",
+			input:    "a\n\n\n\nb\n\n",
+			expected: "a\n\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderMarkdown(tt.input, tt.width))
+		})
+	}
+}
+
+func TestRenderMarkdownStyles(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.ANSI)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+
+	rendered := RenderMarkdown("# Title\n\nSome **bold** text", 80)
+	assert.True(t, strings.Contains(rendered, "\x1b["), "expected ANSI styling, got %q", rendered)
+	assert.Equal(t, "Title\n\nSome bold text", ansi.Strip(rendered))
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StreamWrapper word-wraps text that arrives in pieces, such as a streamed agent response.
+// It remembers the current column between writes and holds back a word until it has seen
+// the end of it, so a word split across two chunks is never broken across lines.
+// The zero value is ready to use.
+type StreamWrapper struct {
+	column int
+	width  int    // width from the last Write, used by Flush
+	space  string // whitespace seen since the last word, written only if the next word fits
+	word   strings.Builder
+}
+
+// Write returns text wrapped to width columns, ready to print. Part of the last word may be
+// held back until a later Write or Flush. A width <= 0 disables wrapping.
+func (w *StreamWrapper) Write(text string, width int) string {
+	w.width = width
+	var out strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\n':
+			w.place(&out, width)
+			w.space = ""
+			out.WriteRune('\n')
+			w.column = 0
+		case ' ', '\t':
+			w.place(&out, width)
+			w.space += string(r)
+		default:
+			w.word.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// Flush returns any held-back text and resets the column, for when the caller is about to
+// print something else that starts on a new line.
+func (w *StreamWrapper) Flush() string {
+	var out strings.Builder
+	w.place(&out, w.width)
+	w.space = ""
+	w.column = 0
+	return out.String()
+}
+
+// place writes the pending word, starting a new line first if it doesn't fit on this one.
+// Whitespace before a word that moves to a new line is dropped.
+func (w *StreamWrapper) place(out *strings.Builder, width int) {
+	if w.word.Len() == 0 {
+		return
+	}
+	word := w.word.String()
+	w.word.Reset()
+
+	wordWidth := lipgloss.Width(word)
+	spaceWidth := lipgloss.Width(w.space)
+	if width > 0 && w.column > 0 && w.column+spaceWidth+wordWidth > width {
+		out.WriteRune('\n')
+		w.column = 0
+	} else {
+		out.WriteString(w.space)
+		w.column += spaceWidth
+	}
+	w.space = ""
+	out.WriteString(word)
+	w.column += wordWidth
+}
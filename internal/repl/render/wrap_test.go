@@ -0,0 +1,80 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamWrapper(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		width  int
+		want   string
+	}{
+		{
+			name:   "wraps at word boundaries",
+			chunks: []string{"the quick brown fox jumps over the lazy dog"},
+			width:  15,
+			want:   "the quick brown\nfox jumps over\nthe lazy dog",
+		},
+		{
+			name:   "word split across chunks stays whole",
+			chunks: []string{"the quick br", "own fox jum", "ps"},
+			width:  12,
+			want:   "the quick\nbrown fox\njumps",
+		},
+		{
+			name:   "existing newlines reset the column and keep indentation",
+			chunks: []string{"a list:\n  - first item here\n  - second"},
+			width:  12,
+			want:   "a list:\n  - first\nitem here\n  - second",
+		},
+		{
+			name:   "long words are not split",
+			chunks: []string{"see https://example.com/a/very/long/path now"},
+			width:  10,
+			want:   "see\nhttps://example.com/a/very/long/path\nnow",
+		},
+		{
+			name:   "zero width disables wrapping",
+			chunks: []string{"the quick brown fox"},
+			width:  0,
+			want:   "the quick brown fox",
+		},
+		{
+			name:   "wide characters count as two columns",
+			chunks: []string{"你好 世界 你好"},
+			width:  10,
+			want:   "你好 世界\n你好",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w StreamWrapper
+			var out strings.Builder
+			for _, chunk := range tt.chunks {
+				out.WriteString(w.Write(chunk, tt.width))
+			}
+			out.WriteString(w.Flush())
+			if out.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestStreamWrapperHoldsPartialWord(t *testing.T) {
+	var w StreamWrapper
+	if got := w.Write("hello wor", 80); got != "hello" {
+		t.Errorf("expected the partial word to be held back, got %q", got)
+	}
+	if got := w.Flush(); got != " wor" {
+		t.Errorf("expected Flush to return the held text, got %q", got)
+	}
+	// Flush resets the column, so the next write starts a fresh line
+	if got := w.Write("abcde ", 5); got != "abcde" {
+		t.Errorf("expected a fresh line after Flush, got %q", got)
+	}
+}
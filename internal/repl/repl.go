@@ -10,7 +10,9 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -53,7 +55,45 @@ type REPL struct {
 	startTime      time.Time
 	startupTracker StartupTimeTracker
 
+	// Per-directory config tracking (.gsh/config.gsh)
+	trustFile        string
+	localConfig      *config.LocalConfig // loaded for the current directory, if any
+	untrustedConfigs map[string]bool     // already warned about
+
 	sigintChannelFactory func() (chan os.Signal, func())
+
+	// program is the input program while the user is typing, so the prompt can be
+	// rendered again from the background (gsh.promptGitAsync)
+	program   *tea.Program
+	programMu sync.Mutex
+
+	// promptTool tracks calls to a tool assigned to gsh.prompt
+	promptTool promptToolState
+}
+
+// promptToolTimeout is how long rendering waits for a gsh.prompt tool. A slower call is
+// cancelled and the last prompt it built is shown instead.
+const promptToolTimeout = 500 * time.Millisecond
+
+// promptToolState remembers the last prompt a gsh.prompt tool built, and whether a call
+// is still running, so a tool that ignores cancellation isn't started again meanwhile
+type promptToolState struct {
+	mu      sync.Mutex
+	last    string
+	running bool
+	done    chan struct{} // closed when the running call returns
+}
+
+// wait blocks until a prompt tool call that outlived its timeout has returned. The call
+// runs on the shared interpreter, so nothing else may evaluate gsh code until it's done.
+func (s *promptToolState) wait() {
+	s.mu.Lock()
+	done := s.done
+	running := s.running
+	s.mu.Unlock()
+	if running {
+		<-done
+	}
 }
 
 // Options holds configuration options for creating a new REPL.
@@ -84,9 +124,16 @@ type Options struct {
 	// If empty, the default path is used.
 	HistoryPath string
 
+	// TrustFile is the path to the list of trusted per-directory configs.
+	// If empty, the default path (~/.gsh/trusted_configs) is used.
+	TrustFile string
+
 	// Logger is the logger to use. If nil, a no-op logger is used.
 	Logger *zap.Logger
 
+	// LogFile is the path Logger writes to, reported by gsh.logging.file.
+	LogFile string
+
 	// ExecMiddleware is optional middleware for command execution.
 	ExecMiddleware []executor.ExecMiddleware
 
@@ -119,6 +166,7 @@ func NewREPL(opts Options) (*REPL, error) {
 	// Create ONE interpreter that will be shared by executor, config, and renderer
 	interp := interpreter.New(&interpreter.Options{
 		Logger:  logger,
+		LogFile: opts.LogFile,
 		Version: opts.BuildVersion,
 		Runner:  opts.Runner,
 	})
@@ -142,6 +190,11 @@ func NewREPL(opts Options) (*REPL, error) {
 			ExitCode:   0,
 			DurationMs: 0,
 		},
+		ShowWelcome:         true,
+		EditDiffColor:       true,
+		ConfirmCommands:     defaultConfirmCommands(),
+		CompleteHiddenFiles: true,
+		SetupMissingKeys:    true,
 	}
 	interp.SDKConfig().SetREPLContext(replCtx)
 
@@ -226,6 +279,11 @@ func NewREPL(opts Options) (*REPL, error) {
 	// Initialize completion provider
 	completionProvider := completion.NewProvider(exec)
 
+	trustFile := opts.TrustFile
+	if trustFile == "" {
+		trustFile = config.TrustFile()
+	}
+
 	repl := &REPL{
 		config:             loadResult.Config,
 		executor:           exec,
@@ -235,6 +293,8 @@ func NewREPL(opts Options) (*REPL, error) {
 		logger:             logger,
 		startTime:          opts.StartTime,
 		startupTracker:     opts.StartupTracker,
+		trustFile:          trustFile,
+		untrustedConfigs:   make(map[string]bool),
 		sigintChannelFactory: func() (chan os.Signal, func()) {
 			ch := make(chan os.Signal, 1)
 			signal.Notify(ch, syscall.SIGINT)
@@ -242,6 +302,11 @@ func NewREPL(opts Options) (*REPL, error) {
 		},
 	}
 
+	// Layer any trusted per-directory config on top of the global config
+	repl.loadLocalConfig()
+
+	interp.SetPromptRefreshHandler(repl.refreshPrompt)
+
 	return repl, nil
 }
 
@@ -266,8 +331,13 @@ func (r *REPL) Run(ctx context.Context) error {
 	// Cache hostname for OSC 7 escape sequences (terminal CWD tracking)
 	hostname, _ := os.Hostname()
 
+	// Ask for missing model API keys before the welcome screen lists the models
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		r.setupMissingAPIKeys(ctx, newTerminalKeySetup())
+	}
+
 	// Emit repl.ready event (welcome screen is handled by event handler in defaults/events/repl.gsh)
-	r.executor.Interpreter().EmitEvent(interpreter.EventReplReady, interpreter.CreateReplReadyContext())
+	r.executor.Interpreter().EmitEvent(interpreter.EventReplReady, interpreter.CreateReplReadyContext(r.executor.GetPwd(), hostname))
 
 	// Track startup time - this is when the user actually sees the welcome screen
 	if r.startupTracker != nil && !r.startTime.IsZero() {
@@ -301,6 +371,9 @@ func (r *REPL) Run(ctx context.Context) error {
 			emitOSC7(os.Stdout, hostname, dir)
 		}
 
+		// Pick up per-directory config after a directory change
+		r.loadLocalConfig()
+
 		// Get prompt - emits repl.prompt event internally
 		prompt := r.getPrompt()
 
@@ -320,6 +393,8 @@ func (r *REPL) Run(ctx context.Context) error {
 		inputModel := input.New(input.Config{
 			Prompt:             prompt,
 			ContinuationPrompt: r.getContinuationPrompt(),
+			AgentPrompt:        r.getAgentPrompt(),
+			RefreshPrompt:      r.getPrompt,
 			HistoryValues:      historyValues,
 			HistorySearchFunc:  r.createHistorySearchFunc(),
 			CompletionProvider: r.completionProvider,
@@ -337,7 +412,9 @@ func (r *REPL) Run(ctx context.Context) error {
 			tea.WithOutput(os.Stderr),
 		)
 
+		r.setProgram(p)
 		finalModel, err := p.Run()
+		r.setProgram(nil)
 		if err != nil {
 			// Check if it's a context cancellation
 			if ctx.Err() != nil {
@@ -370,7 +447,7 @@ func (r *REPL) Run(ctx context.Context) error {
 			// We use \r to return to start of line since Bubble Tea may leave cursor mid-line
 			// For multi-line input, show continuation prompts on subsequent lines
 			lines := strings.Split(result.Value, "\n")
-			fmt.Print("\r" + model.Prompt() + lines[0])
+			fmt.Print("\r" + model.ActivePrompt() + lines[0])
 			for _, line := range lines[1:] {
 				fmt.Print("\n" + model.ContinuationPrompt() + line)
 			}
@@ -392,6 +469,12 @@ func (r *REPL) Run(ctx context.Context) error {
 
 // processCommand handles a submitted command.
 func (r *REPL) processCommand(ctx context.Context, command string) error {
+	// A timed out gsh.prompt tool may still be winding down on the interpreter
+	r.promptTool.wait()
+
+	// Check history exclusions against the command as typed, before a leading space is trimmed
+	recordHistory := r.history != nil && !r.configureHistory().Ignores(command)
+
 	// Trim whitespace
 	command = strings.TrimSpace(command)
 
@@ -442,7 +525,7 @@ func (r *REPL) processCommand(ctx context.Context, command string) error {
 	// Record ALL user input in history (including agent commands like "#...")
 	// This is done before middleware so all user input is captured
 	var historyEntry *history.HistoryEntry
-	if r.history != nil {
+	if recordHistory {
 		entry, err := r.history.StartCommand(command, r.executor.GetPwd())
 		if err != nil {
 			r.logger.Debug("failed to record command in history", zap.Error(err))
@@ -451,6 +534,32 @@ func (r *REPL) processCommand(ctx context.Context, command string) error {
 		}
 	}
 
+	// "<command> | # <message>" sends the command's output to the agent along with the message
+	if pipedCommand, message, ok := input.SplitAgentPipe(command); ok {
+		// The piped command runs right here, so it's confirmed before the agent sees it
+		if !r.confirmCommand(pipedCommand) {
+			fmt.Fprintln(os.Stderr, "gsh: command not run")
+			r.recordLastCommand(command, 1, 0)
+			if historyEntry != nil {
+				if _, finishErr := r.history.FinishCommand(historyEntry, 1); finishErr != nil {
+					r.logger.Debug("failed to finish history entry", zap.Error(finishErr))
+				}
+			}
+			return nil
+		}
+		agentInput := r.agentPipeInput(cmdCtx, pipedCommand, message)
+		if interrupted {
+			r.recordLastCommand(command, 130, 0)
+			if historyEntry != nil {
+				if _, finishErr := r.history.FinishCommand(historyEntry, 130); finishErr != nil {
+					r.logger.Debug("failed to finish history entry", zap.Error(finishErr))
+				}
+			}
+			return nil
+		}
+		command = agentInput
+	}
+
 	// Emit command.input event to middleware chain
 	inputCtx := &interpreter.ObjectValue{
 		Properties: map[string]*interpreter.PropertyDescriptor{
@@ -461,6 +570,8 @@ func (r *REPL) processCommand(ctx context.Context, command string) error {
 
 	// Check if we were interrupted during middleware execution
 	if interrupted {
+		// Record the interruption so the next prompt reflects it
+		r.recordLastCommand(command, 130, 0)
 		if historyEntry != nil {
 			// Record as interrupted (exit code 130 is standard for SIGINT)
 			if _, finishErr := r.history.FinishCommand(historyEntry, 130); finishErr != nil {
@@ -511,6 +622,18 @@ func (r *REPL) processCommand(ctx context.Context, command string) error {
 		return err // Will be ErrExit if user wants to exit
 	}
 
+	// Ask before running commands that match a gsh.confirmCommands pattern
+	if !r.confirmCommand(command) {
+		fmt.Fprintln(os.Stderr, "gsh: command not run")
+		r.recordLastCommand(command, 1, 0)
+		if historyEntry != nil {
+			if _, finishErr := r.history.FinishCommand(historyEntry, 1); finishErr != nil {
+				r.logger.Debug("failed to finish history entry", zap.Error(finishErr))
+			}
+		}
+		return nil
+	}
+
 	// Fall through: execute as shell command
 	ignoreSigint.Store(true)
 	exitCode := r.executeShellCommand(cmdCtx, command)
@@ -527,6 +650,42 @@ func (r *REPL) processCommand(ctx context.Context, command string) error {
 	return nil
 }
 
+// defaultConfirmCommands returns the patterns gsh.confirmCommands starts with:
+// recursive forced rm, dd, forced git push and mkfs
+func defaultConfirmCommands() []*regexp.Regexp {
+	// rm's recursive and force flags may come in either order, combined or separate,
+	// short or long (rm -rf, rm -f -r, rm --recursive --force)
+	args := `(\s+[^\s;&|]+)*\s+`
+	recursive := `(-[a-zA-Z]*[rR][a-zA-Z]*|--recursive)`
+	force := `(-[a-zA-Z]*f[a-zA-Z]*|--force)`
+	combined := `-[a-zA-Z]*([rR][a-zA-Z]*f|f[a-zA-Z]*[rR])[a-zA-Z]*`
+	rm := `\brm(` + args + recursive + args + force + `|` + args + force + args + recursive + `|` + args + combined + `)(\s|$)`
+
+	return []*regexp.Regexp{
+		regexp.MustCompile(rm),
+		regexp.MustCompile(`\bdd\s`),
+		regexp.MustCompile(`\bgit\s+push\b.*\s(-f|--force)(\s|$)`),
+		regexp.MustCompile(`\bmkfs\b`),
+	}
+}
+
+// confirmCommand reports whether command may run. Commands matching a gsh.confirmCommands
+// pattern only run if a repl.command.confirm handler returns { approved: true }.
+func (r *REPL) confirmCommand(command string) bool {
+	replCtx := r.executor.Interpreter().SDKConfig().GetREPLContext()
+	if replCtx == nil {
+		return true
+	}
+	for _, pattern := range replCtx.ConfirmCommands {
+		if pattern.MatchString(command) {
+			result := r.executor.Interpreter().EmitEvent(interpreter.EventReplCommandConfirm,
+				interpreter.CreateReplCommandConfirmContext(command, pattern.String()))
+			return interpreter.CommandConfirmed(result)
+		}
+	}
+	return true
+}
+
 // executeShellCommand executes a command via the shell (mvdan/sh).
 // This is the fall-through path when middleware doesn't handle input.
 // Note: History recording is done in processCommand before this is called.
@@ -541,11 +700,7 @@ func (r *REPL) executeShellCommand(ctx context.Context, command string) int {
 	duration := timeNow().Sub(startTime)
 
 	// Update last exit code and duration
-	r.lastExitCode = exitCode
-	r.lastDurationMs = duration.Milliseconds()
-
-	// Update the interpreter's REPL context with the last command info
-	r.executor.Interpreter().SDKConfig().UpdateLastCommand(command, exitCode, r.lastDurationMs)
+	r.recordLastCommand(command, exitCode, duration.Milliseconds())
 
 	// Emit repl.command.after event with command, exit code, and duration
 	r.executor.Interpreter().EmitEvent(interpreter.EventReplCommandAfter, interpreter.CreateReplCommandAfterContext(command, exitCode, r.lastDurationMs))
@@ -558,6 +713,64 @@ func (r *REPL) executeShellCommand(ctx context.Context, command string) int {
 	return exitCode
 }
 
+// recordLastCommand stores the exit code and duration of the last command so they are
+// available to the next repl.prompt event and via gsh.lastCommand.
+func (r *REPL) recordLastCommand(command string, exitCode int, durationMs int64) {
+	r.lastExitCode = exitCode
+	r.lastDurationMs = durationMs
+	r.executor.Interpreter().SDKConfig().UpdateLastCommand(command, exitCode, durationMs)
+}
+
+// loadLocalConfig loads the .gsh/config.gsh for the current working directory (found by
+// walking up to the repository root) on top of the global config. Local configs only run
+// once the user has trusted them with 'gsh trust', which is checked before every prompt
+// so a config trusted mid-session is picked up. A loaded config is unloaded again once
+// the current directory no longer resolves to it, and reloaded on re-entry.
+func (r *REPL) loadLocalConfig() {
+	configPath := ""
+	if dir := r.executor.GetPwd(); dir != "" {
+		configPath = config.FindLocalConfig(dir)
+	}
+
+	if r.localConfig != nil {
+		if r.localConfig.Path == configPath {
+			return
+		}
+		config.NewLoader(r.logger).UnloadLocalConfig(r.executor.Interpreter(), r.localConfig, r.config)
+		r.localConfig = nil
+	}
+	if configPath == "" {
+		return
+	}
+
+	// Trust is checked against the same bytes that get evaluated
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		r.logger.Warn("failed to read local config", zap.String("path", configPath), zap.Error(err))
+		return
+	}
+	trusted, err := config.IsLocalConfigTrusted(r.trustFile, configPath, content)
+	if err != nil {
+		r.logger.Warn("failed to check local config trust", zap.String("path", configPath), zap.Error(err))
+		return
+	}
+	if !trusted {
+		if !r.untrustedConfigs[configPath] {
+			r.untrustedConfigs[configPath] = true
+			fmt.Fprintf(os.Stderr, "gsh: skipping untrusted local config %s (run 'gsh trust' in %s to allow it)\n",
+				configPath, filepath.Dir(filepath.Dir(configPath)))
+		}
+		return
+	}
+
+	local, err := config.NewLoader(r.logger).LoadLocalConfigInto(r.executor.Interpreter(), configPath, content, r.config)
+	r.localConfig = local
+	if err != nil {
+		r.logger.Warn("config warning", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "gsh: config error: %v\n", err)
+	}
+}
+
 // handleBuiltinCommand handles built-in REPL commands.
 // Returns true if the command was handled, and an error if the REPL should exit.
 func (r *REPL) handleBuiltinCommand(command string) (bool, error) {
@@ -566,9 +779,32 @@ func (r *REPL) handleBuiltinCommand(command string) (bool, error) {
 		// Signal exit by returning ErrExit
 		return true, ErrExit
 
-	default:
-		return false, nil
+	case "help", "?":
+		text, _ := r.helpText("")
+		fmt.Print(text)
+		return true, nil
+
+	case ":models":
+		fmt.Print(r.modelsHelpText())
+		return true, nil
+
+	case ":agents":
+		fmt.Print(r.agentsHelpText())
+		return true, nil
 	}
+
+	// help <topic>
+	if topic, ok := strings.CutPrefix(command, "help "); ok {
+		topic = strings.TrimSpace(topic)
+		if text, ok := r.helpText(topic); ok {
+			fmt.Print(text)
+		} else {
+			fmt.Fprintf(os.Stderr, "gsh: no help for '%s' (topics: %s)\n", topic, strings.Join(helpTopics, ", "))
+		}
+		return true, nil
+	}
+
+	return false, nil
 }
 
 // getPrompt returns the prompt string to display.
@@ -576,15 +812,25 @@ func (r *REPL) handleBuiltinCommand(command string) (bool, error) {
 // Event handlers can set gsh.prompt to customize the prompt.
 func (r *REPL) getPrompt() string {
 	interp := r.executor.Interpreter()
+	r.promptTool.wait()
 
-	// Emit repl.prompt event to let handlers update the prompt dynamically
-	interp.EmitEvent(interpreter.EventReplPrompt, interpreter.CreateReplPromptContext())
+	// Emit repl.prompt event to let handlers update the prompt dynamically.
+	// The last command's exit code and duration are passed so handlers can style the prompt.
+	promptCtx := interpreter.CreateReplPromptContext(r.lastExitCode, r.lastDurationMs)
+	interp.EmitEvent(interpreter.EventReplPrompt, promptCtx)
 
 	// Read gsh.prompt property (may have been updated by event handler)
 	replCtx := interp.SDKConfig().GetREPLContext()
 	if replCtx != nil && replCtx.PromptValue != nil {
-		if strVal, ok := replCtx.PromptValue.(*interpreter.StringValue); ok && strVal.Value != "" {
-			return strVal.Value
+		switch prompt := replCtx.PromptValue.(type) {
+		case *interpreter.StringValue:
+			if prompt.Value != "" {
+				return prompt.Value
+			}
+		case *interpreter.ToolValue:
+			if value := r.callPromptTool(prompt, promptCtx); value != "" {
+				return value
+			}
 		}
 	}
 
@@ -592,6 +838,76 @@ func (r *REPL) getPrompt() string {
 	return "gsh> "
 }
 
+// callPromptTool builds the prompt with a tool assigned to gsh.prompt. If the tool fails,
+// takes longer than promptToolTimeout, or is still running from an earlier render, the
+// last prompt it built is returned instead ("" if there is none).
+func (r *REPL) callPromptTool(tool *interpreter.ToolValue, promptCtx interpreter.Value) string {
+	state := &r.promptTool
+	state.mu.Lock()
+	if state.running {
+		defer state.mu.Unlock()
+		return state.last
+	}
+	state.running = true
+	done := make(chan struct{})
+	state.done = done
+	state.mu.Unlock()
+
+	type promptResult struct {
+		prompt string
+		err    error
+	}
+	results := make(chan promptResult, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), promptToolTimeout)
+	go func() {
+		defer cancel()
+		prompt, err := r.executor.Interpreter().CallPromptTool(ctx, tool, promptCtx)
+
+		state.mu.Lock()
+		state.running = false
+		if err == nil {
+			state.last = prompt
+		}
+		state.mu.Unlock()
+		close(done)
+		results <- promptResult{prompt, err}
+	}()
+
+	select {
+	case result := <-results:
+		if result.err == nil {
+			return result.prompt
+		}
+		r.logger.Warn("gsh.prompt tool failed", zap.String("tool", tool.Name), zap.Error(result.err))
+	case <-ctx.Done():
+		r.logger.Debug("gsh.prompt tool timed out", zap.String("tool", tool.Name), zap.Duration("timeout", promptToolTimeout))
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.last
+}
+
+// setProgram records the input program that is running, or nil once it has finished
+func (r *REPL) setProgram(p *tea.Program) {
+	r.programMu.Lock()
+	defer r.programMu.Unlock()
+	r.program = p
+}
+
+// refreshPrompt asks the running input to render the prompt again. It's called from
+// the background when a git status for gsh.promptGitAsync finishes, so it only sends a
+// message. The input renders the prompt in Update, on the goroutine that also runs
+// completion, rather than running repl.prompt handlers from the git goroutine.
+func (r *REPL) refreshPrompt() {
+	r.programMu.Lock()
+	p := r.program
+	r.programMu.Unlock()
+	if p != nil {
+		p.Send(input.PromptRefreshMsg{})
+	}
+}
+
 // getContinuationPrompt returns the continuation prompt for multi-line input.
 // It reads gsh.continuationPrompt which may have been set by event handlers (e.g., Starship).
 func (r *REPL) getContinuationPrompt() string {
@@ -605,6 +921,19 @@ func (r *REPL) getContinuationPrompt() string {
 	return "> "
 }
 
+// getAgentPrompt returns the prompt shown while typing an agent message.
+// It reads gsh.agentPrompt; an empty result means the regular prompt is kept.
+func (r *REPL) getAgentPrompt() string {
+	interp := r.executor.Interpreter()
+	replCtx := interp.SDKConfig().GetREPLContext()
+	if replCtx != nil && replCtx.AgentPromptValue != nil {
+		if strVal, ok := replCtx.AgentPromptValue.(*interpreter.StringValue); ok {
+			return strVal.Value
+		}
+	}
+	return ""
+}
+
 // getHistoryValues returns recent history entries for navigation.
 func (r *REPL) getHistoryValues() []string {
 	if r.history == nil {
@@ -626,6 +955,16 @@ func (r *REPL) getHistoryValues() []string {
 	return values
 }
 
+// configureHistory applies the gsh.history* settings to the history manager and returns it.
+func (r *REPL) configureHistory() *history.HistoryManager {
+	if replCtx := r.executor.Interpreter().SDKConfig().GetREPLContext(); replCtx != nil {
+		r.history.SetMaxEntries(replCtx.HistoryMaxEntries)
+		r.history.SetIgnore(replCtx.HistoryIgnoreSpace, replCtx.HistoryIgnore)
+		r.history.SetIgnoreDups(replCtx.HistoryIgnoreDups)
+	}
+	return r.history
+}
+
 // createHistorySearchFunc returns a function for searching history (used by Ctrl+R).
 func (r *REPL) createHistorySearchFunc() input.HistorySearchFunc {
 	if r.history == nil {
@@ -686,20 +1025,7 @@ func (a *historyProviderAdapter) FindPrefix(prefix string, limit int) ([]interpr
 	if err != nil {
 		return nil, err
 	}
-	// Convert history.HistoryEntry to interpreter.HistoryEntry
-	result := make([]interpreter.HistoryEntry, len(entries))
-	for i, e := range entries {
-		exitCode := -1 // Default to -1 if exit code is not recorded
-		if e.ExitCode.Valid {
-			exitCode = int(e.ExitCode.Int32)
-		}
-		result[i] = interpreter.HistoryEntry{
-			Command:   e.Command,
-			Timestamp: e.CreatedAt.Unix(),
-			ExitCode:  exitCode,
-		}
-	}
-	return result, nil
+	return toInterpreterHistoryEntries(entries), nil
 }
 
 // GetRecent implements interpreter.HistoryProvider
@@ -713,21 +1039,30 @@ func (a *historyProviderAdapter) GetRecent(limit int) ([]interpreter.HistoryEntr
 	if err != nil {
 		return nil, err
 	}
-	// Convert history.HistoryEntry to interpreter.HistoryEntry
 	// Keep chronological order (oldest first, most recent last)
+	return toInterpreterHistoryEntries(entries), nil
+}
+
+// toInterpreterHistoryEntries converts history.HistoryEntry to interpreter.HistoryEntry.
+// A finished command's duration is the time between its creation and the update
+// that recorded its exit code.
+func toInterpreterHistoryEntries(entries []history.HistoryEntry) []interpreter.HistoryEntry {
 	result := make([]interpreter.HistoryEntry, len(entries))
 	for i, e := range entries {
 		exitCode := -1 // Default to -1 if exit code is not recorded
+		durationMs := int64(-1)
 		if e.ExitCode.Valid {
 			exitCode = int(e.ExitCode.Int32)
+			durationMs = e.UpdatedAt.Sub(e.CreatedAt).Milliseconds()
 		}
 		result[i] = interpreter.HistoryEntry{
-			Command:   e.Command,
-			Timestamp: e.CreatedAt.Unix(),
-			ExitCode:  exitCode,
+			Command:    e.Command,
+			Timestamp:  e.CreatedAt.Unix(),
+			ExitCode:   exitCode,
+			DurationMs: durationMs,
 		}
 	}
-	return result, nil
+	return result
 }
 
 // loadBashConfigs loads bash configuration files in the correct order.
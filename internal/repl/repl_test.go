@@ -1,21 +1,33 @@
 package repl
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 
+	"github.com/kunchenguid/gsh/internal/acp"
+	"github.com/kunchenguid/gsh/internal/history"
+	"github.com/kunchenguid/gsh/internal/keyring"
+
 	// Import all subpackages to verify the directory structure is correct
 	_ "github.com/kunchenguid/gsh/internal/repl/completion"
-	_ "github.com/kunchenguid/gsh/internal/repl/config"
+	"github.com/kunchenguid/gsh/internal/repl/config"
 	_ "github.com/kunchenguid/gsh/internal/repl/context"
 	_ "github.com/kunchenguid/gsh/internal/repl/executor"
 	"github.com/kunchenguid/gsh/internal/repl/input"
@@ -138,6 +150,125 @@ func TestREPL_HandleBuiltinCommand_Exit(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestREPL_HelpBuiltin(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "repl.gsh")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+model fastModel {
+	provider: "openai",
+	model: "gpt-4o-mini",
+}
+agent reviewer {
+	model: fastModel,
+	systemPrompt: "Review code",
+	tools: [gsh.tools.grep, gsh.tools.view_file],
+}
+agent planner {
+	model: gsh.models.premium,
+}
+gsh.models.lite = fastModel
+`), 0644))
+
+	repl, err := NewREPL(Options{
+		ConfigPath:  configPath,
+		HistoryPath: filepath.Join(tmpDir, "history.db"),
+		Logger:      zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	for _, command := range []string{"help", "?", "help models", "help nonsense"} {
+		handled, err := repl.handleBuiltinCommand(command)
+		assert.True(t, handled, "%q should be handled", command)
+		assert.NoError(t, err)
+	}
+
+	// "help" as a prefix of another command is left to the shell
+	handled, _ := repl.handleBuiltinCommand("helpers.sh")
+	assert.False(t, handled)
+
+	general, ok := repl.helpText("")
+	require.True(t, ok)
+	assert.Contains(t, general, "# /clear")
+	assert.Contains(t, general, "exit")
+
+	models, ok := repl.helpText("models")
+	require.True(t, ok)
+	assert.Contains(t, models, "fastModel (openai/gpt-4o-mini)")
+	assert.Contains(t, models, "workhorse    (not set)")
+	assert.Contains(t, models, "  NAME       PROVIDER  MODEL\n  fastModel  openai    gpt-4o-mini\n")
+
+	agents, ok := repl.helpText("agents")
+	require.True(t, ok)
+	assert.Contains(t, agents, "  NAME      MODEL               TOOLS\n")
+	assert.Contains(t, agents, "  planner   gsh.models.premium  0\n")
+	assert.Contains(t, agents, "  reviewer  fastModel           2\n")
+
+	_, ok = repl.helpText("nonsense")
+	assert.False(t, ok)
+}
+
+// captureOutput returns what f writes to stdout
+func captureOutput(f func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	f()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestREPL_ListBuiltins(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "repl.gsh")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+model fastModel {
+	provider: "openai",
+	model: "gpt-4o-mini",
+}
+agent reviewer {
+	model: fastModel,
+}
+`), 0644))
+
+	repl, err := NewREPL(Options{
+		ConfigPath:  configPath,
+		HistoryPath: filepath.Join(tmpDir, "history.db"),
+		Logger:      zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{":models", "  fastModel  openai    gpt-4o-mini\n"},
+		{":agents", "  reviewer  fastModel  0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			var handled bool
+			output := captureOutput(func() {
+				handled, err = repl.handleBuiltinCommand(tt.command)
+			})
+			assert.True(t, handled)
+			assert.NoError(t, err)
+			assert.Contains(t, output, tt.want)
+		})
+	}
+
+	// Anything else starting with ':' is still a shell command
+	handled, _ := repl.handleBuiltinCommand(":")
+	assert.False(t, handled)
+}
+
 func TestREPL_ProcessCommand_Empty(t *testing.T) {
 	tmpDir := t.TempDir()
 	historyPath := filepath.Join(tmpDir, "history.db")
@@ -213,6 +344,97 @@ func TestREPL_ProcessCommand_RecordsHistory(t *testing.T) {
 	assert.Equal(t, int32(0), entries[0].ExitCode.Int32)
 }
 
+func TestREPL_ProcessCommand_HistoryIgnore(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history.db")
+	configPath := filepath.Join(tmpDir, "test.repl.gsh")
+	config := "gsh.historyIgnoreSpace = true\ngsh.historyIgnoreDups = true\ngsh.historyIgnore = [\"^echo secret\"]\ngsh.historyMaxEntries = 2\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+	repl, err := NewREPL(Options{
+		ConfigPath:  configPath,
+		HistoryPath: historyPath,
+		Logger:      zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	ctx := context.Background()
+
+	assert.NoError(t, repl.processCommand(ctx, " echo hidden"))
+	assert.NoError(t, repl.processCommand(ctx, "echo secret value"))
+	assert.NoError(t, repl.processCommand(ctx, "echo one"))
+	assert.NoError(t, repl.processCommand(ctx, "echo two"))
+	assert.NoError(t, repl.processCommand(ctx, "echo three"))
+	assert.NoError(t, repl.processCommand(ctx, "echo three "))
+
+	entries, err := repl.History().GetRecentEntries("", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "echo two", entries[0].Command)
+	assert.Equal(t, "echo three", entries[1].Command)
+}
+
+func TestREPL_ProcessCommand_ConfirmCommands(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history.db")
+	configPath := filepath.Join(tmpDir, "test.repl.gsh")
+	config := `gsh.confirmCommands = ["^touch "]
+tool approve(ctx, next) {
+    if (ctx.command.includes("approved")) {
+        return { approved: true }
+    }
+    return next(ctx)
+}
+gsh.use("repl.command.confirm", approve)
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+	repl, err := NewREPL(Options{
+		ConfigPath:  configPath,
+		HistoryPath: historyPath,
+		Logger:      zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	ctx := context.Background()
+	denied := filepath.Join(tmpDir, "denied")
+	approved := filepath.Join(tmpDir, "approved")
+
+	assert.NoError(t, repl.processCommand(ctx, "touch "+denied))
+	assert.NoFileExists(t, denied)
+	assert.Equal(t, 1, repl.lastExitCode)
+
+	assert.NoError(t, repl.processCommand(ctx, "touch "+approved))
+	assert.FileExists(t, approved)
+	assert.Equal(t, 0, repl.lastExitCode)
+
+	// A command piped to the agent is confirmed before it runs
+	piped := filepath.Join(tmpDir, "piped")
+	assert.NoError(t, repl.processCommand(ctx, "touch "+piped+" | # why"))
+	assert.NoFileExists(t, piped)
+	assert.Equal(t, 1, repl.lastExitCode)
+}
+
+func TestDefaultConfirmCommands(t *testing.T) {
+	matches := func(command string) bool {
+		for _, pattern := range defaultConfirmCommands() {
+			if pattern.MatchString(command) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, command := range []string{"rm -rf build", "rm -fr /tmp/x", "sudo rm -v -Rf /", "rm -r -f dir", "rm -f -r dir", "rm --recursive --force dir", "rm -f dir --recursive", "rm -rv -f dir", "dd if=/dev/zero of=/dev/sda", "git push --force origin main", "git push -f", "git push origin main --force", "mkfs.ext4 /dev/sdb1"} {
+		assert.True(t, matches(command), command)
+	}
+	for _, command := range []string{"rm file.txt", "rm -r build", "rm -f file.txt", "rm --recursive build", "rm -r build; touch -f x", "rmdir -r -f x", "git add .", "git push origin main", "git push --force-with-lease", "git push --force-if-includes origin main", "echo dd"} {
+		assert.False(t, matches(command), command)
+	}
+}
+
 func TestREPL_ProcessCommand_FailingCommand(t *testing.T) {
 	tmpDir := t.TempDir()
 	historyPath := filepath.Join(tmpDir, "history.db")
@@ -298,6 +520,207 @@ gsh.use("repl.prompt", onPrompt)
 	assert.Equal(t, "custom> ", repl.getPrompt())
 }
 
+func TestREPL_RefreshPromptRendersOnInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history.db")
+
+	defaultConfig := `
+renders = 0
+tool onPrompt(ctx, next) {
+	renders = renders + 1
+	gsh.prompt = "render " + renders + "> "
+	return next(ctx)
+}
+gsh.use("repl.prompt", onPrompt)
+`
+
+	repl, err := NewREPL(Options{
+		DefaultConfigContent: defaultConfig,
+		HistoryPath:          historyPath,
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	model := input.New(input.Config{
+		Prompt:        repl.getPrompt(),
+		RefreshPrompt: repl.getPrompt,
+	})
+	assert.Equal(t, "render 1> ", model.Prompt())
+
+	p := tea.NewProgram(model, tea.WithInput(nil), tea.WithOutput(io.Discard))
+	repl.setProgram(p)
+	defer repl.setProgram(nil)
+
+	done := make(chan tea.Model, 1)
+	go func() {
+		final, _ := p.Run()
+		done <- final
+	}()
+
+	// The refresh only queues a message; the prompt is rendered by the program's Update
+	repl.refreshPrompt()
+	p.Quit()
+
+	select {
+	case final := <-done:
+		assert.Equal(t, "render 2> ", final.(input.Model).Prompt())
+	case <-time.After(5 * time.Second):
+		t.Fatal("input program did not finish")
+	}
+}
+
+func TestREPL_GetPrompt_ExitCodeContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history.db")
+
+	defaultConfig := `
+tool onPrompt(ctx, next) {
+	if (ctx.exitCode != 0) {
+		gsh.prompt = "failed(" + ctx.exitCode + ")> "
+	} else {
+		gsh.prompt = "ok> "
+	}
+	return next(ctx)
+}
+gsh.use("repl.prompt", onPrompt)
+`
+
+	repl, err := NewREPL(Options{
+		DefaultConfigContent: defaultConfig,
+		HistoryPath:          historyPath,
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	ctx := context.Background()
+
+	assert.Equal(t, "ok> ", repl.getPrompt())
+
+	_ = repl.processCommand(ctx, "(exit 3)")
+	assert.Equal(t, "failed(3)> ", repl.getPrompt())
+
+	_ = repl.processCommand(ctx, "true")
+	assert.Equal(t, "ok> ", repl.getPrompt())
+}
+
+func TestREPL_GetPrompt_Tool(t *testing.T) {
+	defaultConfig := `
+renders = 0
+mode = "ok"
+tool buildPrompt(ctx) {
+	if (mode == "slow") {
+		exec("sleep 5")
+	}
+	if (mode == "broken") {
+		return 42
+	}
+	renders = renders + 1
+	return "n" + renders + ":" + ctx.exitCode + "> "
+}
+gsh.prompt = buildPrompt
+`
+
+	repl, err := NewREPL(Options{
+		DefaultConfigContent: defaultConfig,
+		HistoryPath:          filepath.Join(t.TempDir(), "history.db"),
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	// The tool runs on every render
+	assert.Equal(t, "n1:0> ", repl.getPrompt())
+	_ = repl.processCommand(context.Background(), "(exit 3)")
+	assert.Equal(t, "n2:3> ", repl.getPrompt())
+
+	// A failing tool falls back to the last prompt it built
+	_, err = repl.executor.Interpreter().EvalString(`mode = "broken"`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "n2:3> ", repl.getPrompt())
+
+	// So does a slow one, which is cancelled after the timeout
+	_, err = repl.executor.Interpreter().EvalString(`mode = "slow"`, nil)
+	require.NoError(t, err)
+	start := time.Now()
+	assert.Equal(t, "n2:3> ", repl.getPrompt())
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestREPL_GetPrompt_ToolTimeoutStopsBeforeNextCommand(t *testing.T) {
+	defaultConfig := `
+cache = 0
+tool buildPrompt() {
+	n = 0
+	while (true) {
+		n = n + 1
+		cache = n
+	}
+	return "never> "
+}
+gsh.prompt = buildPrompt
+
+tool setCache(ctx, next) {
+	cache = -1
+	return next(ctx)
+}
+gsh.use("repl.command.after", setCache)
+`
+
+	repl, err := NewREPL(Options{
+		DefaultConfigContent: defaultConfig,
+		HistoryPath:          filepath.Join(t.TempDir(), "history.db"),
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	// The looping tool times out and is cancelled; the next command waits for it to stop
+	// before editing globals, instead of racing it on the interpreter
+	assert.Equal(t, "gsh> ", repl.getPrompt())
+	require.NoError(t, repl.processCommand(context.Background(), "true"))
+
+	cache, ok := repl.executor.Interpreter().GetVariables()["cache"].(*interpreter.NumberValue)
+	require.True(t, ok)
+	assert.Equal(t, float64(-1), cache.Value)
+}
+
+func TestREPL_PromptExitCodeColor(t *testing.T) {
+	tmpDir := t.TempDir()
+	historyPath := filepath.Join(tmpDir, "history.db")
+
+	defaultConfig := `
+tool onPrompt(ctx, next) {
+	if (gsh.promptExitCodeColor && ctx.exitCode != 0) {
+		gsh.prompt = "red> "
+	} else {
+		gsh.prompt = "plain> "
+	}
+	return next(ctx)
+}
+gsh.use("repl.prompt", onPrompt)
+`
+
+	repl, err := NewREPL(Options{
+		DefaultConfigContent: defaultConfig,
+		HistoryPath:          historyPath,
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	ctx := context.Background()
+	_ = repl.processCommand(ctx, "false")
+
+	// Disabled by default
+	assert.Equal(t, "plain> ", repl.getPrompt())
+
+	_, err = repl.executor.Interpreter().EvalString("gsh.promptExitCodeColor = true", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "red> ", repl.getPrompt())
+}
+
 func TestREPL_Close(t *testing.T) {
 	tmpDir := t.TempDir()
 	historyPath := filepath.Join(tmpDir, "history.db")
@@ -965,3 +1388,424 @@ func TestREPL_ProcessCommand_ContextPassedToShellExecution(t *testing.T) {
 	// Should complete much faster than 5 seconds because context is cancelled
 	assert.Less(t, elapsed, 2*time.Second, "cancelled context should prevent long-running command")
 }
+
+func TestREPL_LoadLocalConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	trustFile := filepath.Join(tmpDir, "trusted_configs")
+
+	project := filepath.Join(tmpDir, "project")
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".gsh"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".gsh", "config.gsh"), []byte(`
+model projectModel {
+	provider: "openai",
+	model: "gpt-4o",
+}
+`), 0644))
+	subdir := filepath.Join(project, "src")
+	require.NoError(t, os.MkdirAll(subdir, 0755))
+
+	newREPL := func() *REPL {
+		repl, err := NewREPL(Options{
+			ConfigPath:  filepath.Join(tmpDir, "nonexistent.gsh"),
+			HistoryPath: filepath.Join(tmpDir, "history.db"),
+			TrustFile:   trustFile,
+			Logger:      zap.NewNop(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { repl.Close() })
+		return repl
+	}
+
+	t.Run("untrusted config is skipped", func(t *testing.T) {
+		repl := newREPL()
+		_, err := repl.executor.ExecuteBash(context.Background(), "cd "+subdir)
+		require.NoError(t, err)
+
+		repl.loadLocalConfig()
+		assert.Nil(t, repl.config.GetModel("projectModel"))
+	})
+
+	t.Run("config trusted mid-session is loaded", func(t *testing.T) {
+		repl := newREPL()
+		_, err := repl.executor.ExecuteBash(context.Background(), "cd "+subdir)
+		require.NoError(t, err)
+
+		repl.loadLocalConfig()
+		assert.Nil(t, repl.config.GetModel("projectModel"))
+
+		require.NoError(t, config.TrustLocalConfig(trustFile, config.FindLocalConfig(subdir)))
+		repl.loadLocalConfig()
+		assert.NotNil(t, repl.config.GetModel("projectModel"))
+	})
+
+	t.Run("trusted config is loaded after cd", func(t *testing.T) {
+		configPath := config.FindLocalConfig(subdir)
+		require.NotEmpty(t, configPath)
+		require.NoError(t, config.TrustLocalConfig(trustFile, configPath))
+
+		repl := newREPL()
+		_, err := repl.executor.ExecuteBash(context.Background(), "cd "+subdir)
+		require.NoError(t, err)
+
+		repl.loadLocalConfig()
+		assert.NotNil(t, repl.config.GetModel("projectModel"))
+	})
+
+	t.Run("config is unloaded outside its directory", func(t *testing.T) {
+		require.NoError(t, config.TrustLocalConfig(trustFile, config.FindLocalConfig(subdir)))
+
+		repl := newREPL()
+		_, err := repl.executor.ExecuteBash(context.Background(), "cd "+subdir)
+		require.NoError(t, err)
+		repl.loadLocalConfig()
+		require.NotNil(t, repl.config.GetModel("projectModel"))
+
+		_, err = repl.executor.ExecuteBash(context.Background(), "cd "+tmpDir)
+		require.NoError(t, err)
+		repl.loadLocalConfig()
+		assert.Nil(t, repl.config.GetModel("projectModel"))
+		_, ok := repl.executor.Interpreter().GetVariables()["projectModel"]
+		assert.False(t, ok)
+
+		// Entering the project again loads it again
+		_, err = repl.executor.ExecuteBash(context.Background(), "cd "+project)
+		require.NoError(t, err)
+		repl.loadLocalConfig()
+		assert.NotNil(t, repl.config.GetModel("projectModel"))
+	})
+}
+
+func TestREPL_ServeACP(t *testing.T) {
+	newTestREPL := func(t *testing.T, config string) *REPL {
+		r, err := NewREPL(Options{
+			DefaultConfigContent: config,
+			HistoryPath:          filepath.Join(t.TempDir(), "history.db"),
+			Logger:               zaptest.NewLogger(t),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { r.Close() })
+		return r
+	}
+
+	t.Run("requires a default agent", func(t *testing.T) {
+		r := newTestREPL(t, "")
+		err := r.ServeACP(context.Background(), strings.NewReader(""), io.Discard)
+		assert.ErrorContains(t, err, "no default agent")
+	})
+
+	t.Run("streams the default agent's reply", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hello\"}}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer server.Close()
+
+		r := newTestREPL(t, fmt.Sprintf(`
+model testModel {
+	provider: "openai",
+	apiKey: "test",
+	model: "test",
+	baseURL: %q,
+}
+agent __defaultAgent {
+	model: testModel,
+	systemPrompt: "test",
+}
+`, server.URL))
+		cwd := t.TempDir()
+		in := strings.NewReader(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"session/new","params":{"cwd":%q,"mcpServers":[]}}
+{"jsonrpc":"2.0","id":2,"method":"session/prompt","params":{"sessionId":"gsh-1","prompt":[{"type":"text","text":"hi"}]}}
+`, cwd))
+		var out bytes.Buffer
+		require.NoError(t, r.ServeACP(context.Background(), in, &out))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"sessionId":"gsh-1"`)
+		assert.Contains(t, lines[1], `"sessionUpdate":"agent_message_chunk"`)
+		assert.Contains(t, lines[1], `"text":"hello"`)
+		assert.Contains(t, lines[2], `"stopReason":"end_turn"`)
+		assert.Equal(t, cwd, r.executor.GetPwd(), "prompt should run in the session directory")
+	})
+}
+
+func TestREPL_RunPrompt(t *testing.T) {
+	newTestREPL := func(t *testing.T, config string) *REPL {
+		r, err := NewREPL(Options{
+			DefaultConfigContent: config,
+			HistoryPath:          filepath.Join(t.TempDir(), "history.db"),
+			Logger:               zaptest.NewLogger(t),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { r.Close() })
+		return r
+	}
+
+	t.Run("requires a default agent", func(t *testing.T) {
+		r := newTestREPL(t, "")
+		err := r.RunPrompt(context.Background(), "hi", io.Discard)
+		assert.ErrorContains(t, err, "no default agent")
+	})
+
+	t.Run("prints the default agent's reply", func(t *testing.T) {
+		var requestBody string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			requestBody = string(body)
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hello\"}}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer server.Close()
+
+		r := newTestREPL(t, fmt.Sprintf(`
+model testModel {
+	provider: "openai",
+	apiKey: "test",
+	model: "test",
+	baseURL: %q,
+}
+agent __defaultAgent {
+	model: testModel,
+	systemPrompt: "test",
+}
+`, server.URL))
+
+		var out bytes.Buffer
+		require.NoError(t, r.RunPrompt(context.Background(), "summarize this\nplease", &out))
+		assert.Equal(t, "hello\n", out.String())
+		assert.Contains(t, requestBody, `summarize this\nplease`)
+		assert.Contains(t, requestBody, "current_directory")
+	})
+
+	t.Run("appends the configured prompt suffix", func(t *testing.T) {
+		var requestBody string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			requestBody = string(body)
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"bonjour\"}}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer server.Close()
+
+		r := newTestREPL(t, fmt.Sprintf(`
+model testModel {
+	provider: "openai",
+	apiKey: "test",
+	model: "test",
+	baseURL: %q,
+}
+agent __defaultAgent {
+	model: testModel,
+	systemPrompt: "You are gsh.",
+}
+gsh.defaultAgentPromptSuffix = "Answer in French."
+`, server.URL))
+
+		require.NoError(t, r.RunPrompt(context.Background(), "hi", io.Discard))
+		assert.Contains(t, requestBody, `You are gsh.\n\nAnswer in French.`)
+		assert.Equal(t, "You are gsh.", r.config.GetAgent(defaultAgentName).Config["systemPrompt"].String(),
+			"the declared agent must not change")
+	})
+}
+
+func TestHiddenAgent(t *testing.T) {
+	owner := &interpreter.PropertyDescriptor{Value: &interpreter.StringValue{Value: "me"}}
+	agent := &interpreter.AgentValue{
+		Name: "reviewer",
+		Config: map[string]interpreter.Value{
+			"metadata": &interpreter.ObjectValue{Properties: map[string]*interpreter.PropertyDescriptor{"owner": owner}},
+		},
+	}
+
+	hidden := hiddenAgent(agent)
+	metadata := hidden.Config["metadata"].(*interpreter.ObjectValue)
+	assert.Equal(t, "reviewer", hidden.Name)
+	assert.Equal(t, &interpreter.BoolValue{Value: true}, metadata.Properties["hidden"].Value)
+	assert.Same(t, owner, metadata.Properties["owner"])
+
+	original := agent.Config["metadata"].(*interpreter.ObjectValue)
+	assert.NotContains(t, original.Properties, "hidden", "the declared agent must not change")
+}
+
+func TestREPL_SetupMissingAPIKeys(t *testing.T) {
+	config := `
+model cloud {
+	provider: "openai",
+	apiKey: env.GSH_TEST_MISSING_KEY,
+	model: "gpt-4",
+}
+model local {
+	provider: "openai",
+	apiKey: "ollama",
+	model: "llama3",
+}
+gsh.models.lite = cloud
+gsh.models.workhorse = cloud
+gsh.models.premium = local
+`
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/api/tags", req.URL.Path)
+		fmt.Fprint(w, `{"models":[{"name":"qwen3:8b"},{"name":"gemma3:4b"}]}`)
+	}))
+	defer ollama.Close()
+
+	newTestREPL := func(t *testing.T) *REPL {
+		r, err := NewREPL(Options{
+			DefaultConfigContent: config,
+			HistoryPath:          filepath.Join(t.TempDir(), "history.db"),
+			Logger:               zaptest.NewLogger(t),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { r.Close() })
+		return r
+	}
+	newKeySetup := func(key, input string, out *bytes.Buffer) *keySetup {
+		return &keySetup{
+			in:         strings.NewReader(input),
+			out:        out,
+			readSecret: func() (string, error) { return key, nil },
+			ollamaURL:  ollama.URL,
+		}
+	}
+
+	t.Run("stores an entered key in the keyring", func(t *testing.T) {
+		keyring.MockInit()
+		r := newTestREPL(t)
+
+		var out bytes.Buffer
+		r.setupMissingAPIKeys(context.Background(), newKeySetup("sk-entered\n", "", &out))
+
+		models := r.executor.Interpreter().SDKConfig().GetModels()
+		assert.Equal(t, &interpreter.StringValue{Value: "sk-entered"}, models.Lite.Config["apiKey"])
+		assert.Empty(t, models.Lite.MissingAPIKey)
+		stored, err := keyring.Get("GSH_TEST_MISSING_KEY")
+		require.NoError(t, err)
+		assert.Equal(t, "sk-entered", stored)
+		assert.Equal(t, 1, strings.Count(out.String(), "reads its API key"), "a model shared by tiers is asked about once")
+		assert.Contains(t, out.String(), "keyring.GSH_TEST_MISSING_KEY")
+	})
+
+	t.Run("picks a local model when skipped", func(t *testing.T) {
+		keyring.MockInit()
+		r := newTestREPL(t)
+
+		var out bytes.Buffer
+		r.setupMissingAPIKeys(context.Background(), newKeySetup("", "2\n", &out))
+
+		models := r.executor.Interpreter().SDKConfig().GetModels()
+		for _, model := range []*interpreter.ModelValue{models.Lite, models.Workhorse} {
+			assert.Equal(t, &interpreter.StringValue{Value: "gemma3:4b"}, model.Config["model"])
+			assert.Equal(t, &interpreter.StringValue{Value: ollama.URL + "/v1"}, model.Config["baseURL"])
+		}
+		assert.Equal(t, "local", models.Premium.Name)
+		assert.Contains(t, out.String(), "Using gemma3:4b for gsh.models.lite and gsh.models.workhorse")
+		_, err := keyring.Get("GSH_TEST_MISSING_KEY")
+		assert.ErrorIs(t, err, keyring.ErrNotFound)
+	})
+
+	t.Run("leaves the tiers alone when everything is skipped", func(t *testing.T) {
+		keyring.MockInit()
+		r := newTestREPL(t)
+
+		var out bytes.Buffer
+		r.setupMissingAPIKeys(context.Background(), newKeySetup("", "\n", &out))
+
+		models := r.executor.Interpreter().SDKConfig().GetModels()
+		assert.Equal(t, "cloud", models.Lite.Name)
+		assert.Equal(t, "env.GSH_TEST_MISSING_KEY", models.Lite.MissingAPIKey)
+		assert.Contains(t, out.String(), "won't work until env.GSH_TEST_MISSING_KEY is set")
+		assert.Contains(t, out.String(), "gsh.setupMissingKeys = false")
+	})
+
+	t.Run("uses a key saved to the keyring earlier", func(t *testing.T) {
+		keyring.MockInit()
+		require.NoError(t, keyring.Set("GSH_TEST_MISSING_KEY", "sk-saved"))
+		r := newTestREPL(t)
+
+		var out bytes.Buffer
+		r.setupMissingAPIKeys(context.Background(), newKeySetup("", "", &out))
+
+		models := r.executor.Interpreter().SDKConfig().GetModels()
+		assert.Equal(t, &interpreter.StringValue{Value: "sk-saved"}, models.Lite.Config["apiKey"])
+		assert.Empty(t, out.String(), "nothing is asked")
+	})
+
+	t.Run("asks nothing when turned off", func(t *testing.T) {
+		keyring.MockInit()
+		r := newTestREPL(t)
+		_, err := r.executor.Interpreter().EvalString("gsh.setupMissingKeys = false", nil)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		r.setupMissingAPIKeys(context.Background(), newKeySetup("sk-entered", "", &out))
+
+		assert.Empty(t, out.String())
+		models := r.executor.Interpreter().SDKConfig().GetModels()
+		assert.Equal(t, "env.GSH_TEST_MISSING_KEY", models.Lite.MissingAPIKey)
+	})
+
+	t.Run("reads no further than the chosen line", func(t *testing.T) {
+		in := strings.NewReader("2\nls\n")
+		assert.Equal(t, "2", readLine(in))
+		rest, _ := io.ReadAll(in)
+		assert.Equal(t, "ls\n", string(rest))
+	})
+}
+
+func TestPromptText(t *testing.T) {
+	text := promptText([]acp.PromptContent{
+		{Type: "text", Text: "explain this"},
+		{Type: "resource", Resource: &acp.PromptResource{URI: "file:///a.go", Text: "package a"}},
+		{Type: "image"},
+	})
+	assert.Equal(t, "explain this\n\n<resource uri=\"file:///a.go\">\npackage a\n</resource>", text)
+}
+
+func TestToInterpreterHistoryEntries(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	entries := toInterpreterHistoryEntries([]history.HistoryEntry{
+		{Command: "make build", CreatedAt: start, UpdatedAt: start.Add(1500 * time.Millisecond), ExitCode: sql.NullInt32{Int32: 2, Valid: true}},
+		{Command: "sleep 100", CreatedAt: start, UpdatedAt: start},
+	})
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, interpreter.HistoryEntry{Command: "make build", Timestamp: 1700000000, ExitCode: 2, DurationMs: 1500}, entries[0])
+	// Unfinished commands have no exit code or duration yet
+	assert.Equal(t, interpreter.HistoryEntry{Command: "sleep 100", Timestamp: 1700000000, ExitCode: -1, DurationMs: -1}, entries[1])
+}
+
+func TestREPL_AgentPipeInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	repl, err := NewREPL(Options{
+		ConfigPath:  filepath.Join(tmpDir, "nonexistent.repl.gsh"),
+		HistoryPath: filepath.Join(tmpDir, "history.db"),
+		Logger:      zap.NewNop(),
+	})
+	require.NoError(t, err)
+	defer repl.Close()
+
+	ctx := context.Background()
+
+	got := repl.agentPipeInput(ctx, "echo out; echo err >&2; exit 2", "why did this fail")
+	assert.Equal(t, "# why did this fail\n\n"+
+		"<command exit_code=\"2\">echo out; echo err >&2; exit 2</command>\n"+
+		"<output>\nout\n</output>\n"+
+		"<stderr>\nerr\n</stderr>", got)
+
+	got = repl.agentPipeInput(ctx, "echo hi", "")
+	assert.True(t, strings.HasPrefix(got, "# "+defaultAgentPipeMessage+"\n\n"), got)
+	assert.NotContains(t, got, "<stderr>")
+
+	got = repl.agentPipeInput(ctx, "echo hi", "@reviewer")
+	assert.True(t, strings.HasPrefix(got, "# @reviewer "+defaultAgentPipeMessage+"\n\n"), got)
+
+	long := strings.Repeat("line of output\n", 5000)
+	limited := limitAgentPipeOutput(long)
+	assert.LessOrEqual(t, len(limited), maxAgentPipeOutputLen)
+	assert.Contains(t, limited, "(truncated)")
+	assert.Equal(t, "short", limitAgentPipeOutput("short\n"))
+}
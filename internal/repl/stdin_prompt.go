@@ -0,0 +1,61 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kunchenguid/gsh/internal/script/interpreter"
+)
+
+// RunPrompt sends prompt to the default agent as a single message, as
+// "gsh --stdin-prompt" does, and writes the agent's final response to out.
+// The agent runs hidden, so the agent event handlers don't render the turn as well.
+func (r *REPL) RunPrompt(ctx context.Context, prompt string, out io.Writer) error {
+	agent, err := r.defaultAgent()
+	if err != nil {
+		return err
+	}
+
+	// Same directory hint the "#" middleware gives the agent
+	message := fmt.Sprintf("<current_directory>%s</current_directory>\n\n%s", r.executor.GetPwd(), prompt)
+	conv := &interpreter.ConversationValue{
+		Messages: []interpreter.ChatMessage{{Role: "user", Content: message, Timestamp: time.Now()}},
+	}
+
+	result, err := r.executor.Interpreter().ExecuteAgentWithCallbacks(ctx, conv, hiddenAgent(agent), true, nil)
+	if err != nil {
+		return err
+	}
+	response := ""
+	if updated, ok := result.(*interpreter.ConversationValue); ok && len(updated.Messages) > 0 {
+		if last := updated.Messages[len(updated.Messages)-1]; last.Role == "assistant" {
+			response = last.Content
+		}
+	}
+	if response == "" {
+		return fmt.Errorf("the agent returned no response")
+	}
+	_, err = fmt.Fprintln(out, strings.TrimRight(response, "\n"))
+	return err
+}
+
+// hiddenAgent returns a copy of agent with metadata.hidden set, which the default
+// agent event handlers check to skip rendering headers, footers and tool status
+func hiddenAgent(agent *interpreter.AgentValue) *interpreter.AgentValue {
+	config := make(map[string]interpreter.Value, len(agent.Config)+1)
+	for key, value := range agent.Config {
+		config[key] = value
+	}
+	metadata := &interpreter.ObjectValue{Properties: map[string]*interpreter.PropertyDescriptor{}}
+	if existing, ok := config["metadata"].(*interpreter.ObjectValue); ok {
+		for key, prop := range existing.Properties {
+			metadata.Properties[key] = prop
+		}
+	}
+	metadata.Properties["hidden"] = &interpreter.PropertyDescriptor{Value: &interpreter.BoolValue{Value: true}}
+	config["metadata"] = metadata
+	return &interpreter.AgentValue{Name: agent.Name, Config: config}
+}
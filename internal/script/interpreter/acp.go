@@ -16,6 +16,9 @@ import (
 // ACP agents connect to external agent processes via the ACP protocol.
 func (i *Interpreter) evalACPDeclaration(env *Environment, node *parser.ACPDeclaration) (Value, error) {
 	acpName := node.Name.Value
+	if err := checkNotConstant(env, node.Name); err != nil {
+		return nil, err
+	}
 
 	// Evaluate each config field and store as Value
 	config := make(map[string]Value)
@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"path"
 
 	"github.com/kunchenguid/gsh/internal/script/parser"
 )
@@ -9,6 +10,9 @@ import (
 // evalAgentDeclaration evaluates an agent declaration
 func (i *Interpreter) evalAgentDeclaration(env *Environment, node *parser.AgentDeclaration) (Value, error) {
 	agentName := node.Name.Value
+	if err := checkNotConstant(env, node.Name); err != nil {
+		return nil, err
+	}
 
 	// Evaluate each config field and store as Value
 	config := make(map[string]Value)
@@ -22,8 +26,14 @@ func (i *Interpreter) evalAgentDeclaration(env *Environment, node *parser.AgentD
 		// Validate common config fields
 		switch key {
 		case "model":
-			// model must be a ModelResolver (ModelValue or SDKModelRef)
-			// gsh.models.* now returns SDKModelRef directly, so no special AST handling needed
+			// model must be a ModelResolver (ModelValue or SDKModelRef).
+			// gsh.models.* returns an SDKModelRef, or null while the tier is unassigned; in that
+			// case keep the reference so the agent resolves the tier when it runs.
+			if _, ok := value.(*NullValue); ok {
+				if ref := i.unassignedModelTier(env, expr); ref != nil {
+					value = ref
+				}
+			}
 			if _, ok := value.(ModelResolver); !ok {
 				return nil, fmt.Errorf("agent config 'model' must be a model reference, got %s", value.Type())
 			}
@@ -32,13 +42,57 @@ func (i *Interpreter) evalAgentDeclaration(env *Environment, node *parser.AgentD
 				return nil, fmt.Errorf("agent config 'systemPrompt' must be a string, got %s", value.Type())
 			}
 		case "tools":
-			if _, ok := value.(*ArrayValue); !ok {
-				return nil, fmt.Errorf("agent config 'tools' must be an array, got %s", value.Type())
+			switch tools := value.(type) {
+			case *ArrayValue:
+				// Elements can be arrays of tools too, such as filesystem.* for all of an
+				// MCP server's tools
+				literal := expr
+				if flat, nested := flattenAgentTools(tools); nested {
+					value, tools, literal = flat, flat, nil
+				}
+				if err := validateAgentTools(agentName, tools, literal); err != nil {
+					return nil, err
+				}
+			case *ToolValue:
+				// Computed each time the agent runs, see resolveAgentTools
+			default:
+				return nil, fmt.Errorf("agent config 'tools' must be an array or a tool returning one, got %s", value.Type())
 			}
 		case "metadata":
 			if _, ok := value.(*ObjectValue); !ok {
 				return nil, fmt.Errorf("agent config 'metadata' must be an object, got %s", value.Type())
 			}
+		case "requireApproval":
+			arr, ok := value.(*ArrayValue)
+			if !ok {
+				return nil, fmt.Errorf("agent config 'requireApproval' must be an array of tool names, got %s", value.Type())
+			}
+			for idx, elem := range arr.Elements {
+				if _, ok := elem.(*StringValue); !ok {
+					return nil, fmt.Errorf("agent config 'requireApproval[%d]' must be a tool name string, got %s", idx, elem.Type())
+				}
+			}
+		case "excludeTools":
+			arr, ok := value.(*ArrayValue)
+			if !ok {
+				return nil, fmt.Errorf("agent config 'excludeTools' must be an array of tool name patterns, got %s", value.Type())
+			}
+			for idx, elem := range arr.Elements {
+				pattern, ok := elem.(*StringValue)
+				if !ok {
+					return nil, fmt.Errorf("agent config 'excludeTools[%d]' must be a tool name pattern string, got %s", idx, elem.Type())
+				}
+				if _, err := path.Match(pattern.Value, ""); err != nil {
+					return nil, fmt.Errorf("agent config 'excludeTools[%d]' is not a valid pattern: %s", idx, pattern.Value)
+				}
+			}
+		case "outputSchema":
+			if _, ok := value.(*ObjectValue); !ok {
+				return nil, fmt.Errorf("agent config 'outputSchema' must be an object, got %s", value.Type())
+			}
+			if _, err := compileOutputSchema(value); err != nil {
+				return nil, fmt.Errorf("agent '%s' has an invalid 'outputSchema': %w", agentName, err)
+			}
 			// Allow other fields without validation for extensibility
 		}
 
@@ -61,3 +115,176 @@ func (i *Interpreter) evalAgentDeclaration(env *Environment, node *parser.AgentD
 
 	return agent, nil
 }
+
+// unassignedModelTier returns the tier reference for an agent model written as
+// gsh.models.<tier>, so an agent can be declared before a model is assigned to its tier.
+func (i *Interpreter) unassignedModelTier(env *Environment, expr parser.Expression) *SDKModelRef {
+	member, ok := expr.(*parser.MemberExpression)
+	if !ok {
+		return nil
+	}
+	obj, err := i.evalExpression(env, member.Object)
+	if err != nil {
+		return nil
+	}
+	models, ok := obj.(*ModelsObjectValue)
+	if !ok {
+		return nil
+	}
+	return models.TierRef(member.Property.Value)
+}
+
+// resolveAgentTools returns the tools an agent can use for this run, leaving out those
+// matching its excludeTools patterns. When the agent's tools config is a tool rather than
+// an array, it is called with a context object ({ agent, cwd }) so the toolset can depend
+// on the current directory or other state.
+func (i *Interpreter) resolveAgentTools(agent *AgentValue) (*ArrayValue, error) {
+	tools, err := i.agentToolsConfig(agent)
+	if err != nil {
+		return nil, err
+	}
+	return excludeAgentTools(agent, tools), nil
+}
+
+// agentToolsConfig returns the tools an agent's tools config lists, calling it first
+// when it is a tool
+func (i *Interpreter) agentToolsConfig(agent *AgentValue) (*ArrayValue, error) {
+	switch tools := agent.Config["tools"].(type) {
+	case nil:
+		return &ArrayValue{}, nil
+	case *ArrayValue:
+		return tools, nil
+	case *ToolValue:
+		ctx := &ObjectValue{
+			Properties: map[string]*PropertyDescriptor{
+				"agent": {Value: agentValueToContextObject(agent)},
+				"cwd":   {Value: &StringValue{Value: i.GetWorkingDir()}},
+			},
+		}
+		// The tool may ignore the context by declaring no parameters
+		args := make([]Value, len(tools.Parameters))
+		for n := range args {
+			args[n] = &NullValue{}
+		}
+		if len(args) > 0 {
+			args[0] = ctx
+		}
+		result, err := i.CallTool(i.globalEnv, tools, args)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s' failed to compute its tools: %w", agent.Name, err)
+		}
+		arr, ok := result.(*ArrayValue)
+		if !ok {
+			return nil, fmt.Errorf("agent '%s' tools tool '%s' must return an array, got %s", agent.Name, tools.Name, result.Type())
+		}
+		arr, _ = flattenAgentTools(arr)
+		if err := validateAgentTools(agent.Name, arr, nil); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("agent '%s' tools config must be an array or a tool, got %s", agent.Name, tools.Type())
+	}
+}
+
+// validateAgentTools checks that every element of an agent's tools array is a callable tool
+// (user-defined, MCP, or native), so typos surface at declaration rather than when the agent runs.
+// When the array is written as a literal, the offending element's source text is included in the error.
+func validateAgentTools(agentName string, tools *ArrayValue, expr parser.Expression) error {
+	literal, _ := expr.(*parser.ArrayLiteral)
+	if literal != nil {
+		// Spread elements expand to any number of values, so literal positions no longer
+		// line up with array indices
+		for _, e := range literal.Elements {
+			if _, ok := e.(*parser.SpreadElement); ok {
+				literal = nil
+				break
+			}
+		}
+	}
+	for idx, elem := range tools.Elements {
+		switch elem.(type) {
+		case *ToolValue, *MCPToolValue, *NativeToolValue:
+			continue
+		}
+		if literal != nil && idx < len(literal.Elements) {
+			return fmt.Errorf("agent '%s' has an invalid tool '%s' in 'tools': expected a tool, got %s",
+				agentName, literal.Elements[idx].String(), elem.Type())
+		}
+		return fmt.Errorf("agent '%s' has an invalid tool at index %d in 'tools': expected a tool, got %s",
+			agentName, idx, elem.Type())
+	}
+	return nil
+}
+
+// flattenAgentTools expands elements of an agent's tools array that are themselves arrays,
+// one level deep. It reports whether there were any, returning tools unchanged if not.
+func flattenAgentTools(tools *ArrayValue) (*ArrayValue, bool) {
+	nested := false
+	for _, elem := range tools.Elements {
+		if _, ok := elem.(*ArrayValue); ok {
+			nested = true
+			break
+		}
+	}
+	if !nested {
+		return tools, false
+	}
+
+	flat := &ArrayValue{Elements: []Value{}}
+	for _, elem := range tools.Elements {
+		if inner, ok := elem.(*ArrayValue); ok {
+			flat.Elements = append(flat.Elements, inner.Elements...)
+		} else {
+			flat.Elements = append(flat.Elements, elem)
+		}
+	}
+	return flat, true
+}
+
+// excludeAgentTools leaves out the tools whose name matches one of the agent's excludeTools
+// patterns. Patterns use path.Match syntax, so "*" matches any run of characters. MCP tools
+// match by their own name or as "server.tool".
+func excludeAgentTools(agent *AgentValue, tools *ArrayValue) *ArrayValue {
+	excludeVal, ok := agent.Config["excludeTools"].(*ArrayValue)
+	if !ok || len(excludeVal.Elements) == 0 {
+		return tools
+	}
+	var patterns []string
+	for _, elem := range excludeVal.Elements {
+		if str, ok := elem.(*StringValue); ok {
+			patterns = append(patterns, str.Value)
+		}
+	}
+
+	excluded := func(names ...string) bool {
+		for _, pattern := range patterns {
+			for _, name := range names {
+				if matched, _ := path.Match(pattern, name); matched {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	kept := &ArrayValue{Elements: []Value{}}
+	for _, elem := range tools.Elements {
+		switch tool := elem.(type) {
+		case *ToolValue:
+			if excluded(tool.Name) {
+				continue
+			}
+		case *MCPToolValue:
+			if excluded(tool.ToolName, tool.ServerName+"."+tool.ToolName) {
+				continue
+			}
+		case *NativeToolValue:
+			if excluded(tool.Name) {
+				continue
+			}
+		}
+		kept.Elements = append(kept.Elements, elem)
+	}
+	return kept
+}
@@ -2,14 +2,18 @@ package interpreter
 
 // Agent lifecycle event names
 const (
-	EventAgentStart          = "agent.start"
-	EventAgentEnd            = "agent.end"
-	EventAgentIterationStart = "agent.iteration.start"
-	EventAgentIterationEnd   = "agent.iteration.end"
-	EventAgentChunk          = "agent.chunk"
-	EventAgentToolPending    = "agent.tool.pending"
-	EventAgentToolStart      = "agent.tool.start"
-	EventAgentToolEnd        = "agent.tool.end"
+	EventAgentStart            = "agent.start"
+	EventAgentEnd              = "agent.end"
+	EventAgentIterationStart   = "agent.iteration.start"
+	EventAgentIterationEnd     = "agent.iteration.end"
+	EventAgentIterationWarning = "agent.iteration.warning"
+	EventAgentIterationLimit   = "agent.iteration.limit"
+	EventAgentChunk            = "agent.chunk"
+	EventAgentThinking         = "agent.thinking"
+	EventAgentToolPending      = "agent.tool.pending"
+	EventAgentToolStart        = "agent.tool.start"
+	EventAgentToolEnd          = "agent.tool.end"
+	EventAgentToolApproval     = "agent.tool.approval"
 )
 
 // ToolOverride represents an override returned by an event handler for tool events.
@@ -100,13 +104,19 @@ func createAgentStartContext(agent *AgentValue, message string) Value {
 }
 
 // createAgentEndContext creates the context object for agent.end event
-// ctx: { agent: { name, metadata, ... }, query: { inputTokens, outputTokens, cachedTokens, durationMs }, error }
+// ctx: { agent: { name, metadata, ... }, query: { inputTokens, outputTokens, cachedTokens, cacheHitRate, durationMs }, error }
+// cacheHitRate is the share of input tokens across the run that were read from the prompt cache, from 0 to 1.
 func createAgentEndContext(agent *AgentValue, stopReason string, durationMs int64, inputTokens, outputTokens, cachedTokens int, err error) Value {
 	var errorVal Value = &NullValue{}
 	if err != nil {
 		errorVal = &StringValue{Value: err.Error()}
 	}
 
+	cacheHitRate := 0.0
+	if inputTokens > 0 {
+		cacheHitRate = float64(cachedTokens) / float64(inputTokens)
+	}
+
 	return &ObjectValue{
 		Properties: map[string]*PropertyDescriptor{
 			"agent": {Value: agentValueToContextObject(agent)},
@@ -115,6 +125,7 @@ func createAgentEndContext(agent *AgentValue, stopReason string, durationMs int6
 					"inputTokens":  {Value: &NumberValue{Value: float64(inputTokens)}},
 					"outputTokens": {Value: &NumberValue{Value: float64(outputTokens)}},
 					"cachedTokens": {Value: &NumberValue{Value: float64(cachedTokens)}},
+					"cacheHitRate": {Value: &NumberValue{Value: cacheHitRate}},
 					"durationMs":   {Value: &NumberValue{Value: float64(durationMs)}},
 				},
 			}},
@@ -134,6 +145,51 @@ func createIterationStartContext(agent *AgentValue, iteration int) Value {
 	}
 }
 
+// createIterationWarningContext creates the context object for agent.iteration.warning event
+// ctx: { agent: { name, metadata, ... }, iteration: number, maxIterations: number, remaining: number }
+func createIterationWarningContext(agent *AgentValue, iteration, iterationLimit, maxIterations int) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"agent":         {Value: agentValueToContextObject(agent)},
+			"iteration":     {Value: &NumberValue{Value: float64(iteration + 1)}}, // 1-based for users
+			"maxIterations": {Value: &NumberValue{Value: float64(maxIterations)}},
+			"remaining":     {Value: &NumberValue{Value: float64(iterationLimit - iteration)}},
+		},
+	}
+}
+
+// createIterationLimitContext creates the context object for agent.iteration.limit event
+// ctx: { agent: { name, metadata, ... }, iterations: number, maxIterations: number }
+func createIterationLimitContext(agent *AgentValue, iterations, maxIterations int) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"agent":         {Value: agentValueToContextObject(agent)},
+			"iterations":    {Value: &NumberValue{Value: float64(iterations)}},
+			"maxIterations": {Value: &NumberValue{Value: float64(maxIterations)}},
+		},
+	}
+}
+
+// extractContinue reports whether an agent.iteration.limit handler returned { continue: true }
+func extractContinue(val Value) bool {
+	obj, ok := val.(*ObjectValue)
+	if !ok {
+		return false
+	}
+	cont, ok := obj.GetPropertyValue("continue").(*BoolValue)
+	return ok && cont.Value
+}
+
+// extractApproved reports whether an agent.tool.approval handler returned { approved: true }
+func extractApproved(val Value) bool {
+	obj, ok := val.(*ObjectValue)
+	if !ok {
+		return false
+	}
+	approved, ok := obj.GetPropertyValue("approved").(*BoolValue)
+	return ok && approved.Value
+}
+
 // createIterationEndContext creates the context object for agent.iteration.end event
 // ctx: { agent: { name, metadata, ... }, iteration: number, usage: { inputTokens, outputTokens, cachedTokens } }
 func createIterationEndContext(agent *AgentValue, iteration int, inputTokens, outputTokens, cachedTokens int) Value {
@@ -152,7 +208,7 @@ func createIterationEndContext(agent *AgentValue, iteration int, inputTokens, ou
 	}
 }
 
-// createChunkContext creates the context object for agent.chunk event
+// createChunkContext creates the context object for agent.chunk and agent.thinking events
 // ctx: { agent: { name, metadata, ... }, content: string }
 func createChunkContext(agent *AgentValue, content string) Value {
 	return &ObjectValue{
@@ -261,6 +261,67 @@ gsh.use("agent.chunk", onChunk)
 	}
 }
 
+// thinkingMockProvider streams reasoning before its answer, like a reasoning model
+type thinkingMockProvider struct{}
+
+func (p *thinkingMockProvider) Name() string { return "thinking-mock" }
+
+func (p *thinkingMockProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	return &ChatResponse{Content: "Hi", Reasoning: "Say hi.", FinishReason: "stop"}, nil
+}
+
+func (p *thinkingMockProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	callbacks.OnReasoning("Say ")
+	callbacks.OnReasoning("hi.")
+	callbacks.OnContent("Hi")
+	return p.ChatCompletion(ctx, request)
+}
+
+// TestAgentThinkingEventsEmitted tests that streamed reasoning is emitted as agent.thinking
+// events, ahead of the answer's agent.chunk events, and kept out of the conversation.
+func TestAgentThinkingEventsEmitted(t *testing.T) {
+	provider := &thinkingMockProvider{}
+	interp := New(nil)
+	interp.providerRegistry.Register(provider)
+
+	_, err := interp.EvalString(`
+emittedEvents = []
+
+tool onThinking(ctx, next) {
+	emittedEvents.push("thinking " + ctx.content); return next(ctx)
+}
+tool onChunk(ctx, next) {
+	emittedEvents.push("chunk " + ctx.content); return next(ctx)
+}
+
+gsh.use("agent.thinking", onThinking)
+gsh.use("agent.chunk", onChunk)
+`, nil)
+	if err != nil {
+		t.Fatalf("Failed to register event handlers: %v", err)
+	}
+
+	agent := &AgentValue{
+		Name:   "testAgent",
+		Config: map[string]Value{"model": &ModelValue{Provider: provider}},
+	}
+	conv := &ConversationValue{Messages: []ChatMessage{{Role: "user", Content: "Hello"}}}
+
+	result, err := interp.ExecuteAgent(context.Background(), conv, agent, true)
+	if err != nil {
+		t.Fatalf("ExecuteAgent failed: %v", err)
+	}
+
+	emitted := strings.Join(getEmittedEvents(interp), "|")
+	if emitted != "thinking Say |thinking hi.|chunk Hi" {
+		t.Errorf("unexpected events: %s", emitted)
+	}
+	newConv := result.(*ConversationValue)
+	if last := newConv.Messages[len(newConv.Messages)-1]; last.Content != "Hi" {
+		t.Errorf("expected the conversation to hold only the answer, got %q", last.Content)
+	}
+}
+
 // TestEventConstants tests that event constant names are correct
 func TestEventConstants(t *testing.T) {
 	// Verify event constants match expected values
@@ -274,7 +335,9 @@ func TestEventConstants(t *testing.T) {
 		{EventAgentIterationEnd, "agent.iteration.end"},
 		{EventAgentToolStart, "agent.tool.start"},
 		{EventAgentToolEnd, "agent.tool.end"},
+		{EventAgentToolApproval, "agent.tool.approval"},
 		{EventAgentChunk, "agent.chunk"},
+		{EventAgentThinking, "agent.thinking"},
 	}
 
 	for _, tt := range tests {
@@ -284,6 +347,33 @@ func TestEventConstants(t *testing.T) {
 	}
 }
 
+func TestAgentEndContextCacheHitRate(t *testing.T) {
+	agent := &AgentValue{Name: "test", Config: map[string]Value{}}
+	tests := []struct {
+		name         string
+		inputTokens  int
+		cachedTokens int
+		expected     float64
+	}{
+		{"partially cached", 2000, 1500, 0.75},
+		{"nothing cached", 2000, 0, 0},
+		{"no input tokens", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := createAgentEndContext(agent, "end_turn", 10, tt.inputTokens, 50, tt.cachedTokens, nil).(*ObjectValue)
+			query := ctx.Properties["query"].Value.(*ObjectValue)
+			if got := query.Properties["cachedTokens"].Value.(*NumberValue).Value; got != float64(tt.cachedTokens) {
+				t.Errorf("expected cachedTokens %d, got %v", tt.cachedTokens, got)
+			}
+			if got := query.Properties["cacheHitRate"].Value.(*NumberValue).Value; got != tt.expected {
+				t.Errorf("expected cacheHitRate %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestExtractToolOverride(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -633,3 +723,115 @@ gsh.use("agent.tool.start", onToolStart)
 		t.Error("Expected to find a tool message in the conversation")
 	}
 }
+
+func TestToolApproval(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     string
+		wantRuns    float64
+		wantAsked   float64
+		wantInReply string
+	}{
+		{
+			name:        "denied without a handler",
+			wantRuns:    0,
+			wantAsked:   0,
+			wantInReply: "did not approve running the 'get_weather' tool",
+		},
+		{
+			name: "denied by handler",
+			handler: `
+tool onApproval(ctx, next) {
+	asked = asked + 1
+	return next(ctx)
+}
+gsh.use("agent.tool.approval", onApproval)
+`,
+			wantRuns:    0,
+			wantAsked:   1,
+			wantInReply: "did not approve",
+		},
+		{
+			name: "approved by handler",
+			handler: `
+tool onApproval(ctx, next) {
+	asked = asked + 1
+	if (ctx.toolCall.name == "get_weather" && ctx.toolCall.args.city == "San Francisco") {
+		return { approved: true }
+	}
+	return next(ctx)
+}
+gsh.use("agent.tool.approval", onApproval)
+`,
+			wantRuns:    1,
+			wantAsked:   1,
+			wantInReply: "sunny",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			defer interp.Close()
+			interp.providerRegistry.Register(NewSmartMockProvider())
+
+			result, err := interp.EvalString(`
+runs = 0
+asked = 0
+`+tt.handler+`
+tool get_weather(city: string): string {
+	runs = runs + 1
+	return "sunny"
+}
+
+model mock { provider: "smart-mock" }
+
+agent Forecaster {
+	model: mock,
+	tools: [get_weather],
+	requireApproval: ["get_weather"],
+	metadata: { streaming: false },
+}
+
+conv = "What's the weather in San Francisco?" | Forecaster
+conv.lastMessage.content
+`, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if reply := result.FinalResult.String(); !strings.Contains(reply, tt.wantInReply) {
+				t.Errorf("expected reply to contain %q, got %q", tt.wantInReply, reply)
+			}
+			runs, _ := result.Env.Get("runs")
+			if runs.(*NumberValue).Value != tt.wantRuns {
+				t.Errorf("expected tool to run %v times, got %v", tt.wantRuns, runs)
+			}
+			asked, _ := result.Env.Get("asked")
+			if asked.(*NumberValue).Value != tt.wantAsked {
+				t.Errorf("expected approval to be asked %v times, got %v", tt.wantAsked, asked)
+			}
+		})
+	}
+}
+
+func TestRequireApprovalValidation(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(NewSmartMockProvider())
+
+	_, err := interp.EvalString(`
+model mock { provider: "smart-mock" }
+agent Bad { model: mock, requireApproval: "exec" }
+`, nil)
+	if err == nil || !strings.Contains(err.Error(), "agent config 'requireApproval' must be an array of tool names") {
+		t.Errorf("expected requireApproval type error, got %v", err)
+	}
+
+	_, err = interp.EvalString(`
+agent Bad { model: mock, requireApproval: ["exec", 1] }
+`, nil)
+	if err == nil || !strings.Contains(err.Error(), "agent config 'requireApproval[1]' must be a tool name string") {
+		t.Errorf("expected requireApproval element error, got %v", err)
+	}
+}
@@ -3,7 +3,9 @@ package interpreter
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kunchenguid/gsh/internal/acp"
 )
@@ -12,7 +14,8 @@ import (
 // if not specified in the agent config.
 const DefaultMaxIterations = 100
 
-// ExecuteAgent executes an agent with a conversation and returns the updated conversation.
+// ExecuteAgent executes an agent with a conversation and returns the updated conversation,
+// or the parsed final response when the agent declares an outputSchema.
 // streaming parameter enables streaming responses.
 // SDK events (agent.start, agent.end, etc.) are emitted via the interpreter's event manager.
 // The user message is inferred from the conversation.
@@ -25,7 +28,8 @@ func (i *Interpreter) ExecuteAgent(
 	return i.executeAgentInternal(ctx, conv, agent, streaming, nil)
 }
 
-// ExecuteAgentWithCallbacks executes an agent with callbacks and returns the updated conversation.
+// ExecuteAgentWithCallbacks executes an agent with callbacks and returns the updated conversation,
+// or the parsed final response when the agent declares an outputSchema.
 // streaming parameter enables streaming responses.
 // callbacks can be used to hook into agent execution events (tool execution, streaming chunks, etc.).
 // SDK events (agent.start, agent.end, etc.) are emitted via the interpreter's event manager regardless of callbacks.
@@ -103,6 +107,9 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 	model := modelResolver.GetModel()
 	if model == nil {
 		err := fmt.Errorf("agent '%s' model could not be resolved (check gsh.models configuration)", agent.Name)
+		if ref, ok := modelResolver.(*SDKModelRef); ok {
+			err = fmt.Errorf("agent '%s' uses %s, which has no model assigned (set it with %s = <model>)", agent.Name, ref, ref)
+		}
 		callOnComplete(acp.StopReasonError, err)
 		return nil, err
 	}
@@ -118,6 +125,36 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 		}
 	}
 
+	// Get the tool result size limit from agent config; 0 means unlimited
+	maxToolResultChars := 0
+	if maxCharsVal, ok := agent.Config["maxToolResultChars"]; ok {
+		if numVal, ok := maxCharsVal.(*NumberValue); ok && numVal.Value > 0 {
+			maxToolResultChars = int(numVal.Value)
+		}
+	}
+
+	// Tools listed in requireApproval only run once an agent.tool.approval handler approves them
+	requireApproval := make(map[string]bool)
+	if approvalVal, ok := agent.Config["requireApproval"].(*ArrayValue); ok {
+		for _, elem := range approvalVal.Elements {
+			if str, ok := elem.(*StringValue); ok {
+				requireApproval[str.Value] = true
+			}
+		}
+	}
+
+	// An outputSchema asks for a structured final response, parsed and returned instead of the conversation
+	var schema *outputSchema
+	if schemaVal, ok := agent.Config["outputSchema"]; ok {
+		var err error
+		if schema, err = compileOutputSchema(schemaVal); err != nil {
+			err = fmt.Errorf("agent '%s' has an invalid 'outputSchema': %w", agent.Name, err)
+			callOnComplete(acp.StopReasonError, err)
+			return nil, err
+		}
+	}
+	schemaRetries := outputSchemaRetries
+
 	// Prepare tools for the agent
 	// First, add tools from callbacks (e.g., REPL built-in tools)
 	tools := []ChatTool{}
@@ -125,35 +162,38 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 		tools = append(tools, callbacks.Tools...)
 	}
 
-	// Then add tools from agent config
-	if toolsVal, ok := agent.Config["tools"]; ok {
-		if toolsArr, ok := toolsVal.(*ArrayValue); ok {
-			for _, toolValInterface := range toolsArr.Elements {
-				// Handle different tool types
-				switch toolVal := toolValInterface.(type) {
-				case *ToolValue:
-					// User-defined tool
-					tool := i.convertUserToolToChatTool(toolVal)
-					tools = append(tools, tool)
-				case *MCPToolValue:
-					// MCP tool
-					tool, err := i.convertMCPToolToChatTool(toolVal)
-					if err != nil {
-						err = fmt.Errorf("failed to convert MCP tool: %w", err)
-						callOnComplete(acp.StopReasonError, err)
-						return nil, err
-					}
-					tools = append(tools, tool)
-				case *NativeToolValue:
-					// Native tool (gsh.tools.*)
-					tool := i.convertNativeToolToChatTool(toolVal)
-					tools = append(tools, tool)
-				default:
-					err := fmt.Errorf("invalid tool type in agent config: %s", toolVal.Type())
-					callOnComplete(acp.StopReasonError, err)
-					return nil, err
-				}
+	// Then add tools from agent config, which may be computed for this run
+	agentTools, err := i.resolveAgentTools(agent)
+	if err != nil {
+		callOnComplete(acp.StopReasonError, err)
+		return nil, err
+	}
+	toolNames := agentToolNames(agentTools)
+	for _, toolValInterface := range agentTools.Elements {
+		// Handle different tool types
+		switch toolVal := toolValInterface.(type) {
+		case *ToolValue:
+			// User-defined tool
+			tool := i.convertUserToolToChatTool(toolVal)
+			tools = append(tools, tool)
+		case *MCPToolValue:
+			// MCP tool
+			tool, err := i.convertMCPToolToChatTool(toolVal)
+			if err != nil {
+				err = fmt.Errorf("failed to convert MCP tool: %w", err)
+				callOnComplete(acp.StopReasonError, err)
+				return nil, err
 			}
+			tool.Name = toolNames[toolVal]
+			tools = append(tools, tool)
+		case *NativeToolValue:
+			// Native tool (gsh.tools.*)
+			tool := i.convertNativeToolToChatTool(toolVal)
+			tools = append(tools, tool)
+		default:
+			err := fmt.Errorf("invalid tool type in agent config: %s", toolVal.Type())
+			callOnComplete(acp.StopReasonError, err)
+			return nil, err
 		}
 	}
 
@@ -175,14 +215,24 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 	buildRequestMessages := func() []ChatMessage {
 		messages := []ChatMessage{}
 
-		// Add system prompt if configured
+		// Add system prompt if configured, followed by any output format instructions
+		systemPrompt := ""
 		if systemPromptVal, ok := agent.Config["systemPrompt"]; ok {
 			if systemPromptStr, ok := systemPromptVal.(*StringValue); ok {
-				messages = append(messages, ChatMessage{
-					Role:    "system",
-					Content: systemPromptStr.Value,
-				})
+				systemPrompt = systemPromptStr.Value
+			}
+		}
+		if schema != nil {
+			if systemPrompt != "" {
+				systemPrompt += "\n\n"
 			}
+			systemPrompt += schema.instructions()
+		}
+		if systemPrompt != "" {
+			messages = append(messages, ChatMessage{
+				Role:    "system",
+				Content: systemPrompt,
+			})
 		}
 
 		// Add all messages from the conversation
@@ -193,8 +243,21 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 	// Determine if we should use streaming
 	useStreaming := streaming
 
-	// Agentic loop - continue until no tool calls or max iterations reached
-	for iteration := 0; iteration < maxIterations; iteration++ {
+	// Agentic loop - continue until no tool calls or the iteration limit is reached.
+	// agent.iteration.warning fires once 80% of the allowance is used so the UI can flag an agent
+	// that may be going in circles. At the limit, an agent.iteration.limit handler can return
+	// { continue: true } to grant another maxIterations instead of stopping.
+	iterationLimit := maxIterations
+	warnAt := iterationLimit - max(1, maxIterations/5)
+	for iteration := 0; ; iteration++ {
+		if iteration == iterationLimit {
+			if !extractContinue(i.EmitEvent(EventAgentIterationLimit, createIterationLimitContext(agent, iteration, maxIterations))) {
+				break
+			}
+			iterationLimit += maxIterations
+			warnAt = iterationLimit - max(1, maxIterations/5)
+		}
+
 		// Check for context cancellation
 		if ctx.Err() != nil {
 			err := ctx.Err()
@@ -202,6 +265,10 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 			return newConv, err
 		}
 
+		if iteration == warnAt {
+			i.EmitEvent(EventAgentIterationWarning, createIterationWarningContext(agent, iteration, iterationLimit, maxIterations))
+		}
+
 		// Emit agent.iteration.start event
 		i.EmitEvent(EventAgentIterationStart, createIterationStartContext(agent, iteration))
 
@@ -217,20 +284,29 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 			Tools:    tools,
 		}
 
-		// Call the model (streaming or non-streaming)
+		// Call the model (streaming or non-streaming). The model fires model.request and
+		// model.response itself; the context tells it which agent is calling.
 		var response *ChatResponse
 		var err error
+		modelCtx := withModelCallAgent(ctx, agent)
 
 		if useStreaming {
-			// Use streaming with tool call detection
+			// Use streaming with tool call detection. Chunks are coalesced when
+			// gsh.agentChunkInterval is set, and always flushed before anything else renders.
+			chunks := newChunkCoalescer(i.agentChunkInterval(), func(content string) {
+				// Emit agent.chunk event
+				i.EmitEvent(EventAgentChunk, createChunkContext(agent, content))
+				// Also call the original callback
+				if callbacks != nil && callbacks.OnChunk != nil {
+					callbacks.OnChunk(content)
+				}
+			})
 			streamCallbacks := &StreamCallbacks{
-				OnContent: func(content string) {
-					// Emit agent.chunk event
-					i.EmitEvent(EventAgentChunk, createChunkContext(agent, content))
-					// Also call the original callback
-					if callbacks != nil && callbacks.OnChunk != nil {
-						callbacks.OnChunk(content)
-					}
+				OnContent: chunks.add,
+				// Emit agent.thinking for reasoning, after any content already received
+				OnReasoning: func(content string) {
+					chunks.flush()
+					i.EmitEvent(EventAgentThinking, createChunkContext(agent, content))
 				},
 				// Check for context cancellation (e.g., Ctrl+C)
 				ShouldCancel: func() bool {
@@ -239,18 +315,33 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 			}
 			// Always emit SDK event when tool call enters pending state (streaming from LLM)
 			streamCallbacks.OnToolPending = func(toolCallID string, toolName string) {
+				chunks.flush()
 				i.EmitEvent(EventAgentToolPending, createToolPendingContext(agent, toolCallID, toolName))
 				// Also call the original callback if provided
 				if callbacks != nil && callbacks.OnToolPending != nil {
 					callbacks.OnToolPending(toolCallID, toolName)
 				}
 			}
-			response, err = model.Provider.StreamingChatCompletion(ctx, request, streamCallbacks)
+			streamCallbacks.OnStreamEnd = chunks.flush
+			if callbacks != nil {
+				streamCallbacks.OnUsage = callbacks.OnUsage
+				if callbacks.OnStreamEnd != nil {
+					streamCallbacks.OnStreamEnd = func() {
+						chunks.flush()
+						callbacks.OnStreamEnd()
+					}
+				}
+			}
+			response, err = i.session.callModel(model, streamCallbacks, func() (*ChatResponse, error) {
+				return model.StreamingChatCompletion(modelCtx, request, streamCallbacks)
+			})
+			chunks.flush()
 		} else {
-			// Non-streaming call
-			response, err = model.ChatCompletion(ctx, request)
+			// Non-streaming call, served from the response cache when enabled
+			response, err = i.session.callModel(model, nil, func() (*ChatResponse, error) {
+				return i.cachedChatCompletion(modelCtx, model, request)
+			})
 		}
-
 		if err != nil {
 			// Check if the error is due to context cancellation (e.g., Ctrl+C interrupt)
 			if ctx.Err() == context.Canceled {
@@ -281,13 +372,35 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 		// If no tool calls, add final response and return
 		if len(response.ToolCalls) == 0 {
 			newConv.Messages = append(newConv.Messages, ChatMessage{
-				Role:    "assistant",
-				Content: response.Content,
+				Role:      "assistant",
+				Content:   response.Content,
+				Timestamp: time.Now(),
 			})
 			// Emit agent.iteration.end event before completing
 			i.EmitEvent(EventAgentIterationEnd, createIterationEndContext(agent, iteration, iterInputTokens, iterOutputTokens, iterCachedTokens))
-			callOnComplete(acp.StopReasonEndTurn, nil)
-			return newConv, nil
+			if schema == nil {
+				callOnComplete(acp.StopReasonEndTurn, nil)
+				return newConv, nil
+			}
+
+			output, err := schema.parse(response.Content)
+			if err == nil {
+				callOnComplete(acp.StopReasonEndTurn, nil)
+				return output, nil
+			}
+			if schemaRetries == 0 {
+				err = fmt.Errorf("agent '%s' final %w", agent.Name, err)
+				callOnComplete(acp.StopReasonError, err)
+				return newConv, err
+			}
+			// Tell the model what was wrong and let it try again
+			schemaRetries--
+			newConv.Messages = append(newConv.Messages, ChatMessage{
+				Role:      "user",
+				Content:   schema.retryPrompt(err),
+				Timestamp: time.Now(),
+			})
+			continue
 		}
 
 		// Add assistant message with tool calls
@@ -295,6 +408,7 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 			Role:      "assistant",
 			Content:   response.Content,
 			ToolCalls: response.ToolCalls,
+			Timestamp: time.Now(),
 		})
 
 		// Execute tool calls and add results
@@ -331,9 +445,13 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 					toolErr = fmt.Errorf("%s", override.Error)
 				}
 				skippedExecution = true
+			} else if toolRequiresApproval(requireApproval, agentTools, toolCall.Name) && !extractApproved(i.EmitEvent(EventAgentToolApproval, startCtx)) {
+				// Nobody approved the call - skip it and tell the model why
+				toolErr = fmt.Errorf("the user did not approve running the '%s' tool, so it was not run", toolCall.Name)
+				skippedExecution = true
 			} else {
 				// Execute the tool normally
-				toolResult, toolErr = i.executeToolCall(agent, toolCall)
+				toolResult, toolErr = i.executeToolCall(agentTools, toolCall)
 			}
 
 			toolDuration := time.Since(toolStart)
@@ -377,12 +495,14 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 				toolResult = fmt.Sprintf("Error executing tool: %v", toolErr)
 			}
 
-			// Add tool result to conversation with proper tool_call_id
+			// Add tool result to conversation with proper tool_call_id.
+			// Only the conversation copy is truncated; callbacks and events saw the full result.
 			newConv.Messages = append(newConv.Messages, ChatMessage{
 				Role:       "tool",
-				Content:    toolResult,
+				Content:    limitToolResult(toolResult, maxToolResultChars),
 				Name:       toolCall.Name,
 				ToolCallID: toolCall.ID,
+				Timestamp:  time.Now(),
 			})
 		}
 
@@ -392,8 +512,48 @@ func (i *Interpreter) executeAgentInternal(ctx context.Context, conv *Conversati
 		// Continue loop to make another call
 	}
 
-	// If we reach here, we hit max iterations - return what we have
-	err := fmt.Errorf("agent reached maximum iterations (%d) without completing", maxIterations)
+	// If we reach here, we hit the iteration limit - return what we have
+	err = fmt.Errorf("agent reached maximum iterations (%d) without completing", iterationLimit)
 	callOnComplete(acp.StopReasonMaxIterations, err)
 	return newConv, err
 }
+
+// limitToolResult truncates a tool result longer than maxChars characters so that a
+// runaway output can't flood the context window. The full result is saved to a temp
+// file whose path is included in the truncation marker. maxChars <= 0 means no limit.
+func limitToolResult(result string, maxChars int) string {
+	if maxChars <= 0 {
+		return result
+	}
+	total := utf8.RuneCountInString(result)
+	if total <= maxChars {
+		return result
+	}
+
+	// Cut at a rune boundary
+	cut := 0
+	for n := 0; n < maxChars; n++ {
+		_, size := utf8.DecodeRuneInString(result[cut:])
+		cut += size
+	}
+
+	marker := fmt.Sprintf("\n\n[gsh: tool result truncated, showing the first %d of %d characters", maxChars, total)
+	if path, err := saveFullToolResult(result); err == nil {
+		marker += ". The full result is saved to " + path
+	}
+	return result[:cut] + marker + "]"
+}
+
+// saveFullToolResult writes a truncated tool result to a temp file and returns its path
+func saveFullToolResult(result string) (string, error) {
+	f, err := os.CreateTemp("", "gsh-tool-result-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(result); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
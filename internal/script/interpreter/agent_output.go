@@ -0,0 +1,75 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// outputSchemaRetries is how many times an agent with an outputSchema is re-prompted
+// when its final response isn't JSON matching the schema.
+const outputSchemaRetries = 1
+
+// outputSchema is a compiled agent outputSchema, ready to validate responses.
+type outputSchema struct {
+	resolved *jsonschema.Resolved
+	text     string // the schema as JSON, for the model's instructions
+}
+
+// compileOutputSchema converts an agent's outputSchema config value into a JSON schema
+// that responses can be validated against.
+func compileOutputSchema(val Value) (*outputSchema, error) {
+	data, err := json.Marshal(ValueToInterface(val))
+	if err != nil {
+		return nil, err
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &outputSchema{resolved: resolved, text: string(data)}, nil
+}
+
+// instructions returns the system prompt text telling the model how to format its final response
+func (s *outputSchema) instructions() string {
+	return "When you have finished, your final response must be only a JSON value that matches this JSON schema, " +
+		"with no other text or code fences:\n" + s.text
+}
+
+// parse decodes a final response and validates it against the schema. A surrounding
+// ```json code fence is tolerated since models often add one anyway.
+func (s *outputSchema) parse(content string) (Value, error) {
+	var result interface{}
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &result); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	if err := s.resolved.Validate(result); err != nil {
+		return nil, fmt.Errorf("response does not match the output schema: %v", err)
+	}
+	return jsonToValue(result), nil
+}
+
+// retryPrompt is the user message sent when a final response fails validation
+func (s *outputSchema) retryPrompt(err error) string {
+	return fmt.Sprintf("Your %s. Reply again with only a JSON value that matches the schema.", err)
+}
+
+// stripCodeFence removes a markdown code fence wrapped around the whole of content
+func stripCodeFence(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") || len(trimmed) < 6 {
+		return trimmed
+	}
+	inner := strings.TrimSuffix(trimmed, "```")
+	newline := strings.Index(inner, "\n")
+	if newline < 0 {
+		return trimmed
+	}
+	return strings.TrimSpace(inner[newline+1:])
+}
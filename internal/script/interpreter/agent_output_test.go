@@ -0,0 +1,151 @@
+package interpreter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// scriptedMockProvider returns its responses in order, repeating the last one,
+// and records every request it receives
+type scriptedMockProvider struct {
+	responses []string
+	requests  []ChatRequest
+}
+
+func (s *scriptedMockProvider) Name() string { return "scripted-mock" }
+
+func (s *scriptedMockProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	s.requests = append(s.requests, request)
+	idx := len(s.requests) - 1
+	if idx >= len(s.responses) {
+		idx = len(s.responses) - 1
+	}
+	return &ChatResponse{Content: s.responses[idx]}, nil
+}
+
+func (s *scriptedMockProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	return s.ChatCompletion(ctx, request)
+}
+
+const outputSchemaScript = `
+model testModel { provider: "scripted-mock", model: "test" }
+
+agent Extractor {
+	model: testModel,
+	systemPrompt: "Extract the person.",
+	outputSchema: {
+		type: "object",
+		properties: {
+			name: { type: "string" },
+			age: { type: "integer" },
+		},
+		required: ["name", "age"],
+	},
+}
+
+result = "Ada is 36" | Extractor
+result
+`
+
+func runOutputSchemaScript(t *testing.T, responses ...string) (*scriptedMockProvider, *EvalResult, error) {
+	t.Helper()
+	mock := &scriptedMockProvider{responses: responses}
+	interp := New(nil)
+	t.Cleanup(func() { interp.Close() })
+	interp.providerRegistry.Register(mock)
+	result, err := interp.EvalString(outputSchemaScript, nil)
+	return mock, result, err
+}
+
+func TestAgentOutputSchema(t *testing.T) {
+	mock, result, err := runOutputSchemaScript(t, "```json\n{\"name\": \"Ada\", \"age\": 36}\n```")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, ok := result.FinalResult.(*ObjectValue)
+	if !ok {
+		t.Fatalf("expected an object result, got %T", result.FinalResult)
+	}
+	if name := obj.GetPropertyValue("name").String(); name != "Ada" {
+		t.Errorf("expected name Ada, got %s", name)
+	}
+	if age, ok := obj.GetPropertyValue("age").(*NumberValue); !ok || age.Value != 36 {
+		t.Errorf("expected age 36, got %s", obj.GetPropertyValue("age").String())
+	}
+
+	system := mock.requests[0].Messages[0]
+	if system.Role != "system" || !strings.HasPrefix(system.Content, "Extract the person.\n\n") ||
+		!strings.Contains(system.Content, `"required":["name","age"]`) {
+		t.Errorf("expected the schema to be appended to the system prompt, got %q", system.Content)
+	}
+}
+
+func TestAgentOutputSchemaRetry(t *testing.T) {
+	mock, result, err := runOutputSchemaScript(t, `{"name": "Ada"}`, `{"name": "Ada", "age": 36}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.FinalResult.(*ObjectValue); !ok {
+		t.Fatalf("expected an object result, got %T", result.FinalResult)
+	}
+	if len(mock.requests) != 2 {
+		t.Fatalf("expected 2 model calls, got %d", len(mock.requests))
+	}
+
+	messages := mock.requests[1].Messages
+	retry := messages[len(messages)-1]
+	if retry.Role != "user" || !strings.Contains(retry.Content, "does not match the output schema") {
+		t.Errorf("expected a retry prompt explaining the failure, got %q", retry.Content)
+	}
+}
+
+func TestAgentOutputSchemaRetryFails(t *testing.T) {
+	mock, _, err := runOutputSchemaScript(t, "Ada is 36 years old.")
+	if err == nil || !strings.Contains(err.Error(), "agent 'Extractor' final response is not valid JSON") {
+		t.Fatalf("expected a final response error, got %v", err)
+	}
+	if len(mock.requests) != 1+outputSchemaRetries {
+		t.Errorf("expected %d model calls, got %d", 1+outputSchemaRetries, len(mock.requests))
+	}
+}
+
+func TestAgentOutputSchemaDeclarationErrors(t *testing.T) {
+	tests := []struct {
+		schema  string
+		wantErr string
+	}{
+		{`"object"`, "agent config 'outputSchema' must be an object, got string"},
+		{`{ type: 5 }`, "agent 'A' has an invalid 'outputSchema'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			interp := New(nil)
+			defer interp.Close()
+			source := `model m { provider: "openai", model: "x" }
+agent A { model: m, outputSchema: ` + tt.schema + ` }`
+			_, err := interp.EvalString(source, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := map[string]string{
+		`{"a": 1}`:                   `{"a": 1}`,
+		"  [1, 2]\n":                 "[1, 2]",
+		"```json\n{\"a\": 1}\n```":   `{"a": 1}`,
+		"```\n[1]\n```":              "[1]",
+		"```{\"a\": 1}```":           "```{\"a\": 1}```",
+		"```json\n{}\n```\ntrailing": "```json\n{}\n```\ntrailing",
+	}
+	for input, want := range tests {
+		if got := stripCodeFence(input); got != want {
+			t.Errorf("stripCodeFence(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
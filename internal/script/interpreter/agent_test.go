@@ -124,10 +124,12 @@ func TestAgentDeclaration(t *testing.T) {
 					apiKey: "test-key",
 					model: "gpt-4",
 				}
+				tool tool1() { return 1 }
+				tool tool2() { return 2 }
 				agent Helper {
 					model: gpt4,
 					systemPrompt: "You help users",
-					tools: ["tool1", "tool2", "tool3"],
+					tools: [tool1, tool2, gsh.tools.exec],
 				}`,
 			checkFunc: func(t *testing.T, result *EvalResult, err error) {
 				if err != nil {
@@ -462,6 +464,34 @@ func TestAgentDeclarationErrors(t *testing.T) {
 				}`,
 			expectedError: "tools' must be an array",
 		},
+		{
+			name: "Agent declaration with a non-tool in tools array",
+			input: `
+				model gpt4 {
+					provider: "openai",
+					apiKey: "test-key",
+					model: "gpt-4",
+				}
+				agent BadTools {
+					model: gpt4,
+					tools: [gsh.tools.exec, "grep"],
+				}`,
+			expectedError: "agent 'BadTools' has an invalid tool '\"grep\"' in 'tools': expected a tool, got string",
+		},
+		{
+			name: "Agent declaration with a misspelled native tool",
+			input: `
+				model gpt4 {
+					provider: "openai",
+					apiKey: "test-key",
+					model: "gpt-4",
+				}
+				agent BadTools {
+					model: gpt4,
+					tools: [gsh.tools.view_fil],
+				}`,
+			expectedError: "invalid tool 'gsh.tools.view_fil'",
+		},
 		{
 			name: "Agent declaration with invalid metadata type (string)",
 			input: `
@@ -1137,6 +1167,223 @@ func TestAgentModelResolution_RuntimeChange(t *testing.T) {
 	})
 }
 
+func TestAgentModelTierAssignedLater(t *testing.T) {
+	mock := &mockModelProvider{name: "tier-mock"}
+
+	t.Run("agent declared before its tier is assigned", func(t *testing.T) {
+		interp := New(nil)
+		defer interp.Close()
+		interp.providerRegistry.Register(mock)
+
+		input := `
+agent Quick {
+	model: gsh.models.lite,
+	systemPrompt: "Be brief",
+}
+
+model fast { provider: "tier-mock", model: "fast-1" }
+gsh.models.lite = fast
+
+"hi" | Quick
+`
+		if _, err := interp.EvalString(input, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mock.calledWithModel != "fast" {
+			t.Errorf("expected agent to use the tier's model 'fast', got %q", mock.calledWithModel)
+		}
+	})
+
+	t.Run("tier still unassigned when the agent runs", func(t *testing.T) {
+		interp := New(nil)
+		defer interp.Close()
+
+		_, err := interp.EvalString(`agent Quick { model: gsh.models.premium }
+"hi" | Quick`, nil)
+		want := "agent 'Quick' uses gsh.models.premium, which has no model assigned (set it with gsh.models.premium = <model>)"
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	})
+
+	t.Run("unknown tier is still rejected", func(t *testing.T) {
+		interp := New(nil)
+		defer interp.Close()
+
+		_, err := interp.EvalString(`agent Quick { model: gsh.models.turbo }`, nil)
+		if err == nil || !strings.Contains(err.Error(), "agent config 'model' must be a model reference, got null") {
+			t.Errorf("expected model reference error, got %v", err)
+		}
+	})
+}
+
+// toolListMockProvider records the tool names offered on each request and calls the
+// first offered tool once before answering
+type toolListMockProvider struct {
+	offered [][]string
+}
+
+func (m *toolListMockProvider) Name() string { return "tool-list-mock" }
+
+func (m *toolListMockProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	var names []string
+	for _, tool := range request.Tools {
+		names = append(names, tool.Name)
+	}
+	m.offered = append(m.offered, names)
+
+	last := request.Messages[len(request.Messages)-1]
+	if len(names) > 0 && last.Role != "tool" {
+		return &ChatResponse{ToolCalls: []ChatToolCall{{ID: "call_1", Name: names[0], Arguments: map[string]interface{}{}}}}, nil
+	}
+	return &ChatResponse{Content: "done: " + last.Content}, nil
+}
+
+func (m *toolListMockProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	return m.ChatCompletion(ctx, request)
+}
+
+func TestAgentComputedTools(t *testing.T) {
+	mock := &toolListMockProvider{}
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(mock)
+
+	projectDir := t.TempDir()
+	interp.runner.Dir = projectDir
+
+	input := `
+model m { provider: "tool-list-mock", model: "test" }
+
+tool deploy() { return "deployed" }
+tool status() { return "ok" }
+
+tool projectTools(ctx) {
+	if (ctx.cwd == env.PROJECT_DIR && ctx.agent.name == "Ops") {
+		return [deploy, status]
+	}
+	return [status]
+}
+
+agent Ops { model: m, tools: projectTools }
+
+inProject = "go" | Ops
+`
+	t.Setenv("PROJECT_DIR", projectDir)
+	result, err := interp.EvalString(input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mock.offered[0], ","); got != "deploy,status" {
+		t.Errorf("expected deploy and status in the project, got %q", got)
+	}
+	convVal, _ := result.Env.Get("inProject")
+	conv := convVal.(*ConversationValue)
+	if last := conv.Messages[len(conv.Messages)-1].Content; last != `done: "deployed"` {
+		t.Errorf("expected the computed deploy tool to run, got %q", last)
+	}
+
+	// The tool list is computed again on every run
+	mock.offered = nil
+	interp.runner.Dir = t.TempDir()
+	if _, err := interp.EvalString(`"go" | Ops`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mock.offered[0], ","); got != "status" {
+		t.Errorf("expected only status outside the project, got %q", got)
+	}
+}
+
+func TestAgentToolGroupsAndExcludes(t *testing.T) {
+	mock := &toolListMockProvider{}
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(mock)
+
+	_, err := interp.EvalString(`
+model m { provider: "tool-list-mock", model: "test" }
+
+tool deploy() { return "deployed" }
+tool deployStaging() { return "staged" }
+tool status() { return "ok" }
+opsTools = [deploy, deployStaging]
+
+agent Ops { model: m, tools: [opsTools, status, gsh.tools.grep], excludeTools: ["deploy*", "grep"] }
+"go" | Ops
+
+tool computed() { return [opsTools, status] }
+agent Computed { model: m, tools: computed, excludeTools: ["deployStaging"] }
+"go" | Computed
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mock.offered[0], ","); got != "status" {
+		t.Errorf("expected deploy tools and grep to be excluded, got %q", got)
+	}
+	if got := strings.Join(mock.offered[len(mock.offered)-2], ","); got != "deploy,status" {
+		t.Errorf("expected computed tools to be flattened and filtered, got %q", got)
+	}
+}
+
+func TestAgentExcludeToolsValidation(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{`agent A { model: m, excludeTools: "exec" }`, "agent config 'excludeTools' must be an array of tool name patterns, got string"},
+		{`agent A { model: m, excludeTools: [1] }`, "agent config 'excludeTools[0]' must be a tool name pattern string, got number"},
+		{`agent A { model: m, excludeTools: ["[exec"] }`, "agent config 'excludeTools[0]' is not a valid pattern: [exec"},
+		{`agent A { model: m, tools: [[1]] }`, "agent 'A' has an invalid tool at index 0 in 'tools': expected a tool, got number"},
+	}
+
+	for _, tt := range tests {
+		interp := New(nil)
+		_, err := interp.EvalString(`model m { provider: "openai", model: "test" }`+"\n"+tt.source, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.source, tt.wantErr, err)
+		}
+		interp.Close()
+	}
+}
+
+func TestAgentComputedToolsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{
+			name:    "tools is neither an array nor a tool",
+			source:  `agent A { model: m, tools: "exec" }`,
+			wantErr: "agent config 'tools' must be an array or a tool returning one, got string",
+		},
+		{
+			name:    "tool returns a non-array",
+			source:  "tool pick() { return gsh.tools.exec }\nagent A { model: m, tools: pick }\n\"go\" | A",
+			wantErr: "agent 'A' tools tool 'pick' must return an array, got tool",
+		},
+		{
+			name:    "tool returns an invalid entry",
+			source:  "tool pick() { return [\"exec\"] }\nagent A { model: m, tools: pick }\n\"go\" | A",
+			wantErr: "agent 'A' has an invalid tool at index 0 in 'tools': expected a tool, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			defer interp.Close()
+			interp.providerRegistry.Register(&toolListMockProvider{})
+
+			_, err := interp.EvalString(`model m { provider: "tool-list-mock", model: "test" }`+"\n"+tt.source, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestAgentValueMethods(t *testing.T) {
 	agent := &AgentValue{
 		Name: "TestAgent",
@@ -106,6 +106,9 @@ func arraySliceImpl(arr *ArrayValue, args []Value) (Value, error) {
 		end = int(args[1].(*NumberValue).Value)
 		if end < 0 {
 			end = len(arr.Elements) + end
+			if end < 0 {
+				end = 0
+			}
 		}
 	}
 
@@ -127,11 +130,147 @@ func arraySliceImpl(arr *ArrayValue, args []Value) (Value, error) {
 	return &ArrayValue{Elements: newElements}, nil
 }
 
-// arrayReverseImpl implements the reverse method
+// arrayReverseImpl implements the reverse method: returns a new array with the
+// elements in reverse order and leaves the original untouched
 func arrayReverseImpl(arr *ArrayValue, args []Value) (Value, error) {
-	// Reverse the array in place
-	for i, j := 0, len(arr.Elements)-1; i < j; i, j = i+1, j-1 {
-		arr.Elements[i], arr.Elements[j] = arr.Elements[j], arr.Elements[i]
+	n := len(arr.Elements)
+	newElements := make([]Value, n)
+	for idx, elem := range arr.Elements {
+		newElements[n-1-idx] = elem
+	}
+	return &ArrayValue{Elements: newElements}, nil
+}
+
+// arrayConcatImpl implements the concat method: returns a new array with the elements
+// of the given arrays appended, leaving the original untouched
+func arrayConcatImpl(arr *ArrayValue, args []Value) (Value, error) {
+	total := len(arr.Elements)
+	for _, arg := range args {
+		other, ok := arg.(*ArrayValue)
+		if !ok {
+			return nil, fmt.Errorf("concat() arguments must be arrays, got %s", arg.Type())
+		}
+		total += len(other.Elements)
+	}
+
+	newElements := make([]Value, 0, total)
+	newElements = append(newElements, arr.Elements...)
+	for _, arg := range args {
+		newElements = append(newElements, arg.(*ArrayValue).Elements...)
+	}
+	return &ArrayValue{Elements: newElements}, nil
+}
+
+// arrayCallback validates the callback argument of a predicate method (find, some, every)
+// and returns a function that calls it with (element, index, array). The callback may
+// declare fewer parameters; extra parameters beyond these three receive null.
+func (i *Interpreter) arrayCallback(method string, args []Value) (func(arr *ArrayValue, idx int) (bool, error), error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s() takes 1 argument (callback: tool), got %d", method, len(args))
+	}
+	tool, ok := args[0].(*ToolValue)
+	if !ok {
+		return nil, fmt.Errorf("%s() callback must be a tool, got %s", method, args[0].Type())
+	}
+
+	return func(arr *ArrayValue, idx int) (bool, error) {
+		available := []Value{arr.Elements[idx], &NumberValue{Value: float64(idx)}, arr}
+		callArgs := make([]Value, len(tool.Parameters))
+		for n := range callArgs {
+			if n < len(available) {
+				callArgs[n] = available[n]
+			} else {
+				callArgs[n] = &NullValue{}
+			}
+		}
+		result, err := i.CallTool(i.globalEnv, tool, callArgs)
+		if err != nil {
+			return false, err
+		}
+		return result.IsTruthy(), nil
+	}, nil
+}
+
+// arrayFindImpl implements the find method: returns the first element for which the
+// callback is truthy, or null. Stops at the first match.
+func (i *Interpreter) arrayFindImpl(arr *ArrayValue, args []Value) (Value, error) {
+	predicate, err := i.arrayCallback("find", args)
+	if err != nil {
+		return nil, err
+	}
+	for idx := 0; idx < len(arr.Elements); idx++ {
+		matched, err := predicate(arr, idx)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return arr.Elements[idx], nil
+		}
+	}
+	return &NullValue{}, nil
+}
+
+// arraySomeImpl implements the some method: true if the callback is truthy for any
+// element. Stops at the first match.
+func (i *Interpreter) arraySomeImpl(arr *ArrayValue, args []Value) (Value, error) {
+	predicate, err := i.arrayCallback("some", args)
+	if err != nil {
+		return nil, err
+	}
+	for idx := 0; idx < len(arr.Elements); idx++ {
+		matched, err := predicate(arr, idx)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return &BoolValue{Value: true}, nil
+		}
+	}
+	return &BoolValue{Value: false}, nil
+}
+
+// arrayEveryImpl implements the every method: true if the callback is truthy for all
+// elements (and for an empty array). Stops at the first mismatch.
+func (i *Interpreter) arrayEveryImpl(arr *ArrayValue, args []Value) (Value, error) {
+	predicate, err := i.arrayCallback("every", args)
+	if err != nil {
+		return nil, err
+	}
+	for idx := 0; idx < len(arr.Elements); idx++ {
+		matched, err := predicate(arr, idx)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return &BoolValue{Value: false}, nil
+		}
+	}
+	return &BoolValue{Value: true}, nil
+}
+
+// arrayIndexOfImpl implements the indexOf method using value equality: returns the
+// index of the first matching element, or -1
+func arrayIndexOfImpl(arr *ArrayValue, args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("indexOf() takes 1 argument (value), got %d", len(args))
+	}
+	for idx, elem := range arr.Elements {
+		if elem.Equals(args[0]) {
+			return &NumberValue{Value: float64(idx)}, nil
+		}
+	}
+	return &NumberValue{Value: -1}, nil
+}
+
+// arrayIncludesImpl implements the includes method using value equality
+func arrayIncludesImpl(arr *ArrayValue, args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("includes() takes 1 argument (value), got %d", len(args))
+	}
+	for _, elem := range arr.Elements {
+		if elem.Equals(args[0]) {
+			return &BoolValue{Value: true}, nil
+		}
 	}
-	return arr, nil
+	return &BoolValue{Value: false}, nil
 }
@@ -1,6 +1,7 @@
 package interpreter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kunchenguid/gsh/internal/script/lexer"
@@ -245,6 +246,196 @@ func TestArraySlice(t *testing.T) {
 	}
 }
 
+func TestArrayCopyingMethods(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"slice with negative start", "result = [1, 2, 3, 4].slice(-3, -1)", "[2, 3]"},
+		{"slice with end before start of array", "result = [1, 2].slice(0, -5)", "[]"},
+		{"slice past the end", "result = [1, 2].slice(1, 10)", "[2]"},
+		{"concat appends arrays", "result = [1].concat([2, 3], [], [4])", "[1, 2, 3, 4]"},
+		{"concat with no arguments copies", "a = [1]\nb = a.concat()\nb.push(2)\nresult = a", "[1]"},
+		{"concat leaves original untouched", "a = [1]\nb = a.concat([2])\nresult = a", "[1]"},
+		{"reverse returns reversed copy", "result = [1, 2, 3].reverse()", "[3, 2, 1]"},
+		{"reverse leaves original untouched", "a = [1, 2, 3]\nb = a.reverse()\nresult = a", "[1, 2, 3]"},
+		{"indexOf finds first match", "result = [\"a\", \"b\", \"a\"].indexOf(\"a\")", "0"},
+		{"indexOf returns -1 when missing", "result = [1, 2].indexOf(3)", "-1"},
+		{"indexOf does not coerce types", "result = [1, 2].indexOf(\"2\")", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			result, err := interp.EvalString(tt.input, nil)
+			if err != nil {
+				t.Fatalf("interpreter error: %v", err)
+			}
+
+			value, ok := result.Env.Get("result")
+			if !ok {
+				t.Fatalf("failed to get result")
+			}
+			if value.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
+
+func TestArrayPredicateMethods(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"find returns first match", "tool isEven(n) { return n % 2 == 0 }\nresult = [1, 3, 4, 6].find(isEven)", "4"},
+		{"find returns null when nothing matches", "tool isEven(n) { return n % 2 == 0 }\nresult = [1, 3].find(isEven)", "null"},
+		{"find receives index", "tool second(x, i) { return i == 1 }\nresult = [\"a\", \"b\", \"c\"].find(second)", "b"},
+		{"some true", "tool isEven(n) { return n % 2 == 0 }\nresult = [1, 2].some(isEven)", "true"},
+		{"some false", "tool isEven(n) { return n % 2 == 0 }\nresult = [1, 3].some(isEven)", "false"},
+		{"some on empty array", "tool isEven(n) { return n % 2 == 0 }\nresult = [].some(isEven)", "false"},
+		{"every true", "tool positive(n) { return n > 0 }\nresult = [1, 2].every(positive)", "true"},
+		{"every false", "tool positive(n) { return n > 0 }\nresult = [1, -2].every(positive)", "false"},
+		{"every on empty array", "tool positive(n) { return n > 0 }\nresult = [].every(positive)", "true"},
+		{"includes number", "result = [1, 2, 3].includes(2)", "true"},
+		{"includes string", "result = [\"a\", \"b\"].includes(\"c\")", "false"},
+		{"includes does not coerce types", "result = [1, 2].includes(\"1\")", "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			result, err := interp.EvalString(tt.input, nil)
+			if err != nil {
+				t.Fatalf("interpreter error: %v", err)
+			}
+
+			value, ok := result.Env.Get("result")
+			if !ok {
+				t.Fatalf("failed to get result")
+			}
+			if value.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
+
+func TestArrayPredicateMethods_ShortCircuit(t *testing.T) {
+	input := `
+calls = 0
+tool isBig(n) {
+	calls = calls + 1
+	return n > 10
+}
+tool isSmall(n) {
+	calls = calls + 1
+	return n < 10
+}
+found = [1, 20, 30, 40].find(isBig)
+findCalls = calls
+calls = 0
+any = [1, 20, 30, 40].some(isBig)
+someCalls = calls
+calls = 0
+all = [1, 20, 30, 40].every(isSmall)
+everyCalls = calls
+`
+	interp := New(nil)
+	result, err := interp.EvalString(input, nil)
+	if err != nil {
+		t.Fatalf("interpreter error: %v", err)
+	}
+
+	for name, expected := range map[string]string{"findCalls": "2", "someCalls": "2", "everyCalls": "2"} {
+		value, _ := result.Env.Get(name)
+		if value.String() != expected {
+			t.Errorf("expected %s to be %s, got %s", name, expected, value.String())
+		}
+	}
+}
+
+func TestArrayPredicateMethods_Errors(t *testing.T) {
+	tests := []struct {
+		input  string
+		errMsg string
+	}{
+		{"[1].find(1)", "find() callback must be a tool"},
+		{"[1].some()", "some() takes 1 argument"},
+		{"[1].includes()", "includes() takes 1 argument"},
+		{"[1].indexOf(1, 2)", "indexOf() takes 1 argument"},
+		{"[1].concat(2)", "concat() arguments must be arrays, got number"},
+		{"tool boom(n) { throw \"boom\" }\n[1].every(boom)", "boom"},
+	}
+
+	for _, tt := range tests {
+		interp := New(nil)
+		_, err := interp.EvalString(tt.input, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.input, tt.errMsg, err)
+		}
+	}
+}
+
+func TestArraySpreadAndFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"spread merges arrays", "a = [1, 2]\nb = [4]\nresult = [...a, 3, ...b]", "[1, 2, 3, 4]"},
+		{"spread of empty array", "a = []\nresult = [0, ...a]", "[0]"},
+		{"spread copies", "a = [1]\nresult = [...a]\nresult.push(2)\nresult = [a.length, result.length]", "[1, 2]"},
+		{"spread of call result", "tool pair() { return [1, 2] }\nresult = [...pair(), ...pair()]", "[1, 2, 1, 2]"},
+		{"from array copies", "a = [1, 2]\nresult = Array.from(a)\nresult.push(3)\nresult = a.length", "2"},
+		{"from string", "result = Array.from(\"héy\")", `["h", "é", "y"]`},
+		{"from empty string", "result = Array.from(\"\")", "[]"},
+		{"from object values", "result = Array.from({b: 2, a: 1, c: 3})", "[1, 2, 3]"},
+		{"from map values", "result = Array.from(Map([[\"y\", 2], [\"x\", 1]]))", "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			result, err := interp.EvalString(tt.input, nil)
+			if err != nil {
+				t.Fatalf("interpreter error: %v", err)
+			}
+
+			value, ok := result.Env.Get("result")
+			if !ok {
+				t.Fatalf("failed to get result")
+			}
+			if value.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
+
+func TestArraySpreadAndFrom_Errors(t *testing.T) {
+	tests := []struct {
+		input  string
+		errMsg string
+	}{
+		{"x = \"abc\"\nresult = [...x]", "spread requires an array, got string (line 2, column 11)"},
+		{"result = [...{a: 1}]", "spread requires an array, got object"},
+		{"result = [...null]", "spread requires an array, got null"},
+		{"result = Array.from(5)", "Array.from() argument must be an array, string, object, map, or set, got number"},
+		{"result = Array.from()", "Array.from() takes exactly 1 argument, got 0"},
+	}
+
+	for _, tt := range tests {
+		interp := New(nil)
+		_, err := interp.EvalString(tt.input, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.input, tt.errMsg, err)
+		}
+	}
+}
+
 func TestStringLength(t *testing.T) {
 	input := "str = \"hello\"\nresult = str.length"
 
@@ -0,0 +1,36 @@
+package interpreter
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// builtinGshEncodeBase64 implements gsh.encodeBase64(text), encoding the string's bytes
+// with standard (padded) base64
+func (i *Interpreter) builtinGshEncodeBase64(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("gsh.encodeBase64() takes 1 argument (text: string), got %d", len(args))
+	}
+	text, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.encodeBase64() argument must be a string, got %s", args[0].Type())
+	}
+	return &StringValue{Value: base64.StdEncoding.EncodeToString([]byte(text.Value))}, nil
+}
+
+// builtinGshDecodeBase64 implements gsh.decodeBase64(data). The decoded bytes are returned
+// as a string unchanged, even if they aren't valid UTF-8.
+func (i *Interpreter) builtinGshDecodeBase64(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("gsh.decodeBase64() takes 1 argument (data: string), got %d", len(args))
+	}
+	data, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.decodeBase64() argument must be a string, got %s", args[0].Type())
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data.Value)
+	if err != nil {
+		return nil, fmt.Errorf("gsh.decodeBase64() argument is not valid base64: %w", err)
+	}
+	return &StringValue{Value: string(decoded)}, nil
+}
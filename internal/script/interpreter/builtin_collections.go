@@ -1,6 +1,9 @@
 package interpreter
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // builtinMap implements the Map() constructor
 // Map() creates an empty map
@@ -68,3 +71,57 @@ func builtinSet(args []Value) (Value, error) {
 
 	return &SetValue{Elements: elements}, nil
 }
+
+// builtinArrayFrom implements Array.from(value), which builds a new array from an array
+// (a shallow copy), a string (one element per character), an object, map, or set (its
+// values, ordered by key so the result is deterministic)
+func builtinArrayFrom(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Array.from() takes exactly 1 argument, got %d", len(args))
+	}
+
+	switch v := args[0].(type) {
+	case *ArrayValue:
+		elements := make([]Value, len(v.Elements))
+		copy(elements, v.Elements)
+		return &ArrayValue{Elements: elements}, nil
+	case *StringValue:
+		runes := []rune(v.Value)
+		elements := make([]Value, len(runes))
+		for idx, r := range runes {
+			elements[idx] = &StringValue{Value: string(r)}
+		}
+		return &ArrayValue{Elements: elements}, nil
+	case *ObjectValue:
+		keys := make([]string, 0, len(v.Properties))
+		for key := range v.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		elements := make([]Value, len(keys))
+		for idx, key := range keys {
+			elements[idx] = v.GetPropertyValue(key)
+		}
+		return &ArrayValue{Elements: elements}, nil
+	case *MapValue:
+		return &ArrayValue{Elements: sortedValues(v.Entries)}, nil
+	case *SetValue:
+		return &ArrayValue{Elements: sortedValues(v.Elements)}, nil
+	default:
+		return nil, fmt.Errorf("Array.from() argument must be an array, string, object, map, or set, got %s", args[0].Type())
+	}
+}
+
+// sortedValues returns the values of m ordered by key
+func sortedValues(m map[string]Value) []Value {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]Value, len(keys))
+	for idx, key := range keys {
+		values[idx] = m[key]
+	}
+	return values
+}
@@ -7,19 +7,26 @@ import (
 
 // builtinNames contains all the names of built-in functions and objects
 var builtinNames = map[string]bool{
-	"print":    true,
-	"input":    true,
-	"JSON":     true,
-	"log":      true,
-	"env":      true,
-	"Map":      true,
-	"Set":      true,
-	"exec":     true,
-	"gsh":      true,
-	"Math":     true,
-	"DateTime": true,
-	"Regexp":   true,
-	"typeof":   true,
+	"print":      true,
+	"input":      true,
+	"JSON":       true,
+	"Array":      true,
+	"log":        true,
+	"env":        true,
+	"Map":        true,
+	"Set":        true,
+	"exec":       true,
+	"gsh":        true,
+	"Math":       true,
+	"DateTime":   true,
+	"Regexp":     true,
+	"typeof":     true,
+	"file":       true,
+	"image":      true,
+	"keyring":    true,
+	"mcpImport":  true,
+	"parseInt":   true,
+	"parseFloat": true,
 }
 
 // isBuiltin checks if a name is a built-in function or object
@@ -98,12 +105,25 @@ func (i *Interpreter) registerBuiltins() {
 	// Register env object for environment variable access
 	i.globalEnv.Set("env", &EnvValue{interp: i})
 
+	// Register keyring object for reading secrets from the OS keyring
+	i.globalEnv.Set("keyring", &KeyringValue{interp: i})
+
 	// Register Map constructor
 	i.globalEnv.Set("Map", &BuiltinValue{
 		Name: "Map",
 		Fn:   builtinMap,
 	})
 
+	// Register Array object with the from method
+	i.globalEnv.Set("Array", &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"from": {Value: &BuiltinValue{
+				Name: "Array.from",
+				Fn:   builtinArrayFrom,
+			}},
+		},
+	})
+
 	// Register Set constructor
 	i.globalEnv.Set("Set", &BuiltinValue{
 		Name: "Set",
@@ -122,11 +142,39 @@ func (i *Interpreter) registerBuiltins() {
 		Fn:   i.builtinInput,
 	})
 
+	// Register file function for referencing files on disk
+	i.globalEnv.Set("file", &BuiltinValue{
+		Name: "file",
+		Fn:   i.builtinFile,
+	})
+
+	// Register image function for attaching images to agent messages
+	i.globalEnv.Set("image", &BuiltinValue{
+		Name: "image",
+		Fn:   i.builtinImage,
+	})
+
+	// Register mcpImport function for starting MCP servers defined in an mcp.json file
+	i.globalEnv.Set("mcpImport", &BuiltinValue{
+		Name: "mcpImport",
+		Fn:   i.builtinMcpImport,
+	})
+
 	// Register typeof function for runtime type inspection
 	i.globalEnv.Set("typeof", &BuiltinValue{
 		Name: "typeof",
 		Fn:   builtinTypeof,
 	})
+
+	// Register parseInt and parseFloat for reading numbers out of strings
+	i.globalEnv.Set("parseInt", &BuiltinValue{
+		Name: "parseInt",
+		Fn:   builtinParseInt,
+	})
+	i.globalEnv.Set("parseFloat", &BuiltinValue{
+		Name: "parseFloat",
+		Fn:   builtinParseFloat,
+	})
 }
 
 // builtinTypeof returns the type of a value as a string
@@ -169,6 +217,10 @@ func builtinTypeof(args []Value) (Value, error) {
 		typeName = "mcp_tool"
 	case *ConversationValue:
 		typeName = "conversation"
+	case *FileValue:
+		typeName = "file"
+	case *ImageValue:
+		typeName = "image"
 	default:
 		typeName = "unknown"
 	}
@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -11,6 +12,7 @@ import (
 // - DateTime.parse(str, format?) - parses a date string into timestamp
 // - DateTime.format(timestamp, format?) - formats a timestamp into string
 // - DateTime.diff(timestamp1, timestamp2, unit?) - returns difference between two timestamps
+// - DateTime.addDays(timestamp, days) - adds calendar days to a timestamp
 func createDateTimeObject() *ObjectValue {
 	return &ObjectValue{
 		Properties: map[string]*PropertyDescriptor{
@@ -30,6 +32,10 @@ func createDateTimeObject() *ObjectValue {
 				Name: "DateTime.diff",
 				Fn:   builtinDateTimeDiff,
 			}, ReadOnly: true},
+			"addDays": {Value: &BuiltinValue{
+				Name: "DateTime.addDays",
+				Fn:   builtinDateTimeAddDays,
+			}, ReadOnly: true},
 		},
 	}
 }
@@ -159,6 +165,28 @@ func builtinDateTimeDiff(args []Value) (Value, error) {
 	return &NumberValue{Value: result}, nil
 }
 
+// builtinDateTimeAddDays implements DateTime.addDays(timestamp, days)
+// Adds whole calendar days in local time, so the time of day is kept across DST changes.
+// Negative days move the timestamp back.
+func builtinDateTimeAddDays(args []Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("DateTime.addDays() takes 2 arguments (timestamp, days), got %d", len(args))
+	}
+
+	ts, ok := args[0].(*NumberValue)
+	if !ok || math.IsNaN(ts.Value) || math.IsInf(ts.Value, 0) {
+		return nil, fmt.Errorf("DateTime.addDays() first argument must be a number (timestamp in ms), got %s", args[0].String())
+	}
+
+	days, ok := args[1].(*NumberValue)
+	if !ok || days.Value != math.Trunc(days.Value) || math.IsInf(days.Value, 0) {
+		return nil, fmt.Errorf("DateTime.addDays() second argument must be a whole number of days, got %s", args[1].String())
+	}
+
+	t := time.UnixMilli(int64(ts.Value)).AddDate(0, 0, int(days.Value))
+	return &NumberValue{Value: float64(t.UnixMilli())}, nil
+}
+
 // dayjsToGoFormat converts dayjs-style format tokens to Go time format
 // Common tokens:
 // YYYY -> 2006, YY -> 06
@@ -490,3 +490,50 @@ func TestDateTimeDiffArgumentValidation(t *testing.T) {
 		t.Fatal("expected error when calling DateTime.diff() with non-string unit")
 	}
 }
+
+func TestDateTimeAddDays(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		days     int
+		expected time.Time
+	}{
+		{1, time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)},
+		{30, time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)},
+		{-31, time.Date(2023, 12, 31, 12, 0, 0, 0, time.Local)},
+		{0, start},
+	}
+
+	for _, tt := range tests {
+		result, err := interp.EvalString(fmt.Sprintf(`DateTime.addDays(%d, %d)`, start.UnixMilli(), tt.days), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		numVal, ok := result.FinalResult.(*NumberValue)
+		if !ok {
+			t.Fatalf("expected number, got %s", result.FinalResult.Type())
+		}
+		if int64(numVal.Value) != tt.expected.UnixMilli() {
+			t.Errorf("addDays(%d): expected %v, got %v", tt.days, tt.expected, time.UnixMilli(int64(numVal.Value)))
+		}
+	}
+}
+
+func TestDateTimeAddDaysArgumentValidation(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	invalid := []string{
+		`DateTime.addDays(1000)`,
+		`DateTime.addDays("2024-01-01", 1)`,
+		`DateTime.addDays(1000, "1")`,
+		`DateTime.addDays(1000, 1.5)`,
+	}
+	for _, input := range invalid {
+		if _, err := interp.EvalString(input, nil); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
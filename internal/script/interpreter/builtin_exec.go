@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"time"
 
@@ -9,25 +10,99 @@ import (
 )
 
 // builtinExec implements the exec() function for executing shell commands
-// exec(command: string, options?: {timeout?: number}): {stdout: string, stderr: string, exitCode: number}
+// exec(command: string, options?: {timeout?: number, env?: object, encoding?: string, stream?: boolean}): {stdout: string, stderr: string, exitCode: number}
+// With options.stream set, output goes straight to the terminal and only the exit code is returned.
 func (i *Interpreter) builtinExec(args []Value) (Value, error) {
+	return i.execCommand("exec", args)
+}
+
+// builtinGshExec implements gsh.exec(), the SDK spelling of exec()
+func (i *Interpreter) builtinGshExec(args []Value) (Value, error) {
+	return i.execCommand("gsh.exec", args)
+}
+
+// builtinGshExecSequence implements gsh.execSequence(commands, options?), which runs
+// commands in order like exec() and returns an array of their results, each with its
+// command. By default it stops after the first command that exits non-zero, like &&;
+// with options.stopOnError set to false it runs every command, like ;. The other options
+// apply to each command as in exec().
+func (i *Interpreter) builtinGshExecSequence(args []Value) (Value, error) {
 	if len(args) == 0 || len(args) > 2 {
-		return nil, fmt.Errorf("exec() takes 1 or 2 arguments (command: string, options?: object), got %d", len(args))
+		return nil, fmt.Errorf("gsh.execSequence() takes 1 or 2 arguments (commands: array, options?: object), got %d", len(args))
+	}
+	commands, ok := args[0].(*ArrayValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.execSequence() first argument must be an array of strings, got %s", args[0].Type())
+	}
+	for idx, cmd := range commands.Elements {
+		if _, ok := cmd.(*StringValue); !ok {
+			return nil, fmt.Errorf("gsh.execSequence() command %d must be a string, got %s", idx, cmd.Type())
+		}
+	}
+
+	stopOnError := true
+	execArgs := []Value{nil}
+	if len(args) == 2 {
+		opts, ok := args[1].(*ObjectValue)
+		if !ok {
+			return nil, fmt.Errorf("gsh.execSequence() second argument must be an object, got %s", args[1].Type())
+		}
+		if val := opts.GetPropertyValue("stopOnError"); val.Type() != ValueTypeNull {
+			b, ok := val.(*BoolValue)
+			if !ok {
+				return nil, fmt.Errorf("gsh.execSequence() options.stopOnError must be a boolean, got %s", val.Type())
+			}
+			stopOnError = b.Value
+		}
+		execArgs = append(execArgs, opts)
+	}
+
+	results := &ArrayValue{Elements: []Value{}}
+	for _, cmd := range commands.Elements {
+		execArgs[0] = cmd
+		result, err := i.execCommand("gsh.execSequence", execArgs)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := result.(*ObjectValue)
+		if !ok {
+			// Streamed commands only return their exit code
+			obj = &ObjectValue{Properties: map[string]*PropertyDescriptor{"exitCode": {Value: result}}}
+		}
+		obj.Properties["command"] = &PropertyDescriptor{Value: cmd}
+		results.Elements = append(results.Elements, obj)
+
+		if exitCode := obj.GetPropertyValue("exitCode").(*NumberValue); stopOnError && exitCode.Value != 0 {
+			break
+		}
+	}
+	return results, nil
+}
+
+// execCommand runs a command through the shared runner in a subshell, so it sees the
+// session's env vars and working directory, and honors the interpreter's context for
+// cancellation. name is the function name used in error messages.
+func (i *Interpreter) execCommand(name string, args []Value) (Value, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("%s() takes 1 or 2 arguments (command: string, options?: object), got %d", name, len(args))
 	}
 
 	// First argument: command (string)
 	cmdValue, ok := args[0].(*StringValue)
 	if !ok {
-		return nil, fmt.Errorf("exec() first argument must be a string, got %s", args[0].Type())
+		return nil, fmt.Errorf("%s() first argument must be a string, got %s", name, args[0].Type())
 	}
 	command := cmdValue.Value
 
 	// Second argument (optional): options object
 	timeout := 60 * time.Second // Default timeout
+	var env map[string]string
+	base64Stdout := false
+	stream := false
 	if len(args) == 2 {
 		optsValue, ok := args[1].(*ObjectValue)
 		if !ok {
-			return nil, fmt.Errorf("exec() second argument must be an object, got %s", args[1].Type())
+			return nil, fmt.Errorf("%s() second argument must be an object, got %s", name, args[1].Type())
 		}
 
 		// Parse timeout option if provided
@@ -36,8 +111,51 @@ func (i *Interpreter) builtinExec(args []Value) (Value, error) {
 			if timeoutNum, ok := timeoutVal.(*NumberValue); ok {
 				timeout = time.Duration(timeoutNum.Value) * time.Millisecond
 			} else {
-				return nil, fmt.Errorf("exec() options.timeout must be a number (milliseconds), got %s", timeoutVal.Type())
+				return nil, fmt.Errorf("%s() options.timeout must be a number (milliseconds), got %s", name, timeoutVal.Type())
+			}
+		}
+
+		// Parse env option if provided: extra environment variables for this command only
+		envVal := optsValue.GetPropertyValue("env")
+		if envVal.Type() != ValueTypeNull {
+			envObj, ok := envVal.(*ObjectValue)
+			if !ok {
+				return nil, fmt.Errorf("%s() options.env must be an object, got %s", name, envVal.Type())
+			}
+			env = make(map[string]string, len(envObj.Properties))
+			for key := range envObj.Properties {
+				val := envObj.GetPropertyValue(key)
+				str, ok := val.(*StringValue)
+				if !ok {
+					return nil, fmt.Errorf("%s() options.env.%s must be a string, got %s", name, key, val.Type())
+				}
+				env[key] = str.Value
+			}
+		}
+
+		// Parse encoding option if provided: "base64" returns stdout base64-encoded, so
+		// binary output survives string operations unchanged
+		encodingVal := optsValue.GetPropertyValue("encoding")
+		if encodingVal.Type() != ValueTypeNull {
+			encoding, ok := encodingVal.(*StringValue)
+			if !ok || (encoding.Value != "utf8" && encoding.Value != "base64") {
+				return nil, fmt.Errorf("%s() options.encoding must be \"utf8\" or \"base64\", got %s", name, encodingVal.String())
+			}
+			base64Stdout = encoding.Value == "base64"
+		}
+
+		// Parse stream option if provided: output goes to the terminal as it is produced
+		// instead of being captured, for long-running commands the user wants to watch
+		streamVal := optsValue.GetPropertyValue("stream")
+		if streamVal.Type() != ValueTypeNull {
+			b, ok := streamVal.(*BoolValue)
+			if !ok {
+				return nil, fmt.Errorf("%s() options.stream must be a boolean, got %s", name, streamVal.Type())
 			}
+			stream = b.Value
+		}
+		if stream && base64Stdout {
+			return nil, fmt.Errorf("%s() options.encoding cannot be used with options.stream", name)
 		}
 	}
 
@@ -47,19 +165,34 @@ func (i *Interpreter) builtinExec(args []Value) (Value, error) {
 	defer cancel()
 
 	// Execute the command in a subshell
-	stdout, stderr, exitCode, err := i.executeBashInSubshell(ctx, command)
+	var stdout, stderr string
+	var exitCode int
+	var err error
+	if stream {
+		exitCode, err = i.executeBashInSubshellStreaming(ctx, command, env)
+	} else {
+		stdout, stderr, exitCode, err = i.executeBashInSubshellWithEnv(ctx, command, env)
+	}
 
 	// Check for context errors (timeout or cancellation)
 	if ctx.Err() == context.DeadlineExceeded {
-		return nil, fmt.Errorf("exec() command timed out after %v", timeout)
+		return nil, fmt.Errorf("%s() command timed out after %v", name, timeout)
 	}
 	if ctx.Err() == context.Canceled {
-		return nil, fmt.Errorf("exec() command cancelled")
+		return nil, fmt.Errorf("%s() command cancelled", name)
 	}
 
 	// If there's an execution error (not just non-zero exit code), return it
 	if err != nil {
-		return nil, fmt.Errorf("exec() failed: %w", err)
+		return nil, fmt.Errorf("%s() failed: %w", name, err)
+	}
+
+	if stream {
+		return &NumberValue{Value: float64(exitCode)}, nil
+	}
+
+	if base64Stdout {
+		stdout = base64.StdEncoding.EncodeToString([]byte(stdout))
 	}
 
 	// Return result as an object with stdout, stderr, and exitCode
@@ -83,3 +216,23 @@ func (i *Interpreter) executeBashInSubshell(ctx context.Context, command string)
 
 	return bash.RunBashCommandInSubShellWithExitCode(ctx, runner, command)
 }
+
+// executeBashInSubshellWithEnv is like executeBashInSubshell, but exports env into the
+// subshell first. The overrides don't leak into the shared runner.
+func (i *Interpreter) executeBashInSubshellWithEnv(ctx context.Context, command string, env map[string]string) (string, string, int, error) {
+	i.runnerMu.RLock()
+	runner := i.runner
+	i.runnerMu.RUnlock()
+
+	return bash.RunBashCommandInSubShellWithEnv(ctx, runner, command, env)
+}
+
+// executeBashInSubshellStreaming is like executeBashInSubshellWithEnv, but the command
+// uses the runner's stdio, so its output is shown live rather than captured
+func (i *Interpreter) executeBashInSubshellStreaming(ctx context.Context, command string, env map[string]string) (int, error) {
+	i.runnerMu.RLock()
+	runner := i.runner
+	i.runnerMu.RUnlock()
+
+	return bash.RunBashCommandInSubShellStreaming(ctx, runner, command, env)
+}
@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// maxPipedFileSize caps how much of a file can be piped into an agent as a message
+const maxPipedFileSize = 1024 * 1024
+
+// FileValue is a reference to a file on disk, created by file(path).
+// Its contents are read when it is used, e.g. when piped into an agent.
+type FileValue struct {
+	// Path is the absolute path of the file
+	Path string
+}
+
+func (f *FileValue) Type() ValueType { return ValueTypeFile }
+func (f *FileValue) String() string  { return fmt.Sprintf("<file %s>", f.Path) }
+func (f *FileValue) IsTruthy() bool  { return true }
+func (f *FileValue) Equals(other Value) bool {
+	if otherFile, ok := other.(*FileValue); ok {
+		return f.Path == otherFile.Path
+	}
+	return false
+}
+
+// GetProperty returns a property of the file reference
+func (f *FileValue) GetProperty(name string) Value {
+	switch name {
+	case "path":
+		return &StringValue{Value: f.Path}
+	case "name":
+		return &StringValue{Value: filepath.Base(f.Path)}
+	default:
+		return &NullValue{}
+	}
+}
+
+// builtinFile implements file(path), which returns a reference to a regular file.
+// Relative paths are resolved against the shell's working directory.
+func (i *Interpreter) builtinFile(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("file() takes exactly 1 argument, got %d", len(args))
+	}
+	pathVal, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("file() argument must be a string, got %s", args[0].Type())
+	}
+
+	path := pathVal.Value
+	if !filepath.IsAbs(path) {
+		if dir := i.GetWorkingDir(); dir != "" {
+			path = filepath.Join(dir, path)
+		}
+	}
+	path, err := resolveFilePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("file(): %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("file(): %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("file(): %s is a directory", pathVal.Value)
+	}
+	return &FileValue{Path: path}, nil
+}
+
+// readFileForPipe reads a file to be sent as a message, refusing files that
+// are too large or not text
+func readFileForPipe(file *FileValue) (string, error) {
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return "", fmt.Errorf("cannot pipe file: %w", err)
+	}
+	if info.Size() > maxPipedFileSize {
+		return "", fmt.Errorf("cannot pipe file %s: size %d bytes exceeds the %d byte limit", file.Path, info.Size(), maxPipedFileSize)
+	}
+
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return "", fmt.Errorf("cannot pipe file: %w", err)
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", fmt.Errorf("cannot pipe file %s: it appears to be a binary file", file.Path)
+	}
+	return string(data), nil
+}
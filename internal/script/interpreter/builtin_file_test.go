@@ -0,0 +1,111 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// evalWithMock evaluates input with a smart mock provider registered
+func evalWithMock(t *testing.T, input string) (Value, error) {
+	t.Helper()
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(NewSmartMockProvider())
+
+	result, err := interp.EvalString(input, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.FinalResult, nil
+}
+
+const fileTestAgent = `
+model testModel {
+	provider: "smart-mock",
+	model: "test"
+}
+
+agent TestAgent {
+	model: testModel,
+}
+`
+
+func TestPipeFileToAgent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "question.txt")
+	if err := os.WriteFile(path, []byte("What is 2+2?"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := evalWithMock(t, fileTestAgent+fmt.Sprintf("file(%q) | TestAgent", path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conv, ok := result.(*ConversationValue)
+	if !ok {
+		t.Fatalf("expected ConversationValue, got %T", result)
+	}
+	if len(conv.Messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(conv.Messages))
+	}
+	if conv.Messages[0].Role != "user" || conv.Messages[0].Content != "What is 2+2?" {
+		t.Errorf("expected file contents as the first user message, got %+v", conv.Messages[0])
+	}
+}
+
+func TestPipeFileRejectsBinaryAndLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "image.bin")
+	if err := os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0x00, 0x01}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	large := filepath.Join(dir, "large.txt")
+	if err := os.WriteFile(large, []byte(strings.Repeat("a", maxPipedFileSize+1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		wantErr string
+	}{
+		{binary, "binary file"},
+		{large, "exceeds"},
+	}
+	for _, tt := range tests {
+		_, err := evalWithMock(t, fileTestAgent+fmt.Sprintf("file(%q) | TestAgent", tt.path))
+		if err == nil {
+			t.Errorf("expected error piping %s", tt.path)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+		}
+	}
+}
+
+func TestFileBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := evalWithMock(t, fmt.Sprintf(`f = file(%q)
+typeof(f) + ":" + f.name`, path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String() != "file:notes.txt" {
+		t.Errorf("expected 'file:notes.txt', got %q", result.String())
+	}
+
+	if _, err := evalWithMock(t, fmt.Sprintf("file(%q)", filepath.Join(dir, "missing.txt"))); err == nil {
+		t.Error("expected error for a missing file")
+	}
+	if _, err := evalWithMock(t, fmt.Sprintf("file(%q)", dir)); err == nil {
+		t.Error("expected error for a directory")
+	}
+}
@@ -0,0 +1,211 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gitCommandPrefix keeps read-only git commands from taking the index lock, like the
+// REPL's git context retriever does, so they can't block a concurrent git command
+const gitCommandPrefix = "GIT_OPTIONAL_LOCKS=0 git "
+
+// defaultGitLogCount is how many commits gsh.git.log() returns without an argument
+const defaultGitLogCount = 10
+
+// createGitObject creates the gsh.git object with helpers for the repository containing
+// the current directory. Each one returns null outside a git repository:
+// - gsh.git.status() - returns { branch, dirty, ahead, behind, files }
+// - gsh.git.root() - returns the repository's top-level directory
+// - gsh.git.log(n?) - returns the last n commits as { hash, author, timestamp, subject }
+func (i *Interpreter) createGitObject() *ObjectValue {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"status": {Value: &BuiltinValue{
+				Name: "gsh.git.status",
+				Fn:   i.builtinGitStatus,
+			}, ReadOnly: true},
+			"root": {Value: &BuiltinValue{
+				Name: "gsh.git.root",
+				Fn:   i.builtinGitRoot,
+			}, ReadOnly: true},
+			"log": {Value: &BuiltinValue{
+				Name: "gsh.git.log",
+				Fn:   i.builtinGitLog,
+			}, ReadOnly: true},
+		},
+	}
+}
+
+// runGit runs a git command in the current directory. ok is false when git fails,
+// which includes running outside a repository.
+func (i *Interpreter) runGit(args string) (stdout string, ok bool, err error) {
+	return i.runGitContext(i.Context(), args)
+}
+
+// runGitContext is like runGit, but runs under ctx instead of the current execution context
+func (i *Interpreter) runGitContext(ctx context.Context, args string) (stdout string, ok bool, err error) {
+	stdout, _, exitCode, err := i.executeBashInSubshell(ctx, gitCommandPrefix+args)
+	if err != nil {
+		return "", false, err
+	}
+	return stdout, exitCode == 0, nil
+}
+
+// builtinGitRoot implements gsh.git.root()
+func (i *Interpreter) builtinGitRoot(args []Value) (Value, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("gsh.git.root() takes no arguments, got %d", len(args))
+	}
+	out, ok, err := i.runGit("rev-parse --show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("gsh.git.root() failed: %w", err)
+	}
+	if !ok {
+		return &NullValue{}, nil
+	}
+	return &StringValue{Value: strings.TrimRight(out, "\n")}, nil
+}
+
+// builtinGitStatus implements gsh.git.status()
+func (i *Interpreter) builtinGitStatus(args []Value) (Value, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("gsh.git.status() takes no arguments, got %d", len(args))
+	}
+	if i.promptGitAsync() {
+		return i.asyncGitStatus(), nil
+	}
+	status, err := i.gitStatus(i.Context())
+	if err != nil {
+		return nil, fmt.Errorf("gsh.git.status() failed: %w", err)
+	}
+	return status, nil
+}
+
+// gitStatus returns the status object for gsh.git.status(), or null outside a repository
+func (i *Interpreter) gitStatus(ctx context.Context) (Value, error) {
+	out, ok, err := i.runGitContext(ctx, "status --porcelain=v2 --branch -z")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &NullValue{}, nil
+	}
+	return parseGitStatus(out), nil
+}
+
+// parseGitStatus converts `git status --porcelain=v2 --branch -z` output into
+// { branch, dirty, ahead, behind, files }. branch is null on a detached HEAD, and each
+// file is { path, status } where status is git's two-letter XY code ("??" if untracked).
+func parseGitStatus(out string) Value {
+	var branch Value = &NullValue{}
+	ahead, behind := 0, 0
+	files := []Value{}
+
+	entries := strings.Split(out, "\x00")
+	for n := 0; n < len(entries); n++ {
+		entry := entries[n]
+		switch {
+		case strings.HasPrefix(entry, "# branch.head "):
+			if head := strings.TrimPrefix(entry, "# branch.head "); head != "(detached)" {
+				branch = &StringValue{Value: head}
+			}
+		case strings.HasPrefix(entry, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(entry, "# branch.ab "), "+%d -%d", &ahead, &behind)
+		case strings.HasPrefix(entry, "1 "), strings.HasPrefix(entry, "u "):
+			// "1 XY sub mH mI mW hH hI path", unmerged entries have more fields before the path
+			fields := 9
+			if entry[0] == 'u' {
+				fields = 11
+			}
+			parts := strings.SplitN(entry, " ", fields)
+			if len(parts) == fields {
+				files = append(files, gitFileValue(parts[fields-1], parts[1]))
+			}
+		case strings.HasPrefix(entry, "2 "):
+			// Renames and copies: "2 XY sub mH mI mW hH hI Xscore path", then the original path
+			parts := strings.SplitN(entry, " ", 10)
+			if len(parts) == 10 {
+				files = append(files, gitFileValue(parts[9], parts[1]))
+			}
+			n++
+		case strings.HasPrefix(entry, "? "):
+			files = append(files, gitFileValue(strings.TrimPrefix(entry, "? "), "??"))
+		}
+	}
+
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"branch": {Value: branch},
+			"dirty":  {Value: &BoolValue{Value: len(files) > 0}},
+			"ahead":  {Value: &NumberValue{Value: float64(ahead)}},
+			"behind": {Value: &NumberValue{Value: float64(behind)}},
+			"files":  {Value: &ArrayValue{Elements: files}},
+		},
+	}
+}
+
+// gitFileValue creates a { path, status } entry for gsh.git.status().files
+func gitFileValue(path, status string) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"path":   {Value: &StringValue{Value: path}},
+			"status": {Value: &StringValue{Value: status}},
+		},
+	}
+}
+
+// builtinGitLog implements gsh.git.log(n?)
+func (i *Interpreter) builtinGitLog(args []Value) (Value, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("gsh.git.log() takes 0 or 1 arguments (n?: number), got %d", len(args))
+	}
+	count := defaultGitLogCount
+	if len(args) == 1 {
+		numVal, ok := args[0].(*NumberValue)
+		if !ok || numVal.Value < 1 || numVal.Value != float64(int(numVal.Value)) {
+			return nil, fmt.Errorf("gsh.git.log() argument must be a positive integer, got %s", args[0].String())
+		}
+		count = int(numVal.Value)
+	}
+
+	if _, inRepo, err := i.runGit("rev-parse --git-dir"); err != nil {
+		return nil, fmt.Errorf("gsh.git.log() failed: %w", err)
+	} else if !inRepo {
+		return &NullValue{}, nil
+	}
+
+	// Fields are separated by \x1f and commits by \x1e, which can't appear in commit metadata
+	out, ok, err := i.runGit(fmt.Sprintf("log -n %d --format=%%H%%x1f%%an%%x1f%%ct%%x1f%%s%%x1e", count))
+	if err != nil {
+		return nil, fmt.Errorf("gsh.git.log() failed: %w", err)
+	}
+	if !ok {
+		// A repository with no commits yet
+		return &ArrayValue{Elements: []Value{}}, nil
+	}
+	return parseGitLog(out), nil
+}
+
+// parseGitLog converts the output of gsh.git.log()'s git log command into an array of
+// { hash, author, timestamp, subject }, with timestamp in milliseconds like DateTime.now()
+func parseGitLog(out string) Value {
+	commits := []Value{}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[2], 10, 64)
+		commits = append(commits, &ObjectValue{
+			Properties: map[string]*PropertyDescriptor{
+				"hash":      {Value: &StringValue{Value: fields[0]}},
+				"author":    {Value: &StringValue{Value: fields[1]}},
+				"timestamp": {Value: &NumberValue{Value: float64(seconds * 1000)}},
+				"subject":   {Value: &StringValue{Value: fields[3]}},
+			},
+		})
+	}
+	return &ArrayValue{Elements: commits}
+}
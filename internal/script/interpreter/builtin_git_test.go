@@ -0,0 +1,194 @@
+package interpreter
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initGitRepo creates a repository in a temp dir with one commit and returns its path
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_AUTHOR_DATE=2024-01-15T10:00:00Z", "GIT_COMMITTER_DATE=2024-01-15T10:00:00Z")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "tracked.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "tracked.txt")
+	git("commit", "-q", "-m", "Initial commit")
+	return dir
+}
+
+func TestGshGit(t *testing.T) {
+	dir := initGitRepo(t)
+	os.WriteFile(filepath.Join(dir, "tracked.txt"), []byte("two\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "new file.txt"), []byte("new\n"), 0644)
+
+	interp := New(nil)
+	defer interp.Close()
+	interp.runner.Dir = dir
+
+	result, err := interp.EvalString(`
+status = gsh.git.status()
+commits = gsh.git.log(5)
+files = []
+for (f of status.files) {
+	files.push(f.status + " " + f.path)
+}
+[gsh.git.root(), status.branch, status.dirty, status.ahead, status.behind, files.join(","), commits.length, commits[0].subject, commits[0].author, commits[0].timestamp]
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root, _ := filepath.EvalSymlinks(dir)
+	want := []string{root, "main", "true", "0", "0", ".M tracked.txt,?? new file.txt", "1", "Initial commit", "Ada", "1705312800000"}
+	got := result.FinalResult.(*ArrayValue).Elements
+	for idx, w := range want {
+		if got[idx].String() != w {
+			t.Errorf("value %d: expected %q, got %q", idx, w, got[idx].String())
+		}
+	}
+}
+
+func TestGshGitOutsideRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	interp := New(nil)
+	defer interp.Close()
+	interp.runner.Dir = t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(interp.runner.Dir))
+
+	for _, code := range []string{`gsh.git.status()`, `gsh.git.root()`, `gsh.git.log()`} {
+		result, err := interp.EvalString(code, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", code, err)
+		}
+		if _, ok := result.FinalResult.(*NullValue); !ok {
+			t.Errorf("%s: expected null outside a repository, got %s", code, result.FinalResult.String())
+		}
+	}
+}
+
+func TestParseGitStatus(t *testing.T) {
+	out := strings.Join([]string{
+		"# branch.oid 1234",
+		"# branch.head (detached)",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -3",
+		"2 R. N... 100644 100644 100644 abc abc R100 new name.go",
+		"old name.go",
+		"u UU N... 100644 100644 100644 100644 a b c conflict.go",
+		"",
+	}, "\x00")
+
+	status := parseGitStatus(out).(*ObjectValue)
+	if _, ok := status.GetPropertyValue("branch").(*NullValue); !ok {
+		t.Errorf("expected null branch when detached, got %s", status.GetPropertyValue("branch").String())
+	}
+	if status.GetPropertyValue("ahead").String() != "2" || status.GetPropertyValue("behind").String() != "3" {
+		t.Errorf("expected ahead 2 behind 3, got %s %s", status.GetPropertyValue("ahead").String(), status.GetPropertyValue("behind").String())
+	}
+	files := status.GetPropertyValue("files").(*ArrayValue).Elements
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	for idx, want := range [][2]string{{"new name.go", "R."}, {"conflict.go", "UU"}} {
+		file := files[idx].(*ObjectValue)
+		if file.GetPropertyValue("path").String() != want[0] || file.GetPropertyValue("status").String() != want[1] {
+			t.Errorf("file %d: expected %v, got %s %s", idx, want, file.GetPropertyValue("path").String(), file.GetPropertyValue("status").String())
+		}
+	}
+}
+
+func TestGshGitStatusAsyncPrompt(t *testing.T) {
+	dir := initGitRepo(t)
+
+	interp := New(nil)
+	defer interp.Close()
+	interp.runner.Dir = dir
+	interp.SDKConfig().SetREPLContext(&REPLContext{})
+
+	refreshed := make(chan struct{}, 1)
+	interp.SetPromptRefreshHandler(func() { refreshed <- struct{}{} })
+
+	_, err := interp.EvalString(`
+gsh.promptGitAsync = true
+shown = null
+tool onPrompt(ctx, next) {
+	status = gsh.git.status()
+	if (status.pending) {
+		shown = "pending"
+	} else {
+		shown = status.branch
+	}
+	return next(ctx)
+}
+gsh.use("repl.prompt", onPrompt)
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shown := func() string {
+		value, _ := interp.globalEnv.Get("shown")
+		return value.String()
+	}
+
+	// Outside the prompt, gsh.git.status() still runs git directly
+	result, err := interp.EvalString(`gsh.git.status().branch`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FinalResult.String() != "main" {
+		t.Errorf("expected a synchronous status outside the prompt, got %s", result.FinalResult.String())
+	}
+
+	interp.EmitEvent(EventReplPrompt, CreateReplPromptContext(0, 0))
+	if shown() != "pending" {
+		t.Fatalf("expected a pending placeholder on the first render, got %s", shown())
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the background git status")
+	}
+	interp.EmitEvent(EventReplPrompt, CreateReplPromptContext(0, 0))
+	if shown() != "main" {
+		t.Errorf("expected the branch after the refresh, got %s", shown())
+	}
+
+	// Later renders show the cached status while refreshing, and only ask for another
+	// render when the status changed
+	interp.EmitEvent(EventReplPrompt, CreateReplPromptContext(0, 0))
+	if shown() != "main" {
+		t.Errorf("expected the cached branch, got %s", shown())
+	}
+	select {
+	case <-refreshed:
+		t.Error("expected no refresh when the status is unchanged")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if _, err := interp.EvalString(`gsh.promptGitAsync = "yes"`, nil); err == nil || !strings.Contains(err.Error(), "must be a boolean") {
+		t.Errorf("expected a boolean error, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// maxPipedImageSize caps how large an image can be attached to a message
+const maxPipedImageSize = 5 * 1024 * 1024
+
+// supportedImageTypes are the media types that can be attached to messages
+var supportedImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ImageValue is a reference to an image file on disk, created by image(path).
+// Piping it into an agent or a conversation attaches the image to a user message.
+type ImageValue struct {
+	// Path is the absolute path of the image
+	Path string
+	// MediaType is the detected media type, e.g. "image/png"
+	MediaType string
+}
+
+func (img *ImageValue) Type() ValueType { return ValueTypeImage }
+func (img *ImageValue) String() string  { return fmt.Sprintf("<image %s>", img.Path) }
+func (img *ImageValue) IsTruthy() bool  { return true }
+func (img *ImageValue) Equals(other Value) bool {
+	if otherImage, ok := other.(*ImageValue); ok {
+		return img.Path == otherImage.Path
+	}
+	return false
+}
+
+// GetProperty returns a property of the image reference
+func (img *ImageValue) GetProperty(name string) Value {
+	switch name {
+	case "path":
+		return &StringValue{Value: img.Path}
+	case "name":
+		return &StringValue{Value: filepath.Base(img.Path)}
+	case "mediaType":
+		return &StringValue{Value: img.MediaType}
+	default:
+		return &NullValue{}
+	}
+}
+
+// builtinImage implements image(path), which returns a reference to a PNG, JPEG, GIF
+// or WebP file. Relative paths are resolved against the shell's working directory.
+func (i *Interpreter) builtinImage(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("image() takes exactly 1 argument, got %d", len(args))
+	}
+	pathVal, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("image() argument must be a string, got %s", args[0].Type())
+	}
+
+	path := pathVal.Value
+	if !filepath.IsAbs(path) {
+		if dir := i.GetWorkingDir(); dir != "" {
+			path = filepath.Join(dir, path)
+		}
+	}
+	path, err := resolveFilePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("image(): %w", err)
+	}
+
+	data, err := readImageData(path)
+	if err != nil {
+		return nil, fmt.Errorf("image(): %w", err)
+	}
+	mediaType := http.DetectContentType(data)
+	if !supportedImageTypes[mediaType] {
+		return nil, fmt.Errorf("image(): %s is not a PNG, JPEG, GIF or WebP image", pathVal.Value)
+	}
+	return &ImageValue{Path: path, MediaType: mediaType}, nil
+}
+
+// readImageForPipe reads an image to be attached to a message
+func readImageForPipe(img *ImageValue) (ChatImage, error) {
+	data, err := readImageData(img.Path)
+	if err != nil {
+		return ChatImage{}, fmt.Errorf("cannot pipe image: %w", err)
+	}
+	return ChatImage{MediaType: img.MediaType, Data: data}, nil
+}
+
+// readImageData reads an image file, refusing directories and files over maxPipedImageSize
+func readImageData(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > maxPipedImageSize {
+		return nil, fmt.Errorf("%s is %d bytes, which exceeds the %d byte image limit", path, info.Size(), maxPipedImageSize)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,72 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG file for media type detection
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestImageBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shot.png")
+	if err := os.WriteFile(path, pngHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := evalWithMock(t, fmt.Sprintf("img = image(%q)\ntypeof(img) + \":\" + img.name + \":\" + img.mediaType", path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String(); got != "image:shot.png:image/png" {
+		t.Errorf("unexpected result: %s", got)
+	}
+
+	text := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(text, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = evalWithMock(t, fmt.Sprintf("image(%q)", text))
+	if err == nil || !strings.Contains(err.Error(), "is not a PNG, JPEG, GIF or WebP image") {
+		t.Errorf("expected unsupported image error, got %v", err)
+	}
+
+	_, err = evalWithMock(t, fmt.Sprintf("image(%q)", dir))
+	if err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("expected directory error, got %v", err)
+	}
+}
+
+func TestPipeImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, pngHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := evalWithMock(t, fileTestAgent+fmt.Sprintf("image(%q) | TestAgent", path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conv, ok := result.(*ConversationValue)
+	if !ok {
+		t.Fatalf("expected ConversationValue, got %T", result)
+	}
+	first := conv.Messages[0]
+	if first.Role != "user" || len(first.Images) != 1 || first.Images[0].MediaType != "image/png" || string(first.Images[0].Data) != string(pngHeader) {
+		t.Errorf("expected the image attached to the first user message, got %+v", first)
+	}
+
+	result, err = evalWithMock(t, fileTestAgent+fmt.Sprintf("conv = \"Describe this\" | TestAgent\nconv | image(%q)", path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conv = result.(*ConversationValue)
+	last := conv.Messages[len(conv.Messages)-1]
+	if last.Role != "user" || len(last.Images) != 1 || last.Content != "" {
+		t.Errorf("expected a user message carrying the image, got %+v", last)
+	}
+}
@@ -0,0 +1,42 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kunchenguid/gsh/internal/keyring"
+	"go.uber.org/zap"
+)
+
+// KeyringValue represents the keyring object for reading secrets from the OS keyring,
+// e.g. apiKey: keyring.OPENAI_API_KEY. Secrets are stored with "gsh keyring set".
+type KeyringValue struct {
+	interp *Interpreter
+}
+
+func (k *KeyringValue) Type() ValueType { return ValueTypeObject }
+func (k *KeyringValue) String() string  { return "<keyring>" }
+func (k *KeyringValue) IsTruthy() bool  { return true }
+func (k *KeyringValue) Equals(other Value) bool {
+	_, ok := other.(*KeyringValue)
+	return ok
+}
+
+// GetProperty looks up a secret in the OS keyring. Missing secrets, and keyrings
+// that are unavailable, read as null so configs degrade like an unset env var.
+func (k *KeyringValue) GetProperty(name string) Value {
+	value, err := keyring.Get(name)
+	if err != nil {
+		if !errors.Is(err, keyring.ErrNotFound) && k.interp.logger != nil {
+			k.interp.logger.Warn("failed to read secret from keyring", zap.String("name", name), zap.Error(err))
+		}
+		return &NullValue{}
+	}
+	return &StringValue{Value: value}
+}
+
+// SetProperty rejects writes: secrets are stored with "gsh keyring set" so they never
+// pass through scripts
+func (k *KeyringValue) SetProperty(name string, value Value) error {
+	return fmt.Errorf("keyring is read-only, use 'gsh keyring set %s' to store a secret", name)
+}
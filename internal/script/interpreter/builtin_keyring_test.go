@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/kunchenguid/gsh/internal/keyring"
+)
+
+func TestKeyringModelAPIKey(t *testing.T) {
+	keyring.MockInit()
+	if err := keyring.Set("TEST_API_KEY", "sk-from-keyring"); err != nil {
+		t.Fatal(err)
+	}
+
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err := interp.EvalString(`
+model testModel {
+	provider: "openai",
+	apiKey: keyring.TEST_API_KEY,
+	model: "gpt-4",
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	modelVal, ok := interp.globalEnv.Get("testModel")
+	if !ok {
+		t.Fatal("model not defined")
+	}
+	apiKey, ok := modelVal.(*ModelValue).Config["apiKey"].(*StringValue)
+	if !ok || apiKey.Value != "sk-from-keyring" {
+		t.Errorf("expected apiKey from keyring, got %v", modelVal.(*ModelValue).Config["apiKey"])
+	}
+}
+
+func TestKeyringMissingSecret(t *testing.T) {
+	keyring.MockInit()
+
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(`keyring.MISSING_KEY ?? "fallback"`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FinalResult.String() != "fallback" {
+		t.Errorf("expected missing secret to read as null, got %v", result.FinalResult)
+	}
+
+	if _, err := interp.EvalString(`keyring.SOME_KEY = "value"`, nil); err == nil {
+		t.Error("expected error when assigning to keyring")
+	}
+}
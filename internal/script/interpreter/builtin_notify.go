@@ -0,0 +1,38 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kunchenguid/gsh/internal/notify"
+	"go.uber.org/zap"
+)
+
+// builtinGshNotify implements gsh.notify(title, message).
+// Returns true if a desktop notification was shown. Having no notifier, or one that
+// fails (e.g. no notification daemon), returns false rather than failing the script.
+func (i *Interpreter) builtinGshNotify(args []Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("gsh.notify() takes 2 arguments (title: string, message: string), got %d", len(args))
+	}
+	title, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.notify() title must be a string, got %s", args[0].Type())
+	}
+	message, ok := args[1].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.notify() message must be a string, got %s", args[1].Type())
+	}
+
+	if err := notify.Send(title.Value, message.Value); err != nil {
+		if i.logger != nil {
+			if errors.Is(err, notify.ErrUnsupported) {
+				i.logger.Debug("desktop notifications unavailable", zap.Error(err))
+			} else {
+				i.logger.Warn("failed to show desktop notification", zap.Error(err))
+			}
+		}
+		return &BoolValue{Value: false}, nil
+	}
+	return &BoolValue{Value: true}, nil
+}
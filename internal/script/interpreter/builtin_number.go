@@ -0,0 +1,116 @@
+package interpreter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// builtinParseInt implements parseInt(str, radix?), which parses the integer at the start
+// of a string, ignoring leading whitespace and anything after the digits. Like JavaScript,
+// a "0x" prefix selects base 16 when no radix is given. Unlike JavaScript, input with no
+// digits returns null rather than NaN, so a default can be supplied with ??.
+func builtinParseInt(args []Value) (Value, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("parseInt() takes 1 or 2 arguments (str: string, radix?: number), got %d", len(args))
+	}
+	str, err := parseArgString("parseInt", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	radix := 0
+	if len(args) == 2 {
+		radixVal, ok := args[1].(*NumberValue)
+		if !ok {
+			return nil, fmt.Errorf("parseInt() radix must be a number, got %s", args[1].Type())
+		}
+		radix = int(radixVal.Value)
+		if float64(radix) != radixVal.Value || radix < 2 || radix > 36 {
+			return nil, fmt.Errorf("parseInt() radix must be an integer between 2 and 36, got %s", radixVal.String())
+		}
+	}
+
+	s := strings.TrimLeftFunc(str, unicode.IsSpace)
+	sign := 1.0
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	if (radix == 0 || radix == 16) && (strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")) {
+		radix = 16
+		s = s[2:]
+	}
+	if radix == 0 {
+		radix = 10
+	}
+
+	// Accumulate in a float64 so long digit strings lose precision instead of overflowing
+	result := 0.0
+	digits := 0
+	for _, r := range s {
+		d := digitValue(r)
+		if d < 0 || d >= radix {
+			break
+		}
+		result = result*float64(radix) + float64(d)
+		digits++
+	}
+	if digits == 0 {
+		return &NullValue{}, nil
+	}
+	return &NumberValue{Value: sign * result}, nil
+}
+
+// leadingFloatPattern matches the decimal number at the start of a string
+var leadingFloatPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?`)
+
+// builtinParseFloat implements parseFloat(str), which parses the decimal number at the
+// start of a string, ignoring leading whitespace and anything after the number. Input with
+// no number returns null, like parseInt.
+func builtinParseFloat(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("parseFloat() takes exactly 1 argument (str: string), got %d", len(args))
+	}
+	str, err := parseArgString("parseFloat", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	match := leadingFloatPattern.FindString(strings.TrimLeftFunc(str, unicode.IsSpace))
+	if match == "" {
+		return &NullValue{}, nil
+	}
+	// The pattern only matches valid numbers; out-of-range values come back as ±Inf or 0
+	value, _ := strconv.ParseFloat(match, 64)
+	return &NumberValue{Value: value}, nil
+}
+
+// parseArgString returns the text parseInt/parseFloat should read from a value. Numbers
+// are accepted so parseInt(3.7) truncates, as in JavaScript.
+func parseArgString(name string, val Value) (string, error) {
+	switch v := val.(type) {
+	case *StringValue:
+		return v.Value, nil
+	case *NumberValue:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("%s() argument must be a string, got %s", name, val.Type())
+}
+
+// digitValue returns the value of r as a digit in bases up to 36, or -1
+func digitValue(r rune) int {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0')
+	case r >= 'a' && r <= 'z':
+		return int(r-'a') + 10
+	case r >= 'A' && r <= 'Z':
+		return int(r-'A') + 10
+	}
+	return -1
+}
@@ -0,0 +1,83 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIntAndParseFloat(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	tests := []struct {
+		code     string
+		expected interface{} // float64, or nil for null
+	}{
+		{`parseInt("42")`, 42.0},
+		{`parseInt("  -17 apples")`, -17.0},
+		{`parseInt("3.99")`, 3.0},
+		{`parseInt(3.7)`, 3.0},
+		{`parseInt("0x1F")`, 31.0},
+		{`parseInt("ff", 16)`, 255.0},
+		{`parseInt("0xff", 16)`, 255.0},
+		{`parseInt("101", 2)`, 5.0},
+		{`parseInt("z", 36)`, 35.0},
+		{`parseInt("129", 2)`, 1.0},
+		{`parseInt("abc")`, nil},
+		{`parseInt("")`, nil},
+		{`parseInt("-")`, nil},
+		{`parseFloat("3.14 seconds")`, 3.14},
+		{`parseFloat("  -1.5e3")`, -1500.0},
+		{`parseFloat(".5")`, 0.5},
+		{`parseFloat("7.")`, 7.0},
+		{`parseFloat("1e")`, 1.0},
+		{`parseFloat("n/a")`, nil},
+		{`parseInt("n/a") ?? 0`, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			result, err := interp.EvalString(tt.code, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expected == nil {
+				if _, ok := result.FinalResult.(*NullValue); !ok {
+					t.Errorf("expected null, got %s", result.FinalResult.String())
+				}
+				return
+			}
+			num, ok := result.FinalResult.(*NumberValue)
+			if !ok || num.Value != tt.expected.(float64) {
+				t.Errorf("expected %v, got %s", tt.expected, result.FinalResult.String())
+			}
+		})
+	}
+}
+
+func TestParseIntAndParseFloatErrors(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	tests := []struct {
+		code    string
+		wantErr string
+	}{
+		{`parseInt()`, "takes 1 or 2 arguments"},
+		{`parseInt(null)`, "argument must be a string"},
+		{`parseInt("10", "2")`, "radix must be a number"},
+		{`parseInt("10", 1)`, "radix must be an integer between 2 and 36"},
+		{`parseInt("10", 2.5)`, "radix must be an integer between 2 and 36"},
+		{`parseFloat("1", "2")`, "takes exactly 1 argument"},
+		{`parseFloat([1])`, "argument must be a string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			_, err := interp.EvalString(tt.code, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
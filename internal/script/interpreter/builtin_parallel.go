@@ -0,0 +1,116 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// builtinGshParallel implements gsh.parallel(tools, options?).
+// It calls each tool concurrently with no arguments and returns their results in order.
+// By default the first call to fail cancels the others and its error is thrown once every
+// call has returned. With options.allErrors, every call runs to completion and one error
+// listing all failures is thrown.
+//
+// Each call runs in an isolated scope: it can read outer variables, but assigning to one
+// creates a local copy, so concurrent calls never write to a shared environment.
+func (i *Interpreter) builtinGshParallel(args []Value) (Value, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("gsh.parallel() takes 1 or 2 arguments (tools: array, options?: object), got %d", len(args))
+	}
+	toolsArr, ok := args[0].(*ArrayValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.parallel() first argument must be an array of tools, got %s", args[0].Type())
+	}
+	tools := make([]*ToolValue, len(toolsArr.Elements))
+	for idx, elem := range toolsArr.Elements {
+		tool, ok := elem.(*ToolValue)
+		if !ok {
+			return nil, fmt.Errorf("gsh.parallel() element %d must be a tool, got %s", idx, elem.Type())
+		}
+		tools[idx] = tool
+	}
+
+	allErrors := false
+	if len(args) == 2 {
+		opts, ok := args[1].(*ObjectValue)
+		if !ok {
+			return nil, fmt.Errorf("gsh.parallel() second argument must be an object, got %s", args[1].Type())
+		}
+		if val := opts.GetPropertyValue("allErrors"); val.Type() != ValueTypeNull {
+			b, ok := val.(*BoolValue)
+			if !ok {
+				return nil, fmt.Errorf("gsh.parallel() options.allErrors must be a boolean, got %s", val.Type())
+			}
+			allErrors = b.Value
+		}
+	}
+
+	ctx, cancel := context.WithCancel(i.Context())
+	defer cancel()
+
+	results := make([]Value, len(tools))
+	errs := make([]error, len(tools))
+	var firstErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for idx, tool := range tools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Cancellation and call stacks are tracked per goroutine
+			i.SetContext(ctx)
+			defer i.ClearContext()
+
+			isolated := *tool
+			isolated.Env = NewIsolatedEnvironment(tool.Env)
+			callArgs := make([]Value, len(tool.Parameters))
+			for n := range callArgs {
+				callArgs[n] = &NullValue{}
+			}
+
+			result, err := i.CallTool(i.globalEnv, &isolated, callArgs)
+			if err != nil {
+				errs[idx] = err
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					if !allErrors {
+						cancel()
+					}
+				}
+				mu.Unlock()
+				return
+			}
+			results[idx] = result
+		}()
+	}
+	wg.Wait()
+
+	if !allErrors {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		return &ArrayValue{Elements: results}, nil
+	}
+
+	var failures []string
+	for idx, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("  [%d] %s: %s", idx, tools[idx].Name, parallelErrorMessage(err)))
+		}
+	}
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("gsh.parallel() %d of %d calls failed:\n%s", len(failures), len(tools), strings.Join(failures, "\n"))
+	}
+	return &ArrayValue{Elements: results}, nil
+}
+
+// parallelErrorMessage returns an error's message without its stack trace
+func parallelErrorMessage(err error) string {
+	if rte, ok := err.(*RuntimeError); ok {
+		return rte.Message
+	}
+	return err.Error()
+}
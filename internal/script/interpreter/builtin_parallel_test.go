@@ -0,0 +1,165 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGshParallel(t *testing.T) {
+	t.Run("returns results in order", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		result, err := interp.EvalString(`
+tool one() { return 1 }
+tool two() { return "two" }
+tool three(unused) { return unused }
+gsh.parallel([one, two, three])
+`, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.FinalResult.String(); got != `[1, "two", null]` {
+			t.Errorf("expected [1, \"two\", null], got %s", got)
+		}
+	})
+
+	t.Run("runs calls concurrently", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		// Each call waits until all three have started, which only happens if they overlap
+		var arrived sync.WaitGroup
+		arrived.Add(3)
+		interp.globalEnv.Set("arrive", &BuiltinValue{
+			Name: "arrive",
+			Fn: func(args []Value) (Value, error) {
+				arrived.Done()
+				done := make(chan struct{})
+				go func() { arrived.Wait(); close(done) }()
+				select {
+				case <-done:
+					return &BoolValue{Value: true}, nil
+				case <-time.After(5 * time.Second):
+					return nil, fmt.Errorf("calls did not run concurrently")
+				}
+			},
+		})
+
+		result, err := interp.EvalString(`
+tool work() { return arrive() }
+gsh.parallel([work, work, work])
+`, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.FinalResult.String(); got != "[true, true, true]" {
+			t.Errorf("expected [true, true, true], got %s", got)
+		}
+	})
+
+	t.Run("assignments stay local to each call", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		result, err := interp.EvalString(`
+counter = 10
+tool bump() {
+    counter = counter + 1
+    return counter
+}
+results = gsh.parallel([bump, bump])
+summary = [results[0], results[1], counter]
+summary
+`, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.FinalResult.String(); got != "[11, 11, 10]" {
+			t.Errorf("expected [11, 11, 10], got %s", got)
+		}
+	})
+
+	t.Run("throws the first error", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		result, err := interp.EvalString(`
+tool ok() { return 1 }
+tool bad() { throw { message: "bad failed", code: 7 } }
+caught = null
+try {
+    gsh.parallel([ok, bad])
+} catch (e) {
+    caught = e.code
+}
+caught
+`, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.FinalResult.String(); got != "7" {
+			t.Errorf("expected the thrown value to be caught, got %s", got)
+		}
+	})
+
+	t.Run("allErrors reports every failure", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		_, err := interp.EvalString(`
+tool ok() { return 1 }
+tool first() { throw "first failed" }
+tool second() { throw "second failed" }
+gsh.parallel([first, ok, second], { allErrors: true })
+`, nil)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		for _, want := range []string{"2 of 3 calls failed", "[0] first: first failed", "[2] second: second failed"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got: %v", want, err)
+			}
+		}
+	})
+
+	t.Run("validates arguments", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		tests := []struct {
+			code    string
+			wantErr string
+		}{
+			{`gsh.parallel()`, "takes 1 or 2 arguments"},
+			{`gsh.parallel("x")`, "must be an array of tools"},
+			{`gsh.parallel([1])`, "element 0 must be a tool"},
+			{`tool t() { return 1 }
+gsh.parallel([t], "x")`, "second argument must be an object"},
+			{`tool t() { return 1 }
+gsh.parallel([t], { allErrors: "yes" })`, "options.allErrors must be a boolean"},
+		}
+		for _, tt := range tests {
+			_, err := interp.EvalString(tt.code, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.code, tt.wantErr, err)
+			}
+		}
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		interp := New(&Options{})
+		defer interp.Close()
+
+		result, err := interp.EvalString(`gsh.parallel([])`, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.FinalResult.String(); got != "[]" {
+			t.Errorf("expected [], got %s", got)
+		}
+	})
+}
@@ -3,10 +3,13 @@ package interpreter
 import (
 	"bufio"
 	"fmt"
-	"go.uber.org/zap/zapcore"
 	"io"
 	"os"
+	"sort"
 	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // builtinPrint implements the print() function
@@ -55,8 +58,19 @@ func (i *Interpreter) builtinInput(args []Value) (Value, error) {
 
 // makeLogFunc creates a log function that uses the zap logger if available,
 // otherwise falls back to stderr output with the given prefix.
+// Arguments are joined with spaces into the message. When there is more than one
+// argument and the last is an object, its properties become structured fields instead,
+// e.g. log.info("deployed", { service: "api", ms: 120 }).
 func (i *Interpreter) makeLogFunc(level zapcore.Level, prefix string) BuiltinFunction {
 	return func(args []Value) (Value, error) {
+		var fields *ObjectValue
+		if len(args) > 1 {
+			if obj, ok := args[len(args)-1].(*ObjectValue); ok {
+				fields = obj
+				args = args[:len(args)-1]
+			}
+		}
+
 		// Build the message from all arguments
 		var parts []string
 		for _, arg := range args {
@@ -64,20 +78,35 @@ func (i *Interpreter) makeLogFunc(level zapcore.Level, prefix string) BuiltinFun
 		}
 		message := strings.Join(parts, " ")
 
+		var keys []string
+		if fields != nil {
+			for key := range fields.Properties {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+		}
+
 		// Use zap logger if available, otherwise fall back to stderr
 		if i.logger != nil {
+			zapFields := make([]zap.Field, 0, len(keys))
+			for _, key := range keys {
+				zapFields = append(zapFields, zap.Any(key, ValueToInterface(fields.GetPropertyValue(key))))
+			}
 			switch level {
 			case zapcore.DebugLevel:
-				i.logger.Debug(message)
+				i.logger.Debug(message, zapFields...)
 			case zapcore.InfoLevel:
-				i.logger.Info(message)
+				i.logger.Info(message, zapFields...)
 			case zapcore.WarnLevel:
-				i.logger.Warn(message)
+				i.logger.Warn(message, zapFields...)
 			case zapcore.ErrorLevel:
-				i.logger.Error(message)
+				i.logger.Error(message, zapFields...)
 			}
 		} else {
-			// Fallback: output to stderr with prefix
+			// Fallback: output to stderr with prefix, and fields as key=value pairs
+			for _, key := range keys {
+				message += fmt.Sprintf(" %s=%s", key, fields.GetPropertyValue(key).String())
+			}
 			fmt.Fprintf(os.Stderr, "[%s] %s\n", prefix, message)
 		}
 
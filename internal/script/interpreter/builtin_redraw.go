@@ -0,0 +1,25 @@
+package interpreter
+
+import "fmt"
+
+// builtinGshRedraw implements gsh.redraw(), which asks the REPL to render the prompt again
+// so a change made outside of a keypress shows up right away. The prompt is rendered on
+// another goroutine, so this never blocks. Calls made while the prompt is being rendered,
+// e.g. from a repl.prompt handler, are ignored since that render is already under way.
+// Outside the REPL this does nothing.
+func (i *Interpreter) builtinGshRedraw(args []Value) (Value, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("gsh.redraw() takes no arguments, got %d", len(args))
+	}
+	if i.promptGit.rendering.Load() > 0 {
+		return &NullValue{}, nil
+	}
+
+	i.promptGit.mu.Lock()
+	onRefresh := i.promptGit.onRefresh
+	i.promptGit.mu.Unlock()
+	if onRefresh != nil {
+		go onRefresh()
+	}
+	return &NullValue{}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"regexp"
 )
 
 // registerGshSDK registers the gsh SDK object with all its properties
@@ -51,6 +52,204 @@ func (i *Interpreter) registerGshSDK() {
 		},
 	}
 
+	// Create gsh.promptExitCodeColor (dynamic, reads from REPL context)
+	promptExitCodeColorObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.PromptExitCodeColor}
+		},
+	}
+
+	// Create gsh.promptGitAsync (dynamic, reads from REPL context)
+	promptGitAsyncObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.PromptGitAsync}
+		},
+	}
+
+	// Create gsh.showWelcome (dynamic, reads from REPL context)
+	showWelcomeObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.ShowWelcome}
+		},
+	}
+
+	// Create gsh.notifyOnAgentComplete (dynamic, reads from REPL context)
+	notifyOnAgentCompleteObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.NotifyOnAgentComplete}
+		},
+	}
+
+	// Create gsh.editDiffColor (dynamic, reads from REPL context)
+	editDiffColorObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.EditDiffColor}
+		},
+	}
+
+	// Create gsh.defaultAgentPromptSuffix (dynamic, reads from REPL context)
+	defaultAgentPromptSuffixObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &StringValue{Value: ""}
+			}
+			return &StringValue{Value: replCtx.DefaultAgentPromptSuffix}
+		},
+	}
+
+	// Create gsh.renderMarkdown (dynamic, reads from REPL context)
+	renderMarkdownObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.RenderMarkdown}
+		},
+	}
+
+	// Create gsh.showThinking (dynamic, reads from REPL context)
+	showThinkingObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.ShowThinking}
+		},
+	}
+
+	// Create gsh.agentResponseWidth (dynamic, reads from REPL context)
+	agentResponseWidthObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &NumberValue{Value: 0}
+			}
+			return &NumberValue{Value: float64(replCtx.AgentResponseWidth)}
+		},
+	}
+
+	// Create gsh.agentChunkInterval (dynamic, reads from REPL context)
+	agentChunkIntervalObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &NumberValue{Value: 0}
+			}
+			return &NumberValue{Value: float64(replCtx.AgentChunkInterval)}
+		},
+	}
+
+	// Create gsh.historyMaxEntries (dynamic, reads from REPL context)
+	historyMaxEntriesObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &NumberValue{Value: 0}
+			}
+			return &NumberValue{Value: float64(replCtx.HistoryMaxEntries)}
+		},
+	}
+
+	// Create gsh.historyIgnoreSpace (dynamic, reads from REPL context)
+	historyIgnoreSpaceObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.HistoryIgnoreSpace}
+		},
+	}
+
+	// Create gsh.historyIgnoreDups (dynamic, reads from REPL context)
+	historyIgnoreDupsObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.HistoryIgnoreDups}
+		},
+	}
+
+	// Create gsh.historyIgnore (dynamic, reads from REPL context)
+	historyIgnoreObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &ArrayValue{Elements: []Value{}}
+			}
+			return patternArray(replCtx.HistoryIgnore)
+		},
+	}
+
+	// Create gsh.confirmCommands (dynamic, reads from REPL context)
+	confirmCommandsObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &ArrayValue{Elements: []Value{}}
+			}
+			return patternArray(replCtx.ConfirmCommands)
+		},
+	}
+
+	// Create gsh.completeHiddenFiles (dynamic, reads from REPL context)
+	completeHiddenFilesObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.CompleteHiddenFiles}
+		},
+	}
+
+	// Create gsh.setupMissingKeys (dynamic, reads from REPL context)
+	setupMissingKeysObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil {
+				return &BoolValue{Value: false}
+			}
+			return &BoolValue{Value: replCtx.SetupMissingKeys}
+		},
+	}
+
+	// Create gsh.welcomeMessage (dynamic, reads from REPL context)
+	welcomeMessageObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil || replCtx.WelcomeMessage == nil {
+				return &NullValue{}
+			}
+			return replCtx.WelcomeMessage
+		},
+	}
+
 	// Create gsh.continuationPrompt (dynamic, reads from REPL context)
 	continuationPromptObj := &DynamicValue{
 		Get: func() Value {
@@ -62,12 +261,29 @@ func (i *Interpreter) registerGshSDK() {
 		},
 	}
 
+	// Create gsh.agentPrompt (dynamic, reads from REPL context)
+	agentPromptObj := &DynamicValue{
+		Get: func() Value {
+			replCtx := i.sdkConfig.GetREPLContext()
+			if replCtx == nil || replCtx.AgentPromptValue == nil {
+				return &StringValue{Value: ""}
+			}
+			return replCtx.AgentPromptValue
+		},
+	}
+
 	// Create gsh.tools object with native tool implementations
 	toolsObj := i.createNativeToolsObject()
 
 	// Create gsh.ui object for UI control (spinner, styles, cursor)
 	uiObj := i.createUIObject()
 
+	// Create gsh.time object
+	timeObj := i.createTimeObject()
+
+	// Create gsh.git object
+	gitObj := i.createGitObject()
+
 	// Create gsh.history object for command history access
 	historyObj := i.createHistoryObject()
 
@@ -156,18 +372,71 @@ func (i *Interpreter) registerGshSDK() {
 	gshObj := &GshObjectValue{
 		interp: i,
 		baseProps: map[string]*PropertyDescriptor{
-			"version":            {Value: &StringValue{Value: i.version}, ReadOnly: true},
-			"terminal":           {Value: terminalObj, ReadOnly: true},
-			"logging":            {Value: loggingObj},
-			"lastAgentRequest":   {Value: lastAgentRequestObj, ReadOnly: true},
-			"tools":              {Value: toolsObj, ReadOnly: true},
-			"ui":                 {Value: uiObj, ReadOnly: true},
-			"models":             {Value: modelsObj, ReadOnly: true},
-			"lastCommand":        {Value: lastCommandObj, ReadOnly: true},
-			"history":            {Value: historyObj, ReadOnly: true},
-			"currentDirectory":   {Value: currentDirectoryObj, ReadOnly: true},
-			"prompt":             {Value: promptObj},
-			"continuationPrompt": {Value: continuationPromptObj},
+			"version":                  {Value: &StringValue{Value: i.version}, ReadOnly: true},
+			"terminal":                 {Value: terminalObj, ReadOnly: true},
+			"logging":                  {Value: loggingObj},
+			"lastAgentRequest":         {Value: lastAgentRequestObj, ReadOnly: true},
+			"tools":                    {Value: toolsObj, ReadOnly: true},
+			"ui":                       {Value: uiObj, ReadOnly: true},
+			"time":                     {Value: timeObj, ReadOnly: true},
+			"git":                      {Value: gitObj, ReadOnly: true},
+			"models":                   {Value: modelsObj, ReadOnly: true},
+			"lastCommand":              {Value: lastCommandObj, ReadOnly: true},
+			"history":                  {Value: historyObj, ReadOnly: true},
+			"currentDirectory":         {Value: currentDirectoryObj, ReadOnly: true},
+			"prompt":                   {Value: promptObj},
+			"continuationPrompt":       {Value: continuationPromptObj},
+			"agentPrompt":              {Value: agentPromptObj},
+			"promptExitCodeColor":      {Value: promptExitCodeColorObj},
+			"promptGitAsync":           {Value: promptGitAsyncObj},
+			"showWelcome":              {Value: showWelcomeObj},
+			"welcomeMessage":           {Value: welcomeMessageObj},
+			"notifyOnAgentComplete":    {Value: notifyOnAgentCompleteObj},
+			"editDiffColor":            {Value: editDiffColorObj},
+			"renderMarkdown":           {Value: renderMarkdownObj},
+			"showThinking":             {Value: showThinkingObj},
+			"defaultAgentPromptSuffix": {Value: defaultAgentPromptSuffixObj},
+			"agentResponseWidth":       {Value: agentResponseWidthObj},
+			"agentChunkInterval":       {Value: agentChunkIntervalObj},
+			"historyMaxEntries":        {Value: historyMaxEntriesObj},
+			"historyIgnoreSpace":       {Value: historyIgnoreSpaceObj},
+			"historyIgnoreDups":        {Value: historyIgnoreDupsObj},
+			"historyIgnore":            {Value: historyIgnoreObj},
+			"confirmCommands":          {Value: confirmCommandsObj},
+			"completeHiddenFiles":      {Value: completeHiddenFilesObj},
+			"setupMissingKeys":         {Value: setupMissingKeysObj},
+			"exec": {Value: &BuiltinValue{
+				Name: "gsh.exec",
+				Fn:   i.builtinGshExec,
+			}, ReadOnly: true},
+			"execSequence": {Value: &BuiltinValue{
+				Name: "gsh.execSequence",
+				Fn:   i.builtinGshExecSequence,
+			}, ReadOnly: true},
+			"parallel": {Value: &BuiltinValue{
+				Name: "gsh.parallel",
+				Fn:   i.builtinGshParallel,
+			}, ReadOnly: true},
+			"notify": {Value: &BuiltinValue{
+				Name: "gsh.notify",
+				Fn:   i.builtinGshNotify,
+			}, ReadOnly: true},
+			"redraw": {Value: &BuiltinValue{
+				Name: "gsh.redraw",
+				Fn:   i.builtinGshRedraw,
+			}, ReadOnly: true},
+			"encodeBase64": {Value: &BuiltinValue{
+				Name: "gsh.encodeBase64",
+				Fn:   i.builtinGshEncodeBase64,
+			}, ReadOnly: true},
+			"decodeBase64": {Value: &BuiltinValue{
+				Name: "gsh.decodeBase64",
+				Fn:   i.builtinGshDecodeBase64,
+			}, ReadOnly: true},
+			"mention": {Value: &BuiltinValue{
+				Name: "gsh.mention",
+				Fn:   i.builtinGshMention,
+			}, ReadOnly: true},
 			"use": {Value: &BuiltinValue{
 				Name: "gsh.use",
 				Fn:   i.builtinGshUse,
@@ -526,6 +795,11 @@ func (l *LoggingObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (l *LoggingObjectValue) PropertyNames() []string {
+	return []string{"level", "file"}
+}
+
 func (l *LoggingObjectValue) GetProperty(name string) Value {
 	switch name {
 	case "level":
@@ -550,7 +824,7 @@ func (l *LoggingObjectValue) SetProperty(name string, value Value) error {
 		}
 		return l.interp.sdkConfig.SetLogLevel(str.Value)
 	case "file":
-		return fmt.Errorf("gsh.logging.file is read-only")
+		return fmt.Errorf("gsh.logging.file is read-only; set it at startup with --log-file or GSH_LOG_FILE")
 	default:
 		return fmt.Errorf("cannot set property '%s' on gsh.logging", name)
 	}
@@ -591,6 +865,12 @@ func (d *DynamicValue) Equals(other Value) bool {
 	}
 	return false
 }
+
+// PropertyNames implements PropertyLister by listing the properties of the current value
+func (d *DynamicValue) PropertyNames() []string {
+	return PropertyNames(d.GetDynamicValue())
+}
+
 func (d *DynamicValue) GetProperty(name string) Value {
 	if d.Get != nil {
 		innerVal := d.Get()
@@ -624,6 +904,11 @@ func (g *GshObjectValue) Equals(other Value) bool {
 	return ok && g.interp == otherGsh.interp
 }
 
+// PropertyNames implements PropertyLister
+func (g *GshObjectValue) PropertyNames() []string {
+	return sortedKeys(g.baseProps)
+}
+
 func (g *GshObjectValue) GetProperty(name string) Value {
 	if prop, ok := g.baseProps[name]; ok {
 		// If the property is a DynamicValue, get its current value
@@ -647,13 +932,15 @@ func (g *GshObjectValue) SetProperty(name string, value Value) error {
 	// Handle special cases
 	switch name {
 	case "prompt":
-		promptStr, ok := value.(*StringValue)
-		if !ok {
-			return fmt.Errorf("gsh.prompt must be a string, got %s", value.Type())
+		// A tool is called to build the prompt each time it renders
+		switch value.(type) {
+		case *StringValue, *ToolValue:
+		default:
+			return fmt.Errorf("gsh.prompt must be a string or a tool, got %s", value.Type())
 		}
 		replCtx := g.interp.sdkConfig.GetREPLContext()
 		if replCtx != nil {
-			replCtx.PromptValue = promptStr
+			replCtx.PromptValue = value
 		}
 		return nil
 	case "continuationPrompt":
@@ -666,6 +953,197 @@ func (g *GshObjectValue) SetProperty(name string, value Value) error {
 			replCtx.ContinuationPromptValue = cpStr
 		}
 		return nil
+	case "agentPrompt":
+		apStr, ok := value.(*StringValue)
+		if !ok {
+			return fmt.Errorf("gsh.agentPrompt must be a string, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.AgentPromptValue = apStr
+		}
+		return nil
+	case "promptExitCodeColor":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.promptExitCodeColor must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.PromptExitCodeColor = boolVal.Value
+		}
+		return nil
+	case "promptGitAsync":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.promptGitAsync must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.PromptGitAsync = boolVal.Value
+		}
+		return nil
+	case "showWelcome":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.showWelcome must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.ShowWelcome = boolVal.Value
+		}
+		return nil
+	case "notifyOnAgentComplete":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.notifyOnAgentComplete must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.NotifyOnAgentComplete = boolVal.Value
+		}
+		return nil
+	case "editDiffColor":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.editDiffColor must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.EditDiffColor = boolVal.Value
+		}
+		return nil
+	case "defaultAgentPromptSuffix":
+		strVal, ok := value.(*StringValue)
+		if !ok {
+			return fmt.Errorf("gsh.defaultAgentPromptSuffix must be a string, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.DefaultAgentPromptSuffix = strVal.Value
+		}
+		return nil
+	case "renderMarkdown":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.renderMarkdown must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.RenderMarkdown = boolVal.Value
+		}
+		return nil
+	case "showThinking":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.showThinking must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.ShowThinking = boolVal.Value
+		}
+		return nil
+	case "agentResponseWidth":
+		numVal, ok := value.(*NumberValue)
+		if !ok || numVal.Value < 0 || numVal.Value != float64(int(numVal.Value)) {
+			return fmt.Errorf("gsh.agentResponseWidth must be a non-negative integer, got %s", value.String())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.AgentResponseWidth = int(numVal.Value)
+		}
+		return nil
+	case "agentChunkInterval":
+		numVal, ok := value.(*NumberValue)
+		if !ok || numVal.Value < 0 || numVal.Value != float64(int(numVal.Value)) {
+			return fmt.Errorf("gsh.agentChunkInterval must be a non-negative integer (milliseconds), got %s", value.String())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.AgentChunkInterval = int(numVal.Value)
+		}
+		return nil
+	case "historyMaxEntries":
+		numVal, ok := value.(*NumberValue)
+		if !ok || numVal.Value < 0 || numVal.Value != float64(int(numVal.Value)) {
+			return fmt.Errorf("gsh.historyMaxEntries must be a non-negative integer, got %s", value.String())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.HistoryMaxEntries = int(numVal.Value)
+		}
+		return nil
+	case "historyIgnoreSpace":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.historyIgnoreSpace must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.HistoryIgnoreSpace = boolVal.Value
+		}
+		return nil
+	case "historyIgnoreDups":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.historyIgnoreDups must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.HistoryIgnoreDups = boolVal.Value
+		}
+		return nil
+	case "historyIgnore":
+		patterns, err := compilePatternArray("gsh.historyIgnore", value)
+		if err != nil {
+			return err
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.HistoryIgnore = patterns
+		}
+		return nil
+	case "confirmCommands":
+		patterns, err := compilePatternArray("gsh.confirmCommands", value)
+		if err != nil {
+			return err
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.ConfirmCommands = patterns
+		}
+		return nil
+	case "completeHiddenFiles":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.completeHiddenFiles must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.CompleteHiddenFiles = boolVal.Value
+		}
+		return nil
+	case "setupMissingKeys":
+		boolVal, ok := value.(*BoolValue)
+		if !ok {
+			return fmt.Errorf("gsh.setupMissingKeys must be a boolean, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.SetupMissingKeys = boolVal.Value
+		}
+		return nil
+	case "welcomeMessage":
+		switch value.(type) {
+		case *StringValue, *ToolValue, *NullValue:
+		default:
+			return fmt.Errorf("gsh.welcomeMessage must be a string, a tool or null, got %s", value.Type())
+		}
+		replCtx := g.interp.sdkConfig.GetREPLContext()
+		if replCtx != nil {
+			replCtx.WelcomeMessage = value
+		}
+		return nil
 	default:
 		// For other properties, delegate to the underlying value's SetProperty if it has one
 		if dv, ok := prop.Value.(*DynamicValue); ok {
@@ -705,6 +1183,25 @@ func (m *ModelsObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (m *ModelsObjectValue) PropertyNames() []string {
+	return []string{"lite", "workhorse", "premium"}
+}
+
+// TierRef returns the reference for a tier whether or not a model is assigned to it yet,
+// or nil if name isn't a tier. GetProperty returns null for an unassigned tier instead.
+func (m *ModelsObjectValue) TierRef(name string) *SDKModelRef {
+	switch name {
+	case "lite":
+		return m.liteRef
+	case "workhorse":
+		return m.workhorseRef
+	case "premium":
+		return m.premiumRef
+	}
+	return nil
+}
+
 func (m *ModelsObjectValue) GetProperty(name string) Value {
 	switch name {
 	case "lite":
@@ -772,6 +1269,11 @@ func (c *LastCommandObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (c *LastCommandObjectValue) PropertyNames() []string {
+	return []string{"command", "exitCode", "durationMs"}
+}
+
 func (c *LastCommandObjectValue) GetProperty(name string) Value {
 	if c.lastCommand == nil {
 		return &NullValue{}
@@ -824,7 +1326,7 @@ func (i *Interpreter) createHistoryObject() *ObjectValue {
 
 // builtinHistoryFindPrefix implements gsh.history.findPrefix(prefix, limit)
 // Returns an array of history entries that start with the given prefix, ordered by most recent first.
-// Each entry is an object with { command, exitCode, timestamp }.
+// Each entry is an object with { command, exitCode, durationMs, timestamp }.
 // Parameters:
 //   - prefix (string): The prefix to search for
 //   - limit (number, optional): Maximum number of history entries to return (default: 10)
@@ -865,26 +1367,14 @@ func (i *Interpreter) builtinHistoryFindPrefix(args []Value) (Value, error) {
 		return &ArrayValue{Elements: []Value{}}, nil // Return empty array on error
 	}
 
-	// Convert to array of objects
-	elements := make([]Value, len(entries))
-	for i, entry := range entries {
-		elements[i] = &ObjectValue{
-			Properties: map[string]*PropertyDescriptor{
-				"command":   {Value: &StringValue{Value: entry.Command}},
-				"exitCode":  {Value: &NumberValue{Value: float64(entry.ExitCode)}},
-				"timestamp": {Value: &NumberValue{Value: float64(entry.Timestamp)}},
-			},
-		}
-	}
-
-	return &ArrayValue{Elements: elements}, nil
+	return historyEntriesToArray(entries), nil
 }
 
 // builtinHistoryGetRecent implements gsh.history.getRecent(limit)
 // Returns an array of the most recent history entries in chronological order
 // (oldest first, most recent last). This ordering is ideal for providing context
 // to LLMs as it shows the natural flow of commands.
-// Each entry is an object with { command, exitCode, timestamp }.
+// Each entry is an object with { command, exitCode, durationMs, timestamp }.
 // Parameters:
 //   - limit (number, optional): Maximum number of history entries to return (default: 10)
 func (i *Interpreter) builtinHistoryGetRecent(args []Value) (Value, error) {
@@ -918,17 +1408,52 @@ func (i *Interpreter) builtinHistoryGetRecent(args []Value) (Value, error) {
 		return &ArrayValue{Elements: []Value{}}, nil // Return empty array on error
 	}
 
-	// Convert to array of objects
+	return historyEntriesToArray(entries), nil
+}
+
+// historyEntriesToArray converts history entries to an array of
+// { command, exitCode, durationMs, timestamp } objects
+func historyEntriesToArray(entries []HistoryEntry) *ArrayValue {
 	elements := make([]Value, len(entries))
 	for i, entry := range entries {
 		elements[i] = &ObjectValue{
 			Properties: map[string]*PropertyDescriptor{
-				"command":   {Value: &StringValue{Value: entry.Command}},
-				"exitCode":  {Value: &NumberValue{Value: float64(entry.ExitCode)}},
-				"timestamp": {Value: &NumberValue{Value: float64(entry.Timestamp)}},
+				"command":    {Value: &StringValue{Value: entry.Command}},
+				"exitCode":   {Value: &NumberValue{Value: float64(entry.ExitCode)}},
+				"durationMs": {Value: &NumberValue{Value: float64(entry.DurationMs)}},
+				"timestamp":  {Value: &NumberValue{Value: float64(entry.Timestamp)}},
 			},
 		}
 	}
+	return &ArrayValue{Elements: elements}
+}
+
+// patternArray converts compiled patterns back to an array of their source strings
+func patternArray(patterns []*regexp.Regexp) *ArrayValue {
+	elements := make([]Value, len(patterns))
+	for idx, pattern := range patterns {
+		elements[idx] = &StringValue{Value: pattern.String()}
+	}
+	return &ArrayValue{Elements: elements}
+}
 
-	return &ArrayValue{Elements: elements}, nil
+// compilePatternArray compiles an array of regular expression strings assigned to setting
+func compilePatternArray(setting string, value Value) ([]*regexp.Regexp, error) {
+	arrVal, ok := value.(*ArrayValue)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of regular expressions, got %s", setting, value.Type())
+	}
+	patterns := make([]*regexp.Regexp, 0, len(arrVal.Elements))
+	for _, elem := range arrVal.Elements {
+		strVal, ok := elem.(*StringValue)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings, got an element of type %s", setting, elem.Type())
+		}
+		pattern, err := regexp.Compile(strVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s has an invalid pattern %q: %v", setting, strVal.Value, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
 }
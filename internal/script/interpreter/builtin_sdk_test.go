@@ -1,7 +1,12 @@
 package interpreter
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/kunchenguid/gsh/internal/notify"
 )
 
 // TestGshModelsAvailableInScriptMode tests that gsh.models is available even without REPL context
@@ -621,7 +626,7 @@ func TestGshHistoryGetRecent(t *testing.T) {
 
 		provider := &mockHistoryProvider{
 			entries: []HistoryEntry{
-				{Command: "failing-cmd", ExitCode: 127, Timestamp: 1234567890},
+				{Command: "failing-cmd", ExitCode: 127, Timestamp: 1234567890, DurationMs: 42},
 			},
 		}
 		interp.SDKConfig().SetHistoryProvider(provider)
@@ -652,6 +657,11 @@ func TestGshHistoryGetRecent(t *testing.T) {
 		if numVal, ok := tsVal.(*NumberValue); !ok || numVal.Value != 1234567890 {
 			t.Errorf("expected timestamp 1234567890, got %v", tsVal)
 		}
+		// Check durationMs
+		durVal := obj.GetPropertyValue("durationMs")
+		if numVal, ok := durVal.(*NumberValue); !ok || numVal.Value != 42 {
+			t.Errorf("expected durationMs 42, got %v", durVal)
+		}
 	})
 
 	t.Run("validates argument types", func(t *testing.T) {
@@ -727,3 +737,459 @@ func TestGshHistoryFindPrefix(t *testing.T) {
 		}
 	})
 }
+
+// TestGshWelcomeSettings tests gsh.showWelcome and gsh.welcomeMessage
+func TestGshWelcomeSettings(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{ShowWelcome: true}
+	interp.SDKConfig().SetREPLContext(replCtx)
+
+	result, err := interp.EvalString(`gsh.welcomeMessage`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.FinalResult.(*NullValue); !ok {
+		t.Errorf("expected null welcomeMessage by default, got %s", result.FinalResult.Type())
+	}
+
+	_, err = interp.EvalString(`
+gsh.showWelcome = false
+gsh.welcomeMessage = "hello"
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.ShowWelcome {
+		t.Error("expected showWelcome to be false")
+	}
+	if msg, ok := replCtx.WelcomeMessage.(*StringValue); !ok || msg.Value != "hello" {
+		t.Errorf("expected welcomeMessage 'hello', got %v", replCtx.WelcomeMessage)
+	}
+
+	if _, err := interp.EvalString(`gsh.welcomeMessage = 42`, nil); err == nil {
+		t.Error("expected error when setting welcomeMessage to a number")
+	}
+	if _, err := interp.EvalString(`gsh.showWelcome = "no"`, nil); err == nil {
+		t.Error("expected error when setting showWelcome to a string")
+	}
+}
+
+// TestGshNotify tests gsh.notify and gsh.notifyOnAgentComplete
+func TestGshNotify(t *testing.T) {
+	recorder := notify.MockInit()
+
+	interp := New(&Options{})
+	defer interp.Close()
+
+	result, err := interp.EvalString(`gsh.notify("Build", "All tests passed")`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || !b.Value {
+		t.Errorf("expected gsh.notify to return true, got %s", result.FinalResult.String())
+	}
+	if got := recorder.Notifications(); len(got) != 1 || got[0].Title != "Build" || got[0].Message != "All tests passed" {
+		t.Errorf("unexpected notifications: %v", got)
+	}
+
+	if _, err := interp.EvalString(`gsh.notify("only a title")`, nil); err == nil {
+		t.Error("expected error when calling gsh.notify with one argument")
+	}
+	if _, err := interp.EvalString(`gsh.notify("title", 42)`, nil); err == nil {
+		t.Error("expected error when message is not a string")
+	}
+
+	// Off by default and only settable to a boolean
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err = interp.EvalString(`gsh.notifyOnAgentComplete`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || b.Value {
+		t.Errorf("expected notifyOnAgentComplete to default to false, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.notifyOnAgentComplete = true`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replCtx.NotifyOnAgentComplete {
+		t.Error("expected notifyOnAgentComplete to be true")
+	}
+	if _, err := interp.EvalString(`gsh.notifyOnAgentComplete = "yes"`, nil); err == nil {
+		t.Error("expected error when setting notifyOnAgentComplete to a string")
+	}
+}
+
+// TestGshRedraw tests that gsh.redraw asks the REPL to render the prompt again
+func TestGshRedraw(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	// Without a REPL there is nothing to redraw
+	if _, err := interp.EvalString(`gsh.redraw()`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	redrawn := make(chan struct{}, 10)
+	interp.SetPromptRefreshHandler(func() { redrawn <- struct{}{} })
+
+	result, err := interp.EvalString(`gsh.redraw()`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FinalResult.Type() != ValueTypeNull {
+		t.Errorf("expected null, got %s", result.FinalResult.String())
+	}
+	select {
+	case <-redrawn:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected gsh.redraw to ask for a redraw")
+	}
+
+	// A repl.prompt handler that calls gsh.redraw must not cause another render
+	_, err = interp.EvalString(`
+tool onPrompt(ctx, next) {
+	gsh.redraw()
+	return next(ctx)
+}
+gsh.use("repl.prompt", onPrompt)
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interp.EmitEvent(EventReplPrompt, CreateReplPromptContext(0, 0))
+	select {
+	case <-redrawn:
+		t.Error("expected no redraw while the prompt is rendering")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := interp.EvalString(`gsh.redraw(1)`, nil); err == nil || !strings.Contains(err.Error(), "takes no arguments") {
+		t.Errorf("expected an argument error, got %v", err)
+	}
+}
+
+// TestGshEditDiffColor tests the gsh.editDiffColor setting
+func TestGshEditDiffColor(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{EditDiffColor: true}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	if _, err := interp.EvalString(`gsh.editDiffColor = false`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.EditDiffColor {
+		t.Error("expected editDiffColor to be false")
+	}
+	result, err := interp.EvalString(`gsh.editDiffColor`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || b.Value {
+		t.Errorf("expected editDiffColor to read back false, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.editDiffColor = "no"`, nil); err == nil {
+		t.Error("expected error when setting editDiffColor to a string")
+	}
+}
+
+// TestGshRenderMarkdown tests the gsh.renderMarkdown setting
+func TestGshRenderMarkdown(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString("gsh.renderMarkdown = true\ngsh.renderMarkdown", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replCtx.RenderMarkdown {
+		t.Error("expected renderMarkdown to be true")
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || !b.Value {
+		t.Errorf("expected renderMarkdown to read back true, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.renderMarkdown = "yes"`, nil); err == nil {
+		t.Error("expected error when setting renderMarkdown to a string")
+	}
+}
+
+// TestGshShowThinking tests the gsh.showThinking setting
+func TestGshShowThinking(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString("gsh.showThinking = true\ngsh.showThinking", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replCtx.ShowThinking {
+		t.Error("expected showThinking to be true")
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || !b.Value {
+		t.Errorf("expected showThinking to read back true, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.showThinking = 1`, nil); err == nil {
+		t.Error("expected error when setting showThinking to a number")
+	}
+}
+
+func TestGshCompleteHiddenFiles(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{CompleteHiddenFiles: true}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString("gsh.completeHiddenFiles = false\ngsh.completeHiddenFiles", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.CompleteHiddenFiles {
+		t.Error("expected completeHiddenFiles to be false")
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || b.Value {
+		t.Errorf("expected completeHiddenFiles to read back false, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.completeHiddenFiles = "no"`, nil); err == nil {
+		t.Error("expected error when setting completeHiddenFiles to a string")
+	}
+}
+
+func TestGshSetupMissingKeys(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{SetupMissingKeys: true}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString("gsh.setupMissingKeys = false\ngsh.setupMissingKeys", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.SetupMissingKeys {
+		t.Error("expected setupMissingKeys to be false")
+	}
+	if b, ok := result.FinalResult.(*BoolValue); !ok || b.Value {
+		t.Errorf("expected setupMissingKeys to read back false, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.setupMissingKeys = "no"`, nil); err == nil {
+		t.Error("expected error when setting setupMissingKeys to a string")
+	}
+}
+
+func TestGshPromptTool(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	_, err := interp.EvalString(`
+tool buildPrompt(ctx) { return "exit " + ctx.exitCode + "> " }
+tool noPrompt() { return 1 }
+gsh.prompt = buildPrompt
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tool, ok := replCtx.PromptValue.(*ToolValue)
+	if !ok {
+		t.Fatalf("expected gsh.prompt to hold the tool, got %v", replCtx.PromptValue)
+	}
+
+	prompt, err := interp.CallPromptTool(context.Background(), tool, CreateReplPromptContext(2, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prompt != "exit 2> " {
+		t.Errorf("expected %q, got %q", "exit 2> ", prompt)
+	}
+
+	noPrompt, _ := interp.globalEnv.Get("noPrompt")
+	if _, err := interp.CallPromptTool(context.Background(), noPrompt.(*ToolValue), CreateReplPromptContext(0, 0)); err == nil || !strings.Contains(err.Error(), "must return a string, got number") {
+		t.Errorf("expected an error for a non-string prompt, got %v", err)
+	}
+
+	if _, err := interp.EvalString(`gsh.prompt = 1`, nil); err == nil || !strings.Contains(err.Error(), "gsh.prompt must be a string or a tool, got number") {
+		t.Errorf("expected an error for a number, got %v", err)
+	}
+}
+
+func TestGshDefaultAgentPromptSuffix(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString(`gsh.defaultAgentPromptSuffix = "Answer in French."
+gsh.defaultAgentPromptSuffix`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.DefaultAgentPromptSuffix != "Answer in French." {
+		t.Errorf("expected suffix to be stored, got %q", replCtx.DefaultAgentPromptSuffix)
+	}
+	if s, ok := result.FinalResult.(*StringValue); !ok || s.Value != "Answer in French." {
+		t.Errorf("expected suffix to read back, got %s", result.FinalResult.String())
+	}
+	if _, err := interp.EvalString(`gsh.defaultAgentPromptSuffix = 1`, nil); err == nil {
+		t.Error("expected error when setting defaultAgentPromptSuffix to a number")
+	}
+}
+
+func TestGshAgentResponseWidth(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	result, err := interp.EvalString(`gsh.agentResponseWidth`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, ok := result.FinalResult.(*NumberValue); !ok || n.Value != 0 {
+		t.Errorf("expected 0 outside the REPL, got %s", result.FinalResult.String())
+	}
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	if _, err := interp.EvalString(`gsh.agentResponseWidth = 100`, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.AgentResponseWidth != 100 {
+		t.Errorf("expected agentResponseWidth 100, got %d", replCtx.AgentResponseWidth)
+	}
+	for _, code := range []string{`gsh.agentResponseWidth = -1`, `gsh.agentResponseWidth = 80.5`, `gsh.agentResponseWidth = "80"`} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), "non-negative integer") {
+			t.Errorf("expected error for %s, got %v", code, err)
+		}
+	}
+}
+
+func TestGshAgentChunkInterval(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	if got := interp.agentChunkInterval(); got != 0 {
+		t.Errorf("expected no chunk interval outside the REPL, got %v", got)
+	}
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString("gsh.agentChunkInterval = 40\ngsh.agentChunkInterval", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, ok := result.FinalResult.(*NumberValue); !ok || n.Value != 40 {
+		t.Errorf("expected agentChunkInterval to read back 40, got %s", result.FinalResult.String())
+	}
+	if got := interp.agentChunkInterval(); got != 40*time.Millisecond {
+		t.Errorf("expected a 40ms chunk interval, got %v", got)
+	}
+	for _, code := range []string{`gsh.agentChunkInterval = -1`, `gsh.agentChunkInterval = 2.5`, `gsh.agentChunkInterval = "40"`} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), "non-negative integer") {
+			t.Errorf("expected error for %s, got %v", code, err)
+		}
+	}
+}
+
+func TestGshHistorySettings(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString(`
+gsh.historyMaxEntries = 500
+gsh.historyIgnoreSpace = true
+gsh.historyIgnoreDups = true
+gsh.historyIgnore = ["^export .*TOKEN=", "password"]
+gsh.historyIgnore
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replCtx.HistoryMaxEntries != 500 || !replCtx.HistoryIgnoreSpace || !replCtx.HistoryIgnoreDups {
+		t.Errorf("expected max entries 500, ignoreSpace and ignoreDups, got %d, %v and %v", replCtx.HistoryMaxEntries, replCtx.HistoryIgnoreSpace, replCtx.HistoryIgnoreDups)
+	}
+	if len(replCtx.HistoryIgnore) != 2 || !replCtx.HistoryIgnore[0].MatchString("export GH_TOKEN=abc") {
+		t.Errorf("unexpected ignore patterns %v", replCtx.HistoryIgnore)
+	}
+	if arr, ok := result.FinalResult.(*ArrayValue); !ok || len(arr.Elements) != 2 || arr.Elements[1].String() != "password" {
+		t.Errorf("expected historyIgnore to read back the patterns, got %s", result.FinalResult.String())
+	}
+
+	for code, want := range map[string]string{
+		`gsh.historyMaxEntries = -1`: "non-negative integer",
+		`gsh.historyIgnoreSpace = 1`: "must be a boolean",
+		`gsh.historyIgnoreDups = 1`:  "must be a boolean",
+		`gsh.historyIgnore = "x"`:    "must be an array",
+		`gsh.historyIgnore = [1]`:    "array of strings",
+		`gsh.historyIgnore = ["(x"]`: "invalid pattern",
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q for %s, got %v", want, code, err)
+		}
+	}
+}
+
+func TestGshConfirmCommands(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	replCtx := &REPLContext{}
+	interp.SDKConfig().SetREPLContext(replCtx)
+	result, err := interp.EvalString(`
+gsh.confirmCommands = ["^terraform destroy", "\\bshred\\b"]
+gsh.confirmCommands
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(replCtx.ConfirmCommands) != 2 || !replCtx.ConfirmCommands[1].MatchString("shred -u key.pem") {
+		t.Errorf("unexpected confirm patterns %v", replCtx.ConfirmCommands)
+	}
+	if arr, ok := result.FinalResult.(*ArrayValue); !ok || len(arr.Elements) != 2 || arr.Elements[0].String() != "^terraform destroy" {
+		t.Errorf("expected confirmCommands to read back the patterns, got %s", result.FinalResult.String())
+	}
+
+	for code, want := range map[string]string{
+		`gsh.confirmCommands = "x"`:    "gsh.confirmCommands must be an array",
+		`gsh.confirmCommands = [1]`:    "array of strings",
+		`gsh.confirmCommands = ["(x"]`: "invalid pattern",
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q for %s, got %v", want, code, err)
+		}
+	}
+}
+
+func TestGshLoggingFile(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	result, err := interp.EvalString(`gsh.logging.file`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.FinalResult.(*NullValue); !ok {
+		t.Errorf("expected null log file without Options.LogFile, got %s", result.FinalResult.String())
+	}
+
+	interp = New(&Options{LogFile: "/tmp/session.log"})
+	defer interp.Close()
+
+	result, err = interp.EvalString(`gsh.logging.file`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FinalResult.String() != "/tmp/session.log" {
+		t.Errorf("expected /tmp/session.log, got %s", result.FinalResult.String())
+	}
+
+	if _, err := interp.EvalString(`gsh.logging.file = "/tmp/other.log"`, nil); err == nil {
+		t.Error("expected error when setting gsh.logging.file")
+	}
+}
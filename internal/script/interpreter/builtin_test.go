@@ -147,6 +147,16 @@ func TestLogFunctions(t *testing.T) {
 			input:    `log.info("Status:", 200, "OK")`,
 			expected: "[INFO] Status: 200 OK\n",
 		},
+		{
+			name:     "log with fields",
+			input:    `log.warn("deploy slow", { service: "api", ms: 1200 })`,
+			expected: "[WARN] deploy slow ms=1200 service=api\n",
+		},
+		{
+			name:     "lone object is the message",
+			input:    `log.info({ a: 1 })`,
+			expected: "[INFO] {a: 1}\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -263,6 +273,37 @@ func TestLogFunctionsWithZapLogger(t *testing.T) {
 	}
 }
 
+func TestLogFunctionsWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zapcore.DebugLevel))
+
+	interp := New(&Options{Logger: logger})
+	defer interp.Close()
+	_, err := interp.EvalString(`log.error("deploy failed:", "api", { attempt: 3, ok: false, tags: ["prod"], owner: { team: "infra" } })`, nil)
+	if err != nil {
+		t.Fatalf("eval error: %v", err)
+	}
+	logger.Sync()
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("failed to parse zap log output as JSON: %v, output: %s", err, buf.String())
+	}
+	if logEntry["level"] != "error" || logEntry["msg"] != "deploy failed: api" {
+		t.Errorf("unexpected level or message: %v", logEntry)
+	}
+	if logEntry["attempt"] != float64(3) || logEntry["ok"] != false {
+		t.Errorf("expected attempt and ok fields, got %v", logEntry)
+	}
+	if tags, ok := logEntry["tags"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "prod" {
+		t.Errorf("expected tags field, got %v", logEntry["tags"])
+	}
+	if owner, ok := logEntry["owner"].(map[string]interface{}); !ok || owner["team"] != "infra" {
+		t.Errorf("expected nested owner field, got %v", logEntry["owner"])
+	}
+}
+
 func TestJSONParse(t *testing.T) {
 	tests := []struct {
 		name        string
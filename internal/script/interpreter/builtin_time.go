@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"fmt"
+	"time"
+)
+
+// createTimeObject creates the gsh.time object with timing primitives:
+// - gsh.time.now() - returns the current time in milliseconds since Unix epoch
+// - gsh.time.sleep(ms) - pauses execution, interruptible via the interpreter's context
+// - gsh.time.format(epochMs, layout?) - formats a timestamp using a Go time layout
+func (i *Interpreter) createTimeObject() *ObjectValue {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"now": {Value: &BuiltinValue{
+				Name: "gsh.time.now",
+				Fn:   builtinTimeNow,
+			}, ReadOnly: true},
+			"sleep": {Value: &BuiltinValue{
+				Name: "gsh.time.sleep",
+				Fn:   i.builtinTimeSleep,
+			}, ReadOnly: true},
+			"format": {Value: &BuiltinValue{
+				Name: "gsh.time.format",
+				Fn:   builtinTimeFormat,
+			}, ReadOnly: true},
+		},
+	}
+}
+
+// builtinTimeNow implements gsh.time.now()
+func builtinTimeNow(args []Value) (Value, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("gsh.time.now() takes no arguments, got %d", len(args))
+	}
+	return &NumberValue{Value: float64(time.Now().UnixMilli())}, nil
+}
+
+// builtinTimeSleep implements gsh.time.sleep(ms)
+// The sleep is cancelled when the interpreter's context is cancelled (e.g., Ctrl+C in the REPL).
+func (i *Interpreter) builtinTimeSleep(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("gsh.time.sleep() takes 1 argument (ms: number), got %d", len(args))
+	}
+	msVal, ok := args[0].(*NumberValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.time.sleep() argument must be a number, got %s", args[0].Type())
+	}
+	if msVal.Value < 0 {
+		return nil, fmt.Errorf("gsh.time.sleep() argument must not be negative, got %v", msVal.Value)
+	}
+
+	timer := time.NewTimer(time.Duration(msVal.Value * float64(time.Millisecond)))
+	defer timer.Stop()
+
+	ctx := i.Context()
+	select {
+	case <-timer.C:
+		return &NullValue{}, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("gsh.time.sleep() cancelled")
+	}
+}
+
+// builtinTimeFormat implements gsh.time.format(epochMs, layout?)
+// The layout uses Go's reference time (e.g., "2006-01-02 15:04:05") and defaults to RFC 3339.
+func builtinTimeFormat(args []Value) (Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("gsh.time.format() takes 1 or 2 arguments (epochMs: number, layout?: string), got %d", len(args))
+	}
+	msVal, ok := args[0].(*NumberValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.time.format() first argument must be a number, got %s", args[0].Type())
+	}
+
+	layout := time.RFC3339
+	if len(args) == 2 {
+		layoutVal, ok := args[1].(*StringValue)
+		if !ok {
+			return nil, fmt.Errorf("gsh.time.format() second argument must be a string (layout), got %s", args[1].Type())
+		}
+		layout = layoutVal.Value
+	}
+
+	t := time.UnixMilli(int64(msVal.Value))
+	return &StringValue{Value: t.Format(layout)}, nil
+}
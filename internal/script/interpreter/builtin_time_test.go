@@ -0,0 +1,129 @@
+package interpreter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGshTimeNow(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	before := time.Now().UnixMilli()
+	result, err := interp.EvalString(`gsh.time.now()`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after := time.Now().UnixMilli()
+
+	numVal, ok := result.FinalResult.(*NumberValue)
+	if !ok {
+		t.Fatalf("expected number, got %s", result.FinalResult.Type())
+	}
+	if ts := int64(numVal.Value); ts < before || ts > after {
+		t.Errorf("expected timestamp between %d and %d, got %d", before, after, ts)
+	}
+}
+
+func TestGshTimeSleep(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	start := time.Now()
+	result, err := interp.EvalString(`gsh.time.sleep(20)`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected sleep of at least 20ms, got %v", elapsed)
+	}
+	if result.FinalResult.Type() != ValueTypeNull {
+		t.Errorf("expected null, got %s", result.FinalResult.Type())
+	}
+}
+
+func TestGshTimeSleepCancelled(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interp.SetContext(ctx)
+	defer interp.ClearContext()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := interp.EvalString(`gsh.time.sleep(10000)`, nil)
+	if err == nil {
+		t.Fatal("expected error when sleep is cancelled")
+	}
+	if !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("expected cancellation error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("sleep was not interrupted promptly (took %v)", elapsed)
+	}
+}
+
+func TestGshTimeFormat(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	ts := time.Date(2024, 3, 5, 14, 7, 9, 0, time.Local).UnixMilli()
+	interp.globalEnv.Set("ts", &NumberValue{Value: float64(ts)})
+
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{`gsh.time.format(ts, "2006-01-02 15:04:05")`, "2024-03-05 14:07:09"},
+		{`gsh.time.format(ts, "Jan 2")`, "Mar 5"},
+		{`gsh.time.format(ts)`, time.UnixMilli(ts).Format(time.RFC3339)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := interp.EvalString(tt.expr, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := result.FinalResult.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGshTimeErrors(t *testing.T) {
+	interp := New(&Options{})
+	defer interp.Close()
+
+	tests := []struct {
+		expr        string
+		errContains string
+	}{
+		{`gsh.time.now(1)`, "takes no arguments"},
+		{`gsh.time.sleep()`, "takes 1 argument"},
+		{`gsh.time.sleep("10")`, "must be a number"},
+		{`gsh.time.sleep(-1)`, "must not be negative"},
+		{`gsh.time.format("now")`, "must be a number"},
+		{`gsh.time.format(0, 1)`, "must be a string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := interp.EvalString(tt.expr, nil)
+			if err == nil {
+				t.Fatalf("expected error for %s", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
 
 	"github.com/kunchenguid/gsh/internal/repl/render"
 )
@@ -44,6 +46,8 @@ type UICursorObject struct{}
 
 // createUIObject creates the gsh.ui object with spinner, styles, and cursor control
 func (i *Interpreter) createUIObject() *ObjectValue {
+	// Shared by writeWrapped and flushWrapped so streamed chunks wrap as one text
+	wrapper := &render.StreamWrapper{}
 	return &ObjectValue{
 		Properties: map[string]*PropertyDescriptor{
 			"spinner": {Value: &UISpinnerObjectValue{interp: i}, ReadOnly: true},
@@ -59,10 +63,187 @@ func (i *Interpreter) createUIObject() *ObjectValue {
 					return &NullValue{}, nil
 				},
 			}, ReadOnly: true},
+			"writeWrapped": {Value: &BuiltinValue{
+				Name: "gsh.ui.writeWrapped",
+				Fn: func(args []Value) (Value, error) {
+					// Write text word-wrapped to a width, continuing the line left by the previous call
+					if len(args) != 2 {
+						return nil, fmt.Errorf("gsh.ui.writeWrapped() takes 2 arguments (text: string, width: number), got %d", len(args))
+					}
+					text, ok := args[0].(*StringValue)
+					if !ok {
+						return nil, fmt.Errorf("gsh.ui.writeWrapped() text must be a string, got %s", args[0].Type())
+					}
+					width, ok := args[1].(*NumberValue)
+					if !ok {
+						return nil, fmt.Errorf("gsh.ui.writeWrapped() width must be a number, got %s", args[1].Type())
+					}
+					fmt.Fprint(os.Stdout, wrapper.Write(text.Value, int(width.Value)))
+					return &NullValue{}, nil
+				},
+			}, ReadOnly: true},
+			"flushWrapped": {Value: &BuiltinValue{
+				Name: "gsh.ui.flushWrapped",
+				Fn: func(args []Value) (Value, error) {
+					// Write any word writeWrapped is still holding and start over at column 0
+					fmt.Fprint(os.Stdout, wrapper.Flush())
+					return &NullValue{}, nil
+				},
+			}, ReadOnly: true},
+			"table": {Value: &BuiltinValue{
+				Name: "gsh.ui.table",
+				Fn: func(args []Value) (Value, error) {
+					headers, rows, err := tableArgs(args)
+					if err != nil {
+						return nil, err
+					}
+					if i.sdkConfig.IsTTY() {
+						fmt.Fprintln(os.Stdout, renderTable(headers, rows, i.sdkConfig.GetTermWidth()))
+					} else {
+						fmt.Fprintln(os.Stdout, renderMarkdownTable(headers, rows))
+					}
+					return &NullValue{}, nil
+				},
+			}, ReadOnly: true},
+			"markdown": {Value: &BuiltinValue{
+				Name: "gsh.ui.markdown",
+				Fn: func(args []Value) (Value, error) {
+					if len(args) < 1 || len(args) > 2 {
+						return nil, fmt.Errorf("gsh.ui.markdown() takes 1-2 arguments (text: string, width?: number), got %d", len(args))
+					}
+					text, ok := args[0].(*StringValue)
+					if !ok {
+						return nil, fmt.Errorf("gsh.ui.markdown() text must be a string, got %s", args[0].Type())
+					}
+					maxWidth := 0
+					if len(args) == 2 {
+						widthVal, ok := args[1].(*NumberValue)
+						if !ok {
+							return nil, fmt.Errorf("gsh.ui.markdown() width must be a number, got %s", args[1].Type())
+						}
+						maxWidth = int(widthVal.Value)
+					}
+					// Markdown is already readable as plain text, so pipes and files get it as-is
+					if !i.sdkConfig.IsTTY() {
+						fmt.Fprintln(os.Stdout, text.Value)
+						return &NullValue{}, nil
+					}
+					width := i.sdkConfig.GetTermWidth()
+					if maxWidth > 0 && (width <= 0 || maxWidth < width) {
+						width = maxWidth
+					}
+					fmt.Fprintln(os.Stdout, render.RenderMarkdown(text.Value, width))
+					return &NullValue{}, nil
+				},
+			}, ReadOnly: true},
 		},
 	}
 }
 
+// tableArgs validates the arguments of gsh.ui.table(headers, rows). Each row is an array
+// of cells or an object keyed by header; missing cells are left empty.
+func tableArgs(args []Value) ([]string, [][]string, error) {
+	if len(args) != 2 {
+		return nil, nil, fmt.Errorf("gsh.ui.table() takes 2 arguments (headers: array, rows: array), got %d", len(args))
+	}
+	headerArr, ok := args[0].(*ArrayValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("gsh.ui.table() headers must be an array, got %s", args[0].Type())
+	}
+	rowArr, ok := args[1].(*ArrayValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("gsh.ui.table() rows must be an array, got %s", args[1].Type())
+	}
+
+	headers := make([]string, len(headerArr.Elements))
+	for idx, header := range headerArr.Elements {
+		headers[idx] = tableCell(header)
+	}
+
+	rows := make([][]string, len(rowArr.Elements))
+	for idx, rowVal := range rowArr.Elements {
+		row := make([]string, len(headers))
+		switch r := rowVal.(type) {
+		case *ArrayValue:
+			if len(r.Elements) > len(headers) {
+				return nil, nil, fmt.Errorf("gsh.ui.table() row %d has %d cells, but there are only %d headers", idx, len(r.Elements), len(headers))
+			}
+			for col, cell := range r.Elements {
+				row[col] = tableCell(cell)
+			}
+		case *ObjectValue:
+			for col, header := range headers {
+				if prop, ok := r.Properties[header]; ok {
+					row[col] = tableCell(prop.Value)
+				}
+			}
+		default:
+			return nil, nil, fmt.Errorf("gsh.ui.table() row %d must be an array or an object, got %s", idx, rowVal.Type())
+		}
+		rows[idx] = row
+	}
+	return headers, rows, nil
+}
+
+// tableCell formats a value as table cell text, with null as an empty cell
+func tableCell(value Value) string {
+	switch v := value.(type) {
+	case *StringValue:
+		return v.Value
+	case *NullValue, nil:
+		return ""
+	default:
+		return v.String()
+	}
+}
+
+// renderTable renders a bordered table, shrinking and truncating columns to fit width
+func renderTable(headers []string, rows [][]string, width int) string {
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(render.ColorGray)).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row == table.HeaderRow {
+				return style.Bold(true)
+			}
+			return style
+		})
+	rendered := t.Render()
+	if width > 0 && lipgloss.Width(rendered) > width {
+		rendered = t.Width(width).Render()
+	}
+	return rendered
+}
+
+// renderMarkdownTable renders a Markdown table, for output that isn't a terminal
+func renderMarkdownTable(headers []string, rows [][]string) string {
+	escape := func(cell string) string {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		return strings.ReplaceAll(strings.ReplaceAll(cell, "\r\n", " "), "\n", " ")
+	}
+	writeRow := func(sb *strings.Builder, cells []string) {
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" " + escape(cell) + " |")
+		}
+	}
+
+	var sb strings.Builder
+	writeRow(&sb, headers)
+	sb.WriteString("\n|")
+	for range headers {
+		sb.WriteString(" --- |")
+	}
+	for _, row := range rows {
+		sb.WriteString("\n")
+		writeRow(&sb, row)
+	}
+	return sb.String()
+}
+
 // UISpinnerObjectValue represents gsh.ui.spinner
 type UISpinnerObjectValue struct {
 	interp *Interpreter
@@ -76,6 +257,11 @@ func (s *UISpinnerObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (s *UISpinnerObjectValue) PropertyNames() []string {
+	return []string{"start", "setMessage", "stop", "stopAll"}
+}
+
 func (s *UISpinnerObjectValue) GetProperty(name string) Value {
 	switch name {
 	case "start":
@@ -207,6 +393,11 @@ func (s *UIStylesObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (s *UIStylesObjectValue) PropertyNames() []string {
+	return []string{"primary", "success", "error", "dim", "bold", "italic"}
+}
+
 func (s *UIStylesObjectValue) GetProperty(name string) Value {
 	switch name {
 	case "primary":
@@ -323,6 +514,11 @@ func (c *UICursorObjectValue) Equals(other Value) bool {
 	return ok
 }
 
+// PropertyNames implements PropertyLister
+func (c *UICursorObjectValue) PropertyNames() []string {
+	return []string{"clearLine", "moveCursor", "clearLines"}
+}
+
 func (c *UICursorObjectValue) GetProperty(name string) Value {
 	switch name {
 	case "clearLine":
@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
+
 	"github.com/kunchenguid/gsh/internal/repl/render"
 )
 
@@ -486,3 +488,129 @@ func TestMoveCursorArgumentValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteWrapped(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_, err := interp.EvalString(`
+gsh.ui.writeWrapped("the quick br", 10)
+gsh.ui.writeWrapped("own fox jumps", 10)
+gsh.ui.flushWrapped()
+`, nil)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "the quick\nbrown fox\njumps"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	for _, code := range []string{`gsh.ui.writeWrapped("text")`, `gsh.ui.writeWrapped(1, 10)`, `gsh.ui.writeWrapped("text", "10")`} {
+		if _, err := interp.EvalString(code, nil); err == nil {
+			t.Errorf("expected error for %s", code)
+		}
+	}
+}
+
+func TestUITable(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	// Capture stdout, which isn't a terminal here, so the table is Markdown
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_, err := interp.EvalString(`
+gsh.ui.table(["Name", "Status"], [["api", "ok | fine"], { Name: "web" }, ["db", 3]])
+`, nil)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "| Name | Status |\n| --- | --- |\n| api | ok \\| fine |\n| web |  |\n| db | 3 |\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	for _, code := range []string{
+		`gsh.ui.table(["a"])`,
+		`gsh.ui.table("a", [])`,
+		`gsh.ui.table(["a"], [["1", "2"]])`,
+		`gsh.ui.table(["a"], ["1"])`,
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil {
+			t.Errorf("expected error for %s", code)
+		}
+	}
+}
+
+func TestUIMarkdown(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	// Capture stdout, which isn't a terminal here, so the Markdown is printed as-is
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_, err := interp.EvalString("gsh.ui.markdown(\"# Title\\n\\n- **one**\")", nil)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "# Title\n\n- **one**\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	for _, code := range []string{
+		`gsh.ui.markdown()`,
+		`gsh.ui.markdown(1)`,
+		`gsh.ui.markdown("a", "80")`,
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil {
+			t.Errorf("expected error for %s", code)
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	headers := []string{"Name", "Description"}
+	rows := [][]string{{"api", "serves the public REST endpoints"}}
+
+	wide := renderTable(headers, rows, 200)
+	if !strings.Contains(wide, "serves the public REST endpoints") {
+		t.Errorf("expected the full cell in a wide table, got:\n%s", wide)
+	}
+
+	narrow := renderTable(headers, rows, 24)
+	for _, line := range strings.Split(narrow, "\n") {
+		if width := lipgloss.Width(line); width > 24 {
+			t.Errorf("expected lines to fit in 24 columns, got %d: %q", width, line)
+		}
+	}
+	if strings.Contains(narrow, "serves the public REST endpoints") {
+		t.Errorf("expected the long cell to be truncated, got:\n%s", narrow)
+	}
+}
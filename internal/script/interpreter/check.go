@@ -0,0 +1,634 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kunchenguid/gsh/internal/script/lexer"
+	"github.com/kunchenguid/gsh/internal/script/parser"
+)
+
+// CheckProblem is a problem found by static validation of a script
+type CheckProblem struct {
+	Path    string // file the problem is in, or "" for the checked source itself
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p CheckProblem) String() string {
+	name := p.Path
+	if name == "" {
+		name = "<input>"
+	}
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", name, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", name, p.Line, p.Column, p.Message)
+}
+
+// valueTypeUnknown marks a binding whose type can't be known without running the script,
+// such as a variable assigned from an expression
+const valueTypeUnknown ValueType = -1
+
+// checkScope maps the names a script binds to the type of value they hold
+type checkScope map[string]ValueType
+
+// CheckString validates a script without running it: nothing is executed, no MCP servers
+// are started and no models are called. It reports references to undefined variables,
+// agent tools that aren't tools, unknown model providers, and imports that can't be
+// resolved. Imported and included files on the filesystem are checked too.
+//
+// Parse errors, including those in imported files, are returned as a *ParseError, since
+// nothing else can be checked until the source parses. The checks are conservative: a
+// name assigned anywhere in a file counts as defined everywhere in it.
+func (i *Interpreter) CheckString(source string, origin *ScriptOrigin) ([]CheckProblem, error) {
+	program, err := parseForCheck("", source)
+	if err != nil {
+		return nil, err
+	}
+
+	basePath := ""
+	if origin != nil && origin.Type == OriginFilesystem {
+		basePath = origin.BasePath
+	}
+	c := &checker{
+		interp:    i,
+		imported:  make(map[string]bool),
+		including: make(map[string]bool),
+	}
+	if err := c.checkProgram(program, "", basePath, nil); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(c.problems, func(a, b int) bool {
+		pa, pb := c.problems[a], c.problems[b]
+		if pa.Path != pb.Path {
+			return pa.Path < pb.Path
+		}
+		if pa.Line != pb.Line {
+			return pa.Line < pb.Line
+		}
+		return pa.Column < pb.Column
+	})
+	return c.problems, nil
+}
+
+// parseForCheck parses source, returning a *ParseError if it doesn't parse
+func parseForCheck(path, source string) (*parser.Program, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, &ParseError{Path: path, Source: source, Errors: p.Errors()}
+	}
+	return program, nil
+}
+
+// checker holds the state of one CheckString call
+type checker struct {
+	interp    *Interpreter
+	problems  []CheckProblem
+	imported  map[string]bool // modules already checked
+	including map[string]bool // files on the current include chain, to stop cycles
+}
+
+// checkedFile is a parsed file being checked
+type checkedFile struct {
+	path     string // as reported in problems
+	basePath string // directory imports resolve against, or "" if unknown
+	scope    checkScope
+	outer    checkScope // scope of the importing file, which modules can see
+}
+
+func (c *checker) addProblem(f *checkedFile, line, column int, format string, args ...interface{}) {
+	c.problems = append(c.problems, CheckProblem{Path: f.path, Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkProgram checks a parsed file. outer is the scope of the file importing it, if any.
+func (c *checker) checkProgram(program *parser.Program, path, basePath string, outer checkScope) error {
+	f := &checkedFile{path: path, basePath: basePath, scope: make(checkScope), outer: outer}
+	if err := c.collectBindings(f, program.Statements); err != nil {
+		return err
+	}
+	for _, stmt := range program.Statements {
+		if err := c.checkStatement(f, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePath resolves an import or include path the way the interpreter does for
+// filesystem scripts. ok is false if the path can't be resolved statically.
+func (c *checker) resolvePath(f *checkedFile, importPath string) (resolved string, ok bool) {
+	if filepath.IsAbs(importPath) {
+		return importPath, true
+	}
+	if !strings.HasPrefix(importPath, "./") && !strings.HasPrefix(importPath, "../") {
+		return "", false
+	}
+	if f.basePath == "" {
+		return "", false
+	}
+	return filepath.Clean(filepath.Join(f.basePath, importPath)), true
+}
+
+// readFile reads and parses an imported or included file, reporting a problem at token
+// if it can't be read. program is nil if the file couldn't be read.
+func (c *checker) readFile(f *checkedFile, token lexer.Token, keyword, importPath string) (string, *parser.Program, error) {
+	if !strings.HasPrefix(importPath, "./") && !strings.HasPrefix(importPath, "../") && !filepath.IsAbs(importPath) {
+		c.addProblem(f, token.Line, token.Column, "invalid %s path: %s (must be relative starting with ./ or ../, or absolute)", keyword, importPath)
+		return "", nil, nil
+	}
+	resolved, ok := c.resolvePath(f, importPath)
+	if !ok {
+		return "", nil, nil
+	}
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		c.addProblem(f, token.Line, token.Column, "failed to read %s %q: %v", keyword, importPath, err)
+		return "", nil, nil
+	}
+	program, err := parseForCheck(resolved, StripShebang(string(content)))
+	if err != nil {
+		return "", nil, err
+	}
+	return resolved, program, nil
+}
+
+// bind records a name bound in the current file. A name bound more than once keeps a
+// known type only if every binding agrees.
+func (f *checkedFile) bind(name string, valueType ValueType) {
+	if existing, ok := f.scope[name]; ok && existing != valueType {
+		valueType = valueTypeUnknown
+	}
+	f.scope[name] = valueType
+}
+
+// lookup returns the type of a name visible in the file, and whether it's defined
+func (c *checker) lookup(f *checkedFile, name string) (ValueType, bool) {
+	if t, ok := f.scope[name]; ok {
+		return t, true
+	}
+	if t, ok := f.outer[name]; ok {
+		return t, true
+	}
+	if val, ok := c.interp.globalEnv.Get(name); ok {
+		return val.Type(), true
+	}
+	return valueTypeUnknown, false
+}
+
+// collectBindings records every name the statements bind, at any depth. Included files
+// share the includer's scope, so their bindings are collected too.
+func (c *checker) collectBindings(f *checkedFile, stmts []parser.Statement) error {
+	for _, stmt := range stmts {
+		if err := c.collectStatementBindings(f, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *checker) collectStatementBindings(f *checkedFile, stmt parser.Statement) error {
+	switch node := stmt.(type) {
+	case *parser.AssignmentStatement:
+		if ident, ok := node.Left.(*parser.Identifier); ok {
+			f.bind(ident.Value, valueTypeUnknown)
+		} else if node.Left == nil && node.Name != nil {
+			f.bind(node.Name.Value, valueTypeUnknown)
+		}
+	case *parser.DestructuringAssignment:
+		for _, name := range node.Names {
+			f.bind(name.Value, valueTypeUnknown)
+		}
+	case *parser.ToolDeclaration:
+		f.bind(node.Name.Value, ValueTypeTool)
+		for _, param := range node.Parameters {
+			f.bind(param.Name.Value, valueTypeUnknown)
+		}
+		return c.collectBindings(f, node.Body.Statements)
+	case *parser.ModelDeclaration:
+		f.bind(node.Name.Value, ValueTypeModel)
+	case *parser.AgentDeclaration:
+		f.bind(node.Name.Value, ValueTypeAgent)
+	case *parser.McpDeclaration:
+		f.bind(node.Name.Value, ValueTypeObject)
+	case *parser.ACPDeclaration:
+		f.bind(node.Name.Value, ValueTypeACP)
+	case *parser.ExportStatement:
+		return c.collectStatementBindings(f, node.Declaration)
+	case *parser.ImportStatement:
+		for _, sym := range node.Symbols {
+			f.bind(sym, valueTypeUnknown)
+		}
+	case *parser.IncludeStatement:
+		resolved, ok := c.resolvePath(f, node.Path.Value)
+		if !ok || c.including[resolved] {
+			return nil
+		}
+		content, err := os.ReadFile(resolved)
+		if err != nil {
+			return nil // reported when the include statement is checked
+		}
+		program, err := parseForCheck(resolved, StripShebang(string(content)))
+		if err != nil {
+			return nil // returned when the include statement is checked
+		}
+		c.including[resolved] = true
+		defer delete(c.including, resolved)
+		included := &checkedFile{path: resolved, basePath: filepath.Dir(resolved), scope: f.scope, outer: f.outer}
+		return c.collectBindings(included, program.Statements)
+	case *parser.BlockStatement:
+		return c.collectBindings(f, node.Statements)
+	case *parser.IfStatement:
+		if err := c.collectBindings(f, node.Consequence.Statements); err != nil {
+			return err
+		}
+		if node.Alternative != nil {
+			return c.collectStatementBindings(f, node.Alternative)
+		}
+	case *parser.WhileStatement:
+		return c.collectBindings(f, node.Body.Statements)
+	case *parser.ForOfStatement:
+		f.bind(node.Variable.Value, valueTypeUnknown)
+		if node.ValueVariable != nil {
+			f.bind(node.ValueVariable.Value, valueTypeUnknown)
+		}
+		return c.collectBindings(f, node.Body.Statements)
+	case *parser.SwitchStatement:
+		for _, sc := range node.Cases {
+			if err := c.collectBindings(f, sc.Body.Statements); err != nil {
+				return err
+			}
+		}
+		if node.Default != nil {
+			return c.collectBindings(f, node.Default.Statements)
+		}
+	case *parser.TryStatement:
+		if err := c.collectBindings(f, node.Block.Statements); err != nil {
+			return err
+		}
+		if node.CatchClause != nil {
+			if node.CatchClause.Parameter != nil {
+				f.bind(node.CatchClause.Parameter.Value, valueTypeUnknown)
+			}
+			if err := c.collectBindings(f, node.CatchClause.Block.Statements); err != nil {
+				return err
+			}
+		}
+		if node.FinallyClause != nil {
+			return c.collectBindings(f, node.FinallyClause.Block.Statements)
+		}
+	}
+	return nil
+}
+
+func (c *checker) checkBlock(f *checkedFile, block *parser.BlockStatement) error {
+	if block == nil {
+		return nil
+	}
+	for _, stmt := range block.Statements {
+		if err := c.checkStatement(f, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *checker) checkStatement(f *checkedFile, stmt parser.Statement) error {
+	switch node := stmt.(type) {
+	case *parser.ExpressionStatement:
+		c.checkExpression(f, node.Expression)
+	case *parser.AssignmentStatement:
+		if node.Left != nil {
+			if _, ok := node.Left.(*parser.Identifier); !ok {
+				c.checkExpression(f, node.Left)
+			}
+		}
+		c.checkExpression(f, node.Value)
+	case *parser.DestructuringAssignment:
+		c.checkExpression(f, node.Value)
+	case *parser.BlockStatement:
+		return c.checkBlock(f, node)
+	case *parser.IfStatement:
+		c.checkExpression(f, node.Condition)
+		if err := c.checkBlock(f, node.Consequence); err != nil {
+			return err
+		}
+		if node.Alternative != nil {
+			return c.checkStatement(f, node.Alternative)
+		}
+	case *parser.WhileStatement:
+		c.checkExpression(f, node.Condition)
+		return c.checkBlock(f, node.Body)
+	case *parser.ForOfStatement:
+		c.checkExpression(f, node.Iterable)
+		return c.checkBlock(f, node.Body)
+	case *parser.SwitchStatement:
+		c.checkExpression(f, node.Value)
+		for _, sc := range node.Cases {
+			for _, val := range sc.Values {
+				c.checkExpression(f, val)
+			}
+			if err := c.checkBlock(f, sc.Body); err != nil {
+				return err
+			}
+		}
+		return c.checkBlock(f, node.Default)
+	case *parser.ReturnStatement:
+		c.checkExpression(f, node.ReturnValue)
+	case *parser.ThrowStatement:
+		c.checkExpression(f, node.Expression)
+	case *parser.DeferStatement:
+		c.checkExpression(f, node.Call)
+	case *parser.TryStatement:
+		if err := c.checkBlock(f, node.Block); err != nil {
+			return err
+		}
+		if node.CatchClause != nil {
+			if err := c.checkBlock(f, node.CatchClause.Block); err != nil {
+				return err
+			}
+		}
+		if node.FinallyClause != nil {
+			return c.checkBlock(f, node.FinallyClause.Block)
+		}
+	case *parser.ToolDeclaration:
+		return c.checkBlock(f, node.Body)
+	case *parser.ModelDeclaration:
+		c.checkConfig(f, node.Config)
+		c.checkModelDeclaration(f, node)
+	case *parser.AgentDeclaration:
+		c.checkConfig(f, node.Config)
+		c.checkAgentDeclaration(f, node)
+	case *parser.McpDeclaration:
+		c.checkConfig(f, node.Config)
+	case *parser.ACPDeclaration:
+		c.checkConfig(f, node.Config)
+	case *parser.ExportStatement:
+		return c.checkStatement(f, node.Declaration)
+	case *parser.ImportStatement:
+		return c.checkImport(f, node)
+	case *parser.IncludeStatement:
+		return c.checkInclude(f, node)
+	}
+	return nil
+}
+
+// checkConfig checks the values of a declaration's config block, in a stable order
+func (c *checker) checkConfig(f *checkedFile, config map[string]parser.Expression) {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.checkExpression(f, config[key])
+	}
+}
+
+func (c *checker) checkExpression(f *checkedFile, expr parser.Expression) {
+	switch node := expr.(type) {
+	case nil:
+	case *parser.Identifier:
+		if _, ok := c.lookup(f, node.Value); !ok {
+			c.addProblem(f, node.Token.Line, node.Token.Column, "undefined variable: %s", node.Value)
+		}
+	case *parser.StringLiteral:
+		if node.IsTemplate {
+			c.checkTemplate(f, node)
+		}
+	case *parser.BinaryExpression:
+		c.checkExpression(f, node.Left)
+		c.checkExpression(f, node.Right)
+	case *parser.UnaryExpression:
+		c.checkExpression(f, node.Right)
+	case *parser.CallExpression:
+		c.checkExpression(f, node.Function)
+		for _, arg := range node.Arguments {
+			c.checkExpression(f, arg)
+		}
+	case *parser.MemberExpression:
+		c.checkExpression(f, node.Object)
+	case *parser.PipeExpression:
+		c.checkExpression(f, node.Left)
+		c.checkExpression(f, node.Right)
+	case *parser.IndexExpression:
+		c.checkExpression(f, node.Left)
+		c.checkExpression(f, node.Index)
+	case *parser.ArrayLiteral:
+		for _, elem := range node.Elements {
+			c.checkExpression(f, elem)
+		}
+	case *parser.SpreadElement:
+		c.checkExpression(f, node.Argument)
+	case *parser.ObjectLiteral:
+		for _, key := range node.Order {
+			c.checkExpression(f, node.Pairs[key])
+		}
+	}
+}
+
+// checkTemplate checks the ${...} interpolations of a template literal. Positions inside
+// an interpolation aren't tracked, so problems point at the start of the literal.
+func (c *checker) checkTemplate(f *checkedFile, node *parser.StringLiteral) {
+	runes := []rune(node.Value)
+	for pos := 0; pos < len(runes)-1; pos++ {
+		if runes[pos] != '$' || runes[pos+1] != '{' {
+			continue
+		}
+		braceCount := 1
+		start := pos + 2
+		end := start
+		for end < len(runes) && braceCount > 0 {
+			switch runes[end] {
+			case '{':
+				braceCount++
+			case '}':
+				braceCount--
+			}
+			if braceCount > 0 {
+				end++
+			}
+		}
+		if braceCount != 0 {
+			c.addProblem(f, node.Token.Line, node.Token.Column, "unclosed template literal interpolation")
+			return
+		}
+
+		p := parser.New(lexer.New(string(runes[start:end])))
+		program := p.ParseProgram()
+		if len(p.Errors()) > 0 {
+			c.addProblem(f, node.Token.Line, node.Token.Column, "error parsing template literal expression: %s", p.Errors()[0])
+		} else {
+			sub := &checker{interp: c.interp}
+			for _, stmt := range program.Statements {
+				_ = sub.checkStatement(f, stmt)
+			}
+			for _, problem := range sub.problems {
+				problem.Line, problem.Column = node.Token.Line, node.Token.Column
+				c.problems = append(c.problems, problem)
+			}
+		}
+		pos = end
+	}
+}
+
+// checkModelDeclaration checks that a model's provider is registered
+func (c *checker) checkModelDeclaration(f *checkedFile, node *parser.ModelDeclaration) {
+	provider, ok := node.Config["provider"].(*parser.StringLiteral)
+	if !ok || provider.IsTemplate {
+		return
+	}
+	if _, found := c.interp.providerRegistry.Get(provider.Value); !found {
+		c.addProblem(f, provider.Token.Line, provider.Token.Column, "model '%s' has unknown model provider: %s", node.Name.Value, provider.Value)
+	}
+}
+
+// checkAgentDeclaration checks an agent's model and tools, mirroring the validation
+// evalAgentDeclaration does when the declaration runs
+func (c *checker) checkAgentDeclaration(f *checkedFile, node *parser.AgentDeclaration) {
+	agentName := node.Name.Value
+
+	model, ok := node.Config["model"]
+	if !ok {
+		c.addProblem(f, node.Name.Token.Line, node.Name.Token.Column, "agent '%s' must have a 'model' field", agentName)
+	} else if ident, ok := model.(*parser.Identifier); ok {
+		if t, ok := c.lookup(f, ident.Value); ok && t != valueTypeUnknown && t != ValueTypeModel {
+			c.addProblem(f, ident.Token.Line, ident.Token.Column, "agent config 'model' must be a model reference, got %s", t)
+		}
+	}
+
+	tools, ok := node.Config["tools"].(*parser.ArrayLiteral)
+	if !ok {
+		return
+	}
+	for _, elem := range tools.Elements {
+		line, column, t := c.toolElementType(f, elem)
+		// Arrays hold more tools, such as filesystem.*
+		if t == valueTypeUnknown || t == ValueTypeTool || t == ValueTypeArray {
+			continue
+		}
+		c.addProblem(f, line, column, "agent '%s' has an invalid tool '%s' in 'tools': expected a tool, got %s", agentName, elem.String(), t)
+	}
+}
+
+// toolElementType returns the position of an element of an agent's tools array and the
+// type of value it holds, if that can be known statically
+func (c *checker) toolElementType(f *checkedFile, elem parser.Expression) (int, int, ValueType) {
+	switch node := elem.(type) {
+	case *parser.Identifier:
+		t, _ := c.lookup(f, node.Value)
+		return node.Token.Line, node.Token.Column, t
+	case *parser.MemberExpression:
+		// gsh.tools.<name> must name a native tool
+		if tools := c.nativeTools(f, node.Object); tools != nil {
+			return node.Property.Token.Line, node.Property.Token.Column, tools.GetPropertyValue(node.Property.Value).Type()
+		}
+		return node.Token.Line, node.Token.Column, valueTypeUnknown
+	case *parser.StringLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeString
+	case *parser.NumberLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeNumber
+	case *parser.BooleanLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeBool
+	case *parser.NullLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeNull
+	case *parser.ArrayLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeArray
+	case *parser.ObjectLiteral:
+		return node.Token.Line, node.Token.Column, ValueTypeObject
+	}
+	return 0, 0, valueTypeUnknown
+}
+
+// nativeTools returns the gsh.tools object if expr is gsh.tools and the script doesn't
+// shadow gsh, or nil otherwise
+func (c *checker) nativeTools(f *checkedFile, expr parser.Expression) *ObjectValue {
+	member, ok := expr.(*parser.MemberExpression)
+	if !ok || member.Property.Value != "tools" {
+		return nil
+	}
+	root, ok := member.Object.(*parser.Identifier)
+	if !ok || root.Value != "gsh" {
+		return nil
+	}
+	if _, shadowed := f.scope["gsh"]; shadowed {
+		return nil
+	}
+	if _, shadowed := f.outer["gsh"]; shadowed {
+		return nil
+	}
+	gsh, ok := c.interp.globalEnv.Get("gsh")
+	if !ok {
+		return nil
+	}
+	gshObj, ok := gsh.(*GshObjectValue)
+	if !ok {
+		return nil
+	}
+	tools, _ := gshObj.GetProperty("tools").(*ObjectValue)
+	return tools
+}
+
+// checkImport checks that an imported module can be read, parses and exports the
+// requested symbols. Each module is checked once.
+func (c *checker) checkImport(f *checkedFile, node *parser.ImportStatement) error {
+	resolved, program, err := c.readFile(f, node.Token, "import", node.Path.Value)
+	if err != nil || program == nil {
+		return err
+	}
+
+	exported := make(map[string]bool)
+	for _, stmt := range program.Statements {
+		if export, ok := stmt.(*parser.ExportStatement); ok {
+			exported[export.Name] = true
+		}
+	}
+	for _, sym := range node.Symbols {
+		if !exported[sym] {
+			c.addProblem(f, node.Token.Line, node.Token.Column, "symbol %q is not exported from %q", sym, node.Path.Value)
+		}
+	}
+
+	if c.imported[resolved] {
+		return nil
+	}
+	c.imported[resolved] = true
+
+	// Modules run in their own scope enclosed by the importer's
+	outer := make(checkScope, len(f.scope)+len(f.outer))
+	for name, t := range f.outer {
+		outer[name] = t
+	}
+	for name, t := range f.scope {
+		outer[name] = t
+	}
+	return c.checkProgram(program, resolved, filepath.Dir(resolved), outer)
+}
+
+// checkInclude checks an included file in the includer's scope
+func (c *checker) checkInclude(f *checkedFile, node *parser.IncludeStatement) error {
+	resolved, program, err := c.readFile(f, node.Token, "include", node.Path.Value)
+	if err != nil || program == nil {
+		return err
+	}
+	if c.including[resolved] {
+		c.addProblem(f, node.Token.Line, node.Token.Column, "circular include detected: %s", resolved)
+		return nil
+	}
+	c.including[resolved] = true
+	defer delete(c.including, resolved)
+
+	included := &checkedFile{path: resolved, basePath: filepath.Dir(resolved), scope: f.scope, outer: f.outer}
+	for _, stmt := range program.Statements {
+		if err := c.checkStatement(included, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
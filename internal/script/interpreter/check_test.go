@@ -0,0 +1,161 @@
+package interpreter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func checkProblems(t *testing.T, source string, origin *ScriptOrigin) []string {
+	t.Helper()
+	interp := New(nil)
+	defer interp.Close()
+
+	problems, err := interp.CheckString(source, origin)
+	if err != nil {
+		t.Fatalf("CheckString failed: %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	return got
+}
+
+func TestCheckString(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "valid script",
+			source: `
+model fast { provider: "openai", model: "gpt-4o-mini" }
+tool add(a, b) { return a + b }
+agent helper { model: fast, tools: [add, gsh.tools.exec] }
+items = [1, 2]
+for (item of items) {
+    total = add(total ?? 0, item)
+}
+try { throw "x" } catch (e) { print(e.message) }
+print(` + "`total: ${total}`" + `)
+`,
+		},
+		{
+			name:   "undefined variable",
+			source: "x = 1\nprint(x + y)",
+			want:   []string{"<input>:2:11: undefined variable: y"},
+		},
+		{
+			name:   "undefined variable in template literal",
+			source: "print(`hi ${name}`)",
+			want:   []string{"<input>:1:7: undefined variable: name"},
+		},
+		{
+			name:   "variable assigned later counts as defined",
+			source: "tool show() { print(message) }\nmessage = \"hi\"\nshow()",
+		},
+		{
+			name:   "unknown provider",
+			source: `model m { provider: "nope", model: "x" }`,
+			want:   []string{"<input>:1:21: model 'm' has unknown model provider: nope"},
+		},
+		{
+			name: "agent tools that aren't tools",
+			source: `model m { provider: "openai", model: "x" }
+agent a { model: m, tools: [m, gsh.tools.nope, "exec"] }`,
+			want: []string{
+				"<input>:2:29: agent 'a' has an invalid tool 'm' in 'tools': expected a tool, got model",
+				"<input>:2:42: agent 'a' has an invalid tool 'gsh.tools.nope' in 'tools': expected a tool, got null",
+				`<input>:2:48: agent 'a' has an invalid tool '"exec"' in 'tools': expected a tool, got string`,
+			},
+		},
+		{
+			name: "agent tools can hold arrays of tools",
+			source: `model m { provider: "openai", model: "x" }
+mcp fs { command: "fs-server" }
+tool t() { return 1 }
+group = [t]
+agent a { model: m, tools: [fs.*, group, [t]] }`,
+		},
+		{
+			name:   "agent without a model",
+			source: `agent a { tools: [] }`,
+			want:   []string{"<input>:1:7: agent 'a' must have a 'model' field"},
+		},
+		{
+			name: "agent model that isn't a model",
+			source: `tool t() { return 1 }
+agent a { model: t }`,
+			want: []string{"<input>:2:18: agent config 'model' must be a model reference, got tool"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkProblems(t, tt.source, nil)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected problems:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestCheckStringImports(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib.gsh":    "export tool helper() { return 1 }\ntool hidden() { return missing }\n",
+		"shared.gsh": "#!/usr/bin/env gsh\ngreeting = \"hi\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := `import { helper, hidden } from "./lib.gsh"
+include "./shared.gsh"
+include "./missing.gsh"
+print(helper(), greeting)`
+	got := checkProblems(t, source, &ScriptOrigin{Type: OriginFilesystem, BasePath: dir})
+
+	libPath := filepath.Join(dir, "lib.gsh")
+	want := []string{
+		`<input>:1:1: symbol "hidden" is not exported from "./lib.gsh"`,
+		`<input>:3:1: failed to read include "./missing.gsh"`,
+		libPath + ":2:24: undefined variable: missing",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(got), got)
+	}
+	for idx := range want {
+		if !strings.HasPrefix(got[idx], want[idx]) {
+			t.Errorf("problem %d: expected prefix %q, got %q", idx, want[idx], got[idx])
+		}
+	}
+}
+
+func TestCheckStringParseError(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err := interp.CheckString("x = (1 + \n", nil)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+}
+
+func TestCheckStringDoesNotRun(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	source := `exec("touch ` + marker + `")`
+	if got := checkProblems(t, source, nil); len(got) != 0 {
+		t.Fatalf("expected no problems, got %v", got)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("expected CheckString not to run the script")
+	}
+}
@@ -0,0 +1,74 @@
+package interpreter
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// chunkCoalescer batches streamed content so fast models don't trigger a render for
+// every token. Content is passed to emit at most once per interval; content that
+// arrives in between is buffered and sent by a timer, or by flush when the stream ends.
+// With a zero interval every chunk is passed straight through.
+type chunkCoalescer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	emit     func(content string)
+	buf      strings.Builder
+	last     time.Time
+	timer    *time.Timer
+}
+
+// newChunkCoalescer creates a coalescer that emits at most once per interval
+func newChunkCoalescer(interval time.Duration, emit func(content string)) *chunkCoalescer {
+	return &chunkCoalescer{interval: interval, emit: emit}
+}
+
+// add buffers content, emitting the buffer if the interval has passed since the last emit
+func (c *chunkCoalescer) add(content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.buf.WriteString(content)
+	elapsed := time.Since(c.last)
+	if elapsed >= c.interval {
+		c.flushLocked()
+		return
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.interval-elapsed, c.flush)
+	}
+}
+
+// flush emits any buffered content right away
+func (c *chunkCoalescer) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked()
+}
+
+// flushLocked emits buffered content. The caller must hold c.mu, which also keeps
+// emits from overlapping when the timer fires during add.
+func (c *chunkCoalescer) flushLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.buf.Len() == 0 {
+		return
+	}
+	content := c.buf.String()
+	c.buf.Reset()
+	c.last = time.Now()
+	c.emit(content)
+}
+
+// agentChunkInterval returns the minimum time between agent.chunk events, set by
+// gsh.agentChunkInterval in the REPL. Scripts emit every chunk.
+func (i *Interpreter) agentChunkInterval() time.Duration {
+	replCtx := i.sdkConfig.GetREPLContext()
+	if replCtx == nil {
+		return 0
+	}
+	return time.Duration(replCtx.AgentChunkInterval) * time.Millisecond
+}
@@ -0,0 +1,80 @@
+package interpreter
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordedChunks collects emitted chunks from the coalescer, which may emit from a timer goroutine
+type recordedChunks struct {
+	mu     sync.Mutex
+	chunks []string
+}
+
+func (r *recordedChunks) emit(content string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chunks = append(r.chunks, content)
+}
+
+func (r *recordedChunks) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.chunks...)
+}
+
+func assertChunks(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected chunks %q, got %q", want, got)
+	}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Fatalf("expected chunks %q, got %q", want, got)
+		}
+	}
+}
+
+func TestChunkCoalescerPassthrough(t *testing.T) {
+	rec := &recordedChunks{}
+	c := newChunkCoalescer(0, rec.emit)
+	c.add("a")
+	c.add("b")
+	c.flush()
+	assertChunks(t, rec.get(), "a", "b")
+}
+
+func TestChunkCoalescerBatches(t *testing.T) {
+	rec := &recordedChunks{}
+	c := newChunkCoalescer(time.Hour, rec.emit)
+
+	// The first chunk goes out immediately, later ones wait for the interval or a flush
+	c.add("Hello")
+	c.add(", ")
+	c.add("world")
+	assertChunks(t, rec.get(), "Hello")
+
+	c.flush()
+	assertChunks(t, rec.get(), "Hello", ", world")
+
+	// Nothing buffered, nothing emitted
+	c.flush()
+	assertChunks(t, rec.get(), "Hello", ", world")
+}
+
+func TestChunkCoalescerTimerFlush(t *testing.T) {
+	rec := &recordedChunks{}
+	c := newChunkCoalescer(20*time.Millisecond, rec.emit)
+
+	c.add("a")
+	c.add("b")
+	assertChunks(t, rec.get(), "a")
+
+	// Buffered content is sent once the interval passes, even without more chunks
+	deadline := time.Now().Add(2 * time.Second)
+	for len(rec.get()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assertChunks(t, rec.get(), "a", "b")
+}
@@ -210,6 +210,162 @@ func TestWhileLoop(t *testing.T) {
 	}
 }
 
+// TestSwitchStatement tests switch statements
+func TestSwitchStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		varName  string
+		expected string
+	}{
+		{
+			name: "matching string case",
+			input: `
+				result = ""
+				switch ("b") {
+					case "a":
+						result = "first"
+					case "b":
+						result = "second"
+				}
+			`,
+			varName:  "result",
+			expected: "second",
+		},
+		{
+			name: "multiple values in one case",
+			input: `
+				result = ""
+				switch (3) {
+					case 1, 2:
+						result = "low"
+					case 3, 4:
+						result = "high"
+				}
+			`,
+			varName:  "result",
+			expected: "high",
+		},
+		{
+			name: "default when nothing matches",
+			input: `
+				result = ""
+				switch ("z") {
+					case "a":
+						result = "a"
+					default:
+						result = "other"
+				}
+			`,
+			varName:  "result",
+			expected: "other",
+		},
+		{
+			name: "no match and no default",
+			input: `
+				result = "unchanged"
+				switch (5) {
+					case 1:
+						result = "one"
+				}
+			`,
+			varName:  "result",
+			expected: "unchanged",
+		},
+		{
+			name: "no fallthrough into later cases",
+			input: `
+				count = 0
+				switch (1) {
+					case 1:
+						count = count + 1
+					case 2:
+						count = count + 10
+					default:
+						count = count + 100
+				}
+			`,
+			varName:  "count",
+			expected: "1",
+		},
+		{
+			name: "break leaves the switch",
+			input: `
+				result = "before"
+				switch (1) {
+					case 1:
+						if (true) {
+							break
+						}
+						result = "after"
+				}
+			`,
+			varName:  "result",
+			expected: "before",
+		},
+		{
+			name: "continue inside switch continues the loop",
+			input: `
+				sum = 0
+				for (n of [1, 2, 3, 4]) {
+					switch (n % 2) {
+						case 0:
+							continue
+					}
+					sum = sum + n
+				}
+			`,
+			varName:  "sum",
+			expected: "4",
+		},
+		{
+			name: "case values can be expressions",
+			input: `
+				limit = 10
+				result = ""
+				switch (10) {
+					case limit - 1:
+						result = "below"
+					case limit:
+						result = "at"
+				}
+			`,
+			varName:  "result",
+			expected: "at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := testEvalFull(t, tt.input)
+			vars := result.Variables()
+			varValue := vars[tt.varName]
+			if varValue.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, varValue.String())
+			}
+		})
+	}
+}
+
+// TestSwitchReturn tests that return inside a switch case leaves the enclosing tool
+func TestSwitchReturn(t *testing.T) {
+	result := testEvalFull(t, `
+		tool describe(n) {
+			switch (n) {
+				case 1:
+					return "one"
+			}
+			return "many"
+		}
+		a = describe(1)
+		b = describe(2)
+	`)
+	vars := result.Variables()
+	if vars["a"].String() != "one" || vars["b"].String() != "many" {
+		t.Errorf("expected one/many, got %q/%q", vars["a"].String(), vars["b"].String())
+	}
+}
+
 // TestForOfLoop tests for-of loops
 func TestForOfLoop(t *testing.T) {
 	tests := []struct {
@@ -275,6 +431,83 @@ func TestForOfLoop(t *testing.T) {
 			varName:  "count",
 			expected: "6",
 		},
+		{
+			name: "for-of with object keys and values in sorted order",
+			input: `
+				result = ""
+				for (key, value of {b: 2, a: 1, c: 3}) {
+					result = result + key + value
+				}
+			`,
+			varName:  "result",
+			expected: "a1b2c3",
+		},
+		{
+			name: "for-of with Map keys and values",
+			input: `
+				result = ""
+				for (key, value of Map([["y", 2], ["x", 1]])) {
+					result = result + key + value
+				}
+			`,
+			varName:  "result",
+			expected: "x1y2",
+		},
+		{
+			name: "for-of with array index and element",
+			input: `
+				result = ""
+				for (idx, val of ["a", "b"]) {
+					result = result + idx + val
+				}
+			`,
+			varName:  "result",
+			expected: "0a1b",
+		},
+		{
+			name: "key-value for-of with break and continue",
+			input: `
+				result = ""
+				for (key, value of {a: 1, b: 2, c: 3, d: 4}) {
+					if (key == "b") {
+						continue
+					}
+					if (key == "d") {
+						break
+					}
+					result = result + key
+				}
+			`,
+			varName:  "result",
+			expected: "ac",
+		},
+		{
+			name: "pushing to an array during for-of does not extend the loop",
+			input: `
+				arr = [1, 2, 3]
+				count = 0
+				for (val of arr) {
+					arr.push(val)
+					count = count + 1
+				}
+			`,
+			varName:  "count",
+			expected: "3",
+		},
+		{
+			name: "Map entries deleted during for-of are skipped",
+			input: `
+				m = Map([["a", 1], ["b", 2], ["c", 3]])
+				result = ""
+				for (key, value of m) {
+					m.delete("b")
+					m.set("z", 26)
+					result = result + key
+				}
+			`,
+			varName:  "result",
+			expected: "ac",
+		},
 		{
 			name: "for-of with array expressions",
 			input: `
@@ -643,6 +876,10 @@ func TestForOfWithNonIterable(t *testing.T) {
 			name:  "for-of with object",
 			input: `for (x of {a: 1}) { x = x + 1 }`,
 		},
+		{
+			name:  "key-value for-of with string",
+			input: `for (k, v of "abc") { k = v }`,
+		},
 	}
 
 	for _, tt := range tests {
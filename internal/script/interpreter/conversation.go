@@ -3,6 +3,7 @@ package interpreter
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/kunchenguid/gsh/internal/acp"
 	"github.com/kunchenguid/gsh/internal/script/parser"
@@ -23,6 +24,14 @@ type AgentCallbacks struct {
 	// Aligned with ACP's session/update message_chunk notifications.
 	OnChunk func(content string)
 
+	// OnUsage is called with incremental token usage as the provider streams it.
+	// Only called when Streaming is true.
+	OnUsage func(delta *ChatUsage)
+
+	// OnStreamEnd is called when a streamed model response has been fully received,
+	// before any requested tools are executed. Only called when Streaming is true.
+	OnStreamEnd func()
+
 	// OnToolPending is called when a tool call enters pending state (starts streaming from LLM).
 	// At this point, we know the tool name but arguments may be incomplete/empty.
 	// This allows showing a "pending" state to the user while arguments stream in.
@@ -67,7 +76,8 @@ type AgentCallbacks struct {
 }
 
 // evalPipeExpression evaluates a pipe expression
-// Handles: String | Agent, Conversation | String, Conversation | Agent
+// Handles: String | Agent, Conversation | String, Conversation | Agent, plus
+// File and Image values in place of a String
 func (i *Interpreter) evalPipeExpression(env *Environment, node *parser.PipeExpression) (Value, error) {
 	// Evaluate left side
 	left, err := i.evalExpression(env, node.Left)
@@ -92,6 +102,27 @@ func (i *Interpreter) evalPipeExpression(env *Environment, node *parser.PipeExpr
 		return i.executeAgentWithString(strVal.Value, agentVal)
 	}
 
+	// Case 1b: File | Agent -> Create conversation from the file contents and execute
+	if leftType == ValueTypeFile && rightType == ValueTypeAgent {
+		content, err := readFileForPipe(left.(*FileValue))
+		if err != nil {
+			return nil, err
+		}
+		return i.executeAgentWithString(content, right.(*AgentValue))
+	}
+
+	// Case 1c: Image | Agent -> Create conversation with the image attached and execute
+	if leftType == ValueTypeImage && rightType == ValueTypeAgent {
+		image, err := readImageForPipe(left.(*ImageValue))
+		if err != nil {
+			return nil, err
+		}
+		conv := &ConversationValue{
+			Messages: []ChatMessage{imageMessage(image)},
+		}
+		return i.executeAgentWithConversation(conv, right.(*AgentValue))
+	}
+
 	// Case 2: Conversation | String -> Add user message
 	if leftType == ValueTypeConversation && rightType == ValueTypeString {
 		convVal := left.(*ConversationValue)
@@ -99,6 +130,21 @@ func (i *Interpreter) evalPipeExpression(env *Environment, node *parser.PipeExpr
 		return i.addMessageToConversation(convVal, strVal.Value)
 	}
 
+	// Case 2b: Conversation | Image -> Add user message with the image attached
+	if leftType == ValueTypeConversation && rightType == ValueTypeImage {
+		image, err := readImageForPipe(right.(*ImageValue))
+		if err != nil {
+			return nil, err
+		}
+		convVal := left.(*ConversationValue)
+		newConv := &ConversationValue{
+			Messages: make([]ChatMessage, len(convVal.Messages), len(convVal.Messages)+1),
+		}
+		copy(newConv.Messages, convVal.Messages)
+		newConv.Messages = append(newConv.Messages, imageMessage(image))
+		return newConv, nil
+	}
+
 	// Case 3: Conversation | Agent -> Execute agent with conversation context
 	if leftType == ValueTypeConversation && rightType == ValueTypeAgent {
 		convVal := left.(*ConversationValue)
@@ -113,6 +159,15 @@ func (i *Interpreter) evalPipeExpression(env *Environment, node *parser.PipeExpr
 		return i.executeACPWithString(strVal.Value, acpVal)
 	}
 
+	// Case 4b: File | ACP -> Create ACP session with the file contents as the prompt
+	if leftType == ValueTypeFile && rightType == ValueTypeACP {
+		content, err := readFileForPipe(left.(*FileValue))
+		if err != nil {
+			return nil, err
+		}
+		return i.executeACPWithString(content, right.(*ACPValue))
+	}
+
 	// Case 5: ACPSession | String -> Send prompt to existing session (auto-executes)
 	if leftType == ValueTypeACPSession && rightType == ValueTypeString {
 		sessionVal := left.(*ACPSessionValue)
@@ -149,8 +204,9 @@ func (i *Interpreter) executeAgentWithString(message string, agent *AgentValue)
 	conv := &ConversationValue{
 		Messages: []ChatMessage{
 			{
-				Role:    "user",
-				Content: message,
+				Role:      "user",
+				Content:   message,
+				Timestamp: time.Now(),
 			},
 		},
 	}
@@ -169,13 +225,23 @@ func (i *Interpreter) addMessageToConversation(conv *ConversationValue, message
 
 	// Add user message
 	newConv.Messages = append(newConv.Messages, ChatMessage{
-		Role:    "user",
-		Content: message,
+		Role:      "user",
+		Content:   message,
+		Timestamp: time.Now(),
 	})
 
 	return newConv, nil
 }
 
+// imageMessage creates a user message that carries only an image
+func imageMessage(image ChatImage) ChatMessage {
+	return ChatMessage{
+		Role:      "user",
+		Images:    []ChatImage{image},
+		Timestamp: time.Now(),
+	}
+}
+
 // executeAgentWithConversation executes an agent with an existing conversation
 func (i *Interpreter) executeAgentWithConversation(conv *ConversationValue, agent *AgentValue) (Value, error) {
 	// Prepare messages for the agent, injecting system prompt at the beginning
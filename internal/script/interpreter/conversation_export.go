@@ -0,0 +1,211 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// getConversationProperty returns conversation properties and methods:
+// - messages / lastMessage - see ConversationValue.GetProperty
+// - toMarkdown(options?) - renders the conversation as a Markdown transcript
+// - export(path, options?) - writes the Markdown transcript to a file
+// - copy() - returns a deep copy, e.g. to keep a checkpoint of the conversation
+//
+// Both methods accept { includeSystem: bool, systemPrompt: string }. System messages are
+// omitted by default. Conversations do not store the agent's system prompt, so callers can
+// pass it as systemPrompt to have it rendered first when includeSystem is true.
+func (i *Interpreter) getConversationProperty(conv *ConversationValue, property string) (Value, error) {
+	switch property {
+	case "toMarkdown":
+		return &BuiltinValue{
+			Name: "toMarkdown",
+			Fn: func(args []Value) (Value, error) {
+				if len(args) > 1 {
+					return nil, fmt.Errorf("toMarkdown() takes at most 1 argument (options?: object), got %d", len(args))
+				}
+				opts, err := parseExportOptions("toMarkdown", args, 0)
+				if err != nil {
+					return nil, err
+				}
+				return &StringValue{Value: opts.render(conv)}, nil
+			},
+		}, nil
+	case "export":
+		return &BuiltinValue{
+			Name: "export",
+			Fn: func(args []Value) (Value, error) {
+				return i.exportConversation(conv, args)
+			},
+		}, nil
+	case "copy":
+		return &BuiltinValue{
+			Name: "copy",
+			Fn: func(args []Value) (Value, error) {
+				if len(args) != 0 {
+					return nil, fmt.Errorf("copy() takes no arguments, got %d", len(args))
+				}
+				return conv.DeepCopy(), nil
+			},
+		}, nil
+	default:
+		return conv.GetProperty(property), nil
+	}
+}
+
+// exportConversation implements conversation.export(path, options?).
+// Relative paths resolve against the shell's current directory. Returns the absolute path written.
+func (i *Interpreter) exportConversation(conv *ConversationValue, args []Value) (Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("export() takes 1 or 2 arguments (path: string, options?: object), got %d", len(args))
+	}
+	pathVal, ok := args[0].(*StringValue)
+	if !ok || pathVal.Value == "" {
+		return nil, fmt.Errorf("export() path must be a non-empty string, got %s", args[0].Type())
+	}
+	opts, err := parseExportOptions("export", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	path := pathVal.Value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(i.GetWorkingDir(), path)
+	}
+
+	if err := os.WriteFile(path, []byte(opts.render(conv)), 0644); err != nil {
+		return nil, fmt.Errorf("export() failed to write %s: %w", path, err)
+	}
+	return &StringValue{Value: path}, nil
+}
+
+// exportOptions holds the options accepted by toMarkdown() and export()
+type exportOptions struct {
+	includeSystem bool
+	systemPrompt  string
+}
+
+// render renders conv as Markdown, prepending systemPrompt when system messages are included
+func (o exportOptions) render(conv *ConversationValue) string {
+	if o.includeSystem && o.systemPrompt != "" {
+		conv = &ConversationValue{
+			Messages: append([]ChatMessage{{Role: "system", Content: o.systemPrompt}}, conv.Messages...),
+		}
+	}
+	return conv.ToMarkdown(o.includeSystem)
+}
+
+// parseExportOptions reads the optional options object at args[index]
+func parseExportOptions(method string, args []Value, index int) (exportOptions, error) {
+	var opts exportOptions
+	if len(args) <= index {
+		return opts, nil
+	}
+	obj, ok := args[index].(*ObjectValue)
+	if !ok {
+		return opts, fmt.Errorf("%s() options must be an object, got %s", method, args[index].Type())
+	}
+
+	switch v := obj.GetPropertyValue("includeSystem").(type) {
+	case *NullValue:
+	case *BoolValue:
+		opts.includeSystem = v.Value
+	default:
+		return opts, fmt.Errorf("%s() option 'includeSystem' must be a boolean, got %s", method, v.Type())
+	}
+
+	switch v := obj.GetPropertyValue("systemPrompt").(type) {
+	case *NullValue:
+	case *StringValue:
+		opts.systemPrompt = v.Value
+	default:
+		return opts, fmt.Errorf("%s() option 'systemPrompt' must be a string, got %s", method, v.Type())
+	}
+
+	return opts, nil
+}
+
+// ToMarkdown renders the conversation as a Markdown transcript with a header per message,
+// tool calls and tool results as fenced code blocks, and message timestamps when known.
+// System messages are omitted unless includeSystem is true.
+func (c *ConversationValue) ToMarkdown(includeSystem bool) string {
+	var sb strings.Builder
+	sb.WriteString("# Conversation\n")
+
+	for _, msg := range c.Messages {
+		if msg.Role == "system" && !includeSystem {
+			continue
+		}
+
+		sb.WriteString("\n## ")
+		sb.WriteString(markdownRoleHeader(msg))
+		if !msg.Timestamp.IsZero() {
+			sb.WriteString(" · ")
+			sb.WriteString(msg.Timestamp.Format(time.DateTime))
+		}
+		sb.WriteString("\n\n")
+
+		if msg.Role == "tool" {
+			writeFencedBlock(&sb, "", msg.Content)
+			continue
+		}
+
+		if content := strings.TrimSpace(msg.Content); content != "" {
+			sb.WriteString(content)
+			sb.WriteString("\n\n")
+		}
+
+		for _, image := range msg.Images {
+			fmt.Fprintf(&sb, "_Image attached (%s)_\n\n", image.MediaType)
+		}
+
+		for _, tc := range msg.ToolCalls {
+			fmt.Fprintf(&sb, "Tool call: `%s`\n\n", tc.Name)
+			args, err := json.MarshalIndent(tc.Arguments, "", "  ")
+			if err != nil {
+				args = []byte(fmt.Sprintf("%v", tc.Arguments))
+			}
+			writeFencedBlock(&sb, "json", string(args))
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// markdownRoleHeader returns the section header for a message
+func markdownRoleHeader(msg ChatMessage) string {
+	switch msg.Role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "system":
+		return "System"
+	case "tool":
+		if msg.Name != "" {
+			return fmt.Sprintf("Tool result: `%s`", msg.Name)
+		}
+		return "Tool result"
+	default:
+		return msg.Role
+	}
+}
+
+// writeFencedBlock writes content as a fenced code block, using a fence longer than
+// any backtick run inside the content so the block cannot be closed early
+func writeFencedBlock(sb *strings.Builder, lang, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	sb.WriteString(fence)
+	sb.WriteString(lang)
+	sb.WriteString("\n")
+	sb.WriteString(strings.TrimRight(content, "\n"))
+	sb.WriteString("\n")
+	sb.WriteString(fence)
+	sb.WriteString("\n\n")
+}
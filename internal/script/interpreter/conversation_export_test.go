@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExportTestConversation() *ConversationValue {
+	ts := time.Date(2025, 3, 14, 9, 26, 53, 0, time.Local)
+	return &ConversationValue{
+		Messages: []ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "user", Content: "How big is the repo?", Timestamp: ts},
+			{
+				Role:      "assistant",
+				ToolCalls: []ChatToolCall{{ID: "call_1", Name: "exec", Arguments: map[string]interface{}{"command": "du -sh ."}}},
+				Timestamp: ts,
+			},
+			{Role: "tool", Name: "exec", ToolCallID: "call_1", Content: "12M\t.\n", Timestamp: ts},
+			{Role: "assistant", Content: "About 12 MB.", Timestamp: ts},
+		},
+	}
+}
+
+func TestConversationToMarkdown(t *testing.T) {
+	md := newExportTestConversation().ToMarkdown(false)
+
+	expected := []string{
+		"# Conversation\n",
+		"## User · 2025-03-14 09:26:53\n\nHow big is the repo?\n",
+		"Tool call: `exec`\n\n```json\n{\n  \"command\": \"du -sh .\"\n}\n```\n",
+		"## Tool result: `exec` · 2025-03-14 09:26:53\n\n```\n12M\t.\n```\n",
+		"## Assistant · 2025-03-14 09:26:53\n\nAbout 12 MB.\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\ngot:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "You are terse.") {
+		t.Errorf("system messages should be omitted by default, got:\n%s", md)
+	}
+
+	withSystem := newExportTestConversation().ToMarkdown(true)
+	if !strings.Contains(withSystem, "## System\n\nYou are terse.\n") {
+		t.Errorf("expected system message when includeSystem is true, got:\n%s", withSystem)
+	}
+}
+
+func TestConversationToMarkdown_FenceEscaping(t *testing.T) {
+	conv := &ConversationValue{
+		Messages: []ChatMessage{
+			{Role: "tool", Name: "view_file", Content: "```go\nfmt.Println()\n```"},
+		},
+	}
+	md := conv.ToMarkdown(false)
+	if !strings.Contains(md, "````\n```go\nfmt.Println()\n```\n````\n") {
+		t.Errorf("expected a longer fence around content containing backticks, got:\n%s", md)
+	}
+}
+
+func TestConversationExportMethods(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	interp := New(nil)
+	defer interp.Close()
+	interp.runner.Dir = tmpDir
+	interp.globalEnv.Set("conv", newExportTestConversation())
+
+	result, err := interp.EvalString(`
+md = conv.toMarkdown({ includeSystem: true, systemPrompt: "Agent prompt" })
+path = conv.export("chat.md")
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	md, _ := result.Env.Get("md")
+	if !strings.Contains(md.String(), "## System\n\nAgent prompt\n") {
+		t.Errorf("expected systemPrompt option to be rendered, got:\n%s", md.String())
+	}
+
+	path, _ := result.Env.Get("path")
+	if path.String() != filepath.Join(tmpDir, "chat.md") {
+		t.Errorf("expected export to resolve against the working directory, got %s", path.String())
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "chat.md"))
+	if err != nil {
+		t.Fatalf("failed to read exported file: %v", err)
+	}
+	if !strings.Contains(string(content), "About 12 MB.") || strings.Contains(string(content), "You are terse.") {
+		t.Errorf("unexpected exported content:\n%s", content)
+	}
+
+	_, err = interp.EvalString(`conv.export("chat.md", { includeSystem: "yes" })`, nil)
+	if err == nil || !strings.Contains(err.Error(), "'includeSystem' must be a boolean") {
+		t.Errorf("expected includeSystem type error, got %v", err)
+	}
+}
+
+func TestConversationCopy(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+	original := newExportTestConversation()
+	interp.globalEnv.Set("conv", original)
+
+	result, err := interp.EvalString(`snapshot = conv.copy()`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, _ := result.Env.Get("snapshot")
+	snapshot, ok := value.(*ConversationValue)
+	if !ok {
+		t.Fatalf("expected copy() to return a conversation, got %T", value)
+	}
+	if snapshot == original || len(snapshot.Messages) != len(original.Messages) {
+		t.Fatalf("expected a separate conversation with %d messages, got %d", len(original.Messages), len(snapshot.Messages))
+	}
+
+	// Changes to the original must not reach the copy
+	original.Messages[1].Content = "changed"
+	original.Messages[2].ToolCalls[0].Arguments["command"] = "rm -rf ."
+	original.Messages = append(original.Messages, ChatMessage{Role: "user", Content: "more"})
+	if snapshot.Messages[1].Content != "How big is the repo?" {
+		t.Errorf("message content leaked into the copy: %q", snapshot.Messages[1].Content)
+	}
+	if snapshot.Messages[2].ToolCalls[0].Arguments["command"] != "du -sh ." {
+		t.Errorf("tool call arguments leaked into the copy: %v", snapshot.Messages[2].ToolCalls[0].Arguments)
+	}
+	if len(snapshot.Messages) != 5 {
+		t.Errorf("expected the copy to keep 5 messages, got %d", len(snapshot.Messages))
+	}
+
+	if _, err := interp.EvalString(`conv.copy(1)`, nil); err == nil {
+		t.Error("expected an error when copy() gets arguments")
+	}
+}
@@ -2,6 +2,8 @@ package interpreter
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -695,6 +697,193 @@ conv = "Do something infinite" | TestAgent
 	}
 }
 
+// TestAgenticLoopIterationLimitEvents tests the warning before the limit and that a
+// limit handler can let the agent continue past it
+func TestAgenticLoopIterationLimitEvents(t *testing.T) {
+	tests := []struct {
+		maxIterations int
+		wantWarnings  string
+		wantLimits    string
+	}{
+		{5, `["5/5:1", "10/5:1"]`, "[5, 10]"},
+		// Limits under 5 still warn on the last iteration
+		{3, `["3/3:1", "6/3:1"]`, "[3, 6]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("maxIterations %d", tt.maxIterations), func(t *testing.T) {
+			mock := &infiniteToolCallMockProvider{}
+
+			interp := New(nil)
+			interp.providerRegistry.Register(mock)
+
+			input := fmt.Sprintf(`
+model testModel {
+	provider: "infinite-mock",
+	model: "test"
+}
+
+tool infiniteTool(): string {
+	return "result"
+}
+
+agent TestAgent {
+	model: testModel,
+	tools: [infiniteTool],
+	maxIterations: %d
+}
+
+warnings = []
+tool onWarning(ctx, next) {
+	warnings.push(ctx.iteration + "/" + ctx.maxIterations + ":" + ctx.remaining)
+	return next(ctx)
+}
+gsh.use("agent.iteration.warning", onWarning)
+
+limits = []
+tool onLimit(ctx, next) {
+	limits.push(ctx.iterations)
+	if (limits.length == 1) {
+		return { continue: true }
+	}
+	return next(ctx)
+}
+gsh.use("agent.iteration.limit", onLimit)
+
+conv = "Do something infinite" | TestAgent
+`, tt.maxIterations)
+
+			l := lexer.New(input)
+			p := parser.New(l)
+			program := p.ParseProgram()
+
+			if len(p.Errors()) > 0 {
+				t.Fatalf("Parser errors: %v", p.Errors())
+			}
+
+			// Continuing once grants another maxIterations
+			total := 2 * tt.maxIterations
+			_, err := interp.Eval(program)
+			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("maximum iterations (%d)", total)) {
+				t.Fatalf("Expected max iterations error after continuing once, got: %v", err)
+			}
+
+			if mock.callCount != total {
+				t.Errorf("Expected %d provider calls, got %d", total, mock.callCount)
+			}
+
+			warnings, _ := interp.globalEnv.Get("warnings")
+			if got := warnings.String(); got != tt.wantWarnings {
+				t.Errorf("Expected a warning before each limit, got %s", got)
+			}
+			limits, _ := interp.globalEnv.Get("limits")
+			if got := limits.String(); got != tt.wantLimits {
+				t.Errorf("Expected limit events at %s iterations, got %s", tt.wantLimits, got)
+			}
+		})
+	}
+}
+
+// TestAgenticLoopMaxToolResultChars tests that oversized tool results are truncated
+// before they are added to the conversation
+func TestAgenticLoopMaxToolResultChars(t *testing.T) {
+	mock := &chainedToolCallMockProvider{}
+
+	interp := New(nil)
+	interp.providerRegistry.Register(mock)
+
+	input := `
+model testModel {
+	provider: "chained-mock",
+	model: "test"
+}
+
+tool step1(input: string): string {
+	return "x".repeat(500)
+}
+
+tool step2(input: string): string {
+	return "short"
+}
+
+tool step3(input: string): string {
+	return "done"
+}
+
+agent TestAgent {
+	model: testModel,
+	tools: [step1, step2, step3],
+	maxToolResultChars: 100
+}
+
+conv = "Run the steps" | TestAgent
+conv
+`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Parser errors: %v", p.Errors())
+	}
+
+	result, err := interp.Eval(program)
+	if err != nil {
+		t.Fatalf("Interpreter error: %v", err)
+	}
+	conv, ok := result.Value().(*ConversationValue)
+	if !ok {
+		t.Fatalf("Expected ConversationValue, got %T", result.Value())
+	}
+
+	results := map[string]string{}
+	for _, msg := range conv.Messages {
+		if msg.Role == "tool" {
+			results[msg.Name] = msg.Content
+		}
+	}
+
+	truncated := results["step1"]
+	// Tool results are JSON encoded, so the string result gains its quotes
+	if !strings.HasPrefix(truncated, `"`+strings.Repeat("x", 99)+"\n\n[gsh: tool result truncated, showing the first 100 of 502 characters") {
+		t.Errorf("expected step1 result to be truncated, got %q", truncated)
+	}
+	if idx := strings.Index(truncated, "saved to "); idx >= 0 {
+		path := strings.TrimSuffix(truncated[idx+len("saved to "):], "]")
+		defer os.Remove(path)
+		full, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read saved tool result: %v", err)
+		}
+		if len(full) != 502 {
+			t.Errorf("expected saved result of 502 characters, got %d", len(full))
+		}
+	} else {
+		t.Errorf("expected truncation marker to mention the saved file, got %q", truncated)
+	}
+
+	if results["step2"] != `"short"` {
+		t.Errorf("expected step2 result to be untouched, got %q", results["step2"])
+	}
+}
+
+func TestLimitToolResult(t *testing.T) {
+	if got := limitToolResult("hello", 0); got != "hello" {
+		t.Errorf("expected no limit for 0, got %q", got)
+	}
+	if got := limitToolResult("hello", 5); got != "hello" {
+		t.Errorf("expected result at the limit to be untouched, got %q", got)
+	}
+
+	got := limitToolResult("héllo wörld", 4)
+	if !strings.HasPrefix(got, "héll\n\n[gsh: tool result truncated, showing the first 4 of 11 characters") {
+		t.Errorf("expected truncation at a character boundary, got %q", got)
+	}
+	if idx := strings.Index(got, "saved to "); idx >= 0 {
+		os.Remove(strings.TrimSuffix(got[idx+len("saved to "):], "]"))
+	}
+}
+
 // chainedToolCallMockProvider returns tool calls in sequence: step1 -> step2 -> step3 -> done
 type chainedToolCallMockProvider struct {
 	callCount int
@@ -0,0 +1,77 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/kunchenguid/gsh/internal/script/parser"
+)
+
+// deferredCall is a call registered with defer. Its callee and arguments are
+// evaluated when the defer statement runs, so later reassignments don't affect it.
+type deferredCall struct {
+	function Value
+	args     []Value
+	node     *parser.CallExpression
+}
+
+// evalDeferStatement evaluates the callee and arguments of a deferred call and
+// registers it with the nearest tool or script scope
+func (i *Interpreter) evalDeferStatement(env *Environment, node *parser.DeferStatement) (Value, error) {
+	scope := env
+	for scope != nil && !scope.deferScope {
+		scope = scope.outer
+	}
+	if scope == nil {
+		return nil, fmt.Errorf("defer statement outside of tool or script (line %d, column %d)",
+			node.Token.Line, node.Token.Column)
+	}
+
+	function, err := i.evalExpression(env, node.Call.Function)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]Value, len(node.Call.Arguments))
+	for idx, argExpr := range node.Call.Arguments {
+		val, err := i.evalExpression(env, argExpr)
+		if err != nil {
+			return nil, err
+		}
+		args[idx] = val
+	}
+
+	scope.deferred = append(scope.deferred, &deferredCall{function: function, args: args, node: node.Call})
+	return &NullValue{}, nil
+}
+
+// runDeferred runs the calls deferred in scope, most recent first, and clears them.
+// Every call runs even if an earlier one fails. The first failure is returned
+// unless err is already set, in which case err is returned unchanged.
+func (i *Interpreter) runDeferred(scope *Environment, err error) error {
+	deferred := scope.deferred
+	scope.deferred = nil
+
+	for idx := len(deferred) - 1; idx >= 0; idx-- {
+		if _, callErr := i.callDeferred(deferred[idx]); callErr != nil && err == nil {
+			err = callErr
+		}
+	}
+	return err
+}
+
+// callDeferred calls an already evaluated callee by binding it and its arguments
+// in a private scope, so the call goes through the same dispatch as any other call
+func (i *Interpreter) callDeferred(call *deferredCall) (Value, error) {
+	callEnv := NewEnvironment()
+	callEnv.Set("defer#function", call.function)
+	expr := &parser.CallExpression{
+		Token:     call.node.Token,
+		Function:  &parser.Identifier{Token: call.node.Token, Value: "defer#function"},
+		Arguments: make([]parser.Expression, len(call.args)),
+	}
+	for idx, arg := range call.args {
+		name := fmt.Sprintf("defer#arg%d", idx)
+		callEnv.Set(name, arg)
+		expr.Arguments[idx] = &parser.Identifier{Token: call.node.Token, Value: name}
+	}
+	return i.evalCallExpression(callEnv, expr)
+}
@@ -0,0 +1,161 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kunchenguid/gsh/internal/acp"
+)
+
+func TestDeferRunsWhenToolReturns(t *testing.T) {
+	input := `
+events = []
+tool work() {
+	defer events.push("first deferred")
+	defer events.push("second deferred")
+	events.push("body")
+	return "done"
+}
+result = work()
+events.push("after")
+`
+	res, err := parseAndEvalThrow(t, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vars := res.Variables()
+	if got := vars["result"].String(); got != "done" {
+		t.Errorf("expected the tool's return value, got %s", got)
+	}
+	if got := vars["events"].String(); got != `["body", "second deferred", "first deferred", "after"]` {
+		t.Errorf("expected deferred calls to run in reverse order on return, got %s", got)
+	}
+}
+
+func TestDeferRunsWhenToolThrows(t *testing.T) {
+	input := `
+events = []
+tool work() {
+	defer events.push("cleanup")
+	throw "boom"
+}
+message = ""
+try {
+	work()
+} catch (e) {
+	message = e.message
+}
+`
+	res, err := parseAndEvalThrow(t, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vars := res.Variables()
+	if got := vars["events"].String(); got != `["cleanup"]` {
+		t.Errorf("expected the deferred call to run, got %s", got)
+	}
+	if got := vars["message"].String(); !strings.Contains(got, "boom") {
+		t.Errorf("expected the original error to be kept, got %s", got)
+	}
+}
+
+func TestDeferEvaluatesArgumentsImmediately(t *testing.T) {
+	input := `
+events = []
+tool work() {
+	for (name of ["a", "b"]) {
+		defer events.push(name)
+	}
+	name = "changed"
+}
+work()
+`
+	res, err := parseAndEvalThrow(t, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := res.Variables()["events"].String(); got != `["b", "a"]` {
+		t.Errorf("expected arguments captured at defer time, got %s", got)
+	}
+}
+
+func TestDeferAtTopLevel(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(`
+events = []
+defer events.push("cleanup")
+events.push("body")
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Variables()["events"].String(); got != `["body", "cleanup"]` {
+		t.Errorf("expected the deferred call to run when the script finishes, got %s", got)
+	}
+
+	_, err = interp.EvalString(`
+events = []
+defer events.push("cleanup")
+throw "boom"
+`, nil)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the script's error, got %v", err)
+	}
+	eventsVal, _ := interp.GlobalEnv().Get("events")
+	if got := eventsVal.String(); got != `["cleanup"]` {
+		t.Errorf("expected the deferred call to run when the script fails, got %s", got)
+	}
+}
+
+func TestDeferredCallError(t *testing.T) {
+	input := `
+tool fail() {
+	throw "cleanup failed"
+}
+tool work() {
+	defer fail()
+	return 1
+}
+work()
+`
+	_, err := parseAndEvalThrow(t, input)
+	if err == nil || !strings.Contains(err.Error(), "cleanup failed") {
+		t.Errorf("expected the deferred call's error, got %v", err)
+	}
+}
+
+func TestDeferClosesACPSession(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	mockSession := acp.NewMockSession("mock-session-1")
+	interp.InjectACPSession("MockAgent", "mock-session-1", mockSession)
+	session := &ACPSessionValue{
+		Agent:     &ACPValue{Name: "MockAgent", Config: map[string]Value{"command": &StringValue{Value: "mock"}}},
+		SessionID: "mock-session-1",
+		Messages:  []ChatMessage{},
+	}
+	interp.globalEnv.Set("session", session)
+
+	_, err := interp.EvalString(`
+tool finish(s) {
+	defer s.close()
+	throw "agent failed"
+}
+try {
+	finish(session)
+} catch (e) {
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !session.Closed {
+		t.Error("expected the session to be closed when the tool failed")
+	}
+}
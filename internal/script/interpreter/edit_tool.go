@@ -62,13 +62,14 @@ func ExecuteNativeEditFileTool(ctx context.Context, args map[string]interface{})
 		return fmt.Sprintf(`{"success": false, "error": %q}`, result.Message), nil
 	}
 
-	return fmt.Sprintf(`{"success": true, "message": %q}`, result.Message), nil
+	return fmt.Sprintf(`{"success": true, "message": %q, "diff": %q}`, result.Message, result.Diff), nil
 }
 
 // EditResult contains the result of an edit operation.
 type EditResult struct {
 	Success bool
 	Message string
+	Diff    string // unified diff of the change, set on success
 }
 
 // ExecuteEdit performs a find-and-replace edit on a file.
@@ -172,6 +173,7 @@ func ExecuteEdit(ctx context.Context, filePath, find, replace string, startLine,
 		newContent = strings.Replace(normalizedContent, find, replace, 1)
 	}
 
+	diff := unifiedDiff(filePath, normalizedContent, newContent)
 	if lineEnding != "\n" {
 		newContent = strings.ReplaceAll(newContent, "\n", lineEnding)
 	}
@@ -182,6 +184,7 @@ func ExecuteEdit(ctx context.Context, filePath, find, replace string, startLine,
 	}
 
 	err = os.WriteFile(absPath, []byte(newContent), info.Mode())
+	globalViewFileCache.invalidate(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
@@ -189,9 +192,75 @@ func ExecuteEdit(ctx context.Context, filePath, find, replace string, startLine,
 	return &EditResult{
 		Success: true,
 		Message: "edit applied successfully",
+		Diff:    diff,
 	}, nil
 }
 
+// diffContextLines is the number of unchanged lines shown around a change in a diff
+const diffContextLines = 3
+
+// unifiedDiff returns a unified diff between two versions of a file. An edit replaces a
+// single region, so the diff is one hunk covering everything between the common leading
+// and trailing lines. Both versions must use "\n" line endings.
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	oldLines := splitDiffLines(before)
+	newLines := splitDiffLines(after)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	start := max(prefix-diffContextLines, 0)
+	oldEnd := min(len(oldLines)-suffix+diffContextLines, len(oldLines))
+	newEnd := min(len(newLines)-suffix+diffContextLines, len(newLines))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+	fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(start, oldEnd-start), hunkRange(start, newEnd-start))
+	for _, line := range oldLines[start:prefix] {
+		out.WriteString(" " + line + "\n")
+	}
+	for _, line := range oldLines[prefix : len(oldLines)-suffix] {
+		out.WriteString("-" + line + "\n")
+	}
+	for _, line := range newLines[prefix : len(newLines)-suffix] {
+		out.WriteString("+" + line + "\n")
+	}
+	for _, line := range oldLines[len(oldLines)-suffix : oldEnd] {
+		out.WriteString(" " + line + "\n")
+	}
+	return out.String()
+}
+
+// splitDiffLines splits content into lines, ignoring the empty string after a final newline
+func splitDiffLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// hunkRange formats the 0-based start and line count of one side of a hunk header. An
+// empty range is given as the line before it, as diff does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
 const editFileToolName = "edit_file"
 const editFileToolDescription = "Perform a find-and-replace edit on a file. The find string must appear exactly once in the file (or within the specified line range). Use start_line and end_line to constrain the search to a specific range."
 
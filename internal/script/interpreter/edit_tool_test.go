@@ -2,8 +2,10 @@ package interpreter
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -246,4 +248,72 @@ func TestExecuteEdit(t *testing.T) {
 			t.Errorf("expected %q, got %q", expected, string(content))
 		}
 	})
+
+	t.Run("returns a unified diff", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "test.txt")
+		initialContent := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"
+		if err := os.WriteFile(filePath, []byte(initialContent), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+
+		result, err := ExecuteEdit(ctx, filePath, "five\n", "FIVE\n5\n", 0, 0)
+		if err != nil {
+			t.Fatalf("ExecuteEdit failed: %v", err)
+		}
+		expected := "--- " + filePath + "\n+++ " + filePath + "\n" +
+			"@@ -2,7 +2,8 @@\n two\n three\n four\n-five\n+FIVE\n+5\n six\n seven\n eight\n"
+		if result.Diff != expected {
+			t.Errorf("expected diff:\n%s\ngot:\n%s", expected, result.Diff)
+		}
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{"unchanged", "a\n", "a\n", ""},
+		{"change first line", "a\nb\n", "x\nb\n", "--- f\n+++ f\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n"},
+		{"pure insertion", "a\nb\n", "a\nnew\nb\n", "--- f\n+++ f\n@@ -1,2 +1,3 @@\n a\n+new\n b\n"},
+		{"pure deletion", "a\nb\nc\n", "a\nc\n", "--- f\n+++ f\n@@ -1,3 +1,2 @@\n a\n-b\n c\n"},
+		{"delete everything", "a\n", "", "--- f\n+++ f\n@@ -1 +0,0 @@\n-a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("f", tt.before, tt.after); got != tt.want {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExecuteNativeEditFileTool_Diff(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello world\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	output, err := ExecuteNativeEditFileTool(context.Background(), map[string]interface{}{
+		"file_path": filePath,
+		"find":      "world",
+		"replace":   "gsh",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteNativeEditFileTool failed: %v", err)
+	}
+
+	var parsed struct {
+		Success bool   `json:"success"`
+		Diff    string `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", output, err)
+	}
+	if !parsed.Success || !strings.Contains(parsed.Diff, "-hello world\n+hello gsh\n") {
+		t.Errorf("expected a diff of the edit, got %q", output)
+	}
 }
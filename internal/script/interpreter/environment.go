@@ -5,8 +5,12 @@ import "fmt"
 // Environment represents a scope for variable bindings
 type Environment struct {
 	store    map[string]Value
-	outer    *Environment // parent scope for nested scopes
-	isolated bool         // if true, Update() won't propagate to parent scopes (for tool isolation)
+	consts   map[string]bool // names in store declared with const
+	outer    *Environment    // parent scope for nested scopes
+	isolated bool            // if true, Update() won't propagate to parent scopes (for tool isolation)
+
+	deferScope bool            // if true, defer statements in this scope and its blocks register here
+	deferred   []*deferredCall // calls to run when a deferScope exits
 }
 
 // NewEnvironment creates a new environment
@@ -49,10 +53,39 @@ func (e *Environment) Set(name string, value Value) {
 	e.store[name] = value
 }
 
+// SetConst defines an immutable binding in the current scope
+// It returns an error if the name is already defined in the current scope
+func (e *Environment) SetConst(name string, value Value) error {
+	if _, ok := e.store[name]; ok {
+		return fmt.Errorf("cannot declare constant '%s': it is already defined in this scope", name)
+	}
+	if e.consts == nil {
+		e.consts = make(map[string]bool)
+	}
+	e.store[name] = value
+	e.consts[name] = true
+	return nil
+}
+
+// IsConst reports whether name resolves to a binding declared with const
+func (e *Environment) IsConst(name string) bool {
+	if _, ok := e.store[name]; ok {
+		return e.consts[name]
+	}
+	if e.outer != nil {
+		return e.outer.IsConst(name)
+	}
+	return false
+}
+
 // Update updates an existing variable's value
-// It returns an error if the variable doesn't exist
+// It returns an error if the variable doesn't exist or is a constant
 // For isolated environments (tool scopes), updates to parent variables create local shadows
 func (e *Environment) Update(name string, value Value) error {
+	if e.IsConst(name) {
+		return fmt.Errorf("cannot reassign constant '%s'", name)
+	}
+
 	// Check current scope
 	if _, ok := e.store[name]; ok {
 		e.store[name] = value
@@ -93,6 +126,7 @@ func (e *Environment) HasLocal(name string) bool {
 func (e *Environment) Delete(name string) bool {
 	if _, ok := e.store[name]; ok {
 		delete(e.store, name)
+		delete(e.consts, name)
 		return true
 	}
 	return false
@@ -140,5 +174,11 @@ func (e *Environment) Clone() *Environment {
 	for k, v := range e.store {
 		newEnv.store[k] = v
 	}
+	for k := range e.consts {
+		if newEnv.consts == nil {
+			newEnv.consts = make(map[string]bool, len(e.consts))
+		}
+		newEnv.consts[k] = true
+	}
 	return newEnv
 }
@@ -222,6 +222,45 @@ func TestEnvironment_Update(t *testing.T) {
 	}
 }
 
+func TestEnvironment_SetConst(t *testing.T) {
+	outer := NewEnvironment()
+	if err := outer.SetConst("x", &NumberValue{Value: 10}); err != nil {
+		t.Fatalf("SetConst() returned error: %v", err)
+	}
+	if !outer.IsConst("x") {
+		t.Error("IsConst() should be true for a constant")
+	}
+
+	// Redeclaring in the same scope fails
+	if err := outer.SetConst("x", &NumberValue{Value: 20}); err == nil {
+		t.Error("SetConst() should fail when the name is already defined")
+	}
+
+	// Updates fail from the same scope, nested scopes and isolated (tool) scopes
+	inner := NewEnclosedEnvironment(outer)
+	isolated := NewIsolatedEnvironment(outer)
+	for _, env := range []*Environment{outer, inner, isolated} {
+		if err := env.Update("x", &NumberValue{Value: 20}); err == nil {
+			t.Error("Update() should fail for a constant")
+		}
+	}
+	value, _ := outer.Get("x")
+	if numVal, ok := value.(*NumberValue); !ok || numVal.Value != 10 {
+		t.Errorf("Expected x=10, got %v", value)
+	}
+
+	// A local shadow in an inner scope is a separate, mutable binding
+	inner.Set("x", &NumberValue{Value: 30})
+	if inner.IsConst("x") {
+		t.Error("IsConst() should be false for a shadowing variable")
+	}
+
+	// Clone keeps constants
+	if !outer.Clone().IsConst("x") {
+		t.Error("Clone() should preserve constants")
+	}
+}
+
 func TestEnvironment_Update_Nested(t *testing.T) {
 	outer := NewEnvironment()
 	outer.Set("x", &NumberValue{Value: 10})
@@ -206,6 +206,62 @@ func TestExec_EnvVarInheritedBySubshell(t *testing.T) {
 	}
 }
 
+// TestExec_EnvOption tests that options.env applies to a single exec() call only,
+// overriding the session's value without changing it
+func TestExec_EnvOption(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	interp.SetEnv("GSH_ENV_OPTION_TEST", "session")
+	t.Cleanup(func() { os.Unsetenv("GSH_ENV_OPTION_TEST") })
+
+	result, err := interp.EvalString(`exec("echo $GSH_ENV_OPTION_TEST-$GSH_ENV_OPTION_EXTRA", {env: {GSH_ENV_OPTION_TEST: "it's overridden", GSH_ENV_OPTION_EXTRA: "extra"}})`, nil)
+	if err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	obj := result.FinalResult.(*ObjectValue)
+	stdout := strings.TrimSpace(obj.GetPropertyValue("stdout").(*StringValue).Value)
+	if stdout != "it's overridden-extra" {
+		t.Errorf("expected overridden env in exec(), got '%s'", stdout)
+	}
+
+	result, err = interp.EvalString(`gsh.exec("echo $GSH_ENV_OPTION_TEST-$GSH_ENV_OPTION_EXTRA")`, nil)
+	if err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	obj = result.FinalResult.(*ObjectValue)
+	stdout = strings.TrimSpace(obj.GetPropertyValue("stdout").(*StringValue).Value)
+	if stdout != "session-" {
+		t.Errorf("expected env overrides not to leak into the session, got '%s'", stdout)
+	}
+	if got := interp.GetEnv("GSH_ENV_OPTION_TEST"); got != "session" {
+		t.Errorf("expected session env to be unchanged, got '%s'", got)
+	}
+}
+
+// TestExec_EnvOptionErrors tests validation of options.env
+func TestExec_EnvOptionErrors(t *testing.T) {
+	tests := []struct {
+		script  string
+		wantErr string
+	}{
+		{`exec("true", {env: "FOO=bar"})`, "options.env must be an object"},
+		{`exec("true", {env: {FOO: 1}})`, "options.env.FOO must be a string"},
+		{`exec("true", {env: {"NOT-VALID": "x"}})`, "invalid environment variable name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			interp := New(nil)
+			defer interp.Close()
+
+			_, err := interp.EvalString(tt.script, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 // TestExec_PWDEnvVarMatchesWorkingDir tests that the PWD environment variable
 // matches the actual working directory (important for tools like starship)
 func TestExec_PWDEnvVarMatchesWorkingDir(t *testing.T) {
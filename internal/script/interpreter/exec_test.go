@@ -1,7 +1,10 @@
 package interpreter
 
 import (
+	"strings"
 	"testing"
+
+	shinterp "mvdan.cc/sh/v3/interp"
 )
 
 func TestBuiltinExec_BasicExecution(t *testing.T) {
@@ -160,6 +163,16 @@ func TestBuiltinExec_InvalidArguments(t *testing.T) {
 			},
 			want: "exec() options.timeout must be a number (milliseconds), got string",
 		},
+		{
+			name: "unknown encoding",
+			args: []Value{
+				&StringValue{Value: "echo"},
+				&ObjectValue{Properties: map[string]*PropertyDescriptor{
+					"encoding": {Value: &StringValue{Value: "hex"}},
+				}},
+			},
+			want: `exec() options.encoding must be "utf8" or "base64", got hex`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,3 +215,150 @@ func TestBuiltinExec_WithTimeout(t *testing.T) {
 		t.Errorf("stdout = %q, want %q", stdout.Value, "test output\n")
 	}
 }
+
+func TestGshExec(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	dir := t.TempDir()
+	interp.runner.Dir = dir
+	interp.SetEnv("GSH_EXEC_TEST", "from-runner")
+
+	result, err := interp.EvalString(`
+tool run() {
+	return gsh.exec("{ echo $GSH_EXEC_TEST; pwd; echo oops >&2; exit 3; }")
+}
+run()
+`, nil)
+	if err != nil {
+		t.Fatalf("gsh.exec() failed: %v", err)
+	}
+
+	obj, ok := result.Value().(*ObjectValue)
+	if !ok {
+		t.Fatalf("gsh.exec() result should be an object, got %s", result.Value().Type())
+	}
+	if got, want := obj.GetPropertyValue("stdout").String(), "from-runner\n"+dir+"\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got := obj.GetPropertyValue("stderr").String(); got != "oops\n" {
+		t.Errorf("stderr = %q, want %q", got, "oops\n")
+	}
+	if got := obj.GetPropertyValue("exitCode").(*NumberValue).Value; got != 3 {
+		t.Errorf("exitCode = %v, want 3", got)
+	}
+
+	_, err = interp.EvalString(`gsh.exec(42)`, nil)
+	if err == nil || !strings.Contains(err.Error(), "gsh.exec() first argument must be a string") {
+		t.Errorf("expected gsh.exec() argument error, got %v", err)
+	}
+}
+
+func TestGshExecSequence(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	summarize := func(code string) string {
+		t.Helper()
+		result, err := interp.EvalString(code, nil)
+		if err != nil {
+			t.Fatalf("gsh.execSequence() failed: %v", err)
+		}
+		var parts []string
+		for _, elem := range result.Value().(*ArrayValue).Elements {
+			obj := elem.(*ObjectValue)
+			parts = append(parts, obj.GetPropertyValue("command").String()+"="+obj.GetPropertyValue("exitCode").String()+":"+strings.TrimSpace(obj.GetPropertyValue("stdout").String()))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	commands := `["echo one", "exit 2", "echo three"]`
+	if got, want := summarize(`gsh.execSequence(`+commands+`)`), "echo one=0:one, exit 2=2:"; got != want {
+		t.Errorf("stopping on error: got %q, want %q", got, want)
+	}
+	if got, want := summarize(`gsh.execSequence(`+commands+`, { stopOnError: false })`), "echo one=0:one, exit 2=2:, echo three=0:three"; got != want {
+		t.Errorf("continuing on error: got %q, want %q", got, want)
+	}
+	if got, want := summarize(`gsh.execSequence(["echo $SEQ_VAR"], { env: { SEQ_VAR: "set" } })`), "echo $SEQ_VAR=0:set"; got != want {
+		t.Errorf("exec options: got %q, want %q", got, want)
+	}
+
+	for code, want := range map[string]string{
+		`gsh.execSequence("echo one")`:                    "first argument must be an array of strings",
+		`gsh.execSequence(["echo one", 2])`:               "command 1 must be a string",
+		`gsh.execSequence(["echo"], { stopOnError: 1 })`:  "options.stopOnError must be a boolean",
+		`gsh.execSequence(["echo"], { timeout: "soon" })`: "gsh.execSequence() options.timeout must be a number",
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", code, want, err)
+		}
+	}
+}
+
+func TestGshExecBase64(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	// Bytes that aren't valid UTF-8, including a NUL, must come back unchanged
+	result, err := interp.EvalString(`
+result = gsh.exec("{ printf '\\211PNG\\000\\377'; echo err >&2; }", { encoding: "base64" })
+values = [result.stdout, result.stderr, gsh.decodeBase64(result.stdout) == gsh.decodeBase64("iVBORwD/"), gsh.encodeBase64("héllo"), gsh.decodeBase64("aMOpbGxv")]
+values
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"iVBORwD/", "err\n", "true", "aMOpbGxv", "héllo"}
+	got := result.FinalResult.(*ArrayValue).Elements
+	for idx, w := range want {
+		if got[idx].String() != w {
+			t.Errorf("value %d: expected %q, got %q", idx, w, got[idx].String())
+		}
+	}
+
+	_, err = interp.EvalString(`gsh.decodeBase64("not base64!")`, nil)
+	if err == nil || !strings.Contains(err.Error(), "gsh.decodeBase64() argument is not valid base64") {
+		t.Errorf("expected an invalid base64 error, got %v", err)
+	}
+}
+
+func TestGshExecStream(t *testing.T) {
+	var out strings.Builder
+	runner, err := shinterp.New(shinterp.StdIO(nil, &out, &out))
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+	interp := New(&Options{Runner: runner})
+	defer interp.Close()
+
+	result, err := interp.EvalString(`
+code = gsh.exec("{ echo building; echo warning >&2; exit 2; }", { stream: true })
+results = gsh.execSequence(["echo one", "echo two"], { stream: true })
+values = [code, results.length, results[1].command, results[1].exitCode]
+values
+`, nil)
+	if err != nil {
+		t.Fatalf("gsh.exec() failed: %v", err)
+	}
+
+	want := []string{"2", "2", "echo two", "0"}
+	got := result.FinalResult.(*ArrayValue).Elements
+	for idx, w := range want {
+		if got[idx].String() != w {
+			t.Errorf("value %d: expected %q, got %q", idx, w, got[idx].String())
+		}
+	}
+	if got, want := out.String(), "building\nwarning\none\ntwo\n"; got != want {
+		t.Errorf("streamed output = %q, want %q", got, want)
+	}
+
+	for code, want := range map[string]string{
+		`gsh.exec("echo", { stream: "yes" })`:                    "gsh.exec() options.stream must be a boolean",
+		`gsh.exec("echo", { stream: true, encoding: "base64" })`: "options.encoding cannot be used with options.stream",
+	} {
+		if _, err := interp.EvalString(code, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", code, want, err)
+		}
+	}
+}
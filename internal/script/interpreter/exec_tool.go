@@ -46,12 +46,29 @@ func ExecuteNativeExecTool(ctx context.Context, args map[string]interface{}, liv
 		}
 	}
 
+	// Parse env (optional): extra environment variables for this command only
+	var env map[string]string
+	if envVal, ok := args["env"]; ok && envVal != nil {
+		envObj, ok := envVal.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("exec tool requires 'env' to be an object")
+		}
+		env = make(map[string]string, len(envObj))
+		for key, val := range envObj {
+			str, ok := val.(string)
+			if !ok {
+				return "", fmt.Errorf("exec tool requires 'env.%s' to be a string", key)
+			}
+			env[key] = str
+		}
+	}
+
 	// Create a timeout context
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Execute with live output
-	result, err := ExecuteCommandWithPTY(execCtx, command, liveOutput, workingDir)
+	result, err := ExecuteCommandWithPTY(execCtx, command, liveOutput, workingDir, env)
 
 	if err != nil {
 		return fmt.Sprintf(`{"error": %q}`, err.Error()), nil
@@ -83,8 +100,9 @@ type ExecResult struct {
 // This allows live output display while also capturing the output.
 // The liveOutput writer receives output in real-time as the command runs.
 // If liveOutput is nil, output is only captured (not displayed).
-// The workingDir parameter specifies the directory to run the command in.
-func ExecuteCommandWithPTY(ctx context.Context, command string, liveOutput io.Writer, workingDir string) (*ExecResult, error) {
+// The workingDir parameter specifies the directory to run the command in, and env holds
+// extra environment variables for the command (may be nil).
+func ExecuteCommandWithPTY(ctx context.Context, command string, liveOutput io.Writer, workingDir string, env map[string]string) (*ExecResult, error) {
 	cmd := exec.CommandContext(ctx, "bash", "-c", command)
 
 	// Set working directory
@@ -96,6 +114,9 @@ func ExecuteCommandWithPTY(ctx context.Context, command string, liveOutput io.Wr
 		"GIT_PAGER=cat",
 		"GIT_TERMINAL_PROMPT=0",
 	)
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
 
 	// Create PTY for the command
 	ptmx, err := pty.Start(cmd)
@@ -190,6 +211,11 @@ func execToolParameters() map[string]interface{} {
 				"type":        "integer",
 				"description": "Timeout in seconds for the command execution. Defaults to 60 seconds if not specified.",
 			},
+			"env": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Extra environment variables to set for this command only",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
 		},
 		"required": []string{"command", "working_directory"},
 	}
@@ -12,7 +12,7 @@ import (
 func TestExecuteCommand_SimpleCommand(t *testing.T) {
 	ctx := context.Background()
 
-	result, err := ExecuteCommandWithPTY(ctx, "echo hello", nil, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "echo hello", nil, "/tmp", nil)
 	if err != nil {
 		t.Fatalf("ExecuteCommandWithPTY failed: %v", err)
 	}
@@ -29,7 +29,7 @@ func TestExecuteCommand_SimpleCommand(t *testing.T) {
 func TestExecuteCommand_NonZeroExitCode(t *testing.T) {
 	ctx := context.Background()
 
-	result, err := ExecuteCommandWithPTY(ctx, "exit 42", nil, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "exit 42", nil, "/tmp", nil)
 	if err != nil {
 		t.Fatalf("ExecuteCommandWithPTY failed: %v", err)
 	}
@@ -43,7 +43,7 @@ func TestExecuteCommand_WithLiveOutput(t *testing.T) {
 	ctx := context.Background()
 	var liveOutput bytes.Buffer
 
-	result, err := ExecuteCommandWithPTY(ctx, "echo live_test", &liveOutput, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "echo live_test", &liveOutput, "/tmp", nil)
 	if err != nil {
 		t.Fatalf("ExecuteCommandWithPTY failed: %v", err)
 	}
@@ -67,7 +67,7 @@ func TestExecuteCommand_ContextCancellation(t *testing.T) {
 	defer cancel()
 
 	// Run a command that takes longer than the timeout
-	result, err := ExecuteCommandWithPTY(ctx, "sleep 10", nil, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "sleep 10", nil, "/tmp", nil)
 
 	// Either we get an error, or we get a non-zero exit code due to signal
 	if err == nil && result != nil && result.ExitCode == 0 {
@@ -86,7 +86,7 @@ func TestExecuteCommand_StderrCapture(t *testing.T) {
 	ctx := context.Background()
 
 	// PTY combines stdout and stderr, so both should appear in output
-	result, err := ExecuteCommandWithPTY(ctx, "echo stdout_msg; echo stderr_msg >&2", nil, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "echo stdout_msg; echo stderr_msg >&2", nil, "/tmp", nil)
 	if err != nil {
 		t.Fatalf("ExecuteCommandWithPTY failed: %v", err)
 	}
@@ -183,6 +183,24 @@ func TestExecuteNativeExecTool_Success(t *testing.T) {
 	}
 }
 
+func TestExecuteNativeExecTool_Env(t *testing.T) {
+	ctx := context.Background()
+
+	args := map[string]interface{}{
+		"command":           "echo $GSH_EXEC_TOOL_ENV",
+		"working_directory": "/tmp",
+		"env":               map[string]interface{}{"GSH_EXEC_TOOL_ENV": "from_env"},
+	}
+	result, err := ExecuteNativeExecTool(ctx, args, io.Discard)
+	if err != nil {
+		t.Fatalf("ExecuteNativeExecTool failed: %v", err)
+	}
+
+	if !strings.Contains(result, "from_env") {
+		t.Errorf("Expected result to contain 'from_env', got: %q", result)
+	}
+}
+
 func TestExecuteNativeExecTool_Timeout(t *testing.T) {
 	ctx := context.Background()
 	args := map[string]interface{}{
@@ -241,7 +259,7 @@ func TestExecuteCommand_WorkingDirectory(t *testing.T) {
 	ctx := context.Background()
 
 	// Test that specifying a working directory works correctly
-	result, err := ExecuteCommandWithPTY(ctx, "pwd", nil, "/tmp")
+	result, err := ExecuteCommandWithPTY(ctx, "pwd", nil, "/tmp", nil)
 	if err != nil {
 		t.Fatalf("ExecuteCommandWithPTY failed: %v", err)
 	}
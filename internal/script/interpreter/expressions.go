@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/kunchenguid/gsh/internal/script/lexer"
@@ -217,32 +218,23 @@ func (i *Interpreter) evalBinaryExpression(env *Environment, node *parser.Binary
 		return nil, err
 	}
 
-	// Handle short-circuit evaluation for logical operators
-	// For &&: if left is falsy, return false without evaluating right
-	// For ||: if left is truthy, return true without evaluating right
+	// Handle short-circuit evaluation for logical operators.
+	// Like JavaScript, they return one of their operands rather than a boolean:
+	// For &&: if left is falsy, return left without evaluating right; otherwise return right
+	// For ||: if left is truthy, return left without evaluating right; otherwise return right
 	// Note: DynamicValue unwrapping is handled by evalExpression
 	switch node.Operator {
 	case "&&":
 		if !left.IsTruthy() {
-			return &BoolValue{Value: false}, nil
+			return left, nil
 		}
-		// Left is truthy, evaluate right and return its truthiness
-		right, err := i.evalExpression(env, node.Right)
-		if err != nil {
-			return nil, err
-		}
-		return &BoolValue{Value: right.IsTruthy()}, nil
+		return i.evalExpression(env, node.Right)
 
 	case "||":
 		if left.IsTruthy() {
-			return &BoolValue{Value: true}, nil
-		}
-		// Left is falsy, evaluate right and return its truthiness
-		right, err := i.evalExpression(env, node.Right)
-		if err != nil {
-			return nil, err
+			return left, nil
 		}
-		return &BoolValue{Value: right.IsTruthy()}, nil
+		return i.evalExpression(env, node.Right)
 
 	case "??":
 		// Nullish coalescing: if left is null, return right; otherwise return left
@@ -323,11 +315,17 @@ func (i *Interpreter) applyBinaryOperator(op string, left, right Value) (Value,
 	}
 
 	// Handle equality comparisons for all types
-	if op == "==" {
+	switch op {
+	case "==":
+		return &BoolValue{Value: looseEquals(left, right)}, nil
+	case "!=":
+		return &BoolValue{Value: !looseEquals(left, right)}, nil
+	case "===":
 		return &BoolValue{Value: left.Equals(right)}, nil
-	}
-	if op == "!=" {
+	case "!==":
 		return &BoolValue{Value: !left.Equals(right)}, nil
+	case "<", "<=", ">", ">=":
+		return nil, fmt.Errorf("cannot compare %s and %s with '%s': only two numbers or two strings can be ordered", left.Type(), right.Type(), op)
 	}
 
 	// Note: &&, ||, and ?? are handled in evalBinaryExpression for short-circuit evaluation
@@ -335,6 +333,33 @@ func (i *Interpreter) applyBinaryOperator(op string, left, right Value) (Value,
 	return nil, fmt.Errorf("unsupported operator '%s' for types %s and %s", op, left.Type(), right.Type())
 }
 
+// looseEquals implements ==. It is strict equality, except that a number equals a string
+// holding the same number ("5" == 5, " 2.50 " == 2.5). Strings that are empty or not a
+// number never equal a number, and no other types are converted.
+func looseEquals(left, right Value) bool {
+	if num, ok := left.(*NumberValue); ok {
+		if str, ok := right.(*StringValue); ok {
+			return stringEqualsNumber(str.Value, num.Value)
+		}
+	}
+	if str, ok := left.(*StringValue); ok {
+		if num, ok := right.(*NumberValue); ok {
+			return stringEqualsNumber(str.Value, num.Value)
+		}
+	}
+	return left.Equals(right)
+}
+
+// stringEqualsNumber reports whether s holds a number equal to n
+func stringEqualsNumber(s string, n float64) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	parsed, err := strconv.ParseFloat(s, 64)
+	return err == nil && parsed == n
+}
+
 // evalUnaryExpression evaluates a unary expression
 func (i *Interpreter) evalUnaryExpression(env *Environment, node *parser.UnaryExpression) (Value, error) {
 	right, err := i.evalExpression(env, node.Right)
@@ -362,14 +387,27 @@ func (i *Interpreter) evalUnaryExpression(env *Environment, node *parser.UnaryEx
 
 // evalArrayLiteral evaluates an array literal
 func (i *Interpreter) evalArrayLiteral(env *Environment, node *parser.ArrayLiteral) (Value, error) {
-	elements := make([]Value, len(node.Elements))
+	elements := make([]Value, 0, len(node.Elements))
 
-	for idx, elem := range node.Elements {
+	for _, elem := range node.Elements {
+		if spread, ok := elem.(*parser.SpreadElement); ok {
+			val, err := i.evalExpression(env, spread.Argument)
+			if err != nil {
+				return nil, err
+			}
+			arr, ok := val.(*ArrayValue)
+			if !ok {
+				return nil, NewRuntimeError("spread requires an array, got %s (line %d, column %d)",
+					val.Type(), spread.Token.Line, spread.Token.Column)
+			}
+			elements = append(elements, arr.Elements...)
+			continue
+		}
 		val, err := i.evalExpression(env, elem)
 		if err != nil {
 			return nil, err
 		}
-		elements[idx] = val
+		elements = append(elements, val)
 	}
 
 	return &ArrayValue{Elements: elements}, nil
@@ -580,7 +618,7 @@ func (i *Interpreter) evalCallExpression(env *Environment, node *parser.CallExpr
 }
 
 // CallTool calls a tool with the given arguments
-func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value) (Value, error) {
+func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value) (result Value, err error) {
 	// Get the body as a block statement
 	body, ok := tool.Body.(*parser.BlockStatement)
 	if !ok {
@@ -591,7 +629,6 @@ func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value)
 	i.pushStackFrame(tool.Name, fmt.Sprintf("tool '%s'", tool.Name))
 
 	// Ensure we handle errors properly before popping the stack frame
-	var finalErr error
 	defer func() {
 		// Pop stack frame after error handling
 		i.popStackFrame()
@@ -601,6 +638,14 @@ func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value)
 	// Start with the tool's captured environment, allowing read/write access
 	// to outer scope variables for consistency with if/for blocks
 	toolEnv := NewEnclosedEnvironment(tool.Env)
+	toolEnv.deferScope = true
+
+	// Run deferred calls however the tool exits
+	defer func() {
+		if err = i.runDeferred(toolEnv, err); err != nil {
+			result = nil
+		}
+	}()
 
 	// Bind parameters to arguments
 	for idx, paramName := range tool.Parameters {
@@ -608,7 +653,7 @@ func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value)
 	}
 
 	// Execute the tool body using the tool's enclosed environment
-	var result Value = &NullValue{}
+	result = &NullValue{}
 	for _, stmt := range body.Statements {
 		val, err := i.evalStatement(toolEnv, stmt)
 		if err != nil {
@@ -617,8 +662,7 @@ func (i *Interpreter) CallTool(env *Environment, tool *ToolValue, args []Value)
 				return cfErr.Value, nil
 			}
 			// Wrap the error with current stack frame before returning
-			finalErr = i.wrapError(err, stmt)
-			return nil, finalErr
+			return nil, i.wrapError(err, stmt)
 		}
 		result = val
 	}
@@ -658,7 +702,11 @@ func (i *Interpreter) evalMemberExpression(env *Environment, node *parser.Member
 	if err != nil {
 		return nil, err
 	}
+	return i.getMember(object, node)
+}
 
+// getMember returns the property of object named by node, for object.property
+func (i *Interpreter) getMember(object Value, node *parser.MemberExpression) (Value, error) {
 	propertyName := node.Property.Value
 
 	// Handle special env object
@@ -671,6 +719,12 @@ func (i *Interpreter) evalMemberExpression(env *Environment, node *parser.Member
 		return mcpProxy.GetProperty(propertyName)
 	}
 
+	// '.*' only selects the tools of an MCP server
+	if propertyName == "*" {
+		return nil, NewRuntimeError("'.*' can only be used on an MCP server, got %s (line %d, column %d)",
+			object.Type(), node.Token.Line, node.Token.Column)
+	}
+
 	// Handle array properties/methods
 	if arrVal, ok := object.(*ArrayValue); ok {
 		return i.getArrayProperty(arrVal, propertyName, node)
@@ -696,6 +750,11 @@ func (i *Interpreter) evalMemberExpression(env *Environment, node *parser.Member
 		return i.getSetProperty(setVal, propertyName, node)
 	}
 
+	// Handle conversation properties/methods
+	if conv, ok := object.(*ConversationValue); ok {
+		return i.getConversationProperty(conv, propertyName)
+	}
+
 	// Handle ACP session properties/methods
 	if acpSession, ok := object.(*ACPSessionValue); ok {
 		return i.getACPSessionProperty(acpSession, propertyName)
@@ -737,6 +796,18 @@ func (i *Interpreter) getArrayProperty(arr *ArrayValue, property string, node *p
 		return &ArrayMethodValue{Name: "slice", Impl: arraySliceImpl, Arr: arr}, nil
 	case "reverse":
 		return &ArrayMethodValue{Name: "reverse", Impl: arrayReverseImpl, Arr: arr}, nil
+	case "concat":
+		return &ArrayMethodValue{Name: "concat", Impl: arrayConcatImpl, Arr: arr}, nil
+	case "find":
+		return &ArrayMethodValue{Name: "find", Impl: i.arrayFindImpl, Arr: arr}, nil
+	case "some":
+		return &ArrayMethodValue{Name: "some", Impl: i.arraySomeImpl, Arr: arr}, nil
+	case "every":
+		return &ArrayMethodValue{Name: "every", Impl: i.arrayEveryImpl, Arr: arr}, nil
+	case "includes":
+		return &ArrayMethodValue{Name: "includes", Impl: arrayIncludesImpl, Arr: arr}, nil
+	case "indexOf":
+		return &ArrayMethodValue{Name: "indexOf", Impl: arrayIndexOfImpl, Arr: arr}, nil
 	default:
 		return nil, NewRuntimeError("array property '%s' not found (line %d, column %d)",
 			property, node.Token.Line, node.Token.Column)
@@ -0,0 +1,132 @@
+package interpreter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// promptGitState serves gsh.git.status() calls made while the prompt renders when
+// gsh.promptGitAsync is on. git status runs in the background instead, and until it
+// finishes callers get the last status seen for the directory, or a placeholder with
+// pending: true. When the result differs from what the prompt showed, the refresh
+// handler is called so the REPL can render the prompt again.
+type promptGitState struct {
+	rendering atomic.Int32 // > 0 while a repl.prompt event is being handled
+
+	mu         sync.Mutex
+	dir        string // directory the cached status belongs to
+	value      Value  // last status seen for dir, nil if there is none yet
+	shown      Value  // status the prompt last rendered
+	fresh      bool   // value changed since the prompt last read it
+	refreshing bool   // a background git status is running
+	onRefresh  func()
+}
+
+// SetPromptRefreshHandler sets the function called when a background git status for
+// gsh.promptGitAsync finishes with a result the current prompt doesn't show yet, or
+// when a script calls gsh.redraw(). The REPL uses it to render the prompt again.
+func (i *Interpreter) SetPromptRefreshHandler(fn func()) {
+	i.promptGit.mu.Lock()
+	defer i.promptGit.mu.Unlock()
+	i.promptGit.onRefresh = fn
+}
+
+// promptGitAsync reports whether gsh.git.status() should answer from the background,
+// which is only while a repl.prompt event is handled and gsh.promptGitAsync is set
+func (i *Interpreter) promptGitAsync() bool {
+	if i.promptGit.rendering.Load() == 0 {
+		return false
+	}
+	replCtx := i.sdkConfig.GetREPLContext()
+	return replCtx != nil && replCtx.PromptGitAsync
+}
+
+// asyncGitStatus returns the status to render in the prompt now and starts a background
+// refresh, unless the cached status was just refreshed for this render
+func (i *Interpreter) asyncGitStatus() Value {
+	p := &i.promptGit
+	dir := i.runnerDir()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.dir != dir {
+		p.dir = dir
+		p.value = nil
+		p.fresh = false
+	}
+
+	if p.value != nil && p.fresh {
+		p.fresh = false
+		p.shown = p.value
+		return p.value
+	}
+
+	if !p.refreshing {
+		p.refreshing = true
+		go i.refreshPromptGitStatus(dir)
+	}
+	result := p.value
+	if result == nil {
+		result = pendingGitStatus()
+	}
+	p.shown = result
+	return result
+}
+
+// refreshPromptGitStatus runs git status for dir in the background and calls the refresh
+// handler if the prompt is showing something else
+func (i *Interpreter) refreshPromptGitStatus(dir string) {
+	value, err := i.gitStatus(context.Background())
+
+	p := &i.promptGit
+	p.mu.Lock()
+	p.refreshing = false
+	if p.dir != dir {
+		// The directory changed while git ran, so start over for the new one
+		p.refreshing = true
+		go i.refreshPromptGitStatus(p.dir)
+		p.mu.Unlock()
+		return
+	}
+	if err != nil {
+		p.mu.Unlock()
+		if i.logger != nil {
+			i.logger.Debug("background git status failed", zap.Error(err))
+		}
+		return
+	}
+	p.value = value
+	p.fresh = p.shown == nil || !value.Equals(p.shown)
+	onRefresh := p.onRefresh
+	changed := p.fresh
+	p.mu.Unlock()
+
+	if changed && onRefresh != nil {
+		onRefresh()
+	}
+}
+
+// pendingGitStatus is the placeholder gsh.git.status() returns before the first
+// background git status for a directory finishes
+func pendingGitStatus() Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"branch":  {Value: &NullValue{}},
+			"dirty":   {Value: &BoolValue{Value: false}},
+			"ahead":   {Value: &NumberValue{Value: 0}},
+			"behind":  {Value: &NumberValue{Value: 0}},
+			"files":   {Value: &ArrayValue{Elements: []Value{}}},
+			"pending": {Value: &BoolValue{Value: true}},
+		},
+	}
+}
+
+// runnerDir returns the working directory of the shared sh runner
+func (i *Interpreter) runnerDir() string {
+	i.runnerMu.RLock()
+	defer i.runnerMu.RUnlock()
+	return i.runner.Dir
+}
@@ -101,6 +101,17 @@ func normalizeImportKey(origin *ScriptOrigin, path string) string {
 	return fmt.Sprintf("%s:%s", origin.Type, path)
 }
 
+// StripShebang removes a leading shebang line from a script, keeping its newline so
+// line numbers in errors stay right
+func StripShebang(source string) string {
+	if strings.HasPrefix(source, "#!") {
+		if idx := strings.Index(source, "\n"); idx >= 0 {
+			return source[idx:]
+		}
+	}
+	return source
+}
+
 // evalImportStatement evaluates an import statement
 func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportStatement) (Value, error) {
 	importPath := node.Path.Value
@@ -141,12 +152,7 @@ func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportS
 		return nil, err
 	}
 
-	// Skip shebang line if present
-	if strings.HasPrefix(content, "#!") {
-		if idx := strings.Index(content, "\n"); idx >= 0 {
-			content = content[idx+1:]
-		}
-	}
+	content = StripShebang(content)
 
 	// Parse the module
 	l := lexer.New(content)
@@ -154,7 +160,7 @@ func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportS
 	program := p.ParseProgram()
 
 	if len(p.Errors()) > 0 {
-		return nil, fmt.Errorf("parse errors in %s: %s", importPath, strings.Join(p.Errors(), "; "))
+		return nil, &ParseError{Path: importPath, Source: content, Errors: p.Errors()}
 	}
 
 	// Save current state
@@ -164,13 +170,15 @@ func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportS
 	// Set up module execution environment
 	i.currentOrigin = origin
 	moduleEnv := NewEnclosedEnvironment(env) // Module has its own scope but can access outer
+	moduleEnv.deferScope = true
 	i.exportedNames = make(map[string]bool)
 
-	// Execute the module
+	// Execute the module, running its deferred calls once it has finished
 	var lastResult Value = &NullValue{}
 	for _, stmt := range program.Statements {
 		val, err := i.evalStatement(moduleEnv, stmt)
 		if err != nil {
+			err = i.runDeferred(moduleEnv, err)
 			// Restore state before returning error
 			i.currentOrigin = prevOrigin
 			i.exportedNames = prevExportedNames
@@ -181,6 +189,12 @@ func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportS
 		}
 	}
 
+	if err := i.runDeferred(moduleEnv, nil); err != nil {
+		i.currentOrigin = prevOrigin
+		i.exportedNames = prevExportedNames
+		return nil, fmt.Errorf("error in %s: %w", importPath, err)
+	}
+
 	// Collect exports from the module
 	exports := make(map[string]Value)
 	for name := range i.exportedNames {
@@ -208,6 +222,66 @@ func (i *Interpreter) evalImportStatement(env *Environment, node *parser.ImportS
 	return lastResult, nil
 }
 
+// evalIncludeStatement evaluates an include statement.
+// The included file is evaluated directly in the current environment, so all of its
+// declarations become visible to the including script. Unlike imports, includes are
+// not cached: including the same file twice evaluates it twice.
+func (i *Interpreter) evalIncludeStatement(env *Environment, node *parser.IncludeStatement) (Value, error) {
+	includePath := node.Path.Value
+
+	// Resolve the include path
+	origin, resolvedPath, err := i.resolveImportPath(includePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve include path %q: %w", includePath, err)
+	}
+
+	// Check for cyclic includes
+	includeKey := normalizeImportKey(origin, resolvedPath)
+	if i.includeStack[includeKey] {
+		return nil, fmt.Errorf("circular include detected: %s", resolvedPath)
+	}
+
+	// Read the file content
+	content, err := i.readImportedFile(origin, resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content = StripShebang(content)
+
+	// Parse the included file
+	l := lexer.New(content)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		return nil, &ParseError{Path: resolvedPath, Source: content, Errors: p.Errors()}
+	}
+
+	// Mark as being included and switch origin so nested paths resolve relative to this file
+	i.includeStack[includeKey] = true
+	prevOrigin := i.currentOrigin
+	i.currentOrigin = origin
+	defer func() {
+		i.currentOrigin = prevOrigin
+		delete(i.includeStack, includeKey)
+	}()
+
+	// Evaluate in the current environment so declarations land in the caller's scope
+	var lastResult Value = &NullValue{}
+	for _, stmt := range program.Statements {
+		val, err := i.evalStatement(env, stmt)
+		if err != nil {
+			return nil, fmt.Errorf("error in %s: %w", resolvedPath, err)
+		}
+		if val != nil {
+			lastResult = val
+		}
+	}
+
+	return lastResult, nil
+}
+
 // evalExportStatement evaluates an export statement
 func (i *Interpreter) evalExportStatement(env *Environment, node *parser.ExportStatement) (Value, error) {
 	// Evaluate the declaration
@@ -608,3 +608,146 @@ export usedModel = testModel
 		t.Errorf("Expected 'usedModel' to be exported")
 	}
 }
+
+func TestIncludeEvaluatesIntoCurrentScope(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gsh-include-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "lib"), 0755); err != nil {
+		t.Fatalf("Failed to create lib dir: %v", err)
+	}
+
+	modelsContent := `
+include "./helpers.gsh"
+greeting = "hello"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "lib", "models.gsh"), []byte(modelsContent), 0644); err != nil {
+		t.Fatalf("Failed to write models.gsh: %v", err)
+	}
+
+	helpersContent := `
+tool double(x) {
+    return x * 2
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "lib", "helpers.gsh"), []byte(helpersContent), 0644); err != nil {
+		t.Fatalf("Failed to write helpers.gsh: %v", err)
+	}
+
+	mainContent := `
+include "./lib/models.gsh"
+result = double(21)
+`
+
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(mainContent, &ScriptOrigin{
+		Type:     OriginFilesystem,
+		BasePath: tmpDir,
+	})
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	vars := result.Variables()
+	greeting, ok := vars["greeting"].(*StringValue)
+	if !ok || greeting.Value != "hello" {
+		t.Errorf("Expected greeting to be \"hello\", got %v", vars["greeting"])
+	}
+	num, ok := vars["result"].(*NumberValue)
+	if !ok || num.Value != 42 {
+		t.Errorf("Expected result to be 42, got %v", vars["result"])
+	}
+}
+
+func TestIncludeCycleDetection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gsh-include-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.gsh"), []byte(`include "./b.gsh"`), 0644); err != nil {
+		t.Fatalf("Failed to write a.gsh: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.gsh"), []byte(`include "./a.gsh"`), 0644); err != nil {
+		t.Fatalf("Failed to write b.gsh: %v", err)
+	}
+
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err = interp.EvalString(`include "./a.gsh"`, &ScriptOrigin{
+		Type:     OriginFilesystem,
+		BasePath: tmpDir,
+	})
+	if err == nil {
+		t.Fatal("Expected circular include error")
+	}
+	if !strings.Contains(err.Error(), "circular include detected") {
+		t.Errorf("Expected circular include error, got: %v", err)
+	}
+}
+
+func TestIncludeSameFileTwice(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gsh-include-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "inc.gsh"), []byte(`counter = counter + 1`), 0644); err != nil {
+		t.Fatalf("Failed to write inc.gsh: %v", err)
+	}
+
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(`
+counter = 0
+include "./inc.gsh"
+include "./inc.gsh"
+`, &ScriptOrigin{
+		Type:     OriginFilesystem,
+		BasePath: tmpDir,
+	})
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	num, ok := result.Variables()["counter"].(*NumberValue)
+	if !ok || num.Value != 2 {
+		t.Errorf("Expected counter to be 2, got %v", result.Variables()["counter"])
+	}
+}
+
+func TestIncludeParseErrorReportsFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gsh-include-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	brokenPath := filepath.Join(tmpDir, "broken.gsh")
+	if err := os.WriteFile(brokenPath, []byte(`x = (1 + `), 0644); err != nil {
+		t.Fatalf("Failed to write broken.gsh: %v", err)
+	}
+
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err = interp.EvalString(`include "./broken.gsh"`, &ScriptOrigin{
+		Type:     OriginFilesystem,
+		BasePath: tmpDir,
+	})
+	if err == nil {
+		t.Fatal("Expected parse error")
+	}
+	if !strings.Contains(err.Error(), brokenPath) {
+		t.Errorf("Expected error to mention %s, got: %v", brokenPath, err)
+	}
+}
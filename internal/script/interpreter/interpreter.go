@@ -50,11 +50,21 @@ type Interpreter struct {
 	importedFiles map[string]bool             // Track imported files (prevent circular imports)
 	moduleExports map[string]map[string]Value // Cache of exported symbols per module
 	exportedNames map[string]bool             // Names exported by the current module
+	includeStack  map[string]bool             // Files currently being included (detect include cycles)
 
 	// ACP client management
 	acpClients       map[string]*acpClientEntry // ACP clients keyed by agent name
 	acpClientsMu     sync.RWMutex               // Protects acpClients access
 	acpClientFactory ACPClientFactory           // Factory for creating ACP clients (can be overridden for testing)
+
+	// promptGit runs gsh.git.status() in the background while the prompt renders (gsh.promptGitAsync)
+	promptGit promptGitState
+
+	// responseCacheDir overrides where cached model responses are stored (default ~/.gsh/cache/responses)
+	responseCacheDir string
+
+	// session records or replays the agents' model responses and tool results (nil when off)
+	session *Session
 }
 
 // EvalResult represents the result of evaluating a program
@@ -95,11 +105,16 @@ type Options struct {
 	// LogLevel is an AtomicLevel for dynamic log level changes.
 	// If nil/zero value, a default InfoLevel is created.
 	LogLevel zap.AtomicLevel
+	// LogFile is the path the Logger writes to, reported by gsh.logging.file.
+	LogFile string
 	// Runner is a sh runner for bash execution. If nil, a new one is created.
 	// Sharing a runner allows the interpreter to inherit env vars and working directory.
 	Runner *interp.Runner
 	// Version is the gsh version string. If empty, "unknown" is used.
 	Version string
+	// Session records the run's model responses and tool results, or replays a recording.
+	// If nil, models and tools are called as usual.
+	Session *Session
 }
 
 // New creates a new interpreter with the given options.
@@ -110,6 +125,7 @@ func New(opts *Options) *Interpreter {
 	}
 	registry := NewProviderRegistry()
 	registry.Register(NewOpenAIProvider())
+	registry.Register(NewBedrockProvider())
 
 	// Create sh runner if not provided
 	runner := opts.Runner
@@ -117,6 +133,8 @@ func New(opts *Options) *Interpreter {
 		shEnv := expand.ListEnviron(os.Environ()...)
 		var err error
 		runner, err = interp.New(
+			// Interactive enables alias expansion, matching the runner used by the REPL and gsh -c
+			interp.Interactive(true),
 			interp.Env(shEnv),
 			interp.StdIO(os.Stdin, os.Stdout, os.Stderr),
 		)
@@ -157,14 +175,18 @@ func New(opts *Options) *Interpreter {
 		callStacks:       newGoroutineCallStacks(),
 		contexts:         newGoroutineContexts(),
 		eventManager:     NewEventManager(),
-		sdkConfig:        NewSDKConfig(opts.Logger, atomicLevel),
+		sdkConfig:        NewSDKConfig(opts.Logger, atomicLevel, opts.LogFile),
 		version:          version,
 		importedFiles:    make(map[string]bool),
 		moduleExports:    make(map[string]map[string]Value),
 		exportedNames:    make(map[string]bool),
+		includeStack:     make(map[string]bool),
 		acpClients:       make(map[string]*acpClientEntry),
 		acpClientFactory: defaultACPClientFactory,
+		session:          opts.Session,
 	}
+	registry.Register(NewRouterProvider(i))
+	i.mcpManager.SetRestartHandler(i.onMCPRestart)
 	i.registerBuiltins()
 	i.registerGshSDK()
 	return i
@@ -343,7 +365,7 @@ func (i *Interpreter) EvalString(source string, origin *ScriptOrigin) (*EvalResu
 
 	// Check for parser errors
 	if len(p.Errors()) > 0 {
-		return nil, fmt.Errorf("parse errors: %s", strings.Join(p.Errors(), "; "))
+		return nil, &ParseError{Source: source, Errors: p.Errors()}
 	}
 
 	return i.Eval(program)
@@ -403,6 +425,16 @@ func (i *Interpreter) GetEventHandlers(eventName string) []*ToolValue {
 	return i.eventManager.GetHandlers(eventName)
 }
 
+// EventHandlerIDs returns the ID of every registered event handler, mapped to its event name
+func (i *Interpreter) EventHandlerIDs() map[string]string {
+	return i.eventManager.HandlerIDs()
+}
+
+// RemoveEventHandler removes the handler with the given ID from an event
+func (i *Interpreter) RemoveEventHandler(eventName, handlerID string) bool {
+	return i.eventManager.RemoveByID(eventName, handlerID)
+}
+
 // EmitEvent emits an event by executing the middleware chain.
 // Each middleware handler receives (ctx, next) where:
 //   - ctx: event-specific context object
@@ -421,6 +453,12 @@ func (i *Interpreter) EmitEvent(eventName string, ctx Value) Value {
 		return nil
 	}
 
+	// gsh.git.status() can answer from the background while the prompt renders
+	if eventName == EventReplPrompt {
+		i.promptGit.rendering.Add(1)
+		defer i.promptGit.rendering.Add(-1)
+	}
+
 	// Create a fresh enclosed environment for each EmitEvent call.
 	// This ensures concurrent calls (e.g., from the prediction goroutine)
 	// each get their own isolated scope chain.
@@ -499,16 +537,21 @@ func (i *Interpreter) SDKConfig() *SDKConfig {
 }
 
 // Eval evaluates a program and returns the result.
+// Calls deferred at the top level run when the program finishes, even if it fails.
 func (i *Interpreter) Eval(program *parser.Program) (*EvalResult, error) {
 	var finalResult Value = &NullValue{}
 
+	i.globalEnv.deferScope = true
 	for _, stmt := range program.Statements {
 		val, err := i.evalStatement(i.globalEnv, stmt)
 		if err != nil {
-			return nil, i.wrapError(err, stmt)
+			return nil, i.runDeferred(i.globalEnv, i.wrapError(err, stmt))
 		}
 		finalResult = val
 	}
+	if err := i.runDeferred(i.globalEnv, nil); err != nil {
+		return nil, err
+	}
 
 	return &EvalResult{
 		FinalResult: finalResult,
@@ -528,6 +571,11 @@ func (i *Interpreter) wrapError(err error, node parser.Node) error {
 		return err
 	}
 
+	// Don't wrap parse errors from imported files — callers render them with source context
+	if _, isParse := err.(*ParseError); isParse {
+		return err
+	}
+
 	// Get the current goroutine's call stack
 	callStack := i.callStacks.get()
 
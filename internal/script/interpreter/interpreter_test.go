@@ -3,8 +3,10 @@ package interpreter
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/kunchenguid/gsh/internal/script/lexer"
 	"github.com/kunchenguid/gsh/internal/script/parser"
@@ -97,6 +99,62 @@ count
 	}
 }
 
+func TestConstDeclaration(t *testing.T) {
+	input := `
+const limit = 5
+total = limit * 2
+tool scaled(n) {
+	return n * limit
+}
+scaled(total)
+`
+	result := testEval(t, input)
+	if result.String() != "50" {
+		t.Errorf("expected 50, got %s", result.String())
+	}
+
+	// Constants can be shadowed by a new binding in an inner scope
+	input = `
+const name = "outer"
+tool greet(name) {
+	return "hello " + name
+}
+greet("inner")
+`
+	result = testEval(t, input)
+	if result.String() != "hello inner" {
+		t.Errorf("expected 'hello inner', got %s", result.String())
+	}
+}
+
+func TestConstReassignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"reassign", "const x = 1\nx = 2", "cannot reassign constant 'x' (line 2, column 1)"},
+		{"reassign in block", "const x = 1\nif (true) {\n\tx = 2\n}", "cannot reassign constant 'x'"},
+		{"reassign in tool", "const x = 1\ntool f() {\n\tx = 2\n}\nf()", "cannot reassign constant 'x'"},
+		{"redeclare", "const x = 1\nconst x = 2", "cannot declare constant 'x': it is already defined in this scope"},
+		{"declare over variable", "x = 1\nconst x = 2", "cannot declare constant 'x'"},
+		{"replace with tool", "const x = 1\ntool x() {\n\treturn 2\n}", "cannot reassign constant 'x'"},
+		{"replace with loop variable", "const x = 1\nfor (x of [1, 2]) {\n}", "cannot reassign constant 'x'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testEvalError(t, tt.input)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
 func TestUndefinedVariable(t *testing.T) {
 	input := "undefinedVar"
 	err := testEvalError(t, input)
@@ -290,6 +348,64 @@ func TestLogicalOperators(t *testing.T) {
 	}
 }
 
+func TestLogicalOperatorsReturnOperands(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = "" || "default"`, "default"},
+		{`x = "name" || "default"`, "name"},
+		{`x = null || 0 || "last"`, "last"},
+		{`x = "a" && "b"`, "b"},
+		{`x = 0 && "never"`, "0"},
+		{`x = "a" && null && "c"`, "null"},
+		{`x = [] || "empty"`, "empty"},
+		// The right operand is only evaluated when needed
+		{`x = "set" || undefinedVariable`, "set"},
+		{`x = false && undefinedVariable`, "false"},
+		{`calls = 0
+tool bump() {
+	calls = calls + 1
+	return true
+}
+y = true || bump()
+z = false && bump()
+x = calls`, "0"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result.String() != tt.expected {
+			t.Errorf("for input %q: expected %q, got %q", tt.input, tt.expected, result.String())
+		}
+	}
+}
+
+func TestNullishCoalescing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = null ?? 5`, "5"},
+		{`x = 0 ?? 5`, "0"},
+		{`x = "" ?? "default"`, ""},
+		{`x = false ?? true`, "false"},
+		{`x = null ?? null ?? "last"`, "last"},
+		{`x = env.GSH_TEST_NULLISH_UNSET ?? "fallback"`, "fallback"},
+		{`cfg = {}
+x = cfg.missing ?? "fallback"`, "fallback"},
+		// The right operand is only evaluated when needed
+		{`x = 1 ?? undefinedVariable`, "1"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result.String() != tt.expected {
+			t.Errorf("for input %q: expected %q, got %q", tt.input, tt.expected, result.String())
+		}
+	}
+}
+
 func TestUnaryOperators(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -462,10 +578,32 @@ func TestEqualityWithDifferentTypes(t *testing.T) {
 		input    string
 		expected bool
 	}{
-		{`x = 5 == "5"`, false},
+		// == converts between numbers and numeric strings
+		{`x = 5 == "5"`, true},
+		{`x = "5" == 5`, true},
+		{`x = 2.5 == " 2.50 "`, true},
+		{`x = 5 != "5"`, false},
+		{`x = 5 == "6"`, false},
+		{`x = 0 == ""`, false},
+		{`x = 0 == "   "`, false},
+		{`x = 5 == "five"`, false},
+		// Other types are never converted
 		{`x = true == 1`, false},
+		{`x = true == "true"`, false},
+		{`x = null == 0`, false},
+		{`x = null == ""`, false},
 		{`x = "hello" == "hello"`, true},
 		{`x = null == null`, true},
+		// === and !== never convert
+		{`x = 5 === "5"`, false},
+		{`x = 5 !== "5"`, true},
+		{`x = 5 === 5`, true},
+		{`x = "a" === "a"`, true},
+		{`x = [1, "2"] === [1, "2"]`, true},
+		{`x = null !== null`, false},
+		// Elements inside arrays and objects are compared strictly
+		{`x = [5] == ["5"]`, false},
+		{`x = {a: 5} == {a: "5"}`, false},
 	}
 
 	for _, tt := range tests {
@@ -481,6 +619,23 @@ func TestEqualityWithDifferentTypes(t *testing.T) {
 	}
 }
 
+func TestOrderingIncompatibleTypes(t *testing.T) {
+	tests := []string{
+		`x = "5" < 10`,
+		`x = 10 >= "5"`,
+		`x = true > false`,
+		`x = null <= 1`,
+		`x = [1] < [2]`,
+	}
+
+	for _, input := range tests {
+		err := testEvalError(t, input)
+		if err == nil || !strings.Contains(err.Error(), "cannot compare") {
+			t.Errorf("for input %q: expected a 'cannot compare' error, got %v", input, err)
+		}
+	}
+}
+
 func TestNestedArraysAndObjects(t *testing.T) {
 	input := `x = {items: [1, 2, 3], nested: {value: 42}}`
 	result := testEval(t, input)
@@ -707,6 +862,34 @@ func TestInterpreter_Context_CancellationIsDetectable(t *testing.T) {
 	}
 }
 
+func TestInterpreter_LoopsStopWhenContextCancelled(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	interp.SetContext(ctx)
+	defer interp.ClearContext()
+
+	// An endless loop stops once the deadline passes
+	if _, err := interp.EvalString(`while (true) { n = 1 }`, nil); err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("expected the while loop to stop with the context, got %v", err)
+	}
+
+	// Later loops don't start another iteration
+	for _, loop := range []string{
+		`for (item of [1, 2, 3]) { count = count + 1 }`,
+		`for (key, value of { a: 1 }) { count = count + 1 }`,
+	} {
+		if _, err := interp.EvalString("count = 0\n"+loop, nil); err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+			t.Errorf("%s: expected the loop to stop with the context, got %v", loop, err)
+		}
+		if got := interp.GetVariables()["count"].String(); got != "0" {
+			t.Errorf("%s: expected no iterations, got %s", loop, got)
+		}
+	}
+}
+
 func TestInterpreter_SetContext_NilReturnsBackground(t *testing.T) {
 	interp := New(nil)
 
@@ -1009,3 +1192,59 @@ func TestConcurrentEmitEventWithEval(t *testing.T) {
 		t.Error(errMsg)
 	}
 }
+
+func TestDestructuringAssignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"array from tool", "tool minMax(a, b) { return [a, b] }\nlo, hi = minMax(1, 9)\nresult = [hi, lo]", "[9, 1]"},
+		{"swap", "a = 1\nb = 2\na, b = [b, a]\nresult = [a, b]", "[2, 1]"},
+		{"missing elements are null", "a, b, c = [1]\nresult = [a, b, c]", "[1, null, null]"},
+		{"extra elements are ignored", "a, b = [1, 2, 3]\nresult = [a, b]", "[1, 2]"},
+		{"object shorthand", "{ x, y } = { x: 1, y: 2, z: 3 }\nresult = [x, y]", "[1, 2]"},
+		{"object rename", "{ x: left } = { x: 5 }\nresult = left", "5"},
+		{"missing property is null", "{ w } = { x: 1 }\nresult = w", "null"},
+		{"properties of other values", "{ length } = [1, 2, 3]\nresult = length", "3"},
+		{"updates outer variable", "a = 0\ntool f() {\n  a, b = [1, 2]\n}\nf()\nresult = a", "1"},
+		{"shorthand object literal", "x = 1\ny = 2\nobj = { x, y }\nresult = [obj.x, obj.y]", "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			result, err := interp.EvalString(tt.input, nil)
+			if err != nil {
+				t.Fatalf("interpreter error: %v", err)
+			}
+			value, ok := result.Env.Get("result")
+			if !ok {
+				t.Fatalf("failed to get result")
+			}
+			if value.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
+
+func TestDestructuringAssignment_Errors(t *testing.T) {
+	tests := []struct {
+		input  string
+		errMsg string
+	}{
+		{"a, b = \"ab\"", "cannot destructure string into a, b: expected an array (line 1, column 1)"},
+		{"a, b = { a: 1 }", "cannot destructure object into a, b: expected an array"},
+		{"{ x } = null", "cannot destructure null (line 1, column 1)"},
+		{"const a = 1\na, b = [2, 3]", "cannot reassign constant 'a'"},
+	}
+
+	for _, tt := range tests {
+		interp := New(nil)
+		_, err := interp.EvalString(tt.input, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.input, tt.errMsg, err)
+		}
+	}
+}
@@ -1,14 +1,27 @@
 package interpreter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 
 	"github.com/kunchenguid/gsh/internal/script/mcp"
 	"github.com/kunchenguid/gsh/internal/script/parser"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+	"golang.org/x/term"
 )
 
+// EventMCPRestart is emitted after a crashed MCP server has been restarted
+const EventMCPRestart = "mcp.restart"
+
+// isTerminal reports whether f is attached to a terminal (replaceable for testing)
+var isTerminal = func(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
 // MCPProxyValue represents a proxy object for an MCP server
 // It allows calling tools via member expressions (e.g., filesystem.read_file)
 type MCPProxyValue struct {
@@ -28,8 +41,22 @@ func (m *MCPProxyValue) Equals(other Value) bool {
 	return false
 }
 
-// GetProperty returns a tool from this MCP server
+// GetProperty returns a tool from this MCP server, or for "*" (filesystem.*) an array of
+// all its tools sorted by name
 func (m *MCPProxyValue) GetProperty(name string) (Value, error) {
+	if name == "*" {
+		names, err := m.Manager.ListTools(m.ServerName)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(names)
+		tools := make([]Value, len(names))
+		for idx, toolName := range names {
+			tools[idx] = &MCPToolValue{ServerName: m.ServerName, ToolName: toolName, Manager: m.Manager}
+		}
+		return &ArrayValue{Elements: tools}, nil
+	}
+
 	// Check if the tool exists
 	tool, err := m.Manager.GetTool(m.ServerName, name)
 	if err != nil {
@@ -92,6 +119,9 @@ func (m *MCPToolValue) Call(args map[string]interface{}) (Value, error) {
 // evalMcpDeclaration evaluates an MCP server declaration
 func (i *Interpreter) evalMcpDeclaration(env *Environment, node *parser.McpDeclaration) (Value, error) {
 	serverName := node.Name.Value
+	if err := checkNotConstant(env, node.Name); err != nil {
+		return nil, err
+	}
 
 	// Build the server config from the declaration
 	config := mcp.ServerConfig{}
@@ -149,6 +179,13 @@ func (i *Interpreter) evalMcpDeclaration(env *Environment, node *parser.McpDecla
 				return nil, fmt.Errorf("MCP config 'url' must be a string, got %s", value.Type())
 			}
 
+		case "socket":
+			if strVal, ok := value.(*StringValue); ok {
+				config.Socket = strVal.Value
+			} else {
+				return nil, fmt.Errorf("MCP config 'socket' must be a string, got %s", value.Type())
+			}
+
 		case "headers":
 			if objVal, ok := value.(*ObjectValue); ok {
 				headers := make(map[string]string)
@@ -165,27 +202,83 @@ func (i *Interpreter) evalMcpDeclaration(env *Environment, node *parser.McpDecla
 				return nil, fmt.Errorf("MCP config 'headers' must be an object, got %s", value.Type())
 			}
 
+		case "autoRestart":
+			if boolVal, ok := value.(*BoolValue); ok {
+				config.DisableAutoRestart = !boolVal.Value
+			} else {
+				return nil, fmt.Errorf("MCP config 'autoRestart' must be a boolean, got %s", value.Type())
+			}
+
 		default:
 			return nil, fmt.Errorf("unknown MCP config field: '%s'", key)
 		}
 	}
 
-	// Register the server with the MCP manager
+	proxy, err := i.registerMCPServer(serverName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register the proxy in the environment
+	env.Set(serverName, proxy)
+
+	return proxy, nil
+}
+
+// registerMCPServer starts a server with the MCP manager and returns a proxy for calling its tools
+func (i *Interpreter) registerMCPServer(serverName string, config mcp.ServerConfig) (*MCPProxyValue, error) {
+	// Spawning can take seconds, so say what we're waiting on
+	stopStatus := showMCPStartupStatus(serverName)
 	err := i.mcpManager.RegisterServer(serverName, config)
+	stopStatus()
 	if err != nil {
 		return nil, fmt.Errorf("failed to register MCP server '%s': %w", serverName, err)
 	}
 
-	// Create a proxy object for the server
-	proxy := &MCPProxyValue{
+	return &MCPProxyValue{
 		ServerName: serverName,
 		Manager:    i.mcpManager,
+	}, nil
+}
+
+// showMCPStartupStatus shows "Starting MCP server <name>…" while a server spawns and returns
+// a function that clears it. Terminals get a spinner from the same manager gsh.ui.spinner uses;
+// otherwise a plain line goes to stderr if it's a terminal, so piped stdout stays clean.
+func showMCPStartupStatus(serverName string) func() {
+	message := fmt.Sprintf("Starting MCP server %s…", serverName)
+	if isTerminal(os.Stdout) {
+		spinner := spinnerManager.NewSpinner()
+		spinner.SetMessage(message)
+		return spinner.Start(context.Background())
 	}
+	if isTerminal(os.Stderr) {
+		fmt.Fprintln(os.Stderr, message)
+	}
+	return func() {}
+}
 
-	// Register the proxy in the environment
-	env.Set(serverName, proxy)
+// onMCPRestart emits mcp.restart when the MCP manager restarts a crashed server
+func (i *Interpreter) onMCPRestart(serverName string, attempt int, err error) {
+	if i.logger != nil {
+		i.logger.Warn("restarted MCP server", zap.String("server", serverName), zap.Int("attempt", attempt), zap.Error(err))
+	}
+	i.EmitEvent(EventMCPRestart, createMCPRestartContext(serverName, attempt, err))
+}
 
-	return proxy, nil
+// createMCPRestartContext creates the context object for the mcp.restart event
+// ctx: { server: string, attempt: number, error: string }
+func createMCPRestartContext(serverName string, attempt int, err error) Value {
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"server":  {Value: &StringValue{Value: serverName}},
+			"attempt": {Value: &NumberValue{Value: float64(attempt)}},
+			"error":   {Value: &StringValue{Value: errMsg}},
+		},
+	}
 }
 
 // mcpResultToValue converts an MCP tool result to a Value
@@ -0,0 +1,125 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kunchenguid/gsh/internal/script/mcp"
+)
+
+// mcpJSONServer is one server entry in an mcp.json file, the format shared by
+// Claude Desktop, Cursor, VS Code and other MCP clients
+type mcpJSONServer struct {
+	Command  string            `json:"command"`
+	Args     []string          `json:"args"`
+	Env      map[string]string `json:"env"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	Disabled bool              `json:"disabled"`
+}
+
+// mcpJSONFile is the top level of an mcp.json file. Most clients use "mcpServers";
+// VS Code uses "servers".
+type mcpJSONFile struct {
+	MCPServers map[string]mcpJSONServer `json:"mcpServers"`
+	Servers    map[string]mcpJSONServer `json:"servers"`
+}
+
+// mcpImportedServer is a server definition read from an mcp.json file
+type mcpImportedServer struct {
+	Name   string
+	Config mcp.ServerConfig
+}
+
+// parseMCPJSON reads server definitions from mcp.json content, sorted by name.
+// Servers marked "disabled" are skipped.
+func parseMCPJSON(data []byte) ([]mcpImportedServer, error) {
+	var file mcpJSONFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if file.MCPServers == nil && file.Servers == nil {
+		return nil, fmt.Errorf(`expected an "mcpServers" or "servers" object`)
+	}
+
+	entries := make(map[string]mcpJSONServer, len(file.MCPServers)+len(file.Servers))
+	for name, server := range file.Servers {
+		entries[name] = server
+	}
+	for name, server := range file.MCPServers {
+		entries[name] = server
+	}
+
+	servers := make([]mcpImportedServer, 0, len(entries))
+	for name, server := range entries {
+		if server.Disabled {
+			continue
+		}
+		if server.Command == "" && server.URL == "" {
+			return nil, fmt.Errorf("server '%s' must specify either command or url", name)
+		}
+		servers = append(servers, mcpImportedServer{
+			Name: name,
+			Config: mcp.ServerConfig{
+				Command: server.Command,
+				Args:    server.Args,
+				Env:     server.Env,
+				URL:     server.URL,
+				Headers: server.Headers,
+			},
+		})
+	}
+	sort.Slice(servers, func(a, b int) bool { return servers[a].Name < servers[b].Name })
+	return servers, nil
+}
+
+// builtinMcpImport implements mcpImport(path), which starts every server defined in an
+// mcp.json file and returns an object mapping server names to their proxies.
+// "~" expands to the home directory; relative paths are resolved against the shell's
+// working directory.
+func (i *Interpreter) builtinMcpImport(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("mcpImport() takes exactly 1 argument (path: string), got %d", len(args))
+	}
+	pathVal, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("mcpImport() argument must be a string, got %s", args[0].Type())
+	}
+
+	path := pathVal.Value
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("mcpImport(): %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	if !filepath.IsAbs(path) {
+		if dir := i.GetWorkingDir(); dir != "" {
+			path = filepath.Join(dir, path)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mcpImport(): %w", err)
+	}
+	servers, err := parseMCPJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("mcpImport(): %s: %w", pathVal.Value, err)
+	}
+
+	result := &ObjectValue{Properties: make(map[string]*PropertyDescriptor, len(servers))}
+	for _, server := range servers {
+		proxy, err := i.registerMCPServer(server.Name, server.Config)
+		if err != nil {
+			return nil, fmt.Errorf("mcpImport(): %w", err)
+		}
+		result.Properties[server.Name] = &PropertyDescriptor{Value: proxy, ReadOnly: true}
+	}
+	return result, nil
+}
@@ -1,11 +1,18 @@
 package interpreter
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/kunchenguid/gsh/internal/repl/render"
 	"github.com/kunchenguid/gsh/internal/script/lexer"
 	"github.com/kunchenguid/gsh/internal/script/parser"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // TestMcpDeclarationParsing tests that MCP declarations are parsed correctly
@@ -210,6 +217,26 @@ mcp test {
 `,
 			wantErr: "must be strings",
 		},
+		{
+			name: "autoRestart must be boolean",
+			input: `
+mcp test {
+	command: "test",
+	args: [],
+	autoRestart: "no",
+}
+`,
+			wantErr: "'autoRestart' must be a boolean",
+		},
+		{
+			name: "socket must be string",
+			input: `
+mcp test {
+	socket: 123,
+}
+`,
+			wantErr: "'socket' must be a string",
+		},
 		{
 			name: "unknown config field",
 			input: `
@@ -604,3 +631,260 @@ c = server2
 		t.Error("expected 'a' and 'c' to be different (different MCP servers)")
 	}
 }
+
+func TestMcpRestartEvent(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err := interp.EvalString(`
+restarts = []
+tool onRestart(ctx, next) {
+	restarts.push(ctx.server + ":" + ctx.attempt + ":" + ctx.error)
+	return next(ctx)
+}
+gsh.use("mcp.restart", onRestart)
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	interp.onMCPRestart("filesystem", 2, fmt.Errorf("connection closed"))
+
+	restarts, _ := interp.globalEnv.Get("restarts")
+	arr := restarts.(*ArrayValue)
+	if len(arr.Elements) != 1 || arr.Elements[0].String() != "filesystem:2:connection closed" {
+		t.Errorf("unexpected restart events: %v", arr)
+	}
+}
+
+// TestMcpStartupSpinner tests that a spinner names the server while it starts and is cleared afterwards
+func TestMcpStartupSpinner(t *testing.T) {
+	var buf bytes.Buffer
+	manager := render.NewSpinnerManager(&buf)
+	originalManager := GetSpinnerManager()
+	SetSpinnerManager(manager)
+	defer SetSpinnerManager(originalManager)
+
+	originalIsTerminal := isTerminal
+	isTerminal = func(f *os.File) bool { return f == os.Stdout }
+	defer func() { isTerminal = originalIsTerminal }()
+
+	interp := New(nil)
+	defer interp.Close()
+
+	// The server exits without speaking MCP, which must still clear the spinner
+	_, err := interp.EvalString(`
+mcp slowstart {
+	command: "sh",
+	args: ["-c", "sleep 0.2"],
+}
+`, nil)
+	if err == nil {
+		t.Fatal("expected error starting a server that doesn't speak MCP")
+	}
+
+	if !strings.Contains(buf.String(), "Starting MCP server slowstart…") {
+		t.Errorf("expected spinner message, got %q", buf.String())
+	}
+	if manager.LiveCount() != 0 {
+		t.Errorf("expected spinner to be stopped, got %d live spinners", manager.LiveCount())
+	}
+}
+
+func TestParseMCPJSON(t *testing.T) {
+	servers, err := parseMCPJSON([]byte(`{
+		"mcpServers": {
+			"filesystem": {
+				"command": "npx",
+				"args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"],
+				"env": {"DEBUG": "1"}
+			},
+			"remote": {
+				"url": "https://example.com/mcp",
+				"headers": {"Authorization": "Bearer token"}
+			},
+			"old": {"command": "old-server", "disabled": true}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 servers (disabled one skipped), got %d", len(servers))
+	}
+	if servers[0].Name != "filesystem" || servers[0].Config.Command != "npx" || len(servers[0].Config.Args) != 3 || servers[0].Config.Env["DEBUG"] != "1" {
+		t.Errorf("unexpected filesystem server: %+v", servers[0])
+	}
+	if servers[1].Name != "remote" || servers[1].Config.URL != "https://example.com/mcp" || servers[1].Config.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("unexpected remote server: %+v", servers[1])
+	}
+
+	// VS Code spells the top-level key "servers"
+	servers, err = parseMCPJSON([]byte(`{"servers": {"git": {"type": "stdio", "command": "uvx", "args": ["mcp-server-git"]}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Name != "git" || servers[0].Config.Command != "uvx" {
+		t.Errorf("unexpected servers: %+v", servers)
+	}
+
+	errorCases := map[string]string{
+		"invalid JSON":       `{"mcpServers": `,
+		"no servers key":     `{"other": {}}`,
+		"no command or url":  `{"mcpServers": {"broken": {"args": ["x"]}}}`,
+		"wrong args type":    `{"mcpServers": {"broken": {"command": "x", "args": "y"}}}`,
+		"top level is array": `[]`,
+	}
+	for name, input := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseMCPJSON([]byte(input)); err == nil {
+				t.Errorf("expected error for %s", input)
+			}
+		})
+	}
+}
+
+func TestMcpImport(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("empty.json", `{"mcpServers": {}}`)
+	write("broken.json", `{"mcpServers": {"nope": {"command": "gsh-nonexistent-mcp-server"}}}`)
+
+	interp := New(nil)
+	defer interp.Close()
+	origin := &ScriptOrigin{Type: OriginFilesystem, BasePath: dir}
+
+	result, err := interp.EvalString(fmt.Sprintf(`mcpImport(%q)`, filepath.Join(dir, "empty.json")), origin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj, ok := result.FinalResult.(*ObjectValue); !ok || len(obj.Properties) != 0 {
+		t.Errorf("expected an empty object, got %s", result.FinalResult.String())
+	}
+
+	_, err = interp.EvalString(fmt.Sprintf(`mcpImport(%q)`, filepath.Join(dir, "broken.json")), origin)
+	if err == nil || !strings.Contains(err.Error(), "'nope'") {
+		t.Errorf("expected error naming the server that failed to start, got %v", err)
+	}
+
+	_, err = interp.EvalString(fmt.Sprintf(`mcpImport(%q)`, filepath.Join(dir, "missing.json")), origin)
+	if err == nil {
+		t.Error("expected error for a missing file")
+	}
+	if _, err := interp.EvalString(`mcpImport(42)`, origin); err == nil {
+		t.Error("expected error for a non-string path")
+	}
+}
+
+// TestHelperMCPServer is not a real test: it runs this test binary as a stdio MCP server
+// with a few tools when GSH_TEST_MCP_HELPER is set, for tests that need a live server
+func TestHelperMCPServer(t *testing.T) {
+	if os.Getenv("GSH_TEST_MCP_HELPER") != "1" {
+		return
+	}
+
+	server := mcpsdk.NewServer(&mcpsdk.Implementation{Name: "helper", Version: "1.0.0"}, nil)
+	for _, name := range []string{"read_file", "write_file", "list_directory"} {
+		mcpsdk.AddTool(server, &mcpsdk.Tool{Name: name}, func(ctx context.Context, req *mcpsdk.CallToolRequest, input struct{}) (*mcpsdk.CallToolResult, any, error) {
+			return &mcpsdk.CallToolResult{Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: name}}}, nil, nil
+		})
+	}
+	_ = server.Run(context.Background(), &mcpsdk.StdioTransport{})
+	os.Exit(0)
+}
+
+// helperMCPServer declares the helper server under name
+func helperMCPServer(t *testing.T, interp *Interpreter, name string) {
+	t.Helper()
+	_, err := interp.EvalString(fmt.Sprintf(`
+mcp %s {
+	command: %q,
+	args: ["-test.run=^TestHelperMCPServer$"],
+	env: { GSH_TEST_MCP_HELPER: "1" },
+}
+`, name, os.Args[0]), nil)
+	if err != nil {
+		t.Fatalf("failed to start the helper MCP server: %v", err)
+	}
+}
+
+func TestMcpWildcard(t *testing.T) {
+	mock := &toolListMockProvider{}
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(mock)
+	helperMCPServer(t, interp, "fs")
+
+	result, err := interp.EvalString(`fs.*`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.FinalResult.String(); got != "[<mcp tool: fs.list_directory>, <mcp tool: fs.read_file>, <mcp tool: fs.write_file>]" {
+		t.Errorf("expected every tool sorted by name, got %s", got)
+	}
+
+	_, err = interp.EvalString(`
+model m { provider: "tool-list-mock", model: "test" }
+tool status() { return "ok" }
+agent Reader { model: m, tools: [fs.*, status], excludeTools: ["fs.write_*"] }
+"go" | Reader
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mock.offered[0], ","); got != "list_directory,read_file,status" {
+		t.Errorf("expected the server's tools without write_file, got %q", got)
+	}
+
+	if _, err := interp.EvalString(`obj = {}
+obj.*`, nil); err == nil || !strings.Contains(err.Error(), "'.*' can only be used on an MCP server") {
+		t.Errorf("expected an error for '.*' on an object, got %v", err)
+	}
+}
+
+func TestMcpSameToolOnTwoServers(t *testing.T) {
+	mock := &toolListMockProvider{}
+	interp := New(nil)
+	defer interp.Close()
+	interp.providerRegistry.Register(mock)
+	helperMCPServer(t, interp, "home")
+	helperMCPServer(t, interp, "work")
+
+	result, err := interp.EvalString(`
+model m { provider: "tool-list-mock", model: "test" }
+tool status() { return "ok" }
+
+approvals = []
+tool approve(ctx, next) {
+	approvals.push(ctx.toolCall.name)
+	return { approved: true }
+}
+gsh.use("agent.tool.approval", approve)
+
+agent Syncer {
+	model: m,
+	tools: [work.read_file, home.read_file, home.list_directory, status],
+	requireApproval: ["work.read_file"],
+}
+conv = "go" | Syncer
+conv.messages[2].content
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the clashing tools are qualified with their server
+	if got := strings.Join(mock.offered[0], ","); got != "work__read_file,home__read_file,list_directory,status" {
+		t.Errorf("expected the read_file tools to be qualified, got %q", got)
+	}
+	if got := result.FinalResult.String(); !strings.Contains(got, "read_file") {
+		t.Errorf("expected the work server's read_file result, got %q", got)
+	}
+	if got := interp.GetVariables()["approvals"].String(); got != `["work__read_file"]` {
+		t.Errorf("expected requireApproval to match work.read_file, got %s", got)
+	}
+}
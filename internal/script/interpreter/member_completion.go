@@ -0,0 +1,93 @@
+package interpreter
+
+import (
+	"sort"
+	"strings"
+)
+
+// PropertyLister is implemented by object values that can enumerate their properties.
+// It lets tooling such as REPL tab-completion discover members like gsh.tools.exec.
+type PropertyLister interface {
+	PropertyNames() []string
+}
+
+// PropertyNames returns the sorted property names of v, or nil if v has none to list.
+func PropertyNames(v Value) []string {
+	var names []string
+	switch val := v.(type) {
+	case *ObjectValue:
+		names = sortedKeys(val.Properties)
+	case PropertyLister:
+		names = val.PropertyNames()
+	default:
+		return nil
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompleteMemberPath completes a dotted member path such as "gsh.tools.ex" against
+// the global scope. It returns the full paths of all matching members, e.g.
+// ["gsh.tools.exec"], or nil if the path does not resolve to an object.
+func (i *Interpreter) CompleteMemberPath(path string) []string {
+	dot := strings.LastIndex(path, ".")
+	if dot <= 0 {
+		return nil
+	}
+	base, prefix := path[:dot], path[dot+1:]
+
+	segments := strings.Split(base, ".")
+	value, ok := i.globalEnv.Get(segments[0])
+	if !ok {
+		return nil
+	}
+	for _, name := range segments[1:] {
+		value = memberValue(value, name)
+		if value == nil {
+			return nil
+		}
+	}
+
+	var completions []string
+	for _, name := range PropertyNames(value) {
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, base+"."+name)
+		}
+	}
+	return completions
+}
+
+// memberValue returns the named property of v without evaluating any code,
+// or nil if v has no such property
+func memberValue(v Value, name string) Value {
+	var member Value
+	switch val := v.(type) {
+	case *ObjectValue:
+		prop, ok := val.Properties[name]
+		if !ok {
+			return nil
+		}
+		member = prop.Value
+	case interface{ GetProperty(string) Value }:
+		member = val.GetProperty(name)
+	default:
+		return nil
+	}
+	if dv, ok := member.(*DynamicValue); ok {
+		member = dv.GetDynamicValue()
+	}
+	if _, isNull := member.(*NullValue); isNull {
+		return nil
+	}
+	return member
+}
+
+// sortedKeys returns the keys of a property map in sorted order
+func sortedKeys(props map[string]*PropertyDescriptor) []string {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
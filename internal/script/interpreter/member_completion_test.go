@@ -0,0 +1,42 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteMemberPath(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err := interp.EvalString(`config = { name: "x", nested: { alpha: 1, beta: 2 } }`, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"gsh.tools.", []string{"gsh.tools.edit_file", "gsh.tools.exec", "gsh.tools.grep", "gsh.tools.view_file"}},
+		{"gsh.tools.ex", []string{"gsh.tools.exec"}},
+		{"gsh.logging.", []string{"gsh.logging.file", "gsh.logging.level"}},
+		{"gsh.ui.styles.b", []string{"gsh.ui.styles.bold"}},
+		{"config.nested.", []string{"config.nested.alpha", "config.nested.beta"}},
+		{"gsh.tools.zzz", nil},
+		{"gsh.version.", nil},
+		{"gsh.missing.", nil},
+		{"unknown.", nil},
+		{"gsh", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, interp.CompleteMemberPath(tt.path))
+		})
+	}
+
+	top := interp.CompleteMemberPath("gsh.")
+	for _, name := range []string{"gsh.terminal", "gsh.logging", "gsh.tools", "gsh.ui", "gsh.models", "gsh.version"} {
+		assert.Contains(t, top, name)
+	}
+}
@@ -0,0 +1,50 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Agents and models declared in the global scope can be addressed with @name in REPL agent
+// chat. Names starting with "__" belong to the default config and are not mentionable.
+
+// isMentionable reports whether a global binding can be addressed with @name
+func isMentionable(name string, value Value) bool {
+	if strings.HasPrefix(name, "__") {
+		return false
+	}
+	switch value.(type) {
+	case *AgentValue, *ModelValue:
+		return true
+	}
+	return false
+}
+
+// MentionNames returns the sorted names of the agents and models that can be @mentioned
+func (i *Interpreter) MentionNames() []string {
+	var names []string
+	for name, value := range i.globalEnv.store {
+		if isMentionable(name, value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinGshMention implements gsh.mention(name), which returns the agent or model
+// declared as name, or null if there is no such mentionable declaration
+func (i *Interpreter) builtinGshMention(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("gsh.mention() takes 1 argument (name: string), got %d", len(args))
+	}
+	name, ok := args[0].(*StringValue)
+	if !ok {
+		return nil, fmt.Errorf("gsh.mention() argument must be a string, got %s", args[0].Type())
+	}
+	if value, ok := i.globalEnv.store[name.Value]; ok && isMentionable(name.Value, value) {
+		return value, nil
+	}
+	return &NullValue{}, nil
+}
@@ -0,0 +1,40 @@
+package interpreter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMention(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(`
+model fast { provider: "openai", model: "gpt-5-mini" }
+agent analyst { model: fast }
+agent __internal { model: fast }
+notAnAgent = "analyst"
+mentions = [gsh.mention("analyst"), gsh.mention("fast"), gsh.mention("__internal"), gsh.mention("notAnAgent"), gsh.mention("missing")]
+mentions
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := result.FinalResult.(*ArrayValue).Elements
+	if agent, ok := got[0].(*AgentValue); !ok || agent.Name != "analyst" {
+		t.Errorf("expected agent analyst, got %s", got[0].String())
+	}
+	if model, ok := got[1].(*ModelValue); !ok || model.Name != "fast" {
+		t.Errorf("expected model fast, got %s", got[1].String())
+	}
+	for idx, value := range got[2:] {
+		if _, ok := value.(*NullValue); !ok {
+			t.Errorf("value %d: expected null, got %s", idx+2, value.String())
+		}
+	}
+
+	if names := interp.MentionNames(); !reflect.DeepEqual(names, []string{"analyst", "fast"}) {
+		t.Errorf("expected mention names [analyst fast], got %v", names)
+	}
+}
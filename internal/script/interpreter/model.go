@@ -1,7 +1,9 @@
 package interpreter
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/kunchenguid/gsh/internal/script/parser"
 )
@@ -9,9 +11,13 @@ import (
 // evalModelDeclaration evaluates a model declaration
 func (i *Interpreter) evalModelDeclaration(env *Environment, node *parser.ModelDeclaration) (Value, error) {
 	modelName := node.Name.Value
+	if err := checkNotConstant(env, node.Name); err != nil {
+		return nil, err
+	}
 
 	// Evaluate each config field and store as Value
 	config := make(map[string]Value)
+	var rateLimiter *modelRateLimiter
 
 	for key, expr := range node.Config {
 		value, err := i.evalExpression(env, expr)
@@ -36,6 +42,14 @@ func (i *Interpreter) evalModelDeclaration(env *Environment, node *parser.ModelD
 			if _, ok := value.(*StringValue); !ok {
 				return nil, fmt.Errorf("model config 'baseURL' must be a string, got %s", value.Type())
 			}
+		case "region":
+			if _, ok := value.(*StringValue); !ok {
+				return nil, fmt.Errorf("model config 'region' must be a string, got %s", value.Type())
+			}
+		case "profile":
+			if _, ok := value.(*StringValue); !ok {
+				return nil, fmt.Errorf("model config 'profile' must be a string, got %s", value.Type())
+			}
 		case "temperature":
 			if _, ok := value.(*NumberValue); !ok {
 				return nil, fmt.Errorf("model config 'temperature' must be a number, got %s", value.Type())
@@ -57,11 +71,38 @@ func (i *Interpreter) evalModelDeclaration(env *Environment, node *parser.ModelD
 					return nil, fmt.Errorf("model config 'headers.%s' must be a string, got %s", headerKey, headerVal.Type())
 				}
 			}
+		case "stopSequences":
+			arr, ok := value.(*ArrayValue)
+			if !ok {
+				return nil, fmt.Errorf("model config 'stopSequences' must be an array of strings, got %s", value.Type())
+			}
+			for idx, elem := range arr.Elements {
+				str, ok := elem.(*StringValue)
+				if !ok {
+					return nil, fmt.Errorf("model config 'stopSequences[%d]' must be a string, got %s", idx, elem.Type())
+				}
+				if str.Value == "" {
+					return nil, fmt.Errorf("model config 'stopSequences[%d]' must not be empty", idx)
+				}
+			}
+		case "strictTools":
+			if _, ok := value.(*BoolValue); !ok {
+				return nil, fmt.Errorf("model config 'strictTools' must be a boolean, got %s", value.Type())
+			}
+		case "vision":
+			if _, ok := value.(*BoolValue); !ok {
+				return nil, fmt.Errorf("model config 'vision' must be a boolean, got %s", value.Type())
+			}
 		case "extraBody":
 			// extraBody must be an object (values can be any type)
 			if _, ok := value.(*ObjectValue); !ok {
 				return nil, fmt.Errorf("model config 'extraBody' must be an object, got %s", value.Type())
 			}
+		case "rateLimit":
+			rateLimiter, err = parseRateLimit(modelName, value)
+			if err != nil {
+				return nil, err
+			}
 			// Allow other fields without validation for extensibility
 		}
 
@@ -80,11 +121,42 @@ func (i *Interpreter) evalModelDeclaration(env *Environment, node *parser.ModelD
 		}
 	}
 
+	// Router models must declare valid weighted options up front
+	if provider != nil && provider.Name() == "router" {
+		if _, err := parseRouterOptions(modelName, config["options"]); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the model value with resolved provider
 	model := &ModelValue{
-		Name:     modelName,
-		Config:   config,
-		Provider: provider,
+		Name:          modelName,
+		Config:        config,
+		Provider:      provider,
+		MissingAPIKey: missingAPIKeySource(node.Config["apiKey"], config["apiKey"]),
+	}
+
+	// Throttle calls when a rate limit is configured, letting the UI know when a call waits
+	if rateLimiter != nil {
+		rateLimiter.onWait = func(wait time.Duration) {
+			i.EmitEvent(EventModelRateLimit, createModelRateLimitContext(model, wait))
+		}
+		model.rateLimiter = rateLimiter
+	}
+
+	// Fire model.request and model.response around every call of the model. A router
+	// skips them, since the model it routes each request to fires its own.
+	if provider == nil || provider.Name() != "router" {
+		model.onRequest = func(ctx context.Context, request *ChatRequest) {
+			i.emitModelRequest(modelCallAgent(ctx), model, request)
+			i.traceModelRequest(model, *request)
+		}
+		model.onResponse = func(ctx context.Context, response *ChatResponse, err error) {
+			i.traceModelResponse(model, response, err)
+			if err == nil {
+				i.emitModelResponse(modelCallAgent(ctx), model, response)
+			}
+		}
 	}
 
 	// Register the model in the environment
@@ -92,3 +164,60 @@ func (i *Interpreter) evalModelDeclaration(env *Environment, node *parser.ModelD
 
 	return model, nil
 }
+
+// missingAPIKeySource returns env.NAME or keyring.NAME when an apiKey written that way
+// evaluated to null or an empty string, and "" otherwise
+func missingAPIKeySource(expr parser.Expression, value Value) string {
+	member, ok := expr.(*parser.MemberExpression)
+	if !ok {
+		return ""
+	}
+	if object, ok := member.Object.(*parser.Identifier); !ok || (object.Value != "env" && object.Value != "keyring") {
+		return ""
+	}
+	source := member.Object.(*parser.Identifier).Value + "." + member.Property.Value
+	switch v := value.(type) {
+	case *NullValue:
+		return source
+	case *StringValue:
+		if v.Value == "" {
+			return source
+		}
+	}
+	return ""
+}
+
+// modelStopSequences returns the stopSequences configured for a model, or nil if there are none
+func modelStopSequences(model *ModelValue) []string {
+	if model == nil {
+		return nil
+	}
+	arr, ok := model.Config["stopSequences"].(*ArrayValue)
+	if !ok || len(arr.Elements) == 0 {
+		return nil
+	}
+	sequences := make([]string, 0, len(arr.Elements))
+	for _, elem := range arr.Elements {
+		if str, ok := elem.(*StringValue); ok {
+			sequences = append(sequences, str.Value)
+		}
+	}
+	return sequences
+}
+
+// checkModelAcceptsImages returns an error if the request carries images but its
+// model is not declared with vision: true
+func checkModelAcceptsImages(request ChatRequest) error {
+	if request.Model == nil {
+		return nil
+	}
+	if vision, ok := request.Model.Config["vision"].(*BoolValue); ok && vision.Value {
+		return nil
+	}
+	for _, msg := range request.Messages {
+		if len(msg.Images) > 0 {
+			return fmt.Errorf("model '%s' does not accept images; set vision: true in its declaration if it is multimodal", request.Model.Name)
+		}
+	}
+	return nil
+}
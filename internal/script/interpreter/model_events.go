@@ -0,0 +1,131 @@
+package interpreter
+
+import "context"
+
+// EventModelRequest is emitted before a request is sent to a model
+const EventModelRequest = "model.request"
+
+// EventModelResponse is emitted after a model call returns a response
+const EventModelResponse = "model.response"
+
+// modelCallAgentKey is the context key for the agent making a model call
+type modelCallAgentKey struct{}
+
+// withModelCallAgent returns ctx marked as the context of a call made by agent
+func withModelCallAgent(ctx context.Context, agent *AgentValue) context.Context {
+	return context.WithValue(ctx, modelCallAgentKey{}, agent)
+}
+
+// modelCallAgent returns the agent making the call ctx belongs to, or nil for calls
+// made outside an agent, such as command prediction
+func modelCallAgent(ctx context.Context) *AgentValue {
+	agent, _ := ctx.Value(modelCallAgentKey{}).(*AgentValue)
+	return agent
+}
+
+// emitModelRequest fires model.request before model is called and applies the message
+// content handlers changed in ctx.request.messages to request. agent is nil for calls
+// made outside an agent.
+// ctx: { agent: { name, metadata, ... } | null, model: string, request: { messages: [...], tools: [...] } }
+func (i *Interpreter) emitModelRequest(agent *AgentValue, model *ModelValue, request *ChatRequest) {
+	if len(i.eventManager.GetHandlers(EventModelRequest)) == 0 {
+		return
+	}
+
+	messages := make([]Value, len(request.Messages))
+	for idx, msg := range request.Messages {
+		messages[idx] = chatMessageToObjectValue(msg)
+	}
+	tools := make([]Value, len(request.Tools))
+	for idx, tool := range request.Tools {
+		tools[idx] = &ObjectValue{
+			Properties: map[string]*PropertyDescriptor{
+				"name":        {Value: &StringValue{Value: tool.Name}},
+				"description": {Value: &StringValue{Value: tool.Description}},
+			},
+		}
+	}
+	requestObj := &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"messages": {Value: &ArrayValue{Elements: messages}},
+			"tools":    {Value: &ArrayValue{Elements: tools}},
+		},
+	}
+	i.EmitEvent(EventModelRequest, &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"agent":   {Value: agentValueToContextObject(agent)},
+			"model":   {Value: &StringValue{Value: model.Name}},
+			"request": {Value: requestObj},
+		},
+	})
+
+	// Only message content can be changed; the messages array itself may have been replaced
+	updated, ok := requestObj.GetPropertyValue("messages").(*ArrayValue)
+	if !ok {
+		return
+	}
+	// The caller still holds the original messages, e.g. as a response cache key
+	request.Messages = append([]ChatMessage(nil), request.Messages...)
+	for idx := 0; idx < len(updated.Elements) && idx < len(request.Messages); idx++ {
+		msgObj, ok := updated.Elements[idx].(*ObjectValue)
+		if !ok {
+			continue
+		}
+		content, ok := msgObj.GetPropertyValue("content").(*StringValue)
+		if !ok || content.Value == request.Messages[idx].Content {
+			continue
+		}
+		request.Messages[idx].Content = content.Value
+		request.Messages[idx].ContentParts = nil
+	}
+}
+
+// emitModelResponse fires model.response after a model call succeeds and applies a
+// change handlers made to ctx.response.content to response.
+// ctx: { agent: { name, metadata, ... } | null, model: string, response: { content, reasoning, toolCalls, finishReason, usage } }
+func (i *Interpreter) emitModelResponse(agent *AgentValue, model *ModelValue, response *ChatResponse) {
+	if len(i.eventManager.GetHandlers(EventModelResponse)) == 0 {
+		return
+	}
+
+	toolCalls := make([]Value, len(response.ToolCalls))
+	for idx, tc := range response.ToolCalls {
+		toolCalls[idx] = &ObjectValue{
+			Properties: map[string]*PropertyDescriptor{
+				"id":        {Value: &StringValue{Value: tc.ID}},
+				"name":      {Value: &StringValue{Value: tc.Name}},
+				"arguments": {Value: InterfaceToValue(tc.Arguments)},
+			},
+		}
+	}
+	var usage Value = &NullValue{}
+	if response.Usage != nil {
+		usage = &ObjectValue{
+			Properties: map[string]*PropertyDescriptor{
+				"inputTokens":  {Value: &NumberValue{Value: float64(response.Usage.PromptTokens)}},
+				"outputTokens": {Value: &NumberValue{Value: float64(response.Usage.CompletionTokens)}},
+				"cachedTokens": {Value: &NumberValue{Value: float64(response.Usage.CachedTokens)}},
+			},
+		}
+	}
+	responseObj := &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"content":      {Value: &StringValue{Value: response.Content}},
+			"reasoning":    {Value: &StringValue{Value: response.Reasoning}},
+			"toolCalls":    {Value: &ArrayValue{Elements: toolCalls}},
+			"finishReason": {Value: &StringValue{Value: response.FinishReason}},
+			"usage":        {Value: usage},
+		},
+	}
+	i.EmitEvent(EventModelResponse, &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"agent":    {Value: agentValueToContextObject(agent)},
+			"model":    {Value: &StringValue{Value: model.Name}},
+			"response": {Value: responseObj},
+		},
+	})
+
+	if content, ok := responseObj.GetPropertyValue("content").(*StringValue); ok {
+		response.Content = content.Value
+	}
+}
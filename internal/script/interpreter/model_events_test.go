@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelRequestResponseEvents(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+	provider := NewSmartMockProvider()
+	interp.providerRegistry.Register(provider)
+
+	result, err := interp.EvalString(`
+model testModel {
+	provider: "smart-mock",
+	model: "test"
+}
+
+agent TestAgent {
+	model: testModel,
+	systemPrompt: "You help with accounts."
+}
+
+seen = []
+
+tool redact(ctx, next) {
+	for (msg of ctx.request.messages) {
+		msg.content = msg.content.replaceAll("4111-1111", "[card]")
+	}
+	seen.push("request " + ctx.agent.name + " " + ctx.model + " " + ctx.request.messages.length)
+	return next(ctx)
+}
+gsh.use("model.request", redact)
+
+tool tag(ctx, next) {
+	seen.push("response " + ctx.response.finishReason)
+	ctx.response.content = ctx.response.content + " [reviewed]"
+	return next(ctx)
+}
+gsh.use("model.response", tag)
+
+conv = "My card is 4111-1111" | TestAgent
+conv.lastMessage.content
+`, nil)
+	require.NoError(t, err)
+
+	require.Len(t, provider.CallHistory, 1)
+	sent := provider.CallHistory[0].Messages
+	require.Len(t, sent, 2)
+	assert.Equal(t, "My card is [card]", sent[1].Content)
+	assert.True(t, strings.HasSuffix(result.FinalResult.String(), " [reviewed]"), result.FinalResult.String())
+
+	vars := interp.GetVariables()
+	seen, ok := vars["seen"].(*ArrayValue)
+	require.True(t, ok)
+	require.Len(t, seen.Elements, 2)
+	assert.Equal(t, "request TestAgent testModel 2", seen.Elements[0].String())
+	assert.True(t, strings.HasPrefix(seen.Elements[1].String(), "response "))
+
+	// The conversation keeps what the user typed; only the request was redacted
+	conv, ok := vars["conv"].(*ConversationValue)
+	require.True(t, ok)
+	assert.Equal(t, "My card is 4111-1111", conv.Messages[0].Content)
+}
+
+func TestModelEventsOutsideAgent(t *testing.T) {
+	interp, mock := newRouterTestInterpreter(t, 0.1)
+
+	result, err := interp.EvalString(routerTestModels+`
+seen = []
+tool redact(ctx, next) {
+	for (msg of ctx.request.messages) {
+		msg.content = msg.content.replaceAll("secret", "[redacted]")
+	}
+	seen.push("request " + ctx.model + " " + (ctx.agent == null))
+	return next(ctx)
+}
+gsh.use("model.request", redact)
+
+tool logResponse(ctx, next) {
+	seen.push("response " + ctx.model)
+	return next(ctx)
+}
+gsh.use("model.response", logResponse)
+`, nil)
+	require.NoError(t, err)
+
+	// Calls made straight on a model, as command prediction does, fire the events too
+	model, _ := result.Env.Get("modelB")
+	messages := []ChatMessage{{Role: "user", Content: "my secret"}}
+	_, err = model.(*ModelValue).ChatCompletion(context.Background(), ChatRequest{Messages: messages})
+	require.NoError(t, err)
+	require.Len(t, mock.CallHistory, 1)
+	assert.Equal(t, "my [redacted]", mock.CallHistory[0].Messages[0].Content)
+	assert.Equal(t, "my secret", messages[0].Content, "the caller's messages are left as they were")
+
+	// A router only fires them for the model it picks
+	router, _ := result.Env.Get("router")
+	_, err = router.(*ModelValue).ChatCompletion(context.Background(), ChatRequest{Messages: messages})
+	require.NoError(t, err)
+
+	seen, ok := interp.GetVariables()["seen"].(*ArrayValue)
+	require.True(t, ok)
+	assert.Equal(t, `["request modelB true", "response modelB", "request modelA true", "response modelA"]`, seen.String())
+}
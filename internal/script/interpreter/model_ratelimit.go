@@ -0,0 +1,82 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// EventModelRateLimit is emitted when a model call has to wait for the model's rateLimit
+const EventModelRateLimit = "model.ratelimit"
+
+// modelRateLimiter throttles a model's chat completion calls with a token bucket:
+//
+//	model fast {
+//	    provider: "openai",
+//	    model: "gpt-5-mini",
+//	    rateLimit: { requestsPerMinute: 60 },
+//	}
+//
+// The bucket holds up to requestsPerMinute tokens, so short bursts go through immediately
+// and sustained use is spread out to the configured rate.
+type modelRateLimiter struct {
+	limiter *rate.Limiter
+
+	// onWait is called before a call blocks, with how long it will wait
+	onWait func(wait time.Duration)
+}
+
+// parseRateLimit validates a model's rateLimit config, { requestsPerMinute: <positive number> }
+func parseRateLimit(modelName string, value Value) (*modelRateLimiter, error) {
+	obj, ok := value.(*ObjectValue)
+	if !ok {
+		return nil, fmt.Errorf("model config 'rateLimit' must be an object, got %s", value.Type())
+	}
+	rpm, ok := obj.GetPropertyValue("requestsPerMinute").(*NumberValue)
+	if !ok || rpm.Value <= 0 {
+		return nil, fmt.Errorf("model '%s' config 'rateLimit.requestsPerMinute' must be a positive number, got %s",
+			modelName, obj.GetPropertyValue("requestsPerMinute").String())
+	}
+	burst := int(math.Max(1, math.Floor(rpm.Value)))
+	return &modelRateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(rpm.Value/60), burst),
+	}, nil
+}
+
+// wait blocks until the limiter allows another call, or returns ctx's error if it is
+// cancelled first.
+func (l *modelRateLimiter) wait(ctx context.Context) error {
+	reservation := l.limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if l.onWait != nil {
+		l.onWait(delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back so the cancelled call doesn't delay later ones
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+// createModelRateLimitContext creates the context object for the model.ratelimit event
+// ctx: { model: string, waitMs: number }
+func createModelRateLimitContext(model *ModelValue, wait time.Duration) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"model":  {Value: &StringValue{Value: model.Name}},
+			"waitMs": {Value: &NumberValue{Value: float64(wait.Milliseconds())}},
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestModelRateLimit(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+	mock := &scriptedMockProvider{responses: []string{"ok"}}
+	interp.providerRegistry.Register(mock)
+
+	result, err := interp.EvalString(`
+waits = []
+tool onRateLimit(ctx, next) {
+	waits.push(ctx.model + " " + ctx.waitMs)
+	return next(ctx)
+}
+gsh.use("model.ratelimit", onRateLimit)
+
+model limited { provider: "scripted-mock", model: "test", rateLimit: { requestsPerMinute: 2 } }
+limited
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model := result.FinalResult.(*ModelValue)
+
+	// The bucket holds requestsPerMinute tokens, so the first two calls don't wait
+	for n := 0; n < 2; n++ {
+		if _, err := model.ChatCompletion(context.Background(), ChatRequest{}); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", n+1, err)
+		}
+	}
+
+	// The third call blocks for about 30 seconds, until the context gives up
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = model.StreamingChatCompletion(ctx, ChatRequest{}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the throttled call to stop with the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the throttled call to return when the context ended, took %v", elapsed)
+	}
+	if len(mock.requests) != 2 {
+		t.Errorf("expected the throttled call not to reach the provider, got %d requests", len(mock.requests))
+	}
+
+	waits, _ := result.Env.Get("waits")
+	elements := waits.(*ArrayValue).Elements
+	if len(elements) != 1 {
+		t.Fatalf("expected one model.ratelimit event, got %s", waits.String())
+	}
+	var name string
+	var waitMs float64
+	if _, err := fmt.Sscan(elements[0].String(), &name, &waitMs); err != nil || name != "limited" || waitMs < 29000 || waitMs > 30000 {
+		t.Errorf("expected a ~30000ms wait for model limited, got %q", elements[0].String())
+	}
+}
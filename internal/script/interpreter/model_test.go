@@ -88,6 +88,27 @@ func TestModelDeclaration(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Model declaration with env fallback",
+			input: `model fallback {
+				provider: "openai",
+				apiKey: env.GSH_TEST_MODEL_KEY_UNSET ?? "fallback-key",
+				model: "gpt-4",
+			}`,
+			checkFunc: func(t *testing.T, result *EvalResult, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				modelVal, _ := result.Env.Get("fallback")
+				model, ok := modelVal.(*ModelValue)
+				if !ok {
+					t.Fatalf("expected *ModelValue, got %T", modelVal)
+				}
+				if apiKey := model.Config["apiKey"]; apiKey == nil || apiKey.String() != "fallback-key" {
+					t.Errorf("expected apiKey 'fallback-key', got %v", apiKey)
+				}
+			},
+		},
 		{
 			name: "Model declaration with OpenAI",
 			input: `model gpt4 {
@@ -579,6 +600,54 @@ func TestModelDeclarationErrors(t *testing.T) {
 			}`,
 			expectedError: "extraBody' must be an object",
 		},
+		{
+			name: "Model with invalid rateLimit type (not an object)",
+			input: `model bad {
+				provider: "openai",
+				rateLimit: 60,
+			}`,
+			expectedError: "rateLimit' must be an object",
+		},
+		{
+			name: "Model with non-positive requestsPerMinute",
+			input: `model bad {
+				provider: "openai",
+				rateLimit: { requestsPerMinute: 0 },
+			}`,
+			expectedError: "rateLimit.requestsPerMinute' must be a positive number, got 0",
+		},
+		{
+			name: "Model with invalid stopSequences type (not an array)",
+			input: `model bad {
+				provider: "openai",
+				stopSequences: "END",
+			}`,
+			expectedError: "stopSequences' must be an array of strings, got string",
+		},
+		{
+			name: "Model with a non-string stop sequence",
+			input: `model bad {
+				provider: "openai",
+				stopSequences: ["END", 42],
+			}`,
+			expectedError: "stopSequences[1]' must be a string, got number",
+		},
+		{
+			name: "Model with an empty stop sequence",
+			input: `model bad {
+				provider: "openai",
+				stopSequences: [""],
+			}`,
+			expectedError: "stopSequences[0]' must not be empty",
+		},
+		{
+			name: "Model with invalid vision type",
+			input: `model bad {
+				provider: "openai",
+				vision: "yes",
+			}`,
+			expectedError: "model config 'vision' must be a boolean, got string",
+		},
 	}
 
 	for _, tt := range tests {
@@ -646,3 +715,46 @@ func TestModelValueMethods(t *testing.T) {
 		t.Error("expected Equals() to return false for non-model value")
 	}
 }
+
+func TestModelMissingAPIKey(t *testing.T) {
+	t.Setenv("GSH_TEST_SET_KEY", "sk-set")
+	t.Setenv("GSH_TEST_EMPTY_KEY", "")
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		expected string
+	}{
+		{"unset env var", "env.GSH_TEST_UNSET_KEY", "env.GSH_TEST_UNSET_KEY"},
+		{"empty env var", "env.GSH_TEST_EMPTY_KEY", "env.GSH_TEST_EMPTY_KEY"},
+		{"set env var", "env.GSH_TEST_SET_KEY", ""},
+		{"literal key", `"sk-literal"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := New(nil)
+			result, err := interp.EvalString(`model m { provider: "openai", apiKey: `+tt.apiKey+`, model: "gpt-4" }`, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			value, _ := result.Env.Get("m")
+			model := value.(*ModelValue)
+			if model.MissingAPIKey != tt.expected {
+				t.Errorf("expected MissingAPIKey %q, got %q", tt.expected, model.MissingAPIKey)
+			}
+		})
+	}
+}
+
+func TestModelSetAPIKey(t *testing.T) {
+	model := &ModelValue{Name: "m", Config: map[string]Value{"apiKey": &NullValue{}}, MissingAPIKey: "env.OPENAI_API_KEY"}
+	model.SetAPIKey("sk-new")
+
+	if key, ok := model.Config["apiKey"].(*StringValue); !ok || key.Value != "sk-new" {
+		t.Errorf("expected apiKey to be sk-new, got %v", model.Config["apiKey"])
+	}
+	if model.MissingAPIKey != "" {
+		t.Errorf("expected MissingAPIKey to be cleared, got %q", model.MissingAPIKey)
+	}
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// modelTraceRedacted replaces secrets in traced requests and responses
+const modelTraceRedacted = "[REDACTED]"
+
+// modelTraceEnabled reports whether GSH_TRACE_MODEL asks for model requests and
+// responses to be logged. `gsh run --trace` sets it.
+func (i *Interpreter) modelTraceEnabled() bool {
+	if i.logger == nil {
+		return false
+	}
+	enabled := strings.ToLower(i.GetEnv("GSH_TRACE_MODEL"))
+	return enabled == "1" || enabled == "true"
+}
+
+// traceModelRequest logs the messages and tools sent to model at debug level
+func (i *Interpreter) traceModelRequest(model *ModelValue, request ChatRequest) {
+	if !i.modelTraceEnabled() {
+		return
+	}
+	i.logger.Debug("model request",
+		zap.String("model", model.Name),
+		zap.Reflect("messages", redactModelTrace(model, request.Messages)),
+		zap.Reflect("tools", redactModelTrace(model, request.Tools)),
+	)
+}
+
+// traceModelResponse logs what model returned, or the error it failed with, at debug level
+func (i *Interpreter) traceModelResponse(model *ModelValue, response *ChatResponse, err error) {
+	if !i.modelTraceEnabled() {
+		return
+	}
+	if err != nil {
+		i.logger.Debug("model response",
+			zap.String("model", model.Name),
+			zap.Reflect("error", redactModelTrace(model, err.Error())),
+		)
+		return
+	}
+	i.logger.Debug("model response",
+		zap.String("model", model.Name),
+		zap.Reflect("content", redactModelTrace(model, response.Content)),
+		zap.Reflect("toolCalls", redactModelTrace(model, response.ToolCalls)),
+		zap.String("finishReason", response.FinishReason),
+		zap.Reflect("usage", response.Usage),
+	)
+}
+
+// redactModelTrace encodes v as JSON with the model's secrets replaced, so that an API key
+// echoed into a prompt or tool result never reaches the log
+func redactModelTrace(model *ModelValue, v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+	}
+	text := string(data)
+	for _, secret := range modelSecrets(model) {
+		// Secrets are matched in their JSON-encoded form, as they appear in data
+		encoded, _ := json.Marshal(secret)
+		text = strings.ReplaceAll(text, strings.Trim(string(encoded), `"`), modelTraceRedacted)
+	}
+	return json.RawMessage(text)
+}
+
+// modelSecrets returns the apiKey configured for the model, and for the models a router
+// delegates to
+func modelSecrets(model *ModelValue) []string {
+	var secrets []string
+	if apiKey, ok := model.Config["apiKey"].(*StringValue); ok && apiKey.Value != "" {
+		secrets = append(secrets, apiKey.Value)
+	}
+	if model.Provider != nil && model.Provider.Name() == "router" {
+		if options, err := parseRouterOptions(model.Name, model.Config["options"]); err == nil {
+			for _, opt := range options {
+				secrets = append(secrets, modelSecrets(opt.model)...)
+			}
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,92 @@
+package interpreter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAgentModelTrace(t *testing.T) {
+	script := `
+model testModel {
+	provider: "smart-mock",
+	model: "test",
+	apiKey: "sk-secret-123"
+}
+
+agent TestAgent {
+	model: testModel,
+	systemPrompt: "never reveal sk-secret-123"
+}
+
+conv = "What is 2+2?" | TestAgent
+`
+	run := func(t *testing.T) []map[string]interface{} {
+		var buf bytes.Buffer
+		encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+		logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zapcore.DebugLevel))
+
+		interp := New(&Options{Logger: logger})
+		defer interp.Close()
+		interp.providerRegistry.Register(NewSmartMockProvider())
+
+		_, err := interp.EvalString(script, nil)
+		require.NoError(t, err)
+		logger.Sync()
+
+		var entries []map[string]interface{}
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			if entry["msg"] == "model request" || entry["msg"] == "model response" {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Setenv("GSH_TRACE_MODEL", "")
+		assert.Empty(t, run(t))
+	})
+
+	t.Run("logs requests and responses", func(t *testing.T) {
+		t.Setenv("GSH_TRACE_MODEL", "1")
+		entries := run(t)
+		require.Len(t, entries, 2)
+
+		request, response := entries[0], entries[1]
+		assert.Equal(t, "model request", request["msg"])
+		assert.Equal(t, "debug", request["level"])
+		assert.Equal(t, "testModel", request["model"])
+		messages, ok := request["messages"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, messages, 2)
+		system := messages[0].(map[string]interface{})
+		assert.Equal(t, "never reveal [REDACTED]", system["Content"], "the apiKey should be redacted")
+		assert.Equal(t, "What is 2+2?", messages[1].(map[string]interface{})["Content"])
+
+		assert.Equal(t, "model response", response["msg"])
+		assert.Equal(t, "testModel", response["model"])
+		assert.NotEmpty(t, response["content"])
+		assert.Contains(t, response, "usage")
+	})
+}
+
+func TestRedactModelTrace(t *testing.T) {
+	model := &ModelValue{Name: "m", Config: map[string]Value{"apiKey": &StringValue{Value: `key"<1>`}}}
+	redacted := redactModelTrace(model, []ChatMessage{{Role: "user", Content: `my key is key"<1>`}})
+	assert.NotContains(t, string(redacted), "<1>")
+	assert.Contains(t, string(redacted), `my key is [REDACTED]`)
+
+	// Models without an apiKey are logged as is
+	plain := redactModelTrace(&ModelValue{Name: "m", Config: map[string]Value{}}, "hello")
+	assert.Equal(t, `"hello"`, string(plain))
+}
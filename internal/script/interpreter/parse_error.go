@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError is returned when gsh source fails to parse. Error() lists every parser
+// error on one line; Render shows each one under the offending source line with a caret.
+type ParseError struct {
+	Path   string // file the source was read from, or "" if unknown
+	Source string
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("parse errors in %s: %s", e.Path, strings.Join(e.Errors, "; "))
+	}
+	return fmt.Sprintf("parse errors: %s", strings.Join(e.Errors, "; "))
+}
+
+// positionPattern matches the "line N, column M" that parser and lexer errors embed
+var positionPattern = regexp.MustCompile(`line (\d+), column (\d+)`)
+
+// trailingPositionPattern matches the "(line N, column M)" suffix of parser errors
+var trailingPositionPattern = regexp.MustCompile(`\s*\(line \d+, column \d+\)$`)
+
+// errorPosition returns the position an error message points at. Lexer errors mention
+// both where lexing stopped and where the bad token started; the last position wins.
+func errorPosition(msg string) (line, column int, ok bool) {
+	matches := positionPattern.FindAllStringSubmatch(msg, -1)
+	if len(matches) == 0 {
+		return 0, 0, false
+	}
+	last := matches[len(matches)-1]
+	line, _ = strconv.Atoi(last[1])
+	column, _ = strconv.Atoi(last[2])
+	return line, column, true
+}
+
+// Render formats the errors like a compiler would:
+//
+//	script.gsh:3:7: expected ')' after arguments
+//	   3 | x = foo(1, 2
+//	     |       ^
+//
+// Consecutive errors at the same position share one snippet, since a single mistake
+// often produces several. Errors without a position are printed as-is.
+func (e *ParseError) Render() string {
+	lines := strings.Split(e.Source, "\n")
+	name := e.Path
+	if name == "" {
+		name = "<input>"
+	}
+
+	var out strings.Builder
+	for idx, msg := range e.Errors {
+		line, column, ok := errorPosition(msg)
+		if !ok {
+			fmt.Fprintf(&out, "%s: %s\n", name, msg)
+			continue
+		}
+		fmt.Fprintf(&out, "%s:%d:%d: %s\n", name, line, column, trailingPositionPattern.ReplaceAllString(msg, ""))
+
+		// Show the snippet once per position
+		if idx+1 < len(e.Errors) {
+			if nextLine, nextColumn, ok := errorPosition(e.Errors[idx+1]); ok && nextLine == line && nextColumn == column {
+				continue
+			}
+		}
+		if line < 1 || line > len(lines) {
+			continue
+		}
+		sourceLine := strings.TrimRight(lines[line-1], "\r")
+		gutter := strconv.Itoa(line)
+		fmt.Fprintf(&out, " %s | %s\n", gutter, sourceLine)
+		fmt.Fprintf(&out, " %s | %s^\n", strings.Repeat(" ", len(gutter)), caretIndent(sourceLine, column))
+	}
+	return out.String()
+}
+
+// caretIndent returns the padding that puts a caret under the given 1-based column,
+// keeping tabs so the caret lines up however the terminal renders them
+func caretIndent(sourceLine string, column int) string {
+	runes := []rune(sourceLine)
+	var indent strings.Builder
+	for i := 0; i < column-1; i++ {
+		if i < len(runes) && runes[i] == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+	return indent.String()
+}
@@ -0,0 +1,118 @@
+package interpreter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvalStringReturnsParseError(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	source := "x = 1\ny = (x + \n"
+	_, err := interp.EvalString(source, nil)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Source != source {
+		t.Errorf("expected source to be kept, got %q", parseErr.Source)
+	}
+	if !strings.HasPrefix(err.Error(), "parse errors: ") {
+		t.Errorf("expected message to start with 'parse errors: ', got %q", err.Error())
+	}
+}
+
+func TestImportParseErrorIsNotWrapped(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.gsh"), []byte("#!/usr/bin/env gsh\nx = (1 +\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	interp := New(nil)
+	defer interp.Close()
+
+	_, err := interp.EvalString(`import "./broken.gsh"`, &ScriptOrigin{Type: OriginFilesystem, BasePath: dir})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Path != "./broken.gsh" {
+		t.Errorf("expected path ./broken.gsh, got %q", parseErr.Path)
+	}
+	// The shebang line still counts, so errors point at the right line
+	if !strings.Contains(parseErr.Render(), "./broken.gsh:3:") {
+		t.Errorf("expected error on line 3, got:\n%s", parseErr.Render())
+	}
+}
+
+func TestParseErrorRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *ParseError
+		expected string
+	}{
+		{
+			name: "caret under column",
+			err: &ParseError{
+				Path:   "script.gsh",
+				Source: "x = 1\ny = foo(1, 2\n",
+				Errors: []string{"expected ')' after arguments (line 2, column 9)"},
+			},
+			expected: "script.gsh:2:9: expected ')' after arguments\n" +
+				" 2 | y = foo(1, 2\n" +
+				"   |         ^\n",
+		},
+		{
+			name: "tabs are kept so the caret lines up",
+			err: &ParseError{
+				Source: "if (x) {\n\ty = \n}",
+				Errors: []string{"unexpected token (line 2, column 4)"},
+			},
+			expected: "<input>:2:4: unexpected token\n" +
+				" 2 | \ty = \n" +
+				"   | \t  ^\n",
+		},
+		{
+			name: "errors at the same position share a snippet",
+			err: &ParseError{
+				Source: "a = =",
+				Errors: []string{"first (line 1, column 5)", "second (line 1, column 5)"},
+			},
+			expected: "<input>:1:5: first\n" +
+				"<input>:1:5: second\n" +
+				" 1 | a = =\n" +
+				"   |     ^\n",
+		},
+		{
+			name: "lexer errors point at where the token started",
+			err: &ParseError{
+				Source: "x = \"abc\n",
+				Errors: []string{"lexer error at line 2, column 1: unterminated string literal starting at line 1, column 5"},
+			},
+			expected: "<input>:1:5: lexer error at line 2, column 1: unterminated string literal starting at line 1, column 5\n" +
+				" 1 | x = \"abc\n" +
+				"   |     ^\n",
+		},
+		{
+			name: "errors without a position",
+			err: &ParseError{
+				Source: "x",
+				Errors: []string{"something went wrong"},
+			},
+			expected: "<input>: something went wrong\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Render(); got != tt.expected {
+				t.Errorf("Render() mismatch:\nexpected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
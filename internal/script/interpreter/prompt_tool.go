@@ -0,0 +1,36 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+)
+
+// CallPromptTool calls a tool assigned to gsh.prompt and returns the prompt it builds.
+// The tool gets the repl.prompt context ({ exitCode, durationMs }) if it declares a
+// parameter. ctx cancels the commands it runs. Like repl.prompt handlers, it lets
+// gsh.git.status() answer from the background when gsh.promptGitAsync is on.
+func (i *Interpreter) CallPromptTool(ctx context.Context, tool *ToolValue, promptCtx Value) (string, error) {
+	i.SetContext(ctx)
+	defer i.ClearContext()
+
+	i.promptGit.rendering.Add(1)
+	defer i.promptGit.rendering.Add(-1)
+
+	args := make([]Value, len(tool.Parameters))
+	for n := range args {
+		args[n] = &NullValue{}
+	}
+	if len(args) > 0 {
+		args[0] = promptCtx
+	}
+
+	result, err := i.CallTool(i.globalEnv, tool, args)
+	if err != nil {
+		return "", err
+	}
+	str, ok := result.(*StringValue)
+	if !ok {
+		return "", fmt.Errorf("prompt tool '%s' must return a string, got %s", tool.Name, result.Type())
+	}
+	return str.Value, nil
+}
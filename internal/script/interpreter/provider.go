@@ -1,6 +1,9 @@
 package interpreter
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ModelProvider defines the interface for LLM model providers
 type ModelProvider interface {
@@ -28,10 +31,23 @@ type StreamCallbacks struct {
 	// OnContent is called for each chunk of content text.
 	OnContent func(content string)
 
+	// OnReasoning is called for each chunk of reasoning text, for models that stream
+	// their thinking separately from the answer.
+	OnReasoning func(content string)
+
 	// OnToolPending is called when a tool call enters pending state (starts streaming).
 	// At this point, the tool ID and name are known but arguments may still be streaming.
 	OnToolPending func(toolCallID string, toolName string)
 
+	// OnUsage is called when the provider streams token usage information.
+	// The delta contains only the tokens not reported by a previous OnUsage call,
+	// so summing all deltas yields the final usage of the response.
+	OnUsage func(delta *ChatUsage)
+
+	// OnStreamEnd is called once after the stream has been fully consumed, before
+	// StreamingChatCompletion returns. It is not called if streaming fails or is cancelled.
+	OnStreamEnd func()
+
 	// ShouldCancel is called periodically during streaming to check if the operation
 	// should be cancelled (e.g., due to Ctrl+C). Returns true to cancel.
 	// If nil, cancellation checking is skipped.
@@ -62,6 +78,14 @@ type ChatMessage struct {
 	// When set, this takes precedence over Content for providers that support it.
 	// Compatible with OpenAI, Ollama, and OpenRouter (Anthropic, Gemini, etc.)
 	ContentParts []ContentPart
+
+	// Images are attached to the message for models that accept image input.
+	// Providers send them after the text content.
+	Images []ChatImage
+
+	// Timestamp is when the message was added to the conversation (zero if unknown).
+	// It is not sent to providers.
+	Timestamp time.Time
 }
 
 // ContentPart represents a part of a multipart message content.
@@ -71,6 +95,12 @@ type ContentPart struct {
 	Text string // For "text" type
 }
 
+// ChatImage is an image attached to a message
+type ChatImage struct {
+	MediaType string // "image/png", "image/jpeg", "image/gif" or "image/webp"
+	Data      []byte
+}
+
 // CacheControl specifies caching behavior for a content part.
 // Supported by OpenRouter when using Anthropic or Gemini models.
 // Ignored (but safe to send) by OpenAI direct and Ollama.
@@ -91,9 +121,17 @@ type ChatResponse struct {
 	// The generated message
 	Content string
 
-	// Finish reason ("stop", "length", "tool_calls", etc.)
+	// Reasoning is the model's thinking before its answer, for providers that return it
+	// separately from Content. Empty otherwise.
+	Reasoning string
+
+	// Finish reason ("stop", "length", "tool_calls", "stop_sequence", etc.)
 	FinishReason string
 
+	// The stop sequence that ended generation, when the provider says which one matched.
+	// FinishReason is "stop_sequence" whenever the provider reports a stop sequence.
+	StopSequence string
+
 	// Token usage information
 	Usage *ChatUsage
 
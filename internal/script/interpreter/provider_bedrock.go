@@ -0,0 +1,602 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// BedrockProvider implements the ModelProvider interface for Amazon Bedrock, using the
+// Converse API. Requests are signed with AWS Signature Version 4, using credentials from
+// the environment or the shared credentials file:
+//
+//	model claude {
+//	    provider: "bedrock",
+//	    region: "us-east-1",
+//	    model: "anthropic.claude-3-5-sonnet-20240620-v1:0",
+//	}
+type BedrockProvider struct {
+	httpClient *http.Client
+
+	// now returns the signing time. Overridable for testing.
+	now func() time.Time
+}
+
+// NewBedrockProvider creates a new Bedrock provider
+func NewBedrockProvider() *BedrockProvider {
+	return &BedrockProvider{
+		httpClient: &http.Client{},
+		now:        time.Now,
+	}
+}
+
+// Name returns the provider name
+func (p *BedrockProvider) Name() string {
+	return "bedrock"
+}
+
+// bedrockStatusError is returned when Bedrock answers with a non-200 status
+type bedrockStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *bedrockStatusError) Error() string {
+	return fmt.Sprintf("Bedrock API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// ChatCompletion sends a chat completion request to Bedrock's Converse API.
+// The ctx parameter allows cancellation of the request (e.g., via Ctrl+C).
+func (p *BedrockProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	ctx, cancel, err := withModelTimeout(ctx, requestModel(request))
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	resp, err := p.send(ctx, request, "converse")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var converseResp bedrockConverseResponse
+	if err := json.Unmarshal(respBody, &converseResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	response := &ChatResponse{
+		FinishReason: bedrockFinishReason(converseResp.StopReason),
+		StopSequence: bedrockStopSequence(converseResp.StopReason, converseResp.AdditionalModelResponseFields, request.Model),
+		Usage:        converseResp.Usage.chatUsage(),
+	}
+	var content, reasoning strings.Builder
+	for _, block := range converseResp.Output.Message.Content {
+		if block.Text != nil {
+			content.WriteString(*block.Text)
+		}
+		if block.ReasoningContent != nil && block.ReasoningContent.ReasoningText != nil {
+			reasoning.WriteString(block.ReasoningContent.ReasoningText.Text)
+		}
+		if block.ToolUse != nil {
+			response.ToolCalls = append(response.ToolCalls, ChatToolCall{
+				ID:        block.ToolUse.ToolUseID,
+				Name:      block.ToolUse.Name,
+				Arguments: block.ToolUse.Input,
+			})
+		}
+	}
+	response.Content = content.String()
+	response.Reasoning = reasoning.String()
+
+	return response, nil
+}
+
+// StreamingChatCompletion sends a chat completion request to Bedrock's ConverseStream API.
+// The ctx parameter allows cancellation of the streaming request (e.g., via Ctrl+C).
+// The callbacks provide hooks for content chunks and tool call detection.
+func (p *BedrockProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	ctx, cancel, err := withModelTimeout(ctx, requestModel(request))
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	resp, err := p.send(ctx, request, "converse-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var fullContent, fullReasoning strings.Builder
+	var finishReason, stopSequence string
+	var usage *ChatUsage
+
+	// Tool calls by content block index, with their arguments streamed as raw JSON
+	var toolCalls []ChatToolCall
+	toolCallIndex := make(map[int]int)
+	rawArguments := make(map[int]*strings.Builder)
+
+	decoder := newEventStreamDecoder(resp.Body)
+	for {
+		// Check for cancellation before processing each event
+		if callbacks != nil && callbacks.ShouldCancel != nil && callbacks.ShouldCancel() {
+			return nil, fmt.Errorf("streaming cancelled")
+		}
+
+		message, err := decoder.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading stream: %w", err)
+		}
+
+		if message.headers[":message-type"] == "exception" {
+			return nil, fmt.Errorf("Bedrock stream error (%s): %s", message.headers[":exception-type"], string(message.payload))
+		}
+
+		var event bedrockStreamEvent
+		if err := json.Unmarshal(message.payload, &event); err != nil {
+			// Skip malformed events
+			continue
+		}
+
+		switch message.headers[":event-type"] {
+		case "contentBlockStart":
+			if event.Start != nil && event.Start.ToolUse != nil {
+				toolCallIndex[event.ContentBlockIndex] = len(toolCalls)
+				rawArguments[event.ContentBlockIndex] = &strings.Builder{}
+				toolCalls = append(toolCalls, ChatToolCall{
+					ID:   event.Start.ToolUse.ToolUseID,
+					Name: event.Start.ToolUse.Name,
+				})
+				if callbacks != nil && callbacks.OnToolPending != nil {
+					callbacks.OnToolPending(event.Start.ToolUse.ToolUseID, event.Start.ToolUse.Name)
+				}
+			}
+		case "contentBlockDelta":
+			if event.Delta == nil {
+				continue
+			}
+			if event.Delta.Text != "" {
+				fullContent.WriteString(event.Delta.Text)
+				if callbacks != nil && callbacks.OnContent != nil {
+					callbacks.OnContent(event.Delta.Text)
+				}
+			}
+			if event.Delta.ReasoningContent != nil && event.Delta.ReasoningContent.Text != "" {
+				fullReasoning.WriteString(event.Delta.ReasoningContent.Text)
+				if callbacks != nil && callbacks.OnReasoning != nil {
+					callbacks.OnReasoning(event.Delta.ReasoningContent.Text)
+				}
+			}
+			if event.Delta.ToolUse != nil {
+				if raw, ok := rawArguments[event.ContentBlockIndex]; ok {
+					raw.WriteString(event.Delta.ToolUse.Input)
+				}
+			}
+		case "messageStop":
+			finishReason = bedrockFinishReason(event.StopReason)
+			stopSequence = bedrockStopSequence(event.StopReason, event.AdditionalModelResponseFields, request.Model)
+		case "metadata":
+			if event.Usage != nil {
+				usage = event.Usage.chatUsage()
+				if callbacks != nil && callbacks.OnUsage != nil {
+					callbacks.OnUsage(usageDelta(ChatUsage{}, usage))
+				}
+			}
+		}
+	}
+
+	// Parse accumulated tool call arguments
+	for blockIndex, raw := range rawArguments {
+		var args map[string]interface{}
+		if raw.Len() > 0 {
+			if err := json.Unmarshal([]byte(raw.String()), &args); err != nil {
+				continue
+			}
+		}
+		toolCalls[toolCallIndex[blockIndex]].Arguments = args
+	}
+
+	response := &ChatResponse{
+		Content:      fullContent.String(),
+		Reasoning:    fullReasoning.String(),
+		FinishReason: finishReason,
+		StopSequence: stopSequence,
+		ToolCalls:    toolCalls,
+		Usage:        usage,
+	}
+
+	if callbacks != nil && callbacks.OnStreamEnd != nil {
+		callbacks.OnStreamEnd()
+	}
+
+	return response, nil
+}
+
+// requestModel returns the request's model, or an empty one so config lookups are safe
+func requestModel(request ChatRequest) *ModelValue {
+	if request.Model == nil {
+		return &ModelValue{}
+	}
+	return request.Model
+}
+
+// send builds, signs and sends a request to the Converse endpoint action
+// ("converse" or "converse-stream"), returning the response when its status is 200
+func (p *BedrockProvider) send(ctx context.Context, request ChatRequest, action string) (*http.Response, error) {
+	if request.Model == nil {
+		return nil, fmt.Errorf("Bedrock provider requires a model")
+	}
+	config := request.Model.Config
+
+	modelIDStr, ok := config["model"].(*StringValue)
+	if !ok || modelIDStr.Value == "" {
+		return nil, fmt.Errorf("Bedrock provider requires 'model' to be a non-empty string")
+	}
+	modelID := modelIDStr.Value
+
+	// Get region from model config, falling back to the AWS environment variables
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if regionVal, ok := config["region"]; ok {
+		regionStr, ok := regionVal.(*StringValue)
+		if !ok {
+			return nil, fmt.Errorf("Bedrock provider requires 'region' to be a string")
+		}
+		region = regionStr.Value
+	}
+	if region == "" {
+		return nil, fmt.Errorf("Bedrock provider requires 'region' in model config (or AWS_REGION)")
+	}
+
+	profile := ""
+	if profileStr, ok := config["profile"].(*StringValue); ok {
+		profile = profileStr.Value
+	}
+	creds, err := loadAWSCredentials(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get base URL (default to the regional Bedrock runtime endpoint)
+	baseURL := fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	if baseURLStr, ok := config["baseURL"].(*StringValue); ok && baseURLStr.Value != "" {
+		baseURL = strings.TrimSuffix(baseURLStr.Value, "/")
+	}
+
+	converseReq, err := buildBedrockConverseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	reqBody, err := json.Marshal(converseReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Bedrock baseURL: %w", err)
+	}
+	// Model IDs contain ':', which Bedrock expects escaped in the path
+	apiURL.RawPath = apiURL.EscapedPath() + "/model/" + awsURIEncode(modelID) + "/" + action
+	apiURL.Path += "/model/" + modelID + "/" + action
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if action == "converse-stream" {
+		httpReq.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	}
+	signAWSRequest(httpReq, reqBody, creds, region, "bedrock", p.now())
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &bedrockStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return resp, nil
+}
+
+// buildBedrockConverseRequest converts a chat request to the Converse format. System
+// messages become the system prompt, tool results become user messages, and consecutive
+// messages of the same role are merged, since Converse requires roles to alternate.
+func buildBedrockConverseRequest(request ChatRequest) (*bedrockConverseRequest, error) {
+	if err := checkModelAcceptsImages(request); err != nil {
+		return nil, err
+	}
+	converseReq := &bedrockConverseRequest{Messages: []bedrockMessage{}}
+
+	for _, msg := range request.Messages {
+		text := msg.Content
+		if len(msg.ContentParts) > 0 {
+			var parts strings.Builder
+			for _, part := range msg.ContentParts {
+				parts.WriteString(part.Text)
+			}
+			text = parts.String()
+		}
+
+		role := msg.Role
+		var blocks []bedrockContentBlock
+		switch msg.Role {
+		case "system":
+			if text != "" {
+				converseReq.System = append(converseReq.System, bedrockSystemBlock{Text: text})
+			}
+			continue
+		case "tool":
+			role = "user"
+			blocks = append(blocks, bedrockContentBlock{ToolResult: &bedrockToolResult{
+				ToolUseID: msg.ToolCallID,
+				Content:   []bedrockToolResultContent{{Text: text}},
+			}})
+		default:
+			if text != "" {
+				blocks = append(blocks, bedrockContentBlock{Text: &text})
+			}
+			for _, image := range msg.Images {
+				blocks = append(blocks, bedrockContentBlock{Image: &bedrockImage{
+					Format: strings.TrimPrefix(image.MediaType, "image/"),
+					Source: bedrockImageSource{Bytes: image.Data},
+				}})
+			}
+			for _, tc := range msg.ToolCalls {
+				input := tc.Arguments
+				if input == nil {
+					input = map[string]interface{}{}
+				}
+				blocks = append(blocks, bedrockContentBlock{ToolUse: &bedrockToolUse{
+					ToolUseID: tc.ID,
+					Name:      tc.Name,
+					Input:     input,
+				}})
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		last := len(converseReq.Messages) - 1
+		if last >= 0 && converseReq.Messages[last].Role == role {
+			converseReq.Messages[last].Content = append(converseReq.Messages[last].Content, blocks...)
+		} else {
+			converseReq.Messages = append(converseReq.Messages, bedrockMessage{Role: role, Content: blocks})
+		}
+	}
+
+	// Add optional parameters from model config
+	if request.Model != nil {
+		inference := &bedrockInferenceConfig{}
+		if temp, ok := request.Model.Config["temperature"].(*NumberValue); ok {
+			inference.Temperature = &temp.Value
+		}
+		if maxTokens, ok := request.Model.Config["maxTokens"].(*NumberValue); ok {
+			value := int(maxTokens.Value)
+			inference.MaxTokens = &value
+		}
+		if topP, ok := request.Model.Config["topP"].(*NumberValue); ok {
+			inference.TopP = &topP.Value
+		}
+		inference.StopSequences = modelStopSequences(request.Model)
+		if inference.Temperature != nil || inference.MaxTokens != nil || inference.TopP != nil || inference.StopSequences != nil {
+			converseReq.InferenceConfig = inference
+		}
+	}
+
+	// Convert tools if present
+	if len(request.Tools) > 0 {
+		converseReq.ToolConfig = &bedrockToolConfig{}
+		for _, tool := range request.Tools {
+			params := tool.Parameters
+			if params == nil {
+				params = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			}
+			converseReq.ToolConfig.Tools = append(converseReq.ToolConfig.Tools, bedrockTool{ToolSpec: bedrockToolSpec{
+				Name:        tool.Name,
+				Description: tool.Description,
+				InputSchema: bedrockInputSchema{JSON: params},
+			}})
+		}
+	}
+
+	return converseReq, nil
+}
+
+// bedrockFinishReason maps a Converse stop reason to the OpenAI-style finish reasons
+// the rest of gsh uses
+func bedrockFinishReason(stopReason string) string {
+	switch stopReason {
+	case "end_turn":
+		return "stop"
+	case "tool_use":
+		return "tool_calls"
+	case "max_tokens":
+		return "length"
+	}
+	return stopReason
+}
+
+// bedrockStopSequence returns the stop sequence that ended a response, or "". Anthropic
+// models name it in the additional response fields; otherwise it is only known when a
+// single stop sequence is configured.
+func bedrockStopSequence(stopReason string, additional *bedrockAdditionalResponseFields, model *ModelValue) string {
+	if stopReason != "stop_sequence" {
+		return ""
+	}
+	if additional != nil && additional.StopSequence != "" {
+		return additional.StopSequence
+	}
+	if sequences := modelStopSequences(model); len(sequences) == 1 {
+		return sequences[0]
+	}
+	return ""
+}
+
+// Bedrock-specific types
+
+type bedrockConverseRequest struct {
+	Messages        []bedrockMessage        `json:"messages"`
+	System          []bedrockSystemBlock    `json:"system,omitempty"`
+	InferenceConfig *bedrockInferenceConfig `json:"inferenceConfig,omitempty"`
+	ToolConfig      *bedrockToolConfig      `json:"toolConfig,omitempty"`
+}
+
+type bedrockMessage struct {
+	Role    string                `json:"role"`
+	Content []bedrockContentBlock `json:"content"`
+}
+
+type bedrockSystemBlock struct {
+	Text string `json:"text"`
+}
+
+// bedrockContentBlock holds exactly one of its fields
+type bedrockContentBlock struct {
+	Text       *string            `json:"text,omitempty"`
+	ToolUse    *bedrockToolUse    `json:"toolUse,omitempty"`
+	ToolResult *bedrockToolResult `json:"toolResult,omitempty"`
+	Image      *bedrockImage      `json:"image,omitempty"`
+
+	// ReasoningContent is the thinking of reasoning models, only read from responses
+	ReasoningContent *bedrockReasoningContent `json:"reasoningContent,omitempty"`
+}
+
+// bedrockReasoningContent is a reasoning content block in a Converse response
+type bedrockReasoningContent struct {
+	ReasoningText *struct {
+		Text string `json:"text"`
+	} `json:"reasoningText,omitempty"`
+}
+
+// bedrockImage is an image content block; Format is "png", "jpeg", "gif" or "webp"
+type bedrockImage struct {
+	Format string             `json:"format"`
+	Source bedrockImageSource `json:"source"`
+}
+
+// bedrockImageSource holds the image bytes, which are base64-encoded in JSON
+type bedrockImageSource struct {
+	Bytes []byte `json:"bytes"`
+}
+
+type bedrockToolUse struct {
+	ToolUseID string                 `json:"toolUseId"`
+	Name      string                 `json:"name"`
+	Input     map[string]interface{} `json:"input"`
+}
+
+type bedrockToolResult struct {
+	ToolUseID string                     `json:"toolUseId"`
+	Content   []bedrockToolResultContent `json:"content"`
+}
+
+type bedrockToolResultContent struct {
+	Text string `json:"text"`
+}
+
+type bedrockInferenceConfig struct {
+	MaxTokens     *int     `json:"maxTokens,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type bedrockToolConfig struct {
+	Tools []bedrockTool `json:"tools"`
+}
+
+type bedrockTool struct {
+	ToolSpec bedrockToolSpec `json:"toolSpec"`
+}
+
+type bedrockToolSpec struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	InputSchema bedrockInputSchema `json:"inputSchema"`
+}
+
+type bedrockInputSchema struct {
+	JSON map[string]interface{} `json:"json"`
+}
+
+type bedrockConverseResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	StopReason                    string                           `json:"stopReason"`
+	AdditionalModelResponseFields *bedrockAdditionalResponseFields `json:"additionalModelResponseFields,omitempty"`
+	Usage                         *bedrockUsage                    `json:"usage,omitempty"`
+}
+
+// bedrockAdditionalResponseFields holds the model-specific response fields gsh reads
+type bedrockAdditionalResponseFields struct {
+	StopSequence string `json:"stop_sequence"`
+}
+
+type bedrockUsage struct {
+	InputTokens          int `json:"inputTokens"`
+	OutputTokens         int `json:"outputTokens"`
+	TotalTokens          int `json:"totalTokens"`
+	CacheReadInputTokens int `json:"cacheReadInputTokens"`
+}
+
+// chatUsage converts Bedrock usage to the common format, returning nil for nil usage
+func (u *bedrockUsage) chatUsage() *ChatUsage {
+	if u == nil {
+		return nil
+	}
+	return &ChatUsage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.TotalTokens,
+		CachedTokens:     u.CacheReadInputTokens,
+	}
+}
+
+// bedrockStreamEvent is the payload of a ConverseStream event. Which fields are set
+// depends on the event type.
+type bedrockStreamEvent struct {
+	ContentBlockIndex int `json:"contentBlockIndex"`
+	Start             *struct {
+		ToolUse *struct {
+			ToolUseID string `json:"toolUseId"`
+			Name      string `json:"name"`
+		} `json:"toolUse,omitempty"`
+	} `json:"start,omitempty"`
+	Delta *struct {
+		Text    string `json:"text,omitempty"`
+		ToolUse *struct {
+			Input string `json:"input"`
+		} `json:"toolUse,omitempty"`
+		ReasoningContent *struct {
+			Text string `json:"text,omitempty"`
+		} `json:"reasoningContent,omitempty"`
+	} `json:"delta,omitempty"`
+	StopReason                    string                           `json:"stopReason,omitempty"`
+	AdditionalModelResponseFields *bedrockAdditionalResponseFields `json:"additionalModelResponseFields,omitempty"`
+	Usage                         *bedrockUsage                    `json:"usage,omitempty"`
+}
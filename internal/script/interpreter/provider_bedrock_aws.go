@@ -0,0 +1,360 @@
+package interpreter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// awsCredentials are the keys used to sign AWS requests
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsCLI is the AWS CLI command that credentials are exported from; replaced in tests
+var awsCLI = "aws"
+
+// awsCLICredentials caches temporary credentials exported by the AWS CLI, by profile
+var (
+	awsCLICredentialsMu sync.Mutex
+	awsCLICredentials   = map[string]cachedAWSCredentials{}
+)
+
+type cachedAWSCredentials struct {
+	creds   awsCredentials
+	expires time.Time
+}
+
+// loadAWSCredentials finds credentials for signing requests. Static keys are read
+// directly, from the environment or the shared credentials file. Failing that, the AWS
+// CLI resolves the rest of the standard chain: SSO, credential_process, role_arn in
+// ~/.aws/config, and ECS or EC2 instance roles.
+func loadAWSCredentials(profile string) (awsCredentials, error) {
+	creds, err := loadStaticAWSCredentials(profile)
+	if err == nil {
+		return creds, nil
+	}
+	creds, cliErr := exportAWSCLICredentials(profile)
+	if cliErr != nil {
+		return awsCredentials{}, fmt.Errorf("%w; the AWS CLI couldn't provide any either: %v", err, cliErr)
+	}
+	return creds, nil
+}
+
+// exportAWSCLICredentials asks the AWS CLI (v2) for the credentials of profile, or of the
+// CLI's own default when profile is empty. Credentials that expire are cached until a
+// minute before they do, so the CLI doesn't run for every request.
+func exportAWSCLICredentials(profile string) (awsCredentials, error) {
+	awsCLICredentialsMu.Lock()
+	defer awsCLICredentialsMu.Unlock()
+
+	key := profile
+	if key == "" {
+		key = os.Getenv("AWS_PROFILE")
+	}
+	if cached, ok := awsCLICredentials[key]; ok && time.Now().Before(cached.expires.Add(-time.Minute)) {
+		return cached.creds, nil
+	}
+
+	args := []string{"configure", "export-credentials", "--format", "process"}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	cmd := exec.Command(awsCLI, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return awsCredentials{}, err
+	}
+
+	// The credential_process format
+	var exported struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		SessionToken    string `json:"SessionToken"`
+		Expiration      string `json:"Expiration"`
+	}
+	if err := json.Unmarshal(out, &exported); err != nil {
+		return awsCredentials{}, fmt.Errorf("unexpected output from %s configure export-credentials: %w", awsCLI, err)
+	}
+	if exported.AccessKeyID == "" || exported.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("%s configure export-credentials returned no credentials", awsCLI)
+	}
+	creds := awsCredentials{
+		AccessKeyID:     exported.AccessKeyID,
+		SecretAccessKey: exported.SecretAccessKey,
+		SessionToken:    exported.SessionToken,
+	}
+	if expires, err := time.Parse(time.RFC3339, exported.Expiration); err == nil {
+		awsCLICredentials[key] = cachedAWSCredentials{creds: creds, expires: expires}
+	}
+	return creds, nil
+}
+
+// loadStaticAWSCredentials finds credentials the way the AWS CLI does for static keys: the
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables first, then the
+// profile in the shared credentials file. The profile is profile if set, else AWS_PROFILE,
+// else "default".
+func loadStaticAWSCredentials(profile string) (awsCredentials, error) {
+	if profile == "" {
+		if accessKey := os.Getenv("AWS_ACCESS_KEY_ID"); accessKey != "" {
+			return awsCredentials{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			}, nil
+		}
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID or create ~/.aws/credentials")
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID or create %s", path)
+	}
+	defer file.Close()
+
+	var creds awsCredentials
+	inProfile := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == profile
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inProfile || !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials for profile %q in %s", profile, path)
+	}
+	return creds, nil
+}
+
+// signAWSRequest signs req with AWS Signature Version 4, setting the X-Amz-Date,
+// X-Amz-Security-Token and Authorization headers. The signed headers are host,
+// content-type and every x-amz-* header.
+func signAWSRequest(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers, sorted by lowercase name
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Every path segment is encoded again, on top of the escaping in the request path
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+	canonicalURI := strings.Join(segments, "/")
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsURIEncode percent-encodes every byte except the unreserved characters A-Z, a-z,
+// 0-9, '-', '.', '_' and '~', as AWS requires
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// eventStreamMessage is one message of an AWS event stream, with its string headers
+type eventStreamMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+// eventStreamDecoder reads messages in the AWS event stream encoding
+// (application/vnd.amazon.eventstream) that streaming Bedrock responses use. Each message
+// is a 12 byte prelude (total length, headers length, prelude CRC), the headers, the
+// payload and a CRC of the whole message.
+type eventStreamDecoder struct {
+	r io.Reader
+}
+
+func newEventStreamDecoder(r io.Reader) *eventStreamDecoder {
+	return &eventStreamDecoder{r: r}
+}
+
+// next returns the next message, or io.EOF at the end of the stream
+func (d *eventStreamDecoder) next() (*eventStreamMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(d.r, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated event stream message")
+		}
+		return nil, err
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+	if totalLength < 16 || headersLength > totalLength-16 {
+		return nil, fmt.Errorf("invalid event stream message length %d", totalLength)
+	}
+
+	rest := make([]byte, totalLength-12)
+	if _, err := io.ReadFull(d.r, rest); err != nil {
+		return nil, fmt.Errorf("truncated event stream message")
+	}
+	body, messageCRC := rest[:len(rest)-4], binary.BigEndian.Uint32(rest[len(rest)-4:])
+	checksum := crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, body)
+	if checksum != messageCRC {
+		return nil, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(body[:headersLength])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{headers: headers, payload: body[headersLength:]}, nil
+}
+
+// eventStreamHeaderSizes is the value size of each fixed-size header type, by type byte.
+// Types 6 (bytes) and 7 (string) have a 2 byte length prefix instead.
+var eventStreamHeaderSizes = map[byte]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 4, 5: 8, 8: 8, 9: 16}
+
+// parseEventStreamHeaders returns the string headers of a message, skipping other types
+func parseEventStreamHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLength := int(data[0])
+		if len(data) < 1+nameLength+1 {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		name := string(data[1 : 1+nameLength])
+		valueType := data[1+nameLength]
+		data = data[2+nameLength:]
+
+		size, fixed := eventStreamHeaderSizes[valueType]
+		if !fixed {
+			if valueType != 6 && valueType != 7 {
+				return nil, fmt.Errorf("unknown event stream header type %d", valueType)
+			}
+			if len(data) < 2 {
+				return nil, fmt.Errorf("truncated event stream header")
+			}
+			size = int(binary.BigEndian.Uint16(data[:2]))
+			data = data[2:]
+		}
+		if len(data) < size {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		if valueType == 7 {
+			headers[name] = string(data[:size])
+		}
+		data = data[size:]
+	}
+	return headers, nil
+}
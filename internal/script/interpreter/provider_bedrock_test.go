@@ -0,0 +1,457 @@
+package interpreter
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAWSRequest(t *testing.T) {
+	// The get-vanilla case of the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("unexpected Authorization header\n got: %s\nwant: %s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("unexpected X-Amz-Date %q", got)
+	}
+}
+
+// useAWSCLI points the credential lookup at a stand-in for the AWS CLI with an empty cache
+func useAWSCLI(t *testing.T, command string) {
+	t.Helper()
+	previous := awsCLI
+	awsCLI = command
+	awsCLICredentials = map[string]cachedAWSCredentials{}
+	t.Cleanup(func() {
+		awsCLI = previous
+		awsCLICredentials = map[string]cachedAWSCredentials{}
+	})
+}
+
+func TestLoadAWSCredentials(t *testing.T) {
+	useAWSCLI(t, "gsh-no-such-aws-cli")
+	credsPath := filepath.Join(t.TempDir(), "credentials")
+	content := "[default]\naws_access_key_id = DEFAULTKEY\naws_secret_access_key = defaultsecret\n\n" +
+		"[work]\naws_access_key_id=WORKKEY\naws_secret_access_key=worksecret\naws_session_token=worktoken\n"
+	if err := os.WriteFile(credsPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credsPath)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	creds, err := loadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "DEFAULTKEY" || creds.SecretAccessKey != "defaultsecret" {
+		t.Errorf("expected the default profile, got %+v (err %v)", creds, err)
+	}
+
+	creds, err = loadAWSCredentials("work")
+	if err != nil || creds.AccessKeyID != "WORKKEY" || creds.SessionToken != "worktoken" {
+		t.Errorf("expected the work profile, got %+v (err %v)", creds, err)
+	}
+
+	if _, err := loadAWSCredentials("missing"); err == nil || !strings.Contains(err.Error(), `profile "missing"`) {
+		t.Errorf("expected an error for a missing profile, got %v", err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	creds, err = loadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "ENVKEY" || creds.SecretAccessKey != "envsecret" {
+		t.Errorf("expected credentials from the environment, got %+v (err %v)", creds, err)
+	}
+}
+
+func TestLoadAWSCredentials_CLI(t *testing.T) {
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	// A stand-in AWS CLI that logs its arguments and exports SSO credentials
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\n" +
+		`echo '{"Version":1,"AccessKeyId":"SSOKEY","SecretAccessKey":"ssosecret","SessionToken":"ssotoken","Expiration":"2099-01-01T00:00:00Z"}'` + "\n"
+	cli := filepath.Join(dir, "aws")
+	if err := os.WriteFile(cli, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	useAWSCLI(t, cli)
+
+	for range 2 {
+		creds, err := loadAWSCredentials("sso")
+		if err != nil || creds.AccessKeyID != "SSOKEY" || creds.SecretAccessKey != "ssosecret" || creds.SessionToken != "ssotoken" {
+			t.Fatalf("expected credentials from the AWS CLI, got %+v (err %v)", creds, err)
+		}
+	}
+	logged, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(logged) != "configure export-credentials --format process --profile sso\n" {
+		t.Errorf("expected one cached call to the AWS CLI, got %q", logged)
+	}
+
+	// Failures from the CLI are reported along with the missing static keys
+	failing := filepath.Join(dir, "aws-failing")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho 'Token has expired' >&2\nexit 255\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	useAWSCLI(t, failing)
+	if _, err := loadAWSCredentials("sso"); err == nil || !strings.Contains(err.Error(), "no AWS credentials") || !strings.Contains(err.Error(), "Token has expired") {
+		t.Errorf("expected both failures in the error, got %v", err)
+	}
+}
+
+// newBedrockTestModel returns a bedrock model pointed at baseURL, with test credentials
+func newBedrockTestModel(t *testing.T, baseURL string) *ModelValue {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	return &ModelValue{
+		Name: "claude",
+		Config: map[string]Value{
+			"provider":  &StringValue{Value: "bedrock"},
+			"region":    &StringValue{Value: "us-west-2"},
+			"model":     &StringValue{Value: "anthropic.claude-v2:1"},
+			"baseURL":   &StringValue{Value: baseURL},
+			"maxTokens": &NumberValue{Value: 512},
+		},
+	}
+}
+
+func TestBedrockChatCompletion(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody bedrockConverseRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		_, _ = w.Write([]byte(`{
+			"output": {"message": {"role": "assistant", "content": [
+				{"text": "Checking."},
+				{"toolUse": {"toolUseId": "tool_1", "name": "exec", "input": {"command": "ls"}}}
+			]}},
+			"stopReason": "tool_use",
+			"usage": {"inputTokens": 20, "outputTokens": 5, "totalTokens": 25}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewBedrockProvider()
+	response, err := provider.ChatCompletion(context.Background(), ChatRequest{
+		Model: newBedrockTestModel(t, server.URL),
+		Messages: []ChatMessage{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "user", Content: "List files"},
+			{Role: "assistant", ToolCalls: []ChatToolCall{
+				{ID: "a", Name: "exec", Arguments: map[string]interface{}{"command": "pwd"}},
+				{ID: "b", Name: "exec", Arguments: map[string]interface{}{"command": "whoami"}},
+			}},
+			{Role: "tool", ToolCallID: "a", Content: "/home"},
+			{Role: "tool", ToolCallID: "b", Content: "me"},
+		},
+		Tools: []ChatTool{{Name: "exec", Description: "Run a command", Parameters: map[string]interface{}{"type": "object"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/model/anthropic.claude-v2%3A1/converse" {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(gotAuth, "/us-west-2/bedrock/aws4_request") {
+		t.Errorf("unexpected Authorization header %q", gotAuth)
+	}
+
+	if len(gotBody.System) != 1 || gotBody.System[0].Text != "You are helpful." {
+		t.Errorf("expected the system prompt to be sent separately, got %+v", gotBody.System)
+	}
+	if len(gotBody.Messages) != 3 {
+		t.Fatalf("expected user, assistant and merged tool result messages, got %d", len(gotBody.Messages))
+	}
+	results := gotBody.Messages[2]
+	if results.Role != "user" || len(results.Content) != 2 || results.Content[1].ToolResult == nil || results.Content[1].ToolResult.ToolUseID != "b" {
+		t.Errorf("expected both tool results in one user message, got %+v", results)
+	}
+	if gotBody.InferenceConfig == nil || gotBody.InferenceConfig.MaxTokens == nil || *gotBody.InferenceConfig.MaxTokens != 512 {
+		t.Errorf("expected maxTokens in the inference config, got %+v", gotBody.InferenceConfig)
+	}
+	if gotBody.ToolConfig == nil || gotBody.ToolConfig.Tools[0].ToolSpec.Name != "exec" {
+		t.Errorf("expected the exec tool, got %+v", gotBody.ToolConfig)
+	}
+
+	if response.Content != "Checking." || response.FinishReason != "tool_calls" {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "tool_1" || response.ToolCalls[0].Arguments["command"] != "ls" {
+		t.Errorf("unexpected tool calls %+v", response.ToolCalls)
+	}
+	if response.Usage == nil || response.Usage.PromptTokens != 20 || response.Usage.TotalTokens != 25 {
+		t.Errorf("unexpected usage %+v", response.Usage)
+	}
+}
+
+func TestBedrockStopSequences(t *testing.T) {
+	tests := []struct {
+		name         string
+		sequences    []string
+		additional   string
+		wantSequence string
+	}{
+		{"named by the model", []string{"\n\n", "END"}, `,"additionalModelResponseFields":{"stop_sequence":"END"}`, "END"},
+		{"only one configured", []string{"END"}, "", "END"},
+		{"unknown which matched", []string{"\n\n", "END"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody bedrockConverseRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &gotBody)
+				_, _ = w.Write([]byte(`{
+					"output": {"message": {"role": "assistant", "content": [{"text": "Done"}]}},
+					"stopReason": "stop_sequence"` + tt.additional + `
+				}`))
+			}))
+			defer server.Close()
+
+			model := newBedrockTestModel(t, server.URL)
+			elements := make([]Value, len(tt.sequences))
+			for idx, seq := range tt.sequences {
+				elements[idx] = &StringValue{Value: seq}
+			}
+			model.Config["stopSequences"] = &ArrayValue{Elements: elements}
+
+			response, err := NewBedrockProvider().ChatCompletion(context.Background(), ChatRequest{
+				Model:    model,
+				Messages: []ChatMessage{{Role: "user", Content: "Go"}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotBody.InferenceConfig == nil || strings.Join(gotBody.InferenceConfig.StopSequences, "|") != strings.Join(tt.sequences, "|") {
+				t.Errorf("expected stopSequences in the inference config, got %+v", gotBody.InferenceConfig)
+			}
+			if response.FinishReason != "stop_sequence" || response.StopSequence != tt.wantSequence {
+				t.Errorf("expected stop_sequence with %q, got %q with %q", tt.wantSequence, response.FinishReason, response.StopSequence)
+			}
+		})
+	}
+}
+
+func TestBedrockChatCompletionStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"The security token included in the request is invalid."}`))
+	}))
+	defer server.Close()
+
+	_, err := NewBedrockProvider().ChatCompletion(context.Background(), ChatRequest{
+		Model:    newBedrockTestModel(t, server.URL),
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
+
+// encodeEventStreamMessage encodes a message with string headers in the AWS event stream format
+func encodeEventStreamMessage(headers map[string]string, payload string) []byte {
+	var headerBytes []byte
+	for name, value := range headers {
+		headerBytes = append(headerBytes, byte(len(name)))
+		headerBytes = append(headerBytes, name...)
+		headerBytes = append(headerBytes, 7)
+		headerBytes = binary.BigEndian.AppendUint16(headerBytes, uint16(len(value)))
+		headerBytes = append(headerBytes, value...)
+	}
+	totalLength := 12 + len(headerBytes) + len(payload) + 4
+	message := binary.BigEndian.AppendUint32(nil, uint32(totalLength))
+	message = binary.BigEndian.AppendUint32(message, uint32(len(headerBytes)))
+	message = binary.BigEndian.AppendUint32(message, crc32.ChecksumIEEE(message))
+	message = append(message, headerBytes...)
+	message = append(message, payload...)
+	return binary.BigEndian.AppendUint32(message, crc32.ChecksumIEEE(message))
+}
+
+func TestBedrockStreamingChatCompletion(t *testing.T) {
+	events := []struct{ eventType, payload string }{
+		{"messageStart", `{"role":"assistant"}`},
+		{"contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"Let me "}}`},
+		{"contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"check."}}`},
+		{"contentBlockStop", `{"contentBlockIndex":0}`},
+		{"contentBlockStart", `{"contentBlockIndex":1,"start":{"toolUse":{"toolUseId":"tool_1","name":"exec"}}}`},
+		{"contentBlockDelta", `{"contentBlockIndex":1,"delta":{"toolUse":{"input":"{\"comm"}}}`},
+		{"contentBlockDelta", `{"contentBlockIndex":1,"delta":{"toolUse":{"input":"and\":\"ls\"}"}}}`},
+		{"contentBlockStop", `{"contentBlockIndex":1}`},
+		{"messageStop", `{"stopReason":"tool_use"}`},
+		{"metadata", `{"usage":{"inputTokens":12,"outputTokens":8,"totalTokens":20}}`},
+	}
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, event := range events {
+			_, _ = w.Write(encodeEventStreamMessage(map[string]string{
+				":message-type": "event",
+				":event-type":   event.eventType,
+			}, event.payload))
+		}
+	}))
+	defer server.Close()
+
+	var content, pending string
+	var usage ChatUsage
+	ended := false
+	response, err := NewBedrockProvider().StreamingChatCompletion(context.Background(), ChatRequest{
+		Model:    newBedrockTestModel(t, server.URL),
+		Messages: []ChatMessage{{Role: "user", Content: "List files"}},
+	}, &StreamCallbacks{
+		OnContent:     func(chunk string) { content += chunk },
+		OnToolPending: func(id, name string) { pending = id + ":" + name },
+		OnUsage:       func(delta *ChatUsage) { usage.TotalTokens += delta.TotalTokens },
+		OnStreamEnd:   func() { ended = true },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/model/anthropic.claude-v2%3A1/converse-stream" {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+	if content != "Let me check." || pending != "tool_1:exec" || usage.TotalTokens != 20 || !ended {
+		t.Errorf("unexpected callbacks: content=%q pending=%q usage=%d ended=%v", content, pending, usage.TotalTokens, ended)
+	}
+	if response.Content != "Let me check." || response.FinishReason != "tool_calls" {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].Arguments["command"] != "ls" {
+		t.Errorf("unexpected tool calls %+v", response.ToolCalls)
+	}
+}
+
+func TestBedrockReasoning(t *testing.T) {
+	t.Run("reasoning block in a response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"output":{"message":{"role":"assistant","content":[` +
+				`{"reasoningContent":{"reasoningText":{"text":"The user wants a greeting.","signature":"sig"}}},` +
+				`{"text":"Hello!"}]}},"stopReason":"end_turn"}`))
+		}))
+		defer server.Close()
+
+		response, err := NewBedrockProvider().ChatCompletion(context.Background(), ChatRequest{
+			Model:    newBedrockTestModel(t, server.URL),
+			Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Content != "Hello!" || response.Reasoning != "The user wants a greeting." {
+			t.Errorf("expected content and reasoning to be separate, got %q and %q", response.Content, response.Reasoning)
+		}
+	})
+
+	t.Run("reasoning deltas while streaming", func(t *testing.T) {
+		events := []struct{ eventType, payload string }{
+			{"contentBlockDelta", `{"contentBlockIndex":0,"delta":{"reasoningContent":{"text":"Greet "}}}`},
+			{"contentBlockDelta", `{"contentBlockIndex":0,"delta":{"reasoningContent":{"text":"them."}}}`},
+			{"contentBlockDelta", `{"contentBlockIndex":0,"delta":{"reasoningContent":{"signature":"sig"}}}`},
+			{"contentBlockDelta", `{"contentBlockIndex":1,"delta":{"text":"Hello!"}}`},
+			{"messageStop", `{"stopReason":"end_turn"}`},
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, event := range events {
+				_, _ = w.Write(encodeEventStreamMessage(map[string]string{
+					":message-type": "event",
+					":event-type":   event.eventType,
+				}, event.payload))
+			}
+		}))
+		defer server.Close()
+
+		var reasoning, content string
+		response, err := NewBedrockProvider().StreamingChatCompletion(context.Background(), ChatRequest{
+			Model:    newBedrockTestModel(t, server.URL),
+			Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+		}, &StreamCallbacks{
+			OnReasoning: func(chunk string) { reasoning += chunk },
+			OnContent:   func(chunk string) { content += chunk },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reasoning != "Greet them." || content != "Hello!" {
+			t.Errorf("unexpected callbacks: reasoning=%q content=%q", reasoning, content)
+		}
+		if response.Content != "Hello!" || response.Reasoning != "Greet them." {
+			t.Errorf("expected content and reasoning to be separate, got %q and %q", response.Content, response.Reasoning)
+		}
+	})
+}
+
+func TestBedrockStreamingException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(encodeEventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+		}, `{"message":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewBedrockProvider().StreamingChatCompletion(context.Background(), ChatRequest{
+		Model:    newBedrockTestModel(t, server.URL),
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "throttlingException") {
+		t.Errorf("expected the stream exception, got %v", err)
+	}
+}
+
+func TestBuildBedrockConverseRequestImages(t *testing.T) {
+	model := newBedrockTestModel(t, "http://localhost")
+	model.Config["vision"] = &BoolValue{Value: true}
+	req := ChatRequest{
+		Model: model,
+		Messages: []ChatMessage{
+			{Role: "user", Content: "What is this?"},
+			{Role: "user", Images: []ChatImage{{MediaType: "image/jpeg", Data: []byte("jpg")}}},
+		},
+	}
+
+	converseReq, err := buildBedrockConverseRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, err := json.Marshal(converseReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"messages":[{"role":"user","content":[{"text":"What is this?"},{"image":{"format":"jpeg","source":{"bytes":"anBn"}}}]}]`
+	if !strings.Contains(string(body), want) {
+		t.Errorf("expected %s in request body, got %s", want, body)
+	}
+
+	delete(model.Config, "vision")
+	if _, err := buildBedrockConverseRequest(req); err == nil || !strings.Contains(err.Error(), "does not accept images") {
+		t.Errorf("expected an error about images, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,6 +30,22 @@ func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
+// openAIStopSequence returns the stop sequence that ended a response, or "". OpenAI's API
+// reports "stop" whether or not a stop sequence matched, but compatible servers such as vLLM
+// also send a stop_reason holding the matched sequence.
+func openAIStopSequence(finishReason string, stopReason any, model *ModelValue) string {
+	seq, ok := stopReason.(string)
+	if finishReason != "stop" || !ok {
+		return ""
+	}
+	for _, configured := range modelStopSequences(model) {
+		if seq == configured {
+			return seq
+		}
+	}
+	return ""
+}
+
 // extractStringContent extracts string content from an interface{} that may be
 // a string or an array of content parts (multipart format).
 // Used when parsing API responses where content could be in either format.
@@ -57,10 +74,11 @@ func extractStringContent(content interface{}) string {
 
 // convertMessageContent converts a ChatMessage's content to the appropriate format.
 // If ContentParts is set, it returns an array of openAIContentPart for multipart/cache control.
-// Otherwise, it returns the plain string content.
+// Otherwise, it returns the plain string content. Images follow as image_url parts.
 func convertMessageContent(msg ChatMessage) []openAIContentPart {
+	var parts []openAIContentPart
 	if len(msg.ContentParts) > 0 {
-		parts := make([]openAIContentPart, len(msg.ContentParts))
+		parts = make([]openAIContentPart, len(msg.ContentParts), len(msg.ContentParts)+len(msg.Images))
 		for i, part := range msg.ContentParts {
 			parts[i] = openAIContentPart{
 				Type: part.Type,
@@ -68,23 +86,43 @@ func convertMessageContent(msg ChatMessage) []openAIContentPart {
 				// deliberately omit CacheControl because we set it later
 			}
 		}
-		return parts
-	} else {
-		parts := make([]openAIContentPart, 1)
+	} else if msg.Content != "" || len(msg.Images) == 0 {
+		parts = make([]openAIContentPart, 1, 1+len(msg.Images))
 		parts[0] = openAIContentPart{
 			Type: "text",
 			Text: msg.Content,
 		}
-		return parts
 	}
+	for _, image := range msg.Images {
+		parts = append(parts, openAIContentPart{
+			Type: "image_url",
+			ImageURL: &openAIImageURL{
+				URL: "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data),
+			},
+		})
+	}
+	return parts
 }
 
 // ChatCompletion sends a chat completion request to OpenAI.
 // The ctx parameter allows cancellation of the request (e.g., via Ctrl+C).
 func (p *OpenAIProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	strict := openAIStrictTools(request)
+	response, err := p.chatCompletion(ctx, request, strict)
+	if strict && isStrictToolsUnsupported(err) {
+		return p.chatCompletion(ctx, request, false)
+	}
+	return response, err
+}
+
+// chatCompletion sends a chat completion request, marking tools strict when strictTools is set
+func (p *OpenAIProvider) chatCompletion(ctx context.Context, request ChatRequest, strictTools bool) (*ChatResponse, error) {
 	if request.Model == nil {
 		return nil, fmt.Errorf("OpenAI provider requires a model")
 	}
+	if err := checkModelAcceptsImages(request); err != nil {
+		return nil, err
+	}
 
 	ctx, cancel, err := withModelTimeout(ctx, request.Model)
 	if err != nil {
@@ -192,20 +230,11 @@ func (p *OpenAIProvider) ChatCompletion(ctx context.Context, request ChatRequest
 			openaiReq.TopP = &topP
 		}
 	}
+	openaiReq.Stop = modelStopSequences(request.Model)
 
 	// Convert tools if present
 	if len(request.Tools) > 0 {
-		openaiReq.Tools = make([]openAITool, len(request.Tools))
-		for i, tool := range request.Tools {
-			openaiReq.Tools[i] = openAITool{
-				Type: "function",
-				Function: openAIFunction{
-					Name:        tool.Name,
-					Description: tool.Description,
-					Parameters:  tool.Parameters,
-				},
-			}
-		}
+		openaiReq.Tools = convertOpenAITools(request.Tools, strictTools)
 	}
 
 	// Get extraBody if configured
@@ -258,7 +287,7 @@ func (p *OpenAIProvider) ChatCompletion(ctx context.Context, request ChatRequest
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, &openAIStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Parse response
@@ -275,8 +304,13 @@ func (p *OpenAIProvider) ChatCompletion(ctx context.Context, request ChatRequest
 	choice := openaiResp.Choices[0]
 	response := &ChatResponse{
 		Content:      extractStringContent(choice.Message.Content),
+		Reasoning:    openAIReasoning(choice.Message.ReasoningContent, choice.Message.Reasoning),
 		FinishReason: choice.FinishReason,
 	}
+	if seq := openAIStopSequence(choice.FinishReason, choice.StopReason, request.Model); seq != "" {
+		response.FinishReason = "stop_sequence"
+		response.StopSequence = seq
+	}
 
 	// Add usage information if present
 	if openaiResp.Usage != nil {
@@ -316,9 +350,24 @@ func (p *OpenAIProvider) ChatCompletion(ctx context.Context, request ChatRequest
 // The ctx parameter allows cancellation of the streaming request (e.g., via Ctrl+C).
 // The callbacks provide hooks for content chunks and tool call detection.
 func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	strict := openAIStrictTools(request)
+	response, err := p.streamingChatCompletion(ctx, request, callbacks, strict)
+	if strict && isStrictToolsUnsupported(err) {
+		// The request is rejected before anything streams, so retrying can't repeat chunks
+		return p.streamingChatCompletion(ctx, request, callbacks, false)
+	}
+	return response, err
+}
+
+// streamingChatCompletion sends a streaming chat completion request, marking tools strict
+// when strictTools is set
+func (p *OpenAIProvider) streamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks, strictTools bool) (*ChatResponse, error) {
 	if request.Model == nil {
 		return nil, fmt.Errorf("OpenAI provider requires a model")
 	}
+	if err := checkModelAcceptsImages(request); err != nil {
+		return nil, err
+	}
 
 	ctx, cancel, err := withModelTimeout(ctx, request.Model)
 	if err != nil {
@@ -430,20 +479,11 @@ func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request Ch
 			openaiReq.TopP = &topP
 		}
 	}
+	openaiReq.Stop = modelStopSequences(request.Model)
 
 	// Convert tools if present
 	if len(request.Tools) > 0 {
-		openaiReq.Tools = make([]openAITool, len(request.Tools))
-		for i, tool := range request.Tools {
-			openaiReq.Tools[i] = openAITool{
-				Type: "function",
-				Function: openAIFunction{
-					Name:        tool.Name,
-					Description: tool.Description,
-					Parameters:  tool.Parameters,
-				},
-			}
-		}
+		openaiReq.Tools = convertOpenAITools(request.Tools, strictTools)
 	}
 
 	// Get extraBody if configured
@@ -492,14 +532,17 @@ func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request Ch
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, &openAIStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Parse SSE stream
 	var fullContent strings.Builder
+	var fullReasoning strings.Builder
 	var finishReason string
+	var stopSequence string
 	var toolCalls []ChatToolCall
 	var usage *ChatUsage
+	var reportedUsage ChatUsage
 
 	// Track which tool calls we've already notified about
 	toolCallNotified := make(map[int]bool)
@@ -548,12 +591,25 @@ func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request Ch
 			if chunk.Usage.PromptTokensDetails != nil {
 				usage.CachedTokens = chunk.Usage.PromptTokensDetails.CachedTokens
 			}
+			// Report only what changed since the last usage chunk (usage is cumulative)
+			if callbacks != nil && callbacks.OnUsage != nil {
+				callbacks.OnUsage(usageDelta(reportedUsage, usage))
+			}
+			reportedUsage = *usage
 		}
 
 		// Process choices
 		if len(chunk.Choices) > 0 {
 			choice := chunk.Choices[0]
 
+			// Accumulate reasoning, which reasoning models stream before the content
+			if reasoning := openAIReasoning(choice.Delta.ReasoningContent, choice.Delta.Reasoning); reasoning != "" {
+				fullReasoning.WriteString(reasoning)
+				if callbacks != nil && callbacks.OnReasoning != nil {
+					callbacks.OnReasoning(reasoning)
+				}
+			}
+
 			// Accumulate content
 			if choice.Delta.Content != "" {
 				fullContent.WriteString(choice.Delta.Content)
@@ -566,6 +622,7 @@ func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request Ch
 			// Capture finish reason
 			if choice.FinishReason != "" {
 				finishReason = choice.FinishReason
+				stopSequence = openAIStopSequence(choice.FinishReason, choice.StopReason, request.Model)
 			}
 
 			// Handle tool calls (accumulated across chunks)
@@ -625,14 +682,43 @@ func (p *OpenAIProvider) StreamingChatCompletion(ctx context.Context, request Ch
 	// Build final response
 	response := &ChatResponse{
 		Content:      fullContent.String(),
+		Reasoning:    fullReasoning.String(),
 		FinishReason: finishReason,
 		ToolCalls:    toolCalls,
 		Usage:        usage,
 	}
+	if stopSequence != "" {
+		response.FinishReason = "stop_sequence"
+		response.StopSequence = stopSequence
+	}
+
+	if callbacks != nil && callbacks.OnStreamEnd != nil {
+		callbacks.OnStreamEnd()
+	}
 
 	return response, nil
 }
 
+// openAIReasoning returns the reasoning text of a message or delta. OpenAI-compatible
+// servers name the field differently: reasoning_content (DeepSeek, vLLM) or reasoning
+// (OpenRouter, Ollama).
+func openAIReasoning(reasoningContent, reasoning string) string {
+	if reasoningContent != "" {
+		return reasoningContent
+	}
+	return reasoning
+}
+
+// usageDelta returns the token counts in current that were not already included in previous.
+func usageDelta(previous ChatUsage, current *ChatUsage) *ChatUsage {
+	return &ChatUsage{
+		PromptTokens:     current.PromptTokens - previous.PromptTokens,
+		CompletionTokens: current.CompletionTokens - previous.CompletionTokens,
+		TotalTokens:      current.TotalTokens - previous.TotalTokens,
+		CachedTokens:     current.CachedTokens - previous.CachedTokens,
+	}
+}
+
 func withModelTimeout(ctx context.Context, model *ModelValue) (context.Context, context.CancelFunc, error) {
 	timeoutVal, ok := model.Config["timeout"]
 	if !ok {
@@ -702,6 +788,7 @@ type openAIStreamingChatCompletionRequest struct {
 	Temperature   *float64             `json:"temperature,omitempty"`
 	MaxTokens     *int                 `json:"max_tokens,omitempty"`
 	TopP          *float64             `json:"top_p,omitempty"`
+	Stop          []string             `json:"stop,omitempty"`
 	Tools         []openAITool         `json:"tools,omitempty"`
 }
 
@@ -726,12 +813,15 @@ type openAIStreamChoice struct {
 	Index        int               `json:"index"`
 	Delta        openAIStreamDelta `json:"delta"`
 	FinishReason string            `json:"finish_reason"`
+	StopReason   any               `json:"stop_reason,omitempty"`
 }
 
 type openAIStreamDelta struct {
-	Role      string                      `json:"role,omitempty"`
-	Content   string                      `json:"content,omitempty"`
-	ToolCalls []openAIStreamDeltaToolCall `json:"tool_calls,omitempty"`
+	Role             string                      `json:"role,omitempty"`
+	Content          string                      `json:"content,omitempty"`
+	ReasoningContent string                      `json:"reasoning_content,omitempty"`
+	Reasoning        string                      `json:"reasoning,omitempty"`
+	ToolCalls        []openAIStreamDeltaToolCall `json:"tool_calls,omitempty"`
 }
 
 type openAIStreamDeltaToolCall struct {
@@ -748,6 +838,7 @@ type openAIChatCompletionRequest struct {
 	Temperature *float64            `json:"temperature,omitempty"`
 	MaxTokens   *int                `json:"max_tokens,omitempty"`
 	TopP        *float64            `json:"top_p,omitempty"`
+	Stop        []string            `json:"stop,omitempty"`
 	Tools       []openAITool        `json:"tools,omitempty"`
 }
 
@@ -759,6 +850,10 @@ type openAIMessage struct {
 	Name       *string                 `json:"name,omitempty"`
 	ToolCallID *string                 `json:"tool_call_id,omitempty"` // Required for tool result messages
 	ToolCalls  []openAIMessageToolCall `json:"tool_calls,omitempty"`
+
+	// Reasoning returned by reasoning models in responses, see openAIReasoning. Never sent.
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	Reasoning        string `json:"reasoning,omitempty"`
 }
 
 // openAIContentPart represents a content part in multipart message format.
@@ -766,6 +861,7 @@ type openAIMessage struct {
 type openAIContentPart struct {
 	Type         string              `json:"type"`                    // "text", "image_url"
 	Text         string              `json:"text,omitempty"`          // For "text" type
+	ImageURL     *openAIImageURL     `json:"image_url,omitempty"`     // For "image_url" type
 	CacheControl *openAICacheControl `json:"cache_control,omitempty"` // For prompt caching
 }
 
@@ -787,6 +883,9 @@ func (p openAIContentPart) MarshalJSON() ([]byte, error) {
 		m["text"] = p.Text
 	}
 
+	if p.ImageURL != nil {
+		m["image_url"] = p.ImageURL
+	}
 	if p.CacheControl != nil {
 		m["cache_control"] = p.CacheControl
 	}
@@ -794,6 +893,11 @@ func (p openAIContentPart) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// openAIImageURL holds an image as a URL, which for attached images is a base64 data URL
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
 // openAICacheControl specifies caching behavior for a content part.
 // Supported by OpenRouter (Anthropic, Gemini). Ignored by OpenAI direct and Ollama.
 type openAICacheControl struct {
@@ -816,6 +920,7 @@ type openAIFunction struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description,omitempty"`
 	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Strict      bool                   `json:"strict,omitempty"`    // Used in tool definitions with strictTools
 	Arguments   string                 `json:"arguments,omitempty"` // Used in tool call responses
 }
 
@@ -832,6 +937,7 @@ type openAIChoice struct {
 	Index        int           `json:"index"`
 	Message      openAIMessage `json:"message"`
 	FinishReason string        `json:"finish_reason"`
+	StopReason   any           `json:"stop_reason,omitempty"` // not part of OpenAI's API, see openAIStopSequence
 }
 
 type openAIUsage struct {
@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAIStatusError is returned when the API answers with a non-200 status
+type openAIStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *openAIStatusError) Error() string {
+	return fmt.Sprintf("OpenAI API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// openAIStrictTools reports whether the request's model opts into strict function calling
+// with strictTools: true, so the model's tool arguments always match the tool schemas
+func openAIStrictTools(request ChatRequest) bool {
+	if len(request.Tools) == 0 || request.Model == nil {
+		return false
+	}
+	strict, ok := request.Model.Config["strictTools"].(*BoolValue)
+	return ok && strict.Value
+}
+
+// isStrictToolsUnsupported reports whether err is the API rejecting strict tool
+// definitions, which providers and models without structured outputs do with a 400
+// that mentions "strict"
+func isStrictToolsUnsupported(err error) bool {
+	var statusErr *openAIStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(statusErr.Body), "strict")
+}
+
+// convertOpenAITools converts tools to the OpenAI format. With strict set, tools whose
+// schema can be expressed in strict mode are marked strict, and the rest are sent as usual.
+func convertOpenAITools(tools []ChatTool, strict bool) []openAITool {
+	result := make([]openAITool, len(tools))
+	for i, tool := range tools {
+		function := openAIFunction{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  tool.Parameters,
+		}
+		if strict {
+			if params, ok := strictToolSchema(tool.Parameters); ok {
+				function.Parameters = params
+				function.Strict = true
+			}
+		}
+		result[i] = openAITool{Type: "function", Function: function}
+	}
+	return result
+}
+
+// strictToolSchema returns a copy of a tool's parameters schema with additionalProperties
+// disabled on every object, as strict mode requires. ok is false when the schema can't be
+// made strict without changing what the tool accepts: optional properties, free-form
+// objects, and arrays without an items schema.
+func strictToolSchema(params map[string]interface{}) (map[string]interface{}, bool) {
+	if params == nil {
+		return nil, false
+	}
+	// Round-trip through JSON for a deep copy with uniform types ([]string becomes []interface{})
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, false
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, false
+	}
+	if !makeSchemaStrict(schema) {
+		return nil, false
+	}
+	return schema, true
+}
+
+// makeSchemaStrict updates schema in place for strict mode, reporting whether it could
+func makeSchemaStrict(schema map[string]interface{}) bool {
+	if variants, ok := schema["anyOf"].([]interface{}); ok {
+		for _, variant := range variants {
+			variantSchema, ok := variant.(map[string]interface{})
+			if !ok || !makeSchemaStrict(variantSchema) {
+				return false
+			}
+		}
+	}
+
+	switch {
+	case schemaHasType(schema, "object"):
+		properties, ok := schema["properties"].(map[string]interface{})
+		if !ok || len(properties) == 0 {
+			return false
+		}
+		required := map[string]bool{}
+		if list, ok := schema["required"].([]interface{}); ok {
+			for _, name := range list {
+				if str, ok := name.(string); ok {
+					required[str] = true
+				}
+			}
+		}
+		for name, prop := range properties {
+			propSchema, ok := prop.(map[string]interface{})
+			if !required[name] || !ok || !makeSchemaStrict(propSchema) {
+				return false
+			}
+		}
+		schema["additionalProperties"] = false
+	case schemaHasType(schema, "array"):
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok || !makeSchemaStrict(items) {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaHasType reports whether a JSON schema's type is name, or a list that includes it
+func schemaHasType(schema map[string]interface{}, name string) bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return t == name
+	case []interface{}:
+		for _, item := range t {
+			if item == name {
+				return true
+			}
+		}
+	}
+	return false
+}
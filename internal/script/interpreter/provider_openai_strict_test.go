@@ -0,0 +1,245 @@
+package interpreter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStrictToolSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		strict bool
+	}{
+		{
+			name: "all properties required",
+			params: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+				"required":   []string{"path"},
+			},
+			strict: true,
+		},
+		{
+			name: "optional property",
+			params: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path":  map[string]interface{}{"type": "string"},
+					"limit": map[string]interface{}{"type": "number"},
+				},
+				"required": []string{"path"},
+			},
+			strict: false,
+		},
+		{
+			name: "free-form object property",
+			params: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"opts": map[string]interface{}{"type": "object"}},
+				"required":   []string{"opts"},
+			},
+			strict: false,
+		},
+		{
+			name: "array without items",
+			params: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"tags": map[string]interface{}{"type": "array"}},
+				"required":   []string{"tags"},
+			},
+			strict: false,
+		},
+		{
+			name:   "no parameters",
+			params: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+			strict: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, ok := strictToolSchema(tt.params)
+			if ok != tt.strict {
+				t.Fatalf("expected strict %v, got %v", tt.strict, ok)
+			}
+			if ok && schema["additionalProperties"] != false {
+				t.Errorf("expected additionalProperties false, got %v", schema["additionalProperties"])
+			}
+			if _, changed := tt.params["additionalProperties"]; changed {
+				t.Error("expected the original schema to be left alone")
+			}
+		})
+	}
+
+	nested := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+					"required":   []interface{}{"id"},
+				},
+			},
+		},
+		"required": []interface{}{"items"},
+	}
+	schema, ok := strictToolSchema(nested)
+	if !ok {
+		t.Fatal("expected nested schema to be strict")
+	}
+	item := schema["properties"].(map[string]interface{})["items"].(map[string]interface{})["items"].(map[string]interface{})
+	if item["additionalProperties"] != false {
+		t.Errorf("expected additionalProperties false on nested objects, got %v", item["additionalProperties"])
+	}
+}
+
+// strictToolsRequest builds a request with one strict-compatible and one optional-parameter tool
+func strictToolsRequest(baseURL string) ChatRequest {
+	return ChatRequest{
+		Model: &ModelValue{
+			Name: "gpt4",
+			Config: map[string]Value{
+				"provider":    &StringValue{Value: "openai"},
+				"apiKey":      &StringValue{Value: "test-key"},
+				"model":       &StringValue{Value: "gpt-4"},
+				"baseURL":     &StringValue{Value: baseURL},
+				"strictTools": &BoolValue{Value: true},
+			},
+		},
+		Messages: []ChatMessage{{Role: "user", Content: "Test"}},
+		Tools: []ChatTool{
+			{Name: "greet", Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+				"required":   []string{"name"},
+			}},
+			{Name: "search", Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+				"required":   []string{},
+			}},
+		},
+	}
+}
+
+// requestToolsStrict returns the strict flag of each tool in a request body
+func requestToolsStrict(t *testing.T, r *http.Request) []interface{} {
+	t.Helper()
+	var body struct {
+		Tools []struct {
+			Function map[string]interface{} `json:"function"`
+		} `json:"tools"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("failed to decode request body: %v", err)
+	}
+	flags := []interface{}{}
+	for _, tool := range body.Tools {
+		flags = append(flags, tool.Function["strict"])
+	}
+	return flags
+}
+
+func TestOpenAIProviderStrictTools(t *testing.T) {
+	var flags []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flags = requestToolsStrict(t, r)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider()
+	if _, err := provider.ChatCompletion(context.Background(), strictToolsRequest(server.URL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(flags) != 2 || flags[0] != true || flags[1] != nil {
+		t.Errorf("expected only the first tool to be strict, got %v", flags)
+	}
+
+	// Without strictTools, no tool is marked strict
+	req := strictToolsRequest(server.URL)
+	delete(req.Model.Config, "strictTools")
+	if _, err := provider.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(flags) != 2 || flags[0] != nil || flags[1] != nil {
+		t.Errorf("expected no strict tools, got %v", flags)
+	}
+}
+
+func TestOpenAIProviderStrictToolsFallback(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		var requests [][]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			flags := requestToolsStrict(t, r)
+			requests = append(requests, flags)
+			if flags[0] == true {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"message": "Unrecognized request argument supplied: strict"}}`))
+				return
+			}
+			if streaming {
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n"))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+		}))
+
+		provider := NewOpenAIProvider()
+		var resp *ChatResponse
+		var err error
+		if streaming {
+			resp, err = provider.StreamingChatCompletion(context.Background(), strictToolsRequest(server.URL), nil)
+		} else {
+			resp, err = provider.ChatCompletion(context.Background(), strictToolsRequest(server.URL))
+		}
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("streaming=%v: unexpected error: %v", streaming, err)
+		}
+		if resp.Content != "ok" {
+			t.Errorf("streaming=%v: expected content 'ok', got %q", streaming, resp.Content)
+		}
+		if len(requests) != 2 || requests[1][0] != nil {
+			t.Errorf("streaming=%v: expected a retry without strict, got %v", streaming, requests)
+		}
+	}
+}
+
+func TestOpenAIProviderStrictToolsOtherErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "context length exceeded"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAIProvider().ChatCompletion(context.Background(), strictToolsRequest(server.URL))
+	if err == nil || !strings.Contains(err.Error(), "OpenAI API returned status 400") {
+		t.Errorf("expected the status error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry for unrelated errors, got %d calls", calls)
+	}
+}
+
+func TestStrictToolsModelConfigValidation(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+	_, err := interp.EvalString(`model m { provider: "openai", model: "gpt-4", strictTools: "yes" }`, nil)
+	if err == nil || !strings.Contains(err.Error(), "model config 'strictTools' must be a boolean, got string") {
+		t.Errorf("expected a boolean error, got %v", err)
+	}
+}
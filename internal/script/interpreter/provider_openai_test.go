@@ -987,3 +987,310 @@ data: [DONE]
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestOpenAIProviderStreamingUsageAndStreamEnd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`data: {"id":"chatcmpl-stream","object":"chat.completion.chunk","created":1234567890,"model":"gpt-4","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"},"finish_reason":null}],"usage":{"prompt_tokens":10,"completion_tokens":1,"total_tokens":11}}
+
+data: {"id":"chatcmpl-stream","object":"chat.completion.chunk","created":1234567890,"model":"gpt-4","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}
+
+data: {"id":"chatcmpl-stream","object":"chat.completion.chunk","created":1234567890,"model":"gpt-4","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15,"prompt_tokens_details":{"cached_tokens":4}}}
+
+data: [DONE]
+`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider()
+	req := ChatRequest{
+		Model: &ModelValue{
+			Name: "gpt4",
+			Config: map[string]Value{
+				"provider": &StringValue{Value: "openai"},
+				"apiKey":   &StringValue{Value: "test-key"},
+				"model":    &StringValue{Value: "gpt-4"},
+				"baseURL":  &StringValue{Value: server.URL},
+			},
+		},
+		Messages: []ChatMessage{
+			{Role: "user", Content: "Test"},
+		},
+	}
+
+	var deltas []ChatUsage
+	var events []string
+	callbacks := &StreamCallbacks{
+		OnContent: func(content string) {
+			events = append(events, "content")
+		},
+		OnUsage: func(delta *ChatUsage) {
+			deltas = append(deltas, *delta)
+		},
+		OnStreamEnd: func() {
+			events = append(events, "end")
+		},
+	}
+
+	resp, err := provider.StreamingChatCompletion(context.Background(), req, callbacks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedDeltas := []ChatUsage{
+		{PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11},
+		{PromptTokens: 0, CompletionTokens: 4, TotalTokens: 4, CachedTokens: 4},
+	}
+	if len(deltas) != len(expectedDeltas) {
+		t.Fatalf("expected %d usage deltas, got %d", len(expectedDeltas), len(deltas))
+	}
+	for i, expected := range expectedDeltas {
+		if deltas[i] != expected {
+			t.Errorf("delta %d: expected %+v, got %+v", i, expected, deltas[i])
+		}
+	}
+
+	if len(events) != 2 || events[0] != "content" || events[1] != "end" {
+		t.Errorf("expected OnStreamEnd once after content, got %v", events)
+	}
+
+	if resp.Usage == nil || resp.Usage.TotalTokens != 15 || resp.Usage.CachedTokens != 4 {
+		t.Errorf("expected final usage total=15 cached=4, got %+v", resp.Usage)
+	}
+}
+
+func TestOpenAIProviderStopSequences(t *testing.T) {
+	tests := []struct {
+		name         string
+		stream       bool
+		stopReason   string
+		wantFinish   string
+		wantSequence string
+	}{
+		{"reports the matched sequence", false, `,"stop_reason":"END"`, "stop_sequence", "END"},
+		{"reports the matched sequence when streaming", true, `,"stop_reason":"END"`, "stop_sequence", "END"},
+		{"plain stop without stop_reason", false, "", "stop", ""},
+		{"stop_reason that is not a configured sequence", false, `,"stop_reason":128001`, "stop", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStop interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var reqBody map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				gotStop = reqBody["stop"]
+
+				w.WriteHeader(http.StatusOK)
+				if tt.stream {
+					w.Write([]byte(`data: {"choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":null}]}
+
+data: {"choices":[{"index":0,"delta":{},"finish_reason":"stop"` + tt.stopReason + `}]}
+
+data: [DONE]
+`))
+					return
+				}
+				w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"` + tt.stopReason + `}]}`))
+			}))
+			defer server.Close()
+
+			req := ChatRequest{
+				Model: &ModelValue{
+					Name: "local",
+					Config: map[string]Value{
+						"provider":      &StringValue{Value: "openai"},
+						"apiKey":        &StringValue{Value: "test-key"},
+						"model":         &StringValue{Value: "llama"},
+						"baseURL":       &StringValue{Value: server.URL},
+						"stopSequences": &ArrayValue{Elements: []Value{&StringValue{Value: "\n\n"}, &StringValue{Value: "END"}}},
+					},
+				},
+				Messages: []ChatMessage{{Role: "user", Content: "Test"}},
+			}
+
+			provider := NewOpenAIProvider()
+			var resp *ChatResponse
+			var err error
+			if tt.stream {
+				resp, err = provider.StreamingChatCompletion(context.Background(), req, nil)
+			} else {
+				resp, err = provider.ChatCompletion(context.Background(), req)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stop, ok := gotStop.([]interface{})
+			if !ok || len(stop) != 2 || stop[0] != "\n\n" || stop[1] != "END" {
+				t.Errorf("expected stop to be sent, got %v", gotStop)
+			}
+			if resp.FinishReason != tt.wantFinish || resp.StopSequence != tt.wantSequence {
+				t.Errorf("expected finish reason %q and stop sequence %q, got %q and %q", tt.wantFinish, tt.wantSequence, resp.FinishReason, resp.StopSequence)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderOmitsStopByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if _, ok := reqBody["stop"]; ok {
+			t.Errorf("expected no stop field, got %v", reqBody["stop"])
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	req := ChatRequest{
+		Model: &ModelValue{
+			Name: "gpt4",
+			Config: map[string]Value{
+				"provider": &StringValue{Value: "openai"},
+				"apiKey":   &StringValue{Value: "test-key"},
+				"model":    &StringValue{Value: "gpt-4"},
+				"baseURL":  &StringValue{Value: server.URL},
+			},
+		},
+		Messages: []ChatMessage{{Role: "user", Content: "Test"}},
+	}
+	resp, err := NewOpenAIProvider().ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FinishReason != "stop" || resp.StopSequence != "" {
+		t.Errorf("expected a plain stop, got %q %q", resp.FinishReason, resp.StopSequence)
+	}
+}
+
+func TestOpenAIProviderImages(t *testing.T) {
+	var gotContent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		messages := reqBody["messages"].([]interface{})
+		gotContent = messages[0].(map[string]interface{})["content"]
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"A cat"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	model := &ModelValue{
+		Name: "vision",
+		Config: map[string]Value{
+			"provider": &StringValue{Value: "openai"},
+			"apiKey":   &StringValue{Value: "test-key"},
+			"model":    &StringValue{Value: "gpt-4o"},
+			"baseURL":  &StringValue{Value: server.URL},
+			"vision":   &BoolValue{Value: true},
+		},
+	}
+	req := ChatRequest{
+		Model: model,
+		Messages: []ChatMessage{{
+			Role:   "user",
+			Images: []ChatImage{{MediaType: "image/png", Data: []byte("png")}},
+		}},
+	}
+
+	provider := NewOpenAIProvider()
+	if _, err := provider.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts, ok := gotContent.([]interface{})
+	if !ok || len(parts) != 1 {
+		t.Fatalf("expected only an image part, got %v", gotContent)
+	}
+	part := parts[0].(map[string]interface{})
+	if part["type"] != "image_url" {
+		t.Errorf("expected image_url part, got %v", part["type"])
+	}
+	imageURL, _ := part["image_url"].(map[string]interface{})
+	if imageURL["url"] != "data:image/png;base64,cG5n" {
+		t.Errorf("expected base64 data URL, got %v", imageURL["url"])
+	}
+
+	// Without vision: true the request is refused before it is sent
+	delete(model.Config, "vision")
+	_, err := provider.ChatCompletion(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "model 'vision' does not accept images") {
+		t.Errorf("expected an error about images, got %v", err)
+	}
+}
+
+func TestOpenAIProviderReasoning(t *testing.T) {
+	newModel := func(url string) *ModelValue {
+		return &ModelValue{
+			Name: "reasoner",
+			Config: map[string]Value{
+				"provider": &StringValue{Value: "openai"},
+				"apiKey":   &StringValue{Value: "test-key"},
+				"model":    &StringValue{Value: "deepseek-reasoner"},
+				"baseURL":  &StringValue{Value: url},
+			},
+		}
+	}
+
+	t.Run("reasoning_content in a response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"4","reasoning_content":"2+2 is 4."},"finish_reason":"stop"}]}`))
+		}))
+		defer server.Close()
+
+		resp, err := NewOpenAIProvider().ChatCompletion(context.Background(), ChatRequest{
+			Model:    newModel(server.URL),
+			Messages: []ChatMessage{{Role: "user", Content: "2+2?"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Content != "4" || resp.Reasoning != "2+2 is 4." {
+			t.Errorf("expected content and reasoning to be separate, got %q and %q", resp.Content, resp.Reasoning)
+		}
+	})
+
+	t.Run("reasoning deltas while streaming", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte(`data: {"choices":[{"index":0,"delta":{"role":"assistant","reasoning":"Adding "},"finish_reason":null}]}
+
+data: {"choices":[{"index":0,"delta":{"reasoning":"two and two."},"finish_reason":null}]}
+
+data: {"choices":[{"index":0,"delta":{"content":"4"},"finish_reason":"stop"}]}
+
+data: [DONE]
+`))
+		}))
+		defer server.Close()
+
+		var events []string
+		resp, err := NewOpenAIProvider().StreamingChatCompletion(context.Background(), ChatRequest{
+			Model:    newModel(server.URL),
+			Messages: []ChatMessage{{Role: "user", Content: "2+2?"}},
+		}, &StreamCallbacks{
+			OnReasoning: func(content string) { events = append(events, "reasoning:"+content) },
+			OnContent:   func(content string) { events = append(events, "content:"+content) },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(events, "|") != "reasoning:Adding |reasoning:two and two.|content:4" {
+			t.Errorf("unexpected callbacks %v", events)
+		}
+		if resp.Content != "4" || resp.Reasoning != "Adding two and two." {
+			t.Errorf("expected content and reasoning to be separate, got %q and %q", resp.Content, resp.Reasoning)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+)
+
+// EventModelRoute is emitted each time a router model picks an underlying model
+const EventModelRoute = "model.route"
+
+// RouterProvider implements ModelProvider by delegating each request to one of several
+// declared models, chosen at random by weight. It is useful for A/B testing models:
+//
+//	model router {
+//	    provider: "router",
+//	    options: [{ model: a, weight: 0.7 }, { model: b, weight: 0.3 }],
+//	}
+//
+// Each choice is surfaced through the model.route event.
+type RouterProvider struct {
+	interp *Interpreter
+
+	// random returns a number in [0, 1). Overridable for testing.
+	random func() float64
+}
+
+// routerOption is a single weighted choice of a router model
+type routerOption struct {
+	model  *ModelValue
+	weight float64
+}
+
+// NewRouterProvider creates a router provider that emits model.route events on interp
+func NewRouterProvider(interp *Interpreter) *RouterProvider {
+	return &RouterProvider{
+		interp: interp,
+		random: rand.Float64,
+	}
+}
+
+// Name returns the provider name
+func (p *RouterProvider) Name() string {
+	return "router"
+}
+
+// ChatCompletion delegates the request to a weighted random choice among the router's models
+func (p *RouterProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	model, err := p.route(request.Model)
+	if err != nil {
+		return nil, err
+	}
+	return model.ChatCompletion(ctx, request)
+}
+
+// StreamingChatCompletion delegates the request to a weighted random choice among the router's models
+func (p *RouterProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	model, err := p.route(request.Model)
+	if err != nil {
+		return nil, err
+	}
+	return model.StreamingChatCompletion(ctx, request, callbacks)
+}
+
+// route picks the model for a request and emits model.route
+func (p *RouterProvider) route(router *ModelValue) (*ModelValue, error) {
+	if router == nil {
+		return nil, fmt.Errorf("router: model is required")
+	}
+	options, err := parseRouterOptions(router.Name, router.Config["options"])
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+	for _, opt := range options {
+		total += opt.weight
+	}
+
+	chosen := options[len(options)-1]
+	target := p.random() * total
+	for _, opt := range options {
+		if target < opt.weight {
+			chosen = opt
+			break
+		}
+		target -= opt.weight
+	}
+
+	if p.interp != nil {
+		p.interp.EmitEvent(EventModelRoute, createModelRouteContext(router, chosen.model, chosen.weight/total))
+	}
+	return chosen.model, nil
+}
+
+// parseRouterOptions validates a router model's options array:
+// a non-empty array of { model: <model>, weight?: <positive number> } objects.
+// The weight defaults to 1.
+func parseRouterOptions(routerName string, value Value) ([]routerOption, error) {
+	arr, ok := value.(*ArrayValue)
+	if !ok || len(arr.Elements) == 0 {
+		return nil, fmt.Errorf("router model '%s' requires 'options': a non-empty array of { model, weight }", routerName)
+	}
+
+	options := make([]routerOption, 0, len(arr.Elements))
+	for idx, elem := range arr.Elements {
+		obj, ok := elem.(*ObjectValue)
+		if !ok {
+			return nil, fmt.Errorf("router model '%s' option %d must be an object, got %s", routerName, idx, elem.Type())
+		}
+
+		model, ok := obj.GetPropertyValue("model").(*ModelValue)
+		if !ok {
+			return nil, fmt.Errorf("router model '%s' option %d 'model' must be a model, got %s", routerName, idx, obj.GetPropertyValue("model").Type())
+		}
+
+		weight := 1.0
+		switch w := obj.GetPropertyValue("weight").(type) {
+		case *NullValue:
+		case *NumberValue:
+			if w.Value <= 0 {
+				return nil, fmt.Errorf("router model '%s' option %d 'weight' must be positive, got %v", routerName, idx, w.Value)
+			}
+			weight = w.Value
+		default:
+			return nil, fmt.Errorf("router model '%s' option %d 'weight' must be a number, got %s", routerName, idx, w.Type())
+		}
+
+		options = append(options, routerOption{model: model, weight: weight})
+	}
+	return options, nil
+}
+
+// createModelRouteContext creates the context object for the model.route event
+// ctx: { router: string, model: string, probability: number }
+func createModelRouteContext(router, chosen *ModelValue, probability float64) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"router":      {Value: &StringValue{Value: router.Name}},
+			"model":       {Value: &StringValue{Value: chosen.Name}},
+			"probability": {Value: &NumberValue{Value: probability}},
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package interpreter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const routerTestModels = `
+model modelA {
+	provider: "smart-mock",
+	model: "a",
+}
+
+model modelB {
+	provider: "smart-mock",
+	model: "b",
+}
+
+model router {
+	provider: "router",
+	options: [{ model: modelA, weight: 0.7 }, { model: modelB, weight: 0.3 }],
+}
+`
+
+func newRouterTestInterpreter(t *testing.T, random float64) (*Interpreter, *SmartMockProvider) {
+	t.Helper()
+	interp := New(nil)
+	t.Cleanup(func() { interp.Close() })
+
+	mock := NewSmartMockProvider()
+	interp.providerRegistry.Register(mock)
+	provider, _ := interp.providerRegistry.Get("router")
+	provider.(*RouterProvider).random = func() float64 { return random }
+	return interp, mock
+}
+
+func TestRouterProvider_WeightedSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		random   float64
+		expected string
+	}{
+		{"low draw picks first option", 0.1, "modelA"},
+		{"draw just below first weight picks first option", 0.69, "modelA"},
+		{"high draw picks second option", 0.7, "modelB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp, mock := newRouterTestInterpreter(t, tt.random)
+			result, err := interp.EvalString(routerTestModels, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			router, _ := result.Env.Get("router")
+
+			_, err = router.(*ModelValue).ChatCompletion(context.Background(), ChatRequest{
+				Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := mock.GetLastRequest().Model.Name; got != tt.expected {
+				t.Errorf("expected request routed to %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRouterProvider_RouteEvent(t *testing.T) {
+	interp, _ := newRouterTestInterpreter(t, 0.9)
+	result, err := interp.EvalString(routerTestModels+`
+agent routed {
+	model: router,
+	systemPrompt: "test",
+}
+
+routes = []
+tool onRoute(ctx, next) {
+	routes.push(ctx)
+	return next(ctx)
+}
+gsh.use("model.route", onRoute)
+
+conv = "hello" | routed
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	routesVal, _ := result.Env.Get("routes")
+	routes := routesVal.(*ArrayValue).Elements
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 model.route event, got %d", len(routes))
+	}
+	ctx := routes[0].(*ObjectValue)
+	if got := ctx.GetPropertyValue("router").String(); got != "router" {
+		t.Errorf("expected router 'router', got %s", got)
+	}
+	if got := ctx.GetPropertyValue("model").String(); got != "modelB" {
+		t.Errorf("expected model 'modelB', got %s", got)
+	}
+	if got := ctx.GetPropertyValue("probability").(*NumberValue).Value; got < 0.29 || got > 0.31 {
+		t.Errorf("expected probability 0.3, got %v", got)
+	}
+}
+
+func TestRouterProvider_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		errMsg  string
+	}{
+		{"missing options", ``, "requires 'options'"},
+		{"empty options", `options: [],`, "requires 'options'"},
+		{"non-object option", `options: ["modelA"],`, "option 0 must be an object"},
+		{"non-model model", `options: [{ model: "modelA" }],`, "option 0 'model' must be a model"},
+		{"non-positive weight", `options: [{ model: modelA, weight: 0 }],`, "option 0 'weight' must be positive"},
+		{"non-number weight", `options: [{ model: modelA, weight: "high" }],`, "option 0 'weight' must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp, _ := newRouterTestInterpreter(t, 0)
+			_, err := interp.EvalString(`
+model modelA {
+	provider: "smart-mock",
+	model: "a",
+}
+
+model router {
+	provider: "router",
+	`+tt.options+`
+}
+`, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -356,6 +356,14 @@ func (s *SmartMockProvider) StreamingChatCompletion(ctx context.Context, request
 		}
 	}
 
+	// Report usage and stream end as a real provider would in its final event
+	if callbacks != nil && callbacks.OnUsage != nil && response.Usage != nil {
+		callbacks.OnUsage(response.Usage)
+	}
+	if callbacks != nil && callbacks.OnStreamEnd != nil {
+		callbacks.OnStreamEnd()
+	}
+
 	return response, nil
 }
 
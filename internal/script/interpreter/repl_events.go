@@ -1,21 +1,38 @@
 package interpreter
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
 
 // REPL event names
 const (
-	EventReplReady         = "repl.ready"
-	EventReplExit          = "repl.exit"
-	EventReplPrompt        = "repl.prompt"
-	EventReplCommandBefore = "repl.command.before"
-	EventReplCommandAfter  = "repl.command.after"
-	EventReplPredict       = "repl.predict"
+	EventReplReady          = "repl.ready"
+	EventReplExit           = "repl.exit"
+	EventReplPrompt         = "repl.prompt"
+	EventReplCommandBefore  = "repl.command.before"
+	EventReplCommandAfter   = "repl.command.after"
+	EventReplCommandConfirm = "repl.command.confirm"
+	EventReplPredict        = "repl.predict"
 )
 
 // CreateReplReadyContext creates the context object for repl.ready event
-// ctx: null (no context needed)
-func CreateReplReadyContext() Value {
-	return &NullValue{}
+// ctx: { cwd: string, hostname: string, user: string, os: string, arch: string }
+func CreateReplReadyContext(cwd, hostname string) Value {
+	user := os.Getenv("USER")
+	if user == "" {
+		user = os.Getenv("USERNAME")
+	}
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"cwd":      {Value: &StringValue{Value: cwd}},
+			"hostname": {Value: &StringValue{Value: hostname}},
+			"user":     {Value: &StringValue{Value: user}},
+			"os":       {Value: &StringValue{Value: runtime.GOOS}},
+			"arch":     {Value: &StringValue{Value: runtime.GOARCH}},
+		},
+	}
 }
 
 // CreateReplExitContext creates the context object for repl.exit event
@@ -25,9 +42,14 @@ func CreateReplExitContext() Value {
 }
 
 // CreateReplPromptContext creates the context object for repl.prompt event
-// ctx: null (no context needed)
-func CreateReplPromptContext() Value {
-	return &NullValue{}
+// ctx: { exitCode: number, durationMs: number } describing the last executed command
+func CreateReplPromptContext(exitCode int, durationMs int64) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"exitCode":   {Value: &NumberValue{Value: float64(exitCode)}},
+			"durationMs": {Value: &NumberValue{Value: float64(durationMs)}},
+		},
+	}
 }
 
 // CreateReplCommandBeforeContext creates the context object for repl.command.before event
@@ -40,6 +62,22 @@ func CreateReplCommandBeforeContext(command string) Value {
 	}
 }
 
+// CreateReplCommandConfirmContext creates the context object for repl.command.confirm event
+// ctx: { command: string, pattern: string }
+func CreateReplCommandConfirmContext(command, pattern string) Value {
+	return &ObjectValue{
+		Properties: map[string]*PropertyDescriptor{
+			"command": {Value: &StringValue{Value: command}},
+			"pattern": {Value: &StringValue{Value: pattern}},
+		},
+	}
+}
+
+// CommandConfirmed reports whether a repl.command.confirm handler returned { approved: true }
+func CommandConfirmed(result Value) bool {
+	return extractApproved(result)
+}
+
 // CreateReplCommandAfterContext creates the context object for repl.command.after event
 // ctx: { command: string, exitCode: number, durationMs: number }
 func CreateReplCommandAfterContext(command string, exitCode int, durationMs int64) Value {
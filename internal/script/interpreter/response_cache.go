@@ -0,0 +1,203 @@
+package interpreter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kunchenguid/gsh/internal/core"
+	"go.uber.org/zap"
+)
+
+// DefaultResponseCacheTTL is how long cached responses stay valid unless
+// GSH_RESPONSE_CACHE_TTL says otherwise.
+const DefaultResponseCacheTTL = 24 * time.Hour
+
+// ResponseCache stores non-streaming model responses on disk, keyed by a hash of the
+// model configuration, messages and tools. It is meant for development, where the same
+// prompts are re-run repeatedly. Enable it with GSH_RESPONSE_CACHE=1.
+type ResponseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// responseCacheEntry is the on-disk format of a cached response
+type responseCacheEntry struct {
+	CreatedAt time.Time     `json:"createdAt"`
+	Response  *ChatResponse `json:"response"`
+}
+
+// NewResponseCache creates a cache storing entries in dir that expire after ttl.
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Get returns the cached response for request, if there is one that has not expired.
+func (c *ResponseCache) Get(request ChatRequest) (*ChatResponse, bool) {
+	key, err := responseCacheKey(request)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var entry responseCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Response == nil {
+		return nil, false
+	}
+	if c.now().Sub(entry.CreatedAt) > c.ttl {
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+// Put stores response as the cached response for request.
+func (c *ResponseCache) Put(request ChatRequest, response *ChatResponse) error {
+	key, err := responseCacheKey(request)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(responseCacheEntry{CreatedAt: c.now(), Response: response})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached response: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create response cache directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(c.dir, key+".json"), data, 0600)
+}
+
+// ClearResponseCache deletes every cached response in dir and returns how many were removed.
+func ClearResponseCache(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// responseCacheKey hashes everything that determines a model's response:
+// the provider, the model config, the messages (without timestamps) and the tools
+func responseCacheKey(request ChatRequest) (string, error) {
+	type keyMessage struct {
+		Role         string
+		Content      string
+		Name         string
+		ToolCallID   string
+		ToolCalls    []ChatToolCall
+		ContentParts []ContentPart
+		Images       []ChatImage
+	}
+	key := struct {
+		Provider string
+		Config   []string
+		Messages []keyMessage
+		Tools    []ChatTool
+	}{
+		Tools: request.Tools,
+	}
+
+	if request.Model != nil {
+		if request.Model.Provider != nil {
+			key.Provider = request.Model.Provider.Name()
+		}
+		for name, value := range request.Model.Config {
+			key.Config = append(key.Config, name+"="+value.String())
+		}
+		sort.Strings(key.Config)
+	}
+	for _, msg := range request.Messages {
+		key.Messages = append(key.Messages, keyMessage{
+			Role:         msg.Role,
+			Content:      msg.Content,
+			Name:         msg.Name,
+			ToolCallID:   msg.ToolCallID,
+			ToolCalls:    msg.ToolCalls,
+			ContentParts: msg.ContentParts,
+			Images:       msg.Images,
+		})
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute response cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// responseCache returns the response cache if GSH_RESPONSE_CACHE enables it, or nil.
+// GSH_RESPONSE_CACHE_TTL optionally sets the expiry as a duration such as "1h".
+func (i *Interpreter) responseCache() *ResponseCache {
+	enabled := strings.ToLower(i.GetEnv("GSH_RESPONSE_CACHE"))
+	if enabled != "1" && enabled != "true" {
+		return nil
+	}
+
+	ttl := DefaultResponseCacheTTL
+	if ttlStr := i.GetEnv("GSH_RESPONSE_CACHE_TTL"); ttlStr != "" {
+		parsed, err := time.ParseDuration(ttlStr)
+		if err != nil || parsed <= 0 {
+			if i.logger != nil {
+				i.logger.Debug("ignoring invalid GSH_RESPONSE_CACHE_TTL", zap.String("value", ttlStr))
+			}
+		} else {
+			ttl = parsed
+		}
+	}
+
+	dir := i.responseCacheDir
+	if dir == "" {
+		dir = core.ResponseCacheDir()
+	}
+	return NewResponseCache(dir, ttl)
+}
+
+// cachedChatCompletion performs a non-streaming chat completion, serving it from the
+// response cache when enabled. Cache hits report no token usage since no API call is made.
+func (i *Interpreter) cachedChatCompletion(ctx context.Context, model *ModelValue, request ChatRequest) (*ChatResponse, error) {
+	cache := i.responseCache()
+	if cache == nil {
+		return model.ChatCompletion(ctx, request)
+	}
+
+	request.Model = model
+	if response, ok := cache.Get(request); ok {
+		if i.logger != nil {
+			i.logger.Debug("response cache hit", zap.String("model", model.Name))
+		}
+		response.Usage = nil
+		return response, nil
+	}
+
+	response, err := model.ChatCompletion(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Put(request, response); err != nil && i.logger != nil {
+		i.logger.Debug("failed to cache response", zap.Error(err))
+	}
+	return response, nil
+}
@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache_GetPut(t *testing.T) {
+	cache := NewResponseCache(t.TempDir(), time.Hour)
+	model := &ModelValue{Name: "m", Config: map[string]Value{"model": &StringValue{Value: "gpt"}}, Provider: NewSmartMockProvider()}
+	request := ChatRequest{
+		Model:    model,
+		Messages: []ChatMessage{{Role: "user", Content: "hi", Timestamp: time.Now()}},
+	}
+
+	_, ok := cache.Get(request)
+	assert.False(t, ok, "empty cache should miss")
+
+	response := &ChatResponse{Content: "hello", FinishReason: "stop"}
+	require.NoError(t, cache.Put(request, response))
+
+	// Timestamps are not part of the key
+	request.Messages[0].Timestamp = time.Now().Add(time.Minute)
+	cached, ok := cache.Get(request)
+	require.True(t, ok)
+	assert.Equal(t, "hello", cached.Content)
+
+	// Different messages, tools or model config miss
+	other := request
+	other.Messages = []ChatMessage{{Role: "user", Content: "bye"}}
+	_, ok = cache.Get(other)
+	assert.False(t, ok)
+
+	other = request
+	other.Tools = []ChatTool{{Name: "exec"}}
+	_, ok = cache.Get(other)
+	assert.False(t, ok)
+
+	other = request
+	other.Model = &ModelValue{Name: "m", Config: map[string]Value{"model": &StringValue{Value: "other"}}, Provider: model.Provider}
+	_, ok = cache.Get(other)
+	assert.False(t, ok)
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	cache := NewResponseCache(t.TempDir(), time.Hour)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	request := ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}}
+	require.NoError(t, cache.Put(request, &ChatResponse{Content: "hello"}))
+
+	cache.now = func() time.Time { return now.Add(59 * time.Minute) }
+	_, ok := cache.Get(request)
+	assert.True(t, ok)
+
+	cache.now = func() time.Time { return now.Add(61 * time.Minute) }
+	_, ok = cache.Get(request)
+	assert.False(t, ok, "expired entries should miss")
+}
+
+func TestClearResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewResponseCache(dir, time.Hour)
+	require.NoError(t, cache.Put(ChatRequest{Messages: []ChatMessage{{Content: "a"}}}, &ChatResponse{}))
+	require.NoError(t, cache.Put(ChatRequest{Messages: []ChatMessage{{Content: "b"}}}, &ChatResponse{}))
+
+	removed, err := ClearResponseCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	removed, err = ClearResponseCache(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
+
+func TestAgentResponseCache(t *testing.T) {
+	script := `
+model testModel {
+	provider: "smart-mock",
+	model: "test"
+}
+
+agent TestAgent {
+	model: testModel,
+	systemPrompt: "test"
+}
+
+conv = "What is 2+2?" | TestAgent
+`
+	run := func(t *testing.T, enabled string, dir string) (*SmartMockProvider, *ConversationValue) {
+		t.Setenv("GSH_RESPONSE_CACHE", enabled)
+		interp := New(nil)
+		defer interp.Close()
+		interp.responseCacheDir = dir
+		provider := NewSmartMockProvider()
+		interp.providerRegistry.Register(provider)
+
+		result, err := interp.EvalString(script, nil)
+		require.NoError(t, err)
+		conv, ok := result.Value().(*ConversationValue)
+		require.True(t, ok)
+		return provider, conv
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		dir := t.TempDir()
+		run(t, "", dir)
+		provider, _ := run(t, "", dir)
+		assert.Len(t, provider.CallHistory, 1)
+	})
+
+	t.Run("second run is served from the cache", func(t *testing.T) {
+		dir := t.TempDir()
+		first, conv1 := run(t, "1", dir)
+		assert.Len(t, first.CallHistory, 1)
+
+		second, conv2 := run(t, "true", dir)
+		assert.Empty(t, second.CallHistory, "cache hit should not call the provider")
+		assert.Equal(t, conv1.Messages[1].Content, conv2.Messages[1].Content)
+	})
+}
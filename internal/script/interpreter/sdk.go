@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"regexp"
 	"sync"
 
 	"go.uber.org/zap"
@@ -127,6 +128,20 @@ func (em *EventManager) GetHandlers(eventName string) []*ToolValue {
 	return result
 }
 
+// HandlerIDs returns the ID of every registered handler, mapped to its event name
+func (em *EventManager) HandlerIDs() map[string]string {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	ids := make(map[string]string)
+	for eventName, entries := range em.handlers {
+		for _, entry := range entries {
+			ids[entry.id] = eventName
+		}
+	}
+	return ids
+}
+
 // HasHandlers returns true if there are any handlers for the given event
 func (em *EventManager) HasHandlers(eventName string) bool {
 	em.mu.RLock()
@@ -163,10 +178,29 @@ type SDKConfig struct {
 
 // REPLContext holds REPL-specific state that's available in the SDK
 type REPLContext struct {
-	LastCommand             *REPLLastCommand
-	PromptValue             Value        // Prompt string set by event handlers (read/write via gsh.prompt)
-	ContinuationPromptValue Value        // Continuation prompt set by event handlers (read/write via gsh.continuationPrompt)
-	Interpreter             *Interpreter // Reference to interpreter for event execution
+	LastCommand              *REPLLastCommand
+	PromptValue              Value            // Prompt string, or a tool that builds it (read/write via gsh.prompt)
+	ContinuationPromptValue  Value            // Continuation prompt set by event handlers (read/write via gsh.continuationPrompt)
+	AgentPromptValue         Value            // Prompt shown while typing a "#" agent message (read/write via gsh.agentPrompt)
+	PromptExitCodeColor      bool             // Whether the default prompt turns red after a failed command (read/write via gsh.promptExitCodeColor)
+	PromptGitAsync           bool             // Whether gsh.git.status() runs in the background while the prompt renders (read/write via gsh.promptGitAsync)
+	ShowWelcome              bool             // Whether the default repl.ready handler shows the welcome screen (read/write via gsh.showWelcome)
+	WelcomeMessage           Value            // Message (string or tool returning a string) shown on the welcome screen (read/write via gsh.welcomeMessage)
+	NotifyOnAgentComplete    bool             // Whether the default agent.end handler sends a desktop notification after long runs (read/write via gsh.notifyOnAgentComplete)
+	EditDiffColor            bool             // Whether the default agent.tool.end handler colors edit_file diffs (read/write via gsh.editDiffColor)
+	RenderMarkdown           bool             // Whether the default agent handlers render responses as Markdown (read/write via gsh.renderMarkdown)
+	ShowThinking             bool             // Whether the default agent.thinking handler prints reasoning in full (read/write via gsh.showThinking)
+	DefaultAgentPromptSuffix string           // Text appended to the default agent's built-in system prompt (read/write via gsh.defaultAgentPromptSuffix)
+	AgentResponseWidth       int              // Column at which the default agent.chunk handler wraps responses, 0 for the terminal width (read/write via gsh.agentResponseWidth)
+	AgentChunkInterval       int              // Minimum milliseconds between agent.chunk events while streaming, 0 to emit every chunk (read/write via gsh.agentChunkInterval)
+	HistoryMaxEntries        int              // Maximum number of history entries kept, oldest pruned first, 0 for no cap (read/write via gsh.historyMaxEntries)
+	HistoryIgnoreSpace       bool             // Whether commands typed with a leading space are left out of history (read/write via gsh.historyIgnoreSpace)
+	HistoryIgnoreDups        bool             // Whether a command identical to the previous history entry is left out of history (read/write via gsh.historyIgnoreDups)
+	HistoryIgnore            []*regexp.Regexp // Commands matching any pattern are left out of history (read/write via gsh.historyIgnore)
+	ConfirmCommands          []*regexp.Regexp // Commands matching any pattern ask for confirmation before running (read/write via gsh.confirmCommands)
+	CompleteHiddenFiles      bool             // Whether path completion offers hidden files before a "." is typed (read/write via gsh.completeHiddenFiles)
+	SetupMissingKeys         bool             // Whether interactive sessions ask for missing model API keys at startup (read/write via gsh.setupMissingKeys)
+	Interpreter              *Interpreter     // Reference to interpreter for event execution
 }
 
 // Models holds the model tier definitions (available in both REPL and script mode)
@@ -185,9 +219,10 @@ type REPLLastCommand struct {
 
 // HistoryEntry represents a single command history entry
 type HistoryEntry struct {
-	Command   string
-	Timestamp int64
-	ExitCode  int
+	Command    string
+	Timestamp  int64
+	ExitCode   int
+	DurationMs int64 // -1 if the command has not finished
 }
 
 // HistoryProvider provides access to command history for gsh scripts
@@ -202,13 +237,9 @@ type HistoryProvider interface {
 }
 
 // NewSDKConfig creates a new SDK configuration
-// The logger should have been created with an AtomicLevel for dynamic level changes to work
-func NewSDKConfig(logger *zap.Logger, atomicLevel zap.AtomicLevel) *SDKConfig {
-	// Extract log file from logger if available
-	logFile := ""
-	// Note: zap doesn't expose output paths directly, so logFile stays empty
-	// It would need to be passed separately if needed in the future
-
+// The logger should have been created with an AtomicLevel for dynamic level changes to work.
+// zap doesn't expose a logger's output paths, so logFile is passed in separately.
+func NewSDKConfig(logger *zap.Logger, atomicLevel zap.AtomicLevel, logFile string) *SDKConfig {
 	return &SDKConfig{
 		logger:           logger,
 		atomicLevel:      atomicLevel,
@@ -0,0 +1,209 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// sessionRecordingVersion is the version of the session file format
+const sessionRecordingVersion = 1
+
+// SessionRecording is the on-disk format of a recorded script run: every model response
+// and every native or MCP tool result the script's agents received, in order
+type SessionRecording struct {
+	Version    int                 `json:"version"`
+	Script     string              `json:"script"`
+	ModelCalls []RecordedModelCall `json:"modelCalls"`
+	ToolCalls  []RecordedToolCall  `json:"toolCalls"`
+}
+
+// RecordedModelCall is one model response, or the error the call failed with
+type RecordedModelCall struct {
+	Model    string        `json:"model"`
+	Response *ChatResponse `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// RecordedToolCall is one tool result, or the error the tool failed with
+type RecordedToolCall struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Result    string                 `json:"result"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// Session records the model responses and tool results of a run, or replays a recording
+// so the run repeats without calling models or running tools. User-defined tools still
+// run when replaying, since they are part of the script; the calls they make are replayed.
+type Session struct {
+	mu        sync.Mutex
+	recording *SessionRecording
+	replaying bool
+	nextModel int
+	nextTool  int
+}
+
+// NewRecordingSession creates a session that records a run of script
+func NewRecordingSession(script string) *Session {
+	return &Session{recording: &SessionRecording{
+		Version:    sessionRecordingVersion,
+		Script:     script,
+		ModelCalls: []RecordedModelCall{},
+		ToolCalls:  []RecordedToolCall{},
+	}}
+}
+
+// LoadReplaySession reads a session file written by a recording session and returns a
+// session that replays it
+func LoadReplaySession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	var recording SessionRecording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", path, err)
+	}
+	if recording.Version != sessionRecordingVersion {
+		return nil, fmt.Errorf("session file %s has unsupported version %d", path, recording.Version)
+	}
+	return &Session{recording: &recording, replaying: true}, nil
+}
+
+// Script returns the path of the script the session was recorded from
+func (s *Session) Script() string {
+	return s.recording.Script
+}
+
+// Save writes the recording to path
+func (s *Session) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.MarshalIndent(s.recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Unused reports an error when a replay ended before using every recorded call,
+// which means the run went differently from the recorded one
+func (s *Session) Unused() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.replaying {
+		return nil
+	}
+	if left := len(s.recording.ModelCalls) - s.nextModel; left > 0 {
+		return fmt.Errorf("replay diverged: %d recorded model call(s) were not used", left)
+	}
+	if left := len(s.recording.ToolCalls) - s.nextTool; left > 0 {
+		return fmt.Errorf("replay diverged: %d recorded tool call(s) were not used", left)
+	}
+	return nil
+}
+
+// callModel runs call, one model request of an agent, through the session. When recording
+// it stores the outcome; when replaying it returns the next recorded outcome instead,
+// passing the response to callbacks as a single streamed chunk. A nil session just calls.
+func (s *Session) callModel(model *ModelValue, callbacks *StreamCallbacks, call func() (*ChatResponse, error)) (*ChatResponse, error) {
+	if s == nil {
+		return call()
+	}
+
+	if !s.replaying {
+		response, err := call()
+		recorded := RecordedModelCall{Model: model.Name, Response: response}
+		if err != nil {
+			recorded = RecordedModelCall{Model: model.Name, Error: err.Error()}
+		}
+		s.mu.Lock()
+		s.recording.ModelCalls = append(s.recording.ModelCalls, recorded)
+		s.mu.Unlock()
+		return response, err
+	}
+
+	s.mu.Lock()
+	index := s.nextModel
+	if index >= len(s.recording.ModelCalls) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("replay diverged: model call %d has no recorded response (the session has %d)", index+1, len(s.recording.ModelCalls))
+	}
+	recorded := s.recording.ModelCalls[index]
+	s.nextModel++
+	s.mu.Unlock()
+
+	if recorded.Model != model.Name {
+		return nil, fmt.Errorf("replay diverged: model call %d was recorded for model %s, but the run called %s", index+1, recorded.Model, model.Name)
+	}
+	if recorded.Error != "" {
+		return nil, errors.New(recorded.Error)
+	}
+	if recorded.Response == nil {
+		return nil, fmt.Errorf("replay: model call %d has no recorded response", index+1)
+	}
+
+	response := *recorded.Response
+	if callbacks != nil {
+		if callbacks.OnReasoning != nil && response.Reasoning != "" {
+			callbacks.OnReasoning(response.Reasoning)
+		}
+		if callbacks.OnContent != nil && response.Content != "" {
+			callbacks.OnContent(response.Content)
+		}
+		if callbacks.OnToolPending != nil {
+			for _, toolCall := range response.ToolCalls {
+				callbacks.OnToolPending(toolCall.ID, toolCall.Name)
+			}
+		}
+		if callbacks.OnUsage != nil && response.Usage != nil {
+			usage := *response.Usage
+			callbacks.OnUsage(&usage)
+		}
+		if callbacks.OnStreamEnd != nil {
+			callbacks.OnStreamEnd()
+		}
+	}
+	return &response, nil
+}
+
+// callTool runs call, one native or MCP tool call, through the session, recording its
+// result or returning the recorded one when replaying. A nil session just calls.
+func (s *Session) callTool(toolCall ChatToolCall, call func() (string, error)) (string, error) {
+	if s == nil {
+		return call()
+	}
+
+	if !s.replaying {
+		result, err := call()
+		recorded := RecordedToolCall{Tool: toolCall.Name, Arguments: toolCall.Arguments, Result: result}
+		if err != nil {
+			recorded.Error = err.Error()
+		}
+		s.mu.Lock()
+		s.recording.ToolCalls = append(s.recording.ToolCalls, recorded)
+		s.mu.Unlock()
+		return result, err
+	}
+
+	s.mu.Lock()
+	index := s.nextTool
+	if index >= len(s.recording.ToolCalls) {
+		s.mu.Unlock()
+		return "", fmt.Errorf("replay diverged: tool call %d (%s) has no recorded result (the session has %d)", index+1, toolCall.Name, len(s.recording.ToolCalls))
+	}
+	recorded := s.recording.ToolCalls[index]
+	s.nextTool++
+	s.mu.Unlock()
+
+	if recorded.Tool != toolCall.Name {
+		return "", fmt.Errorf("replay diverged: tool call %d was recorded for %s, but the run called %s", index+1, recorded.Tool, toolCall.Name)
+	}
+	if recorded.Error != "" {
+		return recorded.Result, errors.New(recorded.Error)
+	}
+	return recorded.Result, nil
+}
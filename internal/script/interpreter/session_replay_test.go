@@ -0,0 +1,178 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sessionMockProvider asks for one deploy tool call, then answers with the tool's result
+type sessionMockProvider struct {
+	calls int
+}
+
+func (m *sessionMockProvider) Name() string { return "session-mock" }
+
+func (m *sessionMockProvider) ChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	m.calls++
+	last := request.Messages[len(request.Messages)-1]
+	if last.Role != "tool" {
+		return &ChatResponse{ToolCalls: []ChatToolCall{{ID: "call_1", Name: "deploy", Arguments: map[string]interface{}{"env": "prod"}}}}, nil
+	}
+	return &ChatResponse{Content: "saw " + last.Content, Usage: &ChatUsage{PromptTokens: 10, CompletionTokens: 2}}, nil
+}
+
+func (m *sessionMockProvider) StreamingChatCompletion(ctx context.Context, request ChatRequest, callbacks *StreamCallbacks) (*ChatResponse, error) {
+	return m.ChatCompletion(ctx, request)
+}
+
+func TestSessionRecordAndReplay(t *testing.T) {
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+	script := `
+model m { provider: "session-mock", model: "test" }
+agent A { model: m, tools: [deploy] }
+conv = "go" | A
+`
+
+	// run returns the number of model calls, the number of deploys and the final reply
+	run := func(t *testing.T, session *Session) (int, int, string) {
+		mock := &sessionMockProvider{}
+		deploys := 0
+		interp := New(&Options{Session: session})
+		defer interp.Close()
+		interp.providerRegistry.Register(mock)
+		interp.globalEnv.Set("deploy", &NativeToolValue{
+			Name:       "deploy",
+			Parameters: map[string]interface{}{"type": "object"},
+			Invoke: func(args map[string]interface{}) (interface{}, error) {
+				deploys++
+				return fmt.Sprintf("deployed to %v", args["env"]), nil
+			},
+		})
+
+		result, err := interp.EvalString(script, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		value, _ := result.Env.Get("conv")
+		conv := value.(*ConversationValue)
+		return mock.calls, deploys, conv.Messages[len(conv.Messages)-1].Content
+	}
+
+	recorder := NewRecordingSession("triage.gsh")
+	calls, deploys, recordedReply := run(t, recorder)
+	if calls != 2 || deploys != 1 {
+		t.Fatalf("expected 2 model calls and 1 deploy while recording, got %d and %d", calls, deploys)
+	}
+	if recordedReply != "saw deployed to prod" {
+		t.Fatalf("unexpected reply %q", recordedReply)
+	}
+	if err := recorder.Save(sessionPath); err != nil {
+		t.Fatalf("failed to save session: %v", err)
+	}
+
+	replay, err := LoadReplaySession(sessionPath)
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if replay.Script() != "triage.gsh" {
+		t.Errorf("expected script triage.gsh, got %q", replay.Script())
+	}
+	calls, deploys, replayedReply := run(t, replay)
+	if calls != 0 || deploys != 0 {
+		t.Errorf("expected no model calls or deploys while replaying, got %d and %d", calls, deploys)
+	}
+	if replayedReply != recordedReply {
+		t.Errorf("expected replayed reply %q, got %q", recordedReply, replayedReply)
+	}
+	if err := replay.Unused(); err != nil {
+		t.Errorf("expected every recorded call to be used, got %v", err)
+	}
+}
+
+func TestSessionReplayStreamsRecordedResponse(t *testing.T) {
+	replay := &Session{replaying: true, recording: &SessionRecording{ModelCalls: []RecordedModelCall{
+		{Model: "m", Response: &ChatResponse{Reasoning: "thinking it over", Content: "done"}},
+	}}}
+
+	var events []string
+	callbacks := &StreamCallbacks{
+		OnReasoning: func(content string) { events = append(events, "reasoning: "+content) },
+		OnContent:   func(content string) { events = append(events, "content: "+content) },
+		OnStreamEnd: func() { events = append(events, "end") },
+	}
+	_, err := replay.callModel(&ModelValue{Name: "m"}, callbacks, func() (*ChatResponse, error) {
+		t.Fatal("replay should not call the model")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(events, ", "); got != "reasoning: thinking it over, content: done, end" {
+		t.Errorf("unexpected callbacks %q", got)
+	}
+}
+
+func TestSessionReplayDiverged(t *testing.T) {
+	model := &ModelValue{Name: "m"}
+	newReplay := func(recording *SessionRecording) *Session {
+		return &Session{recording: recording, replaying: true}
+	}
+	neverCalled := func() (*ChatResponse, error) {
+		t.Fatal("the model must not be called while replaying")
+		return nil, nil
+	}
+
+	t.Run("runs out of model calls", func(t *testing.T) {
+		session := newReplay(&SessionRecording{})
+		_, err := session.callModel(model, nil, neverCalled)
+		if err == nil || !strings.Contains(err.Error(), "model call 1 has no recorded response") {
+			t.Errorf("expected divergence error, got %v", err)
+		}
+	})
+
+	t.Run("different model", func(t *testing.T) {
+		session := newReplay(&SessionRecording{ModelCalls: []RecordedModelCall{{Model: "other", Response: &ChatResponse{}}}})
+		_, err := session.callModel(model, nil, neverCalled)
+		if err == nil || !strings.Contains(err.Error(), "recorded for model other, but the run called m") {
+			t.Errorf("expected divergence error, got %v", err)
+		}
+	})
+
+	t.Run("recorded error", func(t *testing.T) {
+		session := newReplay(&SessionRecording{ModelCalls: []RecordedModelCall{{Model: "m", Error: "rate limited"}}})
+		_, err := session.callModel(model, nil, neverCalled)
+		if err == nil || err.Error() != "rate limited" {
+			t.Errorf("expected the recorded error, got %v", err)
+		}
+	})
+
+	t.Run("unused calls", func(t *testing.T) {
+		session := newReplay(&SessionRecording{ToolCalls: []RecordedToolCall{{Tool: "exec"}}})
+		if err := session.Unused(); err == nil || !strings.Contains(err.Error(), "1 recorded tool call(s) were not used") {
+			t.Errorf("expected unused error, got %v", err)
+		}
+	})
+
+	t.Run("streams the recorded response", func(t *testing.T) {
+		session := newReplay(&SessionRecording{ModelCalls: []RecordedModelCall{{Model: "m", Response: &ChatResponse{
+			Content:   "hello",
+			ToolCalls: []ChatToolCall{{ID: "call_1", Name: "exec"}},
+		}}}})
+		var content, pending string
+		ended := false
+		_, err := session.callModel(model, &StreamCallbacks{
+			OnContent:     func(chunk string) { content += chunk },
+			OnToolPending: func(id, name string) { pending = id + ":" + name },
+			OnStreamEnd:   func() { ended = true },
+		}, neverCalled)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if content != "hello" || pending != "call_1:exec" || !ended {
+			t.Errorf("unexpected callbacks: content=%q pending=%q ended=%v", content, pending, ended)
+		}
+	})
+}
@@ -3,6 +3,8 @@ package interpreter
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/kunchenguid/gsh/internal/script/lexer"
 	"github.com/kunchenguid/gsh/internal/script/parser"
@@ -47,12 +49,16 @@ func (i *Interpreter) evalStatement(env *Environment, stmt parser.Statement) (Va
 	switch node := stmt.(type) {
 	case *parser.AssignmentStatement:
 		return i.evalAssignmentStatement(env, node)
+	case *parser.DestructuringAssignment:
+		return i.evalDestructuringAssignment(env, node)
 	case *parser.ExpressionStatement:
 		return i.evalExpression(env, node.Expression)
 	case *parser.IfStatement:
 		return i.evalIfStatement(env, node)
 	case *parser.WhileStatement:
 		return i.evalWhileStatement(env, node)
+	case *parser.SwitchStatement:
+		return i.evalSwitchStatement(env, node)
 	case *parser.ForOfStatement:
 		return i.evalForOfStatement(env, node)
 	case *parser.BreakStatement:
@@ -63,6 +69,8 @@ func (i *Interpreter) evalStatement(env *Environment, stmt parser.Statement) (Va
 		return i.evalReturnStatement(env, node)
 	case *parser.ThrowStatement:
 		return i.evalThrowStatement(env, node)
+	case *parser.DeferStatement:
+		return i.evalDeferStatement(env, node)
 	case *parser.ToolDeclaration:
 		return i.evalToolDeclaration(env, node)
 	case *parser.McpDeclaration:
@@ -81,6 +89,8 @@ func (i *Interpreter) evalStatement(env *Environment, stmt parser.Statement) (Va
 		return i.evalImportStatement(env, node)
 	case *parser.ExportStatement:
 		return i.evalExportStatement(env, node)
+	case *parser.IncludeStatement:
+		return i.evalIncludeStatement(env, node)
 	default:
 		return nil, fmt.Errorf("unsupported statement type: %T", stmt)
 	}
@@ -107,17 +117,18 @@ func (i *Interpreter) evalAssignmentStatement(env *Environment, stmt *parser.Ass
 	// Handle different assignment targets
 	switch t := target.(type) {
 	case *parser.Identifier:
-		// Simple variable assignment
 		varName := t.Value
-		if env.Has(varName) {
-			// Variable exists in current or parent scope, update it
-			err := env.Update(varName, value)
-			if err != nil {
-				return nil, err
+		if stmt.Const {
+			// Constant declaration: always binds in the current scope
+			if err := env.SetConst(varName, value); err != nil {
+				return nil, NewRuntimeError("%s (line %d, column %d)", err.Error(), t.Token.Line, t.Token.Column)
 			}
-		} else {
-			// Variable doesn't exist, define it in current scope
-			env.Set(varName, value)
+			return value, nil
+		}
+
+		// Simple variable assignment
+		if err := assignVariable(env, t, value); err != nil {
+			return nil, err
 		}
 		return value, nil
 
@@ -134,6 +145,81 @@ func (i *Interpreter) evalAssignmentStatement(env *Environment, stmt *parser.Ass
 	}
 }
 
+// assignVariable assigns value to the variable name, updating it where it is defined, or
+// defining it in the current scope if it doesn't exist yet
+func assignVariable(env *Environment, name *parser.Identifier, value Value) error {
+	if env.IsConst(name.Value) {
+		return NewRuntimeError("cannot reassign constant '%s' (line %d, column %d)", name.Value, name.Token.Line, name.Token.Column)
+	}
+	if env.Has(name.Value) {
+		// Variable exists in current or parent scope, update it
+		return env.Update(name.Value, value)
+	}
+	// Variable doesn't exist, define it in current scope
+	env.Set(name.Value, value)
+	return nil
+}
+
+// evalDestructuringAssignment evaluates a, b = array and { x, y } = object. Array elements
+// and object properties that don't exist are assigned null.
+func (i *Interpreter) evalDestructuringAssignment(env *Environment, stmt *parser.DestructuringAssignment) (Value, error) {
+	value, err := i.evalExpression(env, stmt.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read every value before assigning any, so a, b = [b, a] and { a } = a work
+	values := make([]Value, len(stmt.Names))
+	if stmt.Keys == nil {
+		arr, ok := value.(*ArrayValue)
+		if !ok {
+			return nil, NewRuntimeError("cannot destructure %s into %s: expected an array (line %d, column %d)",
+				value.Type(), joinIdentifiers(stmt.Names), stmt.Token.Line, stmt.Token.Column)
+		}
+		for idx := range stmt.Names {
+			values[idx] = &NullValue{}
+			if idx < len(arr.Elements) {
+				values[idx] = arr.Elements[idx]
+			}
+		}
+	} else {
+		if _, ok := value.(*NullValue); ok {
+			return nil, NewRuntimeError("cannot destructure null (line %d, column %d)", stmt.Token.Line, stmt.Token.Column)
+		}
+		for idx, name := range stmt.Names {
+			member := &parser.MemberExpression{Token: name.Token, Property: &parser.Identifier{Token: name.Token, Value: stmt.Keys[idx]}}
+			if values[idx], err = i.getMember(value, member); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for idx, name := range stmt.Names {
+		if err := assignVariable(env, name, values[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// joinIdentifiers formats names as a comma-separated list, e.g. "a, b"
+func joinIdentifiers(names []*parser.Identifier) string {
+	parts := make([]string, len(names))
+	for idx, name := range names {
+		parts[idx] = name.Value
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkNotConstant returns an error if a declaration or loop variable named name would
+// replace a constant defined in the same scope. Shadowing a constant from an outer scope is allowed.
+func checkNotConstant(env *Environment, name *parser.Identifier) error {
+	if env.HasLocal(name.Value) && env.IsConst(name.Value) {
+		return NewRuntimeError("cannot reassign constant '%s' (line %d, column %d)", name.Value, name.Token.Line, name.Token.Column)
+	}
+	return nil
+}
+
 // evalIndexAssignment handles assignments to array/object indices
 func (i *Interpreter) evalIndexAssignment(env *Environment, indexExpr *parser.IndexExpression, value Value) (Value, error) {
 	// Evaluate the left side (the array or object)
@@ -181,6 +267,9 @@ func (i *Interpreter) evalMemberAssignment(env *Environment, memberExpr *parser.
 
 	// Get property name
 	propertyName := memberExpr.Property.Value
+	if propertyName == "*" {
+		return nil, fmt.Errorf("cannot assign to '.*'")
+	}
 
 	// Delegate to setProperty which handles all property setting logic
 	return i.setProperty(obj, propertyName, value)
@@ -234,7 +323,14 @@ func (i *Interpreter) evalIfStatement(env *Environment, node *parser.IfStatement
 func (i *Interpreter) evalWhileStatement(env *Environment, node *parser.WhileStatement) (Value, error) {
 	var result Value = &NullValue{}
 
+	// Stop between iterations once the context is cancelled, so a timed out or interrupted
+	// tool doesn't keep running in the background
+	ctx := i.Context()
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// Evaluate the condition
 		condition, err := i.evalExpression(env, node.Condition)
 		if err != nil {
@@ -270,6 +366,45 @@ func (i *Interpreter) evalWhileStatement(env *Environment, node *parser.WhileSta
 	return result, nil
 }
 
+// evalSwitchStatement evaluates a switch statement. Case values are compared to the
+// switch value with Equals, in order, and only the first matching case runs (no
+// fallthrough). break leaves the switch; continue and return propagate.
+func (i *Interpreter) evalSwitchStatement(env *Environment, node *parser.SwitchStatement) (Value, error) {
+	value, err := i.evalExpression(env, node.Value)
+	if err != nil {
+		return nil, err
+	}
+	value = UnwrapValue(value)
+
+	body := node.Default
+cases:
+	for _, switchCase := range node.Cases {
+		for _, expr := range switchCase.Values {
+			caseValue, err := i.evalExpression(env, expr)
+			if err != nil {
+				return nil, err
+			}
+			if value.Equals(UnwrapValue(caseValue)) {
+				body = switchCase.Body
+				break cases
+			}
+		}
+	}
+
+	if body == nil {
+		return &NullValue{}, nil
+	}
+
+	result, err := i.evalBlockStatement(env, body)
+	if err != nil {
+		if cfErr, ok := err.(*ControlFlowError); ok && cfErr.Signal == SignalBreak {
+			return &NullValue{}, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
 // evalForOfStatement evaluates a for-of statement
 func (i *Interpreter) evalForOfStatement(env *Environment, node *parser.ForOfStatement) (Value, error) {
 	// Evaluate the iterable expression
@@ -278,11 +413,16 @@ func (i *Interpreter) evalForOfStatement(env *Environment, node *parser.ForOfSta
 		return nil, err
 	}
 
+	if node.ValueVariable != nil {
+		return i.evalForOfEntries(env, node, iterable)
+	}
+
 	// Get the elements to iterate over
 	var elements []Value
 	switch iter := iterable.(type) {
 	case *ArrayValue:
-		elements = iter.Elements
+		// Iterate over a copy so the body can push to or remove from the array safely
+		elements = append([]Value(nil), iter.Elements...)
 	case *StringValue:
 		// Iterate over characters in the string
 		runes := []rune(iter.Value)
@@ -290,15 +430,27 @@ func (i *Interpreter) evalForOfStatement(env *Environment, node *parser.ForOfSta
 		for i, r := range runes {
 			elements[i] = &StringValue{Value: string(r)}
 		}
+	case *ObjectValue, *MapValue:
+		return nil, NewRuntimeError("for-of requires an iterable (array or string), got %s; use for (key, value of ...) to iterate its entries (line %d, column %d)",
+			iterable.Type(), node.Token.Line, node.Token.Column)
 	default:
 		return nil, NewRuntimeError("for-of requires an iterable (array or string), got %s (line %d, column %d)",
 			iterable.Type(), node.Token.Line, node.Token.Column)
 	}
 
+	if err := checkNotConstant(env, node.Variable); err != nil {
+		return nil, err
+	}
+
 	var result Value = &NullValue{}
 
-	// Iterate over elements
+	// Iterate over elements, stopping once the context is cancelled
+	ctx := i.Context()
 	for _, elem := range elements {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// Set the loop variable
 		env.Set(node.Variable.Value, elem)
 
@@ -326,6 +478,90 @@ func (i *Interpreter) evalForOfStatement(env *Environment, node *parser.ForOfSta
 	return result, nil
 }
 
+// evalForOfEntries evaluates the key-value form of for-of, for (key, value of iterable).
+// Objects and Maps are iterated in sorted key order and arrays as (index, element) pairs.
+// The keys are collected before the loop starts: entries added by the body are not visited,
+// and entries deleted by the body are skipped.
+func (i *Interpreter) evalForOfEntries(env *Environment, node *parser.ForOfStatement, iterable Value) (Value, error) {
+	var keys []Value
+	var lookup func(key Value) (Value, bool)
+
+	switch iter := iterable.(type) {
+	case *ObjectValue:
+		for _, key := range sortedKeys(iter.Properties) {
+			keys = append(keys, &StringValue{Value: key})
+		}
+		lookup = func(key Value) (Value, bool) {
+			name := key.(*StringValue).Value
+			if _, ok := iter.Properties[name]; !ok {
+				return nil, false
+			}
+			return iter.GetPropertyValue(name), true
+		}
+	case *MapValue:
+		names := make([]string, 0, len(iter.Entries))
+		for key := range iter.Entries {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		for _, key := range names {
+			keys = append(keys, &StringValue{Value: key})
+		}
+		lookup = func(key Value) (Value, bool) {
+			value, ok := iter.Entries[key.(*StringValue).Value]
+			return value, ok
+		}
+	case *ArrayValue:
+		for idx := range iter.Elements {
+			keys = append(keys, &NumberValue{Value: float64(idx)})
+		}
+		elements := append([]Value(nil), iter.Elements...)
+		lookup = func(key Value) (Value, bool) {
+			return elements[int(key.(*NumberValue).Value)], true
+		}
+	default:
+		return nil, NewRuntimeError("for-of with a key and value requires an iterable object, Map or array, got %s (line %d, column %d)",
+			iterable.Type(), node.Token.Line, node.Token.Column)
+	}
+
+	if err := checkNotConstant(env, node.Variable); err != nil {
+		return nil, err
+	}
+	if err := checkNotConstant(env, node.ValueVariable); err != nil {
+		return nil, err
+	}
+
+	var result Value = &NullValue{}
+	ctx := i.Context()
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		value, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		env.Set(node.Variable.Value, key)
+		env.Set(node.ValueVariable.Value, value)
+
+		var err error
+		result, err = i.evalBlockStatement(env, node.Body)
+		if err != nil {
+			if cfErr, ok := err.(*ControlFlowError); ok {
+				switch cfErr.Signal {
+				case SignalBreak:
+					return &NullValue{}, nil
+				case SignalContinue:
+					continue
+				}
+			}
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 // evalBlockStatement evaluates a block statement
 func (i *Interpreter) evalBlockStatement(env *Environment, node *parser.BlockStatement) (Value, error) {
 	// Create a new enclosed environment for the block scope
@@ -378,24 +614,34 @@ func (i *Interpreter) evalThrowStatement(env *Environment, node *parser.ThrowSta
 
 // evalToolDeclaration evaluates a tool declaration
 func (i *Interpreter) evalToolDeclaration(env *Environment, node *parser.ToolDeclaration) (Value, error) {
+	if err := checkNotConstant(env, node.Name); err != nil {
+		return nil, err
+	}
+
 	// Extract parameter names and types
 	params := make([]string, len(node.Parameters))
 	paramTypes := make(map[string]string)
+	paramDescs := make(map[string]string)
 
 	for idx, param := range node.Parameters {
 		params[idx] = param.Name.Value
 		if param.Type != nil {
 			paramTypes[param.Name.Value] = param.Type.Value
 		}
+		if param.Description != "" {
+			paramDescs[param.Name.Value] = param.Description
+		}
 	}
 
 	// Create the tool value
 	tool := &ToolValue{
-		Name:       node.Name.Value,
-		Parameters: params,
-		ParamTypes: paramTypes,
-		Body:       node.Body,
-		Env:        env, // Capture current environment for closure
+		Name:        node.Name.Value,
+		Parameters:  params,
+		ParamTypes:  paramTypes,
+		Description: node.Description,
+		ParamDescs:  paramDescs,
+		Body:        node.Body,
+		Env:         env, // Capture current environment for closure
 	}
 
 	if node.ReturnType != nil {
@@ -3,6 +3,8 @@ package interpreter
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // String method implementations
@@ -53,22 +55,23 @@ func stringSplitImpl(str *StringValue, args []Value) (Value, error) {
 	return &ArrayValue{Elements: elements}, nil
 }
 
-// stringTrimImpl implements the trim method
+// stringTrimImpl implements the trim method (strips Unicode whitespace)
 func stringTrimImpl(str *StringValue, args []Value) (Value, error) {
 	return &StringValue{Value: strings.TrimSpace(str.Value)}, nil
 }
 
-// stringTrimStartImpl implements the trimStart method
+// stringTrimStartImpl implements the trimStart method (strips Unicode whitespace)
 func stringTrimStartImpl(str *StringValue, args []Value) (Value, error) {
-	return &StringValue{Value: strings.TrimLeft(str.Value, " \t\n\r\v\f")}, nil
+	return &StringValue{Value: strings.TrimLeftFunc(str.Value, unicode.IsSpace)}, nil
 }
 
-// stringTrimEndImpl implements the trimEnd method
+// stringTrimEndImpl implements the trimEnd method (strips Unicode whitespace)
 func stringTrimEndImpl(str *StringValue, args []Value) (Value, error) {
-	return &StringValue{Value: strings.TrimRight(str.Value, " \t\n\r\v\f")}, nil
+	return &StringValue{Value: strings.TrimRightFunc(str.Value, unicode.IsSpace)}, nil
 }
 
-// stringIndexOfImpl implements the indexOf method
+// stringIndexOfImpl implements the indexOf method. Indices count characters
+// (runes), like length, substring and slice.
 func stringIndexOfImpl(str *StringValue, args []Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("indexOf() requires a search string argument")
@@ -78,33 +81,26 @@ func stringIndexOfImpl(str *StringValue, args []Value) (Value, error) {
 	}
 
 	searchStr := args[0].(*StringValue).Value
-	startIndex := 0
+	runes := []rune(str.Value)
 
 	// Optional start index parameter
-	if len(args) > 1 {
-		if args[1].Type() != ValueTypeNumber {
-			return nil, fmt.Errorf("indexOf() start index must be a number")
-		}
-		startIndex = int(args[1].(*NumberValue).Value)
-		if startIndex < 0 {
-			startIndex = 0
-		}
+	startIndex, err := optionalPositionArg("indexOf", "start index", args, 0, len(runes))
+	if err != nil {
+		return nil, err
 	}
 
 	// Search from startIndex
-	if startIndex >= len(str.Value) {
-		return &NumberValue{Value: -1}, nil
-	}
-
-	index := strings.Index(str.Value[startIndex:], searchStr)
+	rest := string(runes[startIndex:])
+	index := strings.Index(rest, searchStr)
 	if index == -1 {
 		return &NumberValue{Value: -1}, nil
 	}
 
-	return &NumberValue{Value: float64(startIndex + index)}, nil
+	return &NumberValue{Value: float64(startIndex + utf8.RuneCountInString(rest[:index]))}, nil
 }
 
-// stringLastIndexOfImpl implements the lastIndexOf method
+// stringLastIndexOfImpl implements the lastIndexOf method. An optional second
+// argument is the last index at which a match may start.
 func stringLastIndexOfImpl(str *StringValue, args []Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("lastIndexOf() requires a search string argument")
@@ -114,8 +110,45 @@ func stringLastIndexOfImpl(str *StringValue, args []Value) (Value, error) {
 	}
 
 	searchStr := args[0].(*StringValue).Value
-	index := strings.LastIndex(str.Value, searchStr)
-	return &NumberValue{Value: float64(index)}, nil
+	runes := []rune(str.Value)
+	searchLen := utf8.RuneCountInString(searchStr)
+
+	fromIndex, err := optionalPositionArg("lastIndexOf", "start index", args, len(runes), len(runes))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only consider matches starting at or before fromIndex
+	limit := fromIndex + searchLen
+	if limit > len(runes) {
+		limit = len(runes)
+	}
+	head := string(runes[:limit])
+	index := strings.LastIndex(head, searchStr)
+	if index == -1 {
+		return &NumberValue{Value: -1}, nil
+	}
+
+	return &NumberValue{Value: float64(utf8.RuneCountInString(head[:index]))}, nil
+}
+
+// optionalPositionArg reads the optional character position at args[1], clamped to
+// [0, length]. It returns def if the argument is absent.
+func optionalPositionArg(method, what string, args []Value, def, length int) (int, error) {
+	if len(args) < 2 {
+		return def, nil
+	}
+	if args[1].Type() != ValueTypeNumber {
+		return 0, fmt.Errorf("%s() %s must be a number", method, what)
+	}
+	pos := int(args[1].(*NumberValue).Value)
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > length {
+		pos = length
+	}
+	return pos, nil
 }
 
 // stringSubstringImpl implements the substring method
@@ -212,7 +245,8 @@ func stringSliceImpl(str *StringValue, args []Value) (Value, error) {
 	return &StringValue{Value: string(runes[start:end])}, nil
 }
 
-// stringStartsWithImpl implements the startsWith method
+// stringStartsWithImpl implements the startsWith method. An optional second
+// argument is the character position to check from.
 func stringStartsWithImpl(str *StringValue, args []Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("startsWith() requires a search string argument")
@@ -222,10 +256,16 @@ func stringStartsWithImpl(str *StringValue, args []Value) (Value, error) {
 	}
 
 	searchStr := args[0].(*StringValue).Value
-	return &BoolValue{Value: strings.HasPrefix(str.Value, searchStr)}, nil
+	runes := []rune(str.Value)
+	position, err := optionalPositionArg("startsWith", "position", args, 0, len(runes))
+	if err != nil {
+		return nil, err
+	}
+	return &BoolValue{Value: strings.HasPrefix(string(runes[position:]), searchStr)}, nil
 }
 
-// stringEndsWithImpl implements the endsWith method
+// stringEndsWithImpl implements the endsWith method. An optional second argument
+// treats the string as if it were only that many characters long.
 func stringEndsWithImpl(str *StringValue, args []Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("endsWith() requires a search string argument")
@@ -235,10 +275,16 @@ func stringEndsWithImpl(str *StringValue, args []Value) (Value, error) {
 	}
 
 	searchStr := args[0].(*StringValue).Value
-	return &BoolValue{Value: strings.HasSuffix(str.Value, searchStr)}, nil
+	runes := []rune(str.Value)
+	endPosition, err := optionalPositionArg("endsWith", "end position", args, len(runes), len(runes))
+	if err != nil {
+		return nil, err
+	}
+	return &BoolValue{Value: strings.HasSuffix(string(runes[:endPosition]), searchStr)}, nil
 }
 
-// stringIncludesImpl implements the includes method
+// stringIncludesImpl implements the includes method. An optional second argument
+// is the character position to start searching from.
 func stringIncludesImpl(str *StringValue, args []Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("includes() requires a search string argument")
@@ -248,7 +294,12 @@ func stringIncludesImpl(str *StringValue, args []Value) (Value, error) {
 	}
 
 	searchStr := args[0].(*StringValue).Value
-	return &BoolValue{Value: strings.Contains(str.Value, searchStr)}, nil
+	runes := []rune(str.Value)
+	position, err := optionalPositionArg("includes", "position", args, 0, len(runes))
+	if err != nil {
+		return nil, err
+	}
+	return &BoolValue{Value: strings.Contains(string(runes[position:]), searchStr)}, nil
 }
 
 // stringReplaceImpl implements the replace method (replaces first occurrence)
@@ -337,21 +388,8 @@ func stringPadStartImpl(str *StringValue, args []Value) (Value, error) {
 		return str, nil
 	}
 
-	padLength := targetLength - currentLength
-	padRunes := []rune(padString)
-
-	// Build the padding
-	var padding strings.Builder
-	for padding.Len() < padLength {
-		for _, r := range padRunes {
-			if padding.Len() >= padLength {
-				break
-			}
-			padding.WriteRune(r)
-		}
-	}
-
-	return &StringValue{Value: padding.String() + str.Value}, nil
+	padding := buildPadding([]rune(padString), targetLength-currentLength)
+	return &StringValue{Value: padding + str.Value}, nil
 }
 
 // stringPadEndImpl implements the padEnd method
@@ -383,21 +421,17 @@ func stringPadEndImpl(str *StringValue, args []Value) (Value, error) {
 		return str, nil
 	}
 
-	padLength := targetLength - currentLength
-	padRunes := []rune(padString)
+	padding := buildPadding([]rune(padString), targetLength-currentLength)
+	return &StringValue{Value: str.Value + padding}, nil
+}
 
-	// Build the padding
-	var padding strings.Builder
-	for padding.Len() < padLength {
-		for _, r := range padRunes {
-			if padding.Len() >= padLength {
-				break
-			}
-			padding.WriteRune(r)
-		}
+// buildPadding repeats padRunes until exactly padLength characters (runes) are produced
+func buildPadding(padRunes []rune, padLength int) string {
+	padding := make([]rune, padLength)
+	for i := range padding {
+		padding[i] = padRunes[i%len(padRunes)]
 	}
-
-	return &StringValue{Value: str.Value + padding.String()}, nil
+	return string(padding)
 }
 
 // stringCharAtImpl implements the charAt method
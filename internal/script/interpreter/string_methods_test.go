@@ -84,6 +84,11 @@ func TestStringLastIndexOf(t *testing.T) {
 			input:    "str = \"hello world\"\nresult = str.lastIndexOf(\"world\")",
 			expected: "6",
 		},
+		{
+			name:     "lastIndexOf - with start index",
+			input:    "str = \"hello hello\"\nresult = str.lastIndexOf(\"hello\", 5)",
+			expected: "0",
+		},
 		{
 			name:     "lastIndexOf - not found",
 			input:    "str = \"hello world\"\nresult = str.lastIndexOf(\"foo\")",
@@ -252,6 +257,11 @@ func TestStringStartsWith(t *testing.T) {
 			input:    "str = \"hello world\"\nresult = str.startsWith(\"world\")",
 			expected: "false",
 		},
+		{
+			name:     "startsWith - with position",
+			input:    "str = \"hello world\"\nresult = str.startsWith(\"world\", 6)",
+			expected: "true",
+		},
 		{
 			name:     "startsWith - empty string",
 			input:    "str = \"hello\"\nresult = str.startsWith(\"\")",
@@ -303,6 +313,11 @@ func TestStringEndsWith(t *testing.T) {
 			input:    "str = \"hello world\"\nresult = str.endsWith(\"hello\")",
 			expected: "false",
 		},
+		{
+			name:     "endsWith - with end position",
+			input:    "str = \"hello world\"\nresult = str.endsWith(\"hello\", 5)",
+			expected: "true",
+		},
 		{
 			name:     "endsWith - empty string",
 			input:    "str = \"hello\"\nresult = str.endsWith(\"\")",
@@ -354,6 +369,11 @@ func TestStringIncludes(t *testing.T) {
 			input:    "str = \"hello world\"\nresult = str.includes(\"foo\")",
 			expected: "false",
 		},
+		{
+			name:     "includes - with position",
+			input:    "str = \"hello world\"\nresult = str.includes(\"hello\", 1)",
+			expected: "false",
+		},
 		{
 			name:     "includes - at beginning",
 			input:    "str = \"hello world\"\nresult = str.includes(\"hello\")",
@@ -787,6 +807,66 @@ func TestStringUnicodeSupport(t *testing.T) {
 			input:    "str = \"👋 hello\"\nresult = str.substring(2, 7)",
 			expected: "hello",
 		},
+		{
+			name:     "unicode - trim strips unicode whitespace",
+			input:    "str = \"\u00a0\u3000 hello\u2003\"\nresult = str.trim()",
+			expected: "hello",
+		},
+		{
+			name:     "unicode - trimStart strips unicode whitespace",
+			input:    "str = \"\u00a0\u3000hello \"\nresult = str.trimStart()",
+			expected: "hello ",
+		},
+		{
+			name:     "unicode - trimEnd strips unicode whitespace",
+			input:    "str = \" hello\u00a0\u2003\"\nresult = str.trimEnd()",
+			expected: " hello",
+		},
+		{
+			name:     "unicode - toUpperCase",
+			input:    "str = \"café ß\"\nresult = str.toUpperCase()",
+			expected: "CAFÉ ß",
+		},
+		{
+			name:     "unicode - toLowerCase",
+			input:    "str = \"ÉCOLE ΣΑΣ\"\nresult = str.toLowerCase()",
+			expected: "école σασ",
+		},
+		{
+			name:     "unicode - indexOf counts characters",
+			input:    "str = \"héllo wörld\"\nresult = str.indexOf(\"wö\")",
+			expected: "6",
+		},
+		{
+			name:     "unicode - indexOf with start index after emoji",
+			input:    "str = \"👋 a 👋 a\"\nresult = str.indexOf(\"a\", 3)",
+			expected: "6",
+		},
+		{
+			name:     "unicode - lastIndexOf counts characters",
+			input:    "str = \"👋 a 👋 a\"\nresult = str.lastIndexOf(\"👋\")",
+			expected: "4",
+		},
+		{
+			name:     "unicode - indexOf result works with substring",
+			input:    "str = \"café: open\"\nresult = str.substring(str.indexOf(\":\") + 2)",
+			expected: "open",
+		},
+		{
+			name:     "unicode - startsWith with position",
+			input:    "str = \"👋 hello\"\nresult = str.startsWith(\"hello\", 2)",
+			expected: "true",
+		},
+		{
+			name:     "unicode - padStart with multi-byte pad string",
+			input:    "str = \"x\"\nresult = str.padStart(4, \"·\")",
+			expected: "···x",
+		},
+		{
+			name:     "unicode - padEnd with emoji",
+			input:    "str = \"👋\"\nresult = str.padEnd(3, \"-\")",
+			expected: "👋--",
+		},
 	}
 
 	for _, tt := range tests {
@@ -23,36 +23,84 @@ func classifyToolKind(toolName string) acp.ToolKind {
 	}
 }
 
-// executeToolCall executes a tool call from the agent
-func (i *Interpreter) executeToolCall(agent *AgentValue, toolCall ChatToolCall) (string, error) {
-	// Find the tool in the agent's tool list
-	toolsVal, ok := agent.Config["tools"]
-	if !ok {
-		return "", fmt.Errorf("agent has no tools configured")
-	}
-
-	toolsArr, ok := toolsVal.(*ArrayValue)
-	if !ok {
-		return "", fmt.Errorf("agent tools config is not an array")
+// mcpToolNameSeparator joins the server and tool names of an MCP tool that is offered to
+// the model under a qualified name. Providers only allow letters, digits, "_" and "-" in
+// tool names, so the "server.tool" spelling used in scripts can't be sent as is.
+const mcpToolNameSeparator = "__"
+
+// agentToolNames returns the name the model sees for each tool in tools. MCP tools keep
+// their own name unless another tool in tools has the same name, in which case they are
+// qualified with their server, as in fs__read_file, so that two servers exposing
+// read_file don't conflict.
+func agentToolNames(tools *ArrayValue) map[Value]string {
+	counts := make(map[string]int)
+	for _, elem := range tools.Elements {
+		switch tool := elem.(type) {
+		case *ToolValue:
+			counts[tool.Name]++
+		case *MCPToolValue:
+			counts[tool.ToolName]++
+		case *NativeToolValue:
+			counts[tool.Name]++
+		}
 	}
 
-	// Find the matching tool
-	for _, toolValInterface := range toolsArr.Elements {
-		switch toolVal := toolValInterface.(type) {
+	names := make(map[Value]string, len(tools.Elements))
+	for _, elem := range tools.Elements {
+		switch tool := elem.(type) {
 		case *ToolValue:
-			if toolVal.Name == toolCall.Name {
-				return i.executeUserToolCall(toolVal, toolCall.Arguments)
-			}
+			names[tool] = tool.Name
 		case *MCPToolValue:
-			if toolVal.ToolName == toolCall.Name {
-				return i.executeMCPToolCall(toolVal, toolCall.Arguments)
+			if counts[tool.ToolName] > 1 {
+				names[tool] = tool.ServerName + mcpToolNameSeparator + tool.ToolName
+			} else {
+				names[tool] = tool.ToolName
 			}
 		case *NativeToolValue:
-			if toolVal.Name == toolCall.Name {
-				return i.executeNativeToolCall(toolVal, toolCall.Arguments)
-			}
+			names[tool] = tool.Name
 		}
 	}
+	return names
+}
+
+// findAgentTool returns the tool in tools the model calls name, or nil if there is none
+func findAgentTool(tools *ArrayValue, name string) Value {
+	names := agentToolNames(tools)
+	for _, elem := range tools.Elements {
+		if toolName, ok := names[elem]; ok && toolName == name {
+			return elem
+		}
+	}
+	return nil
+}
+
+// toolRequiresApproval reports whether the tool the model calls name is listed in
+// requireApproval. MCP tools match by their own name or as "server.tool".
+func toolRequiresApproval(requireApproval map[string]bool, tools *ArrayValue, name string) bool {
+	if requireApproval[name] {
+		return true
+	}
+	if tool, ok := findAgentTool(tools, name).(*MCPToolValue); ok {
+		return requireApproval[tool.ToolName] || requireApproval[tool.ServerName+"."+tool.ToolName]
+	}
+	return false
+}
+
+// executeToolCall executes a tool call from the agent, looking the tool up in the agent's
+// tools for this run
+func (i *Interpreter) executeToolCall(tools *ArrayValue, toolCall ChatToolCall) (string, error) {
+	switch toolVal := findAgentTool(tools, toolCall.Name).(type) {
+	case *ToolValue:
+		return i.executeUserToolCall(toolVal, toolCall.Arguments)
+	case *MCPToolValue:
+		return i.session.callTool(toolCall, func() (string, error) {
+			return i.executeMCPToolCall(toolVal, toolCall.Arguments)
+		})
+	case *NativeToolValue:
+		return i.session.callTool(toolCall, func() (string, error) {
+			return i.executeNativeToolCall(toolVal, toolCall.Arguments)
+		})
+	}
 
 	return "", fmt.Errorf("tool '%s' not found in agent configuration", toolCall.Name)
 }
@@ -131,16 +179,24 @@ func (i *Interpreter) convertUserToolToChatTool(tool *ToolValue) ChatTool {
 		if typeName, ok := tool.ParamTypes[paramName]; ok {
 			paramType = mapGSHTypeToJSONType(typeName)
 		}
-		properties[paramName] = map[string]interface{}{
+		property := map[string]interface{}{
 			"type": paramType,
 		}
+		if desc, ok := tool.ParamDescs[paramName]; ok {
+			property["description"] = desc
+		}
+		properties[paramName] = property
 		required = append(required, paramName)
 	}
 	params["required"] = required
 
+	description := tool.Description
+	if description == "" {
+		description = fmt.Sprintf("User-defined tool: %s", tool.Name)
+	}
 	return ChatTool{
 		Name:        tool.Name,
-		Description: fmt.Sprintf("User-defined tool: %s", tool.Name),
+		Description: description,
 		Parameters:  params,
 	}
 }
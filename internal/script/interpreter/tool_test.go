@@ -526,3 +526,48 @@ func TestToolStringArrayType(t *testing.T) {
 		t.Errorf("expected '3', got %s", value.String())
 	}
 }
+
+func TestConvertUserToolToChatTool(t *testing.T) {
+	interp := New(nil)
+	defer interp.Close()
+
+	result, err := interp.EvalString(`
+# Looks up a user by email.
+# Returns null when there is no such user.
+tool findUser(
+	# the address to look up, e.g. ada@example.com
+	email: string,
+	includeDeleted: boolean,
+) {
+	return null
+}
+
+tool undocumented() {
+	return null
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	documented, _ := result.Env.Get("findUser")
+	chatTool := interp.convertUserToolToChatTool(documented.(*ToolValue))
+	expected := "Looks up a user by email.\nReturns null when there is no such user."
+	if chatTool.Description != expected {
+		t.Errorf("ChatTool.Description = %q, want %q", chatTool.Description, expected)
+	}
+	properties := chatTool.Parameters["properties"].(map[string]interface{})
+	email := properties["email"].(map[string]interface{})
+	if email["description"] != "the address to look up, e.g. ada@example.com" {
+		t.Errorf("email description = %v", email["description"])
+	}
+	if _, ok := properties["includeDeleted"].(map[string]interface{})["description"]; ok {
+		t.Error("includeDeleted should have no description")
+	}
+
+	undocumented, _ := result.Env.Get("undocumented")
+	chatTool = interp.convertUserToolToChatTool(undocumented.(*ToolValue))
+	if chatTool.Description != "User-defined tool: undocumented" {
+		t.Errorf("ChatTool.Description = %q, want the default description", chatTool.Description)
+	}
+}
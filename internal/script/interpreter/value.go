@@ -41,6 +41,10 @@ const (
 	ValueTypeACP
 	// ValueTypeACPSession represents an active ACP session
 	ValueTypeACPSession
+	// ValueTypeFile represents a reference to a file on disk
+	ValueTypeFile
+	// ValueTypeImage represents an image that can be attached to a message
+	ValueTypeImage
 )
 
 // String returns the string representation of the value type
@@ -76,6 +80,10 @@ func (vt ValueType) String() string {
 		return "acp"
 	case ValueTypeACPSession:
 		return "acpsession"
+	case ValueTypeFile:
+		return "file"
+	case ValueTypeImage:
+		return "image"
 	default:
 		return "unknown"
 	}
@@ -398,6 +406,8 @@ func deepCopyValue(v Value) Value {
 		return val.DeepCopy()
 	case *ArrayValue:
 		return val.DeepCopy()
+	case *ConversationValue:
+		return val.DeepCopy()
 	default:
 		// Primitive values (StringValue, NumberValue, BoolValue, NullValue, etc.)
 		// are immutable, so returning the same reference is safe
@@ -442,12 +452,14 @@ func NewError(format string, args ...interface{}) *ErrorValue {
 
 // ToolValue represents a tool/function value
 type ToolValue struct {
-	Name       string
-	Parameters []string
-	ParamTypes map[string]string // parameter name -> type annotation (optional)
-	ReturnType string            // return type annotation (optional)
-	Body       interface{}       // *parser.BlockStatement for user-defined tools
-	Env        *Environment      // closure environment
+	Name        string
+	Parameters  []string
+	ParamTypes  map[string]string // parameter name -> type annotation (optional)
+	ReturnType  string            // return type annotation (optional)
+	Description string            // doc comment above the declaration (optional)
+	ParamDescs  map[string]string // parameter name -> doc comment above it (optional)
+	Body        interface{}       // *parser.BlockStatement for user-defined tools
+	Env         *Environment      // closure environment
 }
 
 func (t *ToolValue) Type() ValueType { return ValueTypeTool }
@@ -467,6 +479,24 @@ type ModelValue struct {
 	Name     string
 	Config   map[string]Value
 	Provider ModelProvider
+
+	// MissingAPIKey is where the model's apiKey is read from, env.NAME or keyring.NAME,
+	// when that was unset at declaration, and "" otherwise
+	MissingAPIKey string
+
+	// rateLimiter throttles calls when the model declares a rateLimit, nil otherwise
+	rateLimiter *modelRateLimiter
+
+	// onRequest and onResponse run around every call, so model.request and model.response
+	// fire whoever makes it. They are nil for models not declared by an interpreter.
+	onRequest  func(ctx context.Context, request *ChatRequest)
+	onResponse func(ctx context.Context, response *ChatResponse, err error)
+}
+
+// SetAPIKey replaces the model's apiKey, e.g. once the user supplies a missing one
+func (m *ModelValue) SetAPIKey(key string) {
+	m.Config["apiKey"] = &StringValue{Value: key}
+	m.MissingAPIKey = ""
 }
 
 func (m *ModelValue) Type() ValueType { return ValueTypeModel }
@@ -513,9 +543,19 @@ func (m *ModelValue) ChatCompletion(ctx context.Context, request ChatRequest) (*
 	if m.Provider == nil {
 		return nil, fmt.Errorf("model '%s' has no provider configured", m.Name)
 	}
+	if err := m.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	// Ensure the request uses this model
 	request.Model = m
-	return m.Provider.ChatCompletion(ctx, request)
+	if m.onRequest != nil {
+		m.onRequest(ctx, &request)
+	}
+	response, err := m.Provider.ChatCompletion(ctx, request)
+	if m.onResponse != nil {
+		m.onResponse(ctx, response, err)
+	}
+	return response, err
 }
 
 // StreamingChatCompletion performs a streaming chat completion using this model's provider.
@@ -525,9 +565,27 @@ func (m *ModelValue) StreamingChatCompletion(ctx context.Context, request ChatRe
 	if m.Provider == nil {
 		return nil, fmt.Errorf("model '%s' has no provider configured", m.Name)
 	}
+	if err := m.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	// Ensure the request uses this model
 	request.Model = m
-	return m.Provider.StreamingChatCompletion(ctx, request, callbacks)
+	if m.onRequest != nil {
+		m.onRequest(ctx, &request)
+	}
+	response, err := m.Provider.StreamingChatCompletion(ctx, request, callbacks)
+	if m.onResponse != nil {
+		m.onResponse(ctx, response, err)
+	}
+	return response, err
+}
+
+// waitForRateLimit blocks until the model's rateLimit allows another call
+func (m *ModelValue) waitForRateLimit(ctx context.Context) error {
+	if m.rateLimiter == nil {
+		return nil
+	}
+	return m.rateLimiter.wait(ctx)
 }
 
 // AgentValue represents an agent configuration
@@ -582,6 +640,67 @@ func (c *ConversationValue) Equals(other Value) bool {
 	return false
 }
 
+// DeepCopy creates a completely independent deep copy of the ConversationValue,
+// including each message's tool calls and content parts.
+func (c *ConversationValue) DeepCopy() *ConversationValue {
+	if c == nil {
+		return nil
+	}
+	copied := &ConversationValue{
+		Messages: make([]ChatMessage, len(c.Messages)),
+	}
+	for idx, msg := range c.Messages {
+		if msg.ToolCalls != nil {
+			toolCalls := make([]ChatToolCall, len(msg.ToolCalls))
+			for j, tc := range msg.ToolCalls {
+				toolCalls[j] = tc
+				toolCalls[j].Arguments = deepCopyArguments(tc.Arguments)
+			}
+			msg.ToolCalls = toolCalls
+		}
+		if msg.ContentParts != nil {
+			parts := make([]ContentPart, len(msg.ContentParts))
+			copy(parts, msg.ContentParts)
+			msg.ContentParts = parts
+		}
+		if msg.Images != nil {
+			images := make([]ChatImage, len(msg.Images))
+			copy(images, msg.Images)
+			msg.Images = images
+		}
+		copied.Messages[idx] = msg
+	}
+	return copied
+}
+
+// deepCopyArguments copies decoded JSON tool call arguments
+func deepCopyArguments(v map[string]interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(v))
+	for key, val := range v {
+		copied[key] = deepCopyJSON(val)
+	}
+	return copied
+}
+
+// deepCopyJSON copies a decoded JSON value, recursing into objects and arrays
+func deepCopyJSON(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return deepCopyArguments(val)
+	case []interface{}:
+		copied := make([]interface{}, len(val))
+		for idx, item := range val {
+			copied[idx] = deepCopyJSON(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
 // GetProperty returns a property of the conversation
 func (c *ConversationValue) GetProperty(name string) Value {
 	switch name {
@@ -0,0 +1,100 @@
+package interpreter
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// viewFileCache is a thread-safe LRU cache of file lines read by view_file.
+// Agents often view the same file several times in one session; an entry is
+// reused only while the file's modification time and size are unchanged.
+type viewFileCache struct {
+	mu      sync.Mutex
+	cache   map[string]*viewFileCacheEntry
+	order   []string // tracks insertion order for LRU eviction
+	maxSize int
+	maxFile int64 // files larger than this are never cached
+}
+
+// viewFileCacheEntry holds a file's normalized lines and the stat info they were read at
+type viewFileCacheEntry struct {
+	modTime time.Time
+	size    int64
+	lines   []string
+}
+
+// Global view_file cache, small enough that large sessions don't hold much memory
+var globalViewFileCache = &viewFileCache{
+	cache:   make(map[string]*viewFileCacheEntry),
+	order:   make([]string, 0, 32),
+	maxSize: 32,      // Cache up to 32 files
+	maxFile: 1 << 20, // of at most 1MB each
+}
+
+// readLines returns the lines of the file at absPath, with \r\n and \r line endings
+// normalized to \n. Unchanged files are served from the cache.
+func (c *viewFileCache) readLines(absPath string) ([]string, error) {
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.cache[absPath]; ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		c.mu.Unlock()
+		return entry.lines, nil
+	}
+	c.mu.Unlock()
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	fileContent := strings.ReplaceAll(string(content), "\r\n", "\n")
+	fileContent = strings.ReplaceAll(fileContent, "\r", "\n")
+	lines := strings.Split(fileContent, "\n")
+
+	if info.Mode().IsRegular() && info.Size() <= c.maxFile {
+		c.put(absPath, &viewFileCacheEntry{modTime: info.ModTime(), size: info.Size(), lines: lines})
+	}
+	return lines, nil
+}
+
+// put stores entry for absPath, evicting the oldest entry if at capacity
+func (c *viewFileCache) put(absPath string, entry *viewFileCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache[absPath]; ok {
+		c.removeFromOrder(absPath)
+	} else if len(c.cache) >= c.maxSize {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.cache, oldest)
+	}
+	c.cache[absPath] = entry
+	c.order = append(c.order, absPath)
+}
+
+// invalidate drops any cached lines for absPath, e.g. after gsh itself writes the file
+func (c *viewFileCache) invalidate(absPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache[absPath]; ok {
+		delete(c.cache, absPath)
+		c.removeFromOrder(absPath)
+	}
+}
+
+// removeFromOrder removes absPath from the eviction order. The caller must hold c.mu.
+func (c *viewFileCache) removeFromOrder(absPath string) {
+	for idx, path := range c.order {
+		if path == absPath {
+			c.order = append(c.order[:idx], c.order[idx+1:]...)
+			return
+		}
+	}
+}
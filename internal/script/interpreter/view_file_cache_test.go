@@ -0,0 +1,124 @@
+package interpreter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestViewFileCache(maxSize int) *viewFileCache {
+	return &viewFileCache{
+		cache:   make(map[string]*viewFileCacheEntry),
+		order:   make([]string, 0, maxSize),
+		maxSize: maxSize,
+		maxFile: 1024,
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func readTestLines(t *testing.T, cache *viewFileCache, path string) string {
+	t.Helper()
+	lines, err := cache.readLines(path)
+	if err != nil {
+		t.Fatalf("readLines(%s) failed: %v", path, err)
+	}
+	return strings.Join(lines, "|")
+}
+
+// TestViewFileCacheHit tests that an unchanged file is served from the cache
+func TestViewFileCacheHit(t *testing.T) {
+	cache := newTestViewFileCache(4)
+	path := filepath.Join(t.TempDir(), "a.txt")
+	writeTestFile(t, path, "one\r\ntwo")
+
+	if got := readTestLines(t, cache, path); got != "one|two" {
+		t.Errorf("expected %q, got %q", "one|two", got)
+	}
+
+	// Swap the cached lines so a hit is observable
+	cache.cache[path].lines = []string{"cached"}
+	if got := readTestLines(t, cache, path); got != "cached" {
+		t.Errorf("expected the cached lines, got %q", got)
+	}
+}
+
+// TestViewFileCacheInvalidation tests that a changed file is read again
+func TestViewFileCacheInvalidation(t *testing.T) {
+	cache := newTestViewFileCache(4)
+	path := filepath.Join(t.TempDir(), "a.txt")
+	writeTestFile(t, path, "old")
+	readTestLines(t, cache, path)
+
+	t.Run("size changes", func(t *testing.T) {
+		writeTestFile(t, path, "longer")
+		if got := readTestLines(t, cache, path); got != "longer" {
+			t.Errorf("expected %q, got %q", "longer", got)
+		}
+	})
+
+	t.Run("same size, new modification time", func(t *testing.T) {
+		writeTestFile(t, path, "LONGER")
+		later := time.Now().Add(time.Hour)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatalf("failed to set times: %v", err)
+		}
+		if got := readTestLines(t, cache, path); got != "LONGER" {
+			t.Errorf("expected %q, got %q", "LONGER", got)
+		}
+	})
+
+	t.Run("explicit invalidate", func(t *testing.T) {
+		cache.cache[path].lines = []string{"cached"}
+		cache.invalidate(path)
+		if got := readTestLines(t, cache, path); got != "LONGER" {
+			t.Errorf("expected %q, got %q", "LONGER", got)
+		}
+		if len(cache.order) != 1 {
+			t.Errorf("expected one entry in the eviction order, got %v", cache.order)
+		}
+	})
+
+	t.Run("deleted file", func(t *testing.T) {
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+		if _, err := cache.readLines(path); err == nil {
+			t.Error("expected an error for a deleted file")
+		}
+	})
+}
+
+// TestViewFileCacheEviction tests that the cache evicts the oldest file and skips large files
+func TestViewFileCacheEviction(t *testing.T) {
+	cache := newTestViewFileCache(2)
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "c")}
+	for _, path := range paths {
+		writeTestFile(t, path, filepath.Base(path))
+		readTestLines(t, cache, path)
+	}
+
+	if _, ok := cache.cache[paths[0]]; ok {
+		t.Error("expected the oldest file to be evicted")
+	}
+	if len(cache.cache) != 2 || len(cache.order) != 2 {
+		t.Errorf("expected 2 cached files, got %d (order %v)", len(cache.cache), cache.order)
+	}
+
+	big := filepath.Join(dir, "big")
+	writeTestFile(t, big, strings.Repeat("x", 2048))
+	if got := readTestLines(t, cache, big); len(got) != 2048 {
+		t.Errorf("expected the full large file, got %d characters", len(got))
+	}
+	if _, ok := cache.cache[big]; ok {
+		t.Error("expected a file over maxFile not to be cached")
+	}
+}
@@ -56,14 +56,10 @@ func ExecuteViewFile(ctx context.Context, filePath string, startLine, endLine in
 		return "", fmt.Errorf("failed to resolve path: %w", err)
 	}
 
-	content, err := os.ReadFile(absPath)
+	lines, err := globalViewFileCache.readLines(absPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
-
-	fileContent := strings.ReplaceAll(string(content), "\r\n", "\n")
-	fileContent = strings.ReplaceAll(fileContent, "\r", "\n")
-	lines := strings.Split(fileContent, "\n")
 	totalLines := len(lines)
 
 	if startLine <= 0 {
@@ -47,10 +47,19 @@ func (l *Lexer) NextToken() Token {
 
 	l.skipWhitespace()
 
-	// Skip comments
+	// Skip comments, keeping the block of full-line comments right above the token as its doc
+	var doc []string
 	for l.ch == '#' {
-		l.readLineComment()
+		ownLine := l.atLineStart()
+		comment := l.readLineComment()
+		start := l.position
 		l.skipWhitespace()
+		switch {
+		case !ownLine || strings.Count(l.input[start:l.position], "\n") > 1:
+			doc = nil // a trailing comment or a blank line ends the block
+		default:
+			doc = append(doc, strings.TrimPrefix(strings.TrimPrefix(comment, "#"), " "))
+		}
 	}
 
 	tok.Line = l.line
@@ -59,9 +68,13 @@ func (l *Lexer) NextToken() Token {
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
-			ch := l.ch
 			l.readChar()
-			tok = Token{Type: OP_EQ, Literal: string(ch) + string(l.ch), Line: tok.Line, Column: tok.Column}
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = Token{Type: OP_SEQ, Literal: "===", Line: tok.Line, Column: tok.Column}
+			} else {
+				tok = Token{Type: OP_EQ, Literal: "==", Line: tok.Line, Column: tok.Column}
+			}
 		} else {
 			tok = newToken(OP_ASSIGN, l.ch, tok.Line, tok.Column)
 		}
@@ -77,9 +90,13 @@ func (l *Lexer) NextToken() Token {
 		tok = newToken(OP_PERCENT, l.ch, tok.Line, tok.Column)
 	case '!':
 		if l.peekChar() == '=' {
-			ch := l.ch
 			l.readChar()
-			tok = Token{Type: OP_NEQ, Literal: string(ch) + string(l.ch), Line: tok.Line, Column: tok.Column}
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = Token{Type: OP_SNEQ, Literal: "!==", Line: tok.Line, Column: tok.Column}
+			} else {
+				tok = Token{Type: OP_NEQ, Literal: "!=", Line: tok.Line, Column: tok.Column}
+			}
 		} else {
 			tok = newToken(OP_BANG, l.ch, tok.Line, tok.Column)
 		}
@@ -130,7 +147,13 @@ func (l *Lexer) NextToken() Token {
 	case ';':
 		tok = newToken(SEMICOLON, l.ch, tok.Line, tok.Column)
 	case '.':
-		tok = newToken(DOT, l.ch, tok.Line, tok.Column)
+		if l.peekChar() == '.' && l.peekCharN(2) == '.' {
+			l.readChar()
+			l.readChar()
+			tok = Token{Type: ELLIPSIS, Literal: "...", Line: tok.Line, Column: tok.Column}
+		} else {
+			tok = newToken(DOT, l.ch, tok.Line, tok.Column)
+		}
 	case '(':
 		tok = newToken(LPAREN, l.ch, tok.Line, tok.Column)
 	case ')':
@@ -180,6 +203,7 @@ func (l *Lexer) NextToken() Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = LookupIdent(tok.Literal)
+			tok.Doc = strings.Join(doc, "\n")
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = NUMBER
@@ -439,6 +463,12 @@ func (l *Lexer) readTemplateString() string {
 	return result.String()
 }
 
+// atLineStart reports whether only whitespace precedes the current character on its line
+func (l *Lexer) atLineStart() bool {
+	lineStart := strings.LastIndexByte(l.input[:l.position], '\n') + 1
+	return strings.TrimSpace(l.input[lineStart:l.position]) == ""
+}
+
 // readLineComment reads a comment until end of line
 func (l *Lexer) readLineComment() string {
 	position := l.position
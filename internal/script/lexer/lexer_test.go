@@ -534,11 +534,11 @@ z = 30`
 }
 
 func TestOperators(t *testing.T) {
-	input := `= + - * / % ! == != < > <= >= && || | ? ??`
+	input := `= + - * / % ! == != === !== < > <= >= && || | ? ??`
 
 	expectedTypes := []TokenType{
 		OP_ASSIGN, OP_PLUS, OP_MINUS, OP_ASTERISK, OP_SLASH, OP_PERCENT,
-		OP_BANG, OP_EQ, OP_NEQ, OP_LT, OP_GT, OP_LTE, OP_GTE,
+		OP_BANG, OP_EQ, OP_NEQ, OP_SEQ, OP_SNEQ, OP_LT, OP_GT, OP_LTE, OP_GTE,
 		OP_AND, OP_OR, OP_PIPE, OP_QUESTION, OP_NULLCOAL,
 	}
 
@@ -555,11 +555,11 @@ func TestOperators(t *testing.T) {
 }
 
 func TestDelimiters(t *testing.T) {
-	input := `, : ; . ( ) { } [ ]`
+	input := `, : ; . ... ( ) { } [ ] ..`
 
 	expectedTypes := []TokenType{
-		COMMA, COLON, SEMICOLON, DOT,
-		LPAREN, RPAREN, LBRACE, RBRACE, LBRACKET, RBRACKET,
+		COMMA, COLON, SEMICOLON, DOT, ELLIPSIS,
+		LPAREN, RPAREN, LBRACE, RBRACE, LBRACKET, RBRACKET, DOT, DOT,
 	}
 
 	l := New(input)
@@ -575,12 +575,13 @@ func TestDelimiters(t *testing.T) {
 }
 
 func TestKeywords(t *testing.T) {
-	input := `mcp model agent tool if else for of while break continue try catch return import export from`
+	input := `mcp model agent tool if else for of while break continue try catch return import export from include const switch case default defer`
 
 	expectedTypes := []TokenType{
 		KW_MCP, KW_MODEL, KW_AGENT, KW_TOOL, KW_IF, KW_ELSE,
 		KW_FOR, KW_OF, KW_WHILE, KW_BREAK, KW_CONTINUE, KW_TRY, KW_CATCH, KW_RETURN,
-		KW_IMPORT, KW_EXPORT, KW_FROM,
+		KW_IMPORT, KW_EXPORT, KW_FROM, KW_INCLUDE, KW_CONST,
+		KW_SWITCH, KW_CASE, KW_DEFAULT, KW_DEFER,
 	}
 
 	l := New(input)
@@ -1162,3 +1163,33 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDocComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"single line", "# Adds two numbers\ntool add() {}", "Adds two numbers"},
+		{"several lines", "# Adds two numbers.\n#\n#   Returns the sum.\ntool add() {}", "Adds two numbers.\n\n  Returns the sum."},
+		{"indented", "  # Adds two numbers\n  tool add() {}", "Adds two numbers"},
+		{"blank line before the token", "# Section header\n\ntool add() {}", ""},
+		{"trailing comment on the previous line", "x = 1 # counter\ntool add() {}", ""},
+		{"no comment", "tool add() {}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.input)
+			var tok Token
+			for tok = l.NextToken(); tok.Type != KW_TOOL && tok.Type != EOF; tok = l.NextToken() {
+			}
+			if tok.Type != KW_TOOL {
+				t.Fatalf("expected a tool token")
+			}
+			if tok.Doc != tt.expected {
+				t.Errorf("expected doc %q, got %q", tt.expected, tok.Doc)
+			}
+		})
+	}
+}
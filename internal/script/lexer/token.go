@@ -38,6 +38,12 @@ const (
 	KW_IMPORT
 	KW_EXPORT
 	KW_FROM
+	KW_INCLUDE
+	KW_CONST
+	KW_SWITCH
+	KW_CASE
+	KW_DEFAULT
+	KW_DEFER
 	KW_GO // Reserved for future concurrency support (fire-and-forget)
 
 	// Operators
@@ -50,6 +56,8 @@ const (
 	OP_BANG     // !
 	OP_EQ       // ==
 	OP_NEQ      // !=
+	OP_SEQ      // ===
+	OP_SNEQ     // !==
 	OP_LT       // <
 	OP_GT       // >
 	OP_LTE      // <=
@@ -65,6 +73,7 @@ const (
 	COLON     // :
 	SEMICOLON // ;
 	DOT       // .
+	ELLIPSIS  // ...
 	LPAREN    // (
 	RPAREN    // )
 	LBRACE    // {
@@ -79,6 +88,7 @@ type Token struct {
 	Literal string
 	Line    int
 	Column  int
+	Doc     string // full-line # comments directly above an identifier or keyword, without the #
 }
 
 // keywords maps keyword strings to their token types
@@ -103,6 +113,12 @@ var keywords = map[string]TokenType{
 	"import":   KW_IMPORT,
 	"export":   KW_EXPORT,
 	"from":     KW_FROM,
+	"include":  KW_INCLUDE,
+	"const":    KW_CONST,
+	"switch":   KW_SWITCH,
+	"case":     KW_CASE,
+	"default":  KW_DEFAULT,
+	"defer":    KW_DEFER,
 	"go":       KW_GO, // Reserved for future concurrency support (fire-and-forget)
 }
 
@@ -36,6 +36,8 @@ func TestTokenTypeString(t *testing.T) {
 		{OP_BANG, "OP_BANG"},
 		{OP_EQ, "OP_EQ"},
 		{OP_NEQ, "OP_NEQ"},
+		{OP_SEQ, "OP_SEQ"},
+		{OP_SNEQ, "OP_SNEQ"},
 		{OP_LT, "OP_LT"},
 		{OP_GT, "OP_GT"},
 		{OP_LTE, "OP_LTE"},
@@ -193,7 +195,7 @@ func TestOperatorTokens(t *testing.T) {
 	// Test that we have all necessary operator tokens defined
 	operators := []TokenType{
 		OP_ASSIGN, OP_PLUS, OP_MINUS, OP_ASTERISK, OP_SLASH, OP_PERCENT,
-		OP_BANG, OP_EQ, OP_NEQ, OP_LT, OP_GT, OP_LTE, OP_GTE,
+		OP_BANG, OP_EQ, OP_NEQ, OP_SEQ, OP_SNEQ, OP_LT, OP_GT, OP_LTE, OP_GTE,
 		OP_AND, OP_OR, OP_PIPE, OP_QUESTION, OP_NULLCOAL,
 	}
 
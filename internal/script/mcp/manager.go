@@ -2,11 +2,14 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,8 +25,25 @@ type ServerConfig struct {
 	// For HTTP/SSE transport (remote server)
 	URL     string            // Server URL for remote connections
 	Headers map[string]string // HTTP headers for authentication
+
+	// For Unix domain socket transport (local server that is already running)
+	Socket string // Path of the socket to connect to
+
+	// DisableAutoRestart makes tool calls fail fast when a stdio server has died,
+	// instead of restarting it and retrying the call
+	DisableAutoRestart bool
 }
 
+// Restart policy for crashed stdio servers
+const (
+	maxRestartAttempts  = 3
+	defaultRestartDelay = 500 * time.Millisecond
+)
+
+// RestartHandler is called after a crashed stdio server has been restarted.
+// attempt is the number of tries it took; err is the failure that triggered the restart.
+type RestartHandler func(serverName string, attempt int, err error)
+
 // MCPServer represents a running MCP server instance
 type MCPServer struct {
 	Name    string
@@ -31,6 +51,9 @@ type MCPServer struct {
 	Session *mcp.ClientSession
 	Tools   map[string]*mcp.Tool // Available tools from this server
 	mu      sync.RWMutex
+
+	// restartMu ensures concurrent failed calls restart the server only once
+	restartMu sync.Mutex
 }
 
 // Manager manages multiple MCP servers
@@ -39,18 +62,29 @@ type Manager struct {
 	mu      sync.RWMutex
 	ctx     context.Context
 	cancel  context.CancelFunc
+
+	onRestart    RestartHandler
+	restartDelay time.Duration // initial backoff between restart attempts, doubled each time
 }
 
 // NewManager creates a new MCP manager
 func NewManager() *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
-		servers: make(map[string]*MCPServer),
-		ctx:     ctx,
-		cancel:  cancel,
+		servers:      make(map[string]*MCPServer),
+		ctx:          ctx,
+		cancel:       cancel,
+		restartDelay: defaultRestartDelay,
 	}
 }
 
+// SetRestartHandler sets the function called whenever a crashed server is restarted
+func (m *Manager) SetRestartHandler(handler RestartHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRestart = handler
+}
+
 // RegisterServer registers and starts an MCP server
 func (m *Manager) RegisterServer(name string, config ServerConfig) error {
 	m.mu.Lock()
@@ -62,8 +96,8 @@ func (m *Manager) RegisterServer(name string, config ServerConfig) error {
 	}
 
 	// Validate config
-	if config.Command == "" && config.URL == "" {
-		return fmt.Errorf("MCP server '%s' must specify either command or URL", name)
+	if config.Command == "" && config.URL == "" && config.Socket == "" {
+		return fmt.Errorf("MCP server '%s' must specify either command, URL or socket", name)
 	}
 
 	// Create server instance
@@ -74,16 +108,22 @@ func (m *Manager) RegisterServer(name string, config ServerConfig) error {
 	}
 
 	// Start the server based on transport type
-	if config.Command != "" {
+	switch {
+	case config.Command != "":
 		// Stdio transport
 		if err := m.startStdioServer(server); err != nil {
 			return fmt.Errorf("failed to start stdio server '%s': %w", name, err)
 		}
-	} else {
+	case config.URL != "":
 		// HTTP/SSE transport
 		if err := m.startHTTPServer(server); err != nil {
 			return fmt.Errorf("failed to start HTTP server '%s': %w", name, err)
 		}
+	default:
+		// Unix domain socket transport
+		if err := m.startSocketServer(server); err != nil {
+			return fmt.Errorf("failed to connect to socket server '%s': %w", name, err)
+		}
 	}
 
 	m.servers[name] = server
@@ -118,16 +158,17 @@ func (m *Manager) startStdioServer(server *MCPServer) error {
 		return fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
 
-	server.Session = session
-
 	// List available tools
 	toolsList, err := session.ListTools(m.ctx, nil)
 	if err != nil {
+		session.Close()
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
-	// Store tools
+	// Store the session and tools, replacing those of a previous process on restart
 	server.mu.Lock()
+	server.Session = session
+	server.Tools = make(map[string]*mcp.Tool, len(toolsList.Tools))
 	for _, tool := range toolsList.Tools {
 		server.Tools[tool.Name] = tool
 	}
@@ -190,6 +231,34 @@ func (m *Manager) startHTTPServer(server *MCPServer) error {
 	return nil
 }
 
+// startSocketServer connects to an MCP server listening on a Unix domain socket
+func (m *Manager) startSocketServer(server *MCPServer) error {
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "gsh-mcp-client",
+		Version: "1.0.0",
+	}, nil)
+
+	session, err := client.Connect(m.ctx, &socketTransport{Path: server.Config.Socket}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MCP server at %s: %w", server.Config.Socket, err)
+	}
+
+	toolsList, err := session.ListTools(m.ctx, nil)
+	if err != nil {
+		session.Close()
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	server.mu.Lock()
+	server.Session = session
+	for _, tool := range toolsList.Tools {
+		server.Tools[tool.Name] = tool
+	}
+	server.mu.Unlock()
+
+	return nil
+}
+
 // headerTransport is a custom http.RoundTripper that adds headers to requests
 type headerTransport struct {
 	base    http.RoundTripper
@@ -289,10 +358,19 @@ func (m *Manager) CallTool(serverName, toolName string, arguments map[string]int
 	}
 
 	// Call the tool
-	result, err := server.Session.CallTool(m.ctx, &mcp.CallToolParams{
+	params := &mcp.CallToolParams{
 		Name:      toolName,
 		Arguments: arguments,
-	})
+	}
+	session := server.session()
+	result, err := session.CallTool(m.ctx, params)
+	if err != nil && m.canRestart(server, err) {
+		// The server process died: restart it and re-run the call once
+		if restartErr := m.restartServer(server, session, err); restartErr != nil {
+			return nil, fmt.Errorf("failed to call tool '%s' on server '%s': %w (restart failed: %v)", toolName, serverName, err, restartErr)
+		}
+		result, err = server.session().CallTool(m.ctx, params)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tool '%s' on server '%s': %w", toolName, serverName, err)
 	}
@@ -300,6 +378,93 @@ func (m *Manager) CallTool(serverName, toolName string, arguments map[string]int
 	return result, nil
 }
 
+// session returns the server's current client session
+func (s *MCPServer) session() *mcp.ClientSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Session
+}
+
+// canRestart reports whether a failed call should restart the server: it must be
+// a stdio server with auto-restart enabled whose connection has been lost
+func (m *Manager) canRestart(server *MCPServer, err error) bool {
+	if server.Config.Command == "" || server.Config.DisableAutoRestart || m.ctx.Err() != nil {
+		return false
+	}
+	return isConnectionLost(err)
+}
+
+// isConnectionLost reports whether err means the server process is gone
+// (it exited, or its pipes are broken) rather than the call itself failing
+func isConnectionLost(err error) bool {
+	return errors.Is(err, mcp.ErrConnectionClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrClosedPipe) ||
+		errors.Is(err, os.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// restartServer restarts a crashed stdio server, retrying with exponential backoff.
+// failed is the session the failed call used; if another call already replaced it,
+// the server is not restarted again.
+func (m *Manager) restartServer(server *MCPServer, failed *mcp.ClientSession, cause error) error {
+	server.restartMu.Lock()
+	defer server.restartMu.Unlock()
+
+	if server.session() != failed {
+		return nil
+	}
+	if failed != nil {
+		failed.Close()
+	}
+
+	delay := m.restartDelay
+	var err error
+	for attempt := 1; attempt <= maxRestartAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(delay):
+			case <-m.ctx.Done():
+				return m.ctx.Err()
+			}
+			delay *= 2
+		}
+
+		if err = m.startStdioServer(server); err == nil {
+			m.mu.RLock()
+			onRestart := m.onRestart
+			m.mu.RUnlock()
+			if onRestart != nil {
+				onRestart(server.Name, attempt, cause)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", maxRestartAttempts, err)
+}
+
+// RemoveServer shuts down a server and unregisters it, so its name can be registered again
+func (m *Manager) RemoveServer(name string) error {
+	m.mu.Lock()
+	server, exists := m.servers[name]
+	delete(m.servers, name)
+	m.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("MCP server '%s' not found", name)
+	}
+
+	server.mu.RLock()
+	session := server.Session
+	server.mu.RUnlock()
+	if session != nil {
+		if err := session.Close(); err != nil {
+			return fmt.Errorf("failed to close server '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
 // ListServers returns all registered server names
 func (m *Manager) ListServers() []string {
 	m.mu.RLock()
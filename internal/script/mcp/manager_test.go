@@ -30,7 +30,7 @@ func TestManagerRegisterServer_ValidationErrors(t *testing.T) {
 			name:        "missing command and URL",
 			serverName:  "test",
 			config:      ServerConfig{},
-			expectedErr: "must specify either command or URL",
+			expectedErr: "must specify either command, URL or socket",
 		},
 		{
 			name:       "duplicate server registration",
@@ -219,6 +219,25 @@ func TestManagerClose(t *testing.T) {
 	}
 }
 
+func TestManagerRemoveServer(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	manager.mu.Lock()
+	manager.servers["test"] = &MCPServer{
+		Name:  "test",
+		Tools: make(map[string]*sdkmcp.Tool),
+	}
+	manager.mu.Unlock()
+
+	require.NoError(t, manager.RemoveServer("test"))
+	assert.Empty(t, manager.ListServers())
+
+	err := manager.RemoveServer("test")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
 func TestServerConfig_Validation(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -266,11 +285,11 @@ func TestServerConfig_Validation(t *testing.T) {
 				// We expect errors for valid configs because we can't actually connect
 				// But the error should not be about validation
 				if err != nil {
-					assert.NotContains(t, err.Error(), "must specify either command or URL")
+					assert.NotContains(t, err.Error(), "must specify either command, URL or socket")
 				}
 			} else {
 				assert.Error(t, err)
-				assert.Contains(t, err.Error(), "must specify either command or URL")
+				assert.Contains(t, err.Error(), "must specify either command, URL or socket")
 			}
 		})
 	}
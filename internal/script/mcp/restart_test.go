@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperStdioServer is not a real test: it runs this test binary as a stdio
+// MCP server when GSH_TEST_MCP_HELPER is set, so restart tests have a server to crash.
+func TestHelperStdioServer(t *testing.T) {
+	if os.Getenv("GSH_TEST_MCP_HELPER") != "1" {
+		return
+	}
+
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "crashy", Version: "1.0.0"}, nil)
+
+	// crash_once exits the process the first time it is called, then succeeds
+	sdkmcp.AddTool(server, &sdkmcp.Tool{Name: "crash_once"}, func(ctx context.Context, req *sdkmcp.CallToolRequest, input struct{}) (*sdkmcp.CallToolResult, any, error) {
+		marker := os.Getenv("GSH_TEST_MCP_MARKER")
+		if _, err := os.Stat(marker); os.IsNotExist(err) {
+			_ = os.WriteFile(marker, nil, 0600)
+			os.Exit(1)
+		}
+		return &sdkmcp.CallToolResult{Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: "recovered"}}}, nil, nil
+	})
+
+	// crash always exits the process
+	sdkmcp.AddTool(server, &sdkmcp.Tool{Name: "crash"}, func(ctx context.Context, req *sdkmcp.CallToolRequest, input struct{}) (*sdkmcp.CallToolResult, any, error) {
+		os.Exit(1)
+		return nil, nil, nil
+	})
+
+	_ = server.Run(context.Background(), &sdkmcp.StdioTransport{})
+	os.Exit(0)
+}
+
+// restartRecorder collects restart notifications from a Manager
+type restartRecorder struct {
+	mu       sync.Mutex
+	attempts []int
+}
+
+func (r *restartRecorder) handle(serverName string, attempt int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, attempt)
+}
+
+func (r *restartRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.attempts)
+}
+
+// newCrashyManager registers the helper server and records restarts
+func newCrashyManager(t *testing.T, disableAutoRestart bool) (*Manager, *restartRecorder) {
+	manager := NewManager()
+	t.Cleanup(func() { manager.Close() })
+	manager.restartDelay = 10 * time.Millisecond
+
+	recorder := &restartRecorder{}
+	manager.SetRestartHandler(recorder.handle)
+
+	err := manager.RegisterServer("crashy", ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestHelperStdioServer$"},
+		Env: map[string]string{
+			"GSH_TEST_MCP_HELPER": "1",
+			"GSH_TEST_MCP_MARKER": filepath.Join(t.TempDir(), "crashed"),
+		},
+		DisableAutoRestart: disableAutoRestart,
+	})
+	require.NoError(t, err)
+	return manager, recorder
+}
+
+func TestCallTool_RestartsCrashedServer(t *testing.T) {
+	manager, recorder := newCrashyManager(t, false)
+
+	result, err := manager.CallTool("crashy", "crash_once", nil)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "recovered", result.Content[0].(*sdkmcp.TextContent).Text)
+	assert.Equal(t, 1, recorder.count())
+
+	// The restarted server keeps serving calls
+	result, err = manager.CallTool("crashy", "crash_once", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 1, recorder.count())
+}
+
+func TestCallTool_RetriesOnlyOnce(t *testing.T) {
+	manager, recorder := newCrashyManager(t, false)
+
+	_, err := manager.CallTool("crashy", "crash", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, recorder.count(), "server should be restarted once per failed call")
+}
+
+func TestCallTool_AutoRestartDisabled(t *testing.T) {
+	manager, recorder := newCrashyManager(t, true)
+
+	_, err := manager.CallTool("crashy", "crash_once", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 0, recorder.count())
+
+	// Still fails fast: the dead server is not brought back
+	_, err = manager.CallTool("crashy", "crash_once", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 0, recorder.count())
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// socketTransport connects to an MCP server listening on a Unix domain socket.
+// Messages use the same framing as the stdio transport: one JSON-RPC message per line.
+type socketTransport struct {
+	Path string
+}
+
+// Connect implements mcp.Transport
+func (t *socketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", t.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &socketConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// socketConn is an mcp.Connection over a socket. Reads happen on a single goroutine,
+// but writes may be concurrent, so they are serialized.
+type socketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+}
+
+// Read implements mcp.Connection. Closing the connection unblocks a pending read.
+func (c *socketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return jsonrpc.DecodeMessage(line)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Write implements mcp.Connection
+func (c *socketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.conn.Write(append(data, '\n'))
+	return err
+}
+
+// Close implements mcp.Connection
+func (c *socketConn) Close() error {
+	return c.conn.Close()
+}
+
+// SessionID implements mcp.Connection. Socket connections have no session ID.
+func (c *socketConn) SessionID() string {
+	return ""
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connTransport serves MCP over a connection that has already been accepted
+type connTransport struct {
+	conn net.Conn
+}
+
+func (t *connTransport) Connect(context.Context) (mcp.Connection, error) {
+	return &socketConn{conn: t.conn, reader: bufio.NewReader(t.conn)}, nil
+}
+
+// startSocketMCPServer serves a test MCP server with an echo tool on a Unix socket
+// and returns the socket path
+func startSocketMCPServer(t *testing.T) string {
+	t.Helper()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{
+		Name:    "test-socket-server",
+		Version: "1.0.0",
+	}, nil)
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "echo",
+		Description: "Echoes the input message",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input struct {
+		Message string `json:"message"`
+	}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: input.Message}},
+		}, nil, nil
+	})
+
+	// Socket paths are limited to about 100 bytes, so stay out of the long test temp dir
+	dir, err := os.MkdirTemp("", "gsh-mcp")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "mcp.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				session, err := mcpServer.Connect(ctx, &connTransport{conn: conn}, nil)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				session.Wait()
+			}()
+		}
+	}()
+
+	return path
+}
+
+func TestSocketMCPServer(t *testing.T) {
+	path := startSocketMCPServer(t)
+
+	manager := NewManager()
+	defer manager.Close()
+
+	err := manager.RegisterServer("local", ServerConfig{Socket: path})
+	require.NoError(t, err)
+
+	tools, err := manager.ListTools("local")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"echo"}, tools)
+
+	result, err := manager.CallTool("local", "echo", map[string]interface{}{"message": "over the socket"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "over the socket", text.Text)
+}
+
+func TestSocketMCPServer_Missing(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close()
+
+	err := manager.RegisterServer("local", ServerConfig{Socket: filepath.Join(t.TempDir(), "missing.sock")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to socket server 'local'")
+	assert.Contains(t, err.Error(), "missing.sock")
+
+	_, err = manager.GetServer("local")
+	assert.Error(t, err, "a server that failed to connect should not be registered")
+}
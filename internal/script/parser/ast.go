@@ -143,6 +143,7 @@ type AssignmentStatement struct {
 	Left           Expression  // can be Identifier or IndexExpression
 	TypeAnnotation *Identifier // optional type annotation (e.g., ": string")
 	Value          Expression
+	Const          bool // declared with 'const': the binding can't be reassigned
 	// Legacy field for backward compatibility
 	Name *Identifier
 }
@@ -151,6 +152,9 @@ func (a *AssignmentStatement) statementNode()       {}
 func (a *AssignmentStatement) TokenLiteral() string { return a.Token.Literal }
 func (a *AssignmentStatement) String() string {
 	var out strings.Builder
+	if a.Const {
+		out.WriteString("const ")
+	}
 	if a.Left != nil {
 		out.WriteString(a.Left.String())
 	} else if a.Name != nil {
@@ -167,6 +171,39 @@ func (a *AssignmentStatement) String() string {
 	return out.String()
 }
 
+// DestructuringAssignment assigns several variables at once, from the elements of an
+// array (a, b = pair) or the properties of an object ({ x, y } = point). In the object
+// form, { x: left } binds property x to the variable left.
+type DestructuringAssignment struct {
+	Token lexer.Token   // the first identifier or the '{' token
+	Names []*Identifier // the variables to assign, in order
+	Keys  []string      // the property read into each variable; nil for the array form
+	Value Expression
+}
+
+func (d *DestructuringAssignment) statementNode()       {}
+func (d *DestructuringAssignment) TokenLiteral() string { return d.Token.Literal }
+func (d *DestructuringAssignment) String() string {
+	parts := make([]string, len(d.Names))
+	for idx, name := range d.Names {
+		parts[idx] = name.String()
+		if d.Keys != nil && d.Keys[idx] != name.Value {
+			parts[idx] = d.Keys[idx] + ": " + name.String()
+		}
+	}
+	var out strings.Builder
+	if d.Keys != nil {
+		out.WriteString("{ " + strings.Join(parts, ", ") + " }")
+	} else {
+		out.WriteString(strings.Join(parts, ", "))
+	}
+	out.WriteString(" = ")
+	if d.Value != nil {
+		out.WriteString(d.Value.String())
+	}
+	return out.String()
+}
+
 // ExpressionStatement wraps an expression as a statement
 type ExpressionStatement struct {
 	Token      lexer.Token // the first token of the expression
@@ -283,8 +320,8 @@ func (i *IndexExpression) String() string {
 
 // ArrayLiteral represents an array literal (e.g., [1, 2, 3])
 type ArrayLiteral struct {
-	Token    lexer.Token // the '[' token
-	Elements []Expression
+	Token    lexer.Token  // the '[' token
+	Elements []Expression // may include *SpreadElement
 }
 
 func (a *ArrayLiteral) expressionNode()      {}
@@ -302,6 +339,16 @@ func (a *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// SpreadElement represents a spread inside an array literal (e.g., ...items in [...items, 4])
+type SpreadElement struct {
+	Token    lexer.Token // the '...' token
+	Argument Expression
+}
+
+func (s *SpreadElement) expressionNode()      {}
+func (s *SpreadElement) TokenLiteral() string { return s.Token.Literal }
+func (s *SpreadElement) String() string       { return "..." + s.Argument.String() }
+
 // ObjectLiteral represents an object literal (e.g., {key: value})
 type ObjectLiteral struct {
 	Token lexer.Token // the '{' token
@@ -367,12 +414,64 @@ func (w *WhileStatement) String() string {
 	return out.String()
 }
 
-// ForOfStatement represents a for-of loop
+// SwitchStatement represents a switch statement. The first case with a value
+// equal to Value runs; there is no fallthrough between cases.
+type SwitchStatement struct {
+	Token   lexer.Token // the 'switch' token
+	Value   Expression
+	Cases   []*SwitchCase
+	Default *BlockStatement // nil if there is no default case
+}
+
+func (s *SwitchStatement) statementNode()       {}
+func (s *SwitchStatement) TokenLiteral() string { return s.Token.Literal }
+func (s *SwitchStatement) String() string {
+	var out strings.Builder
+	out.WriteString("switch (")
+	out.WriteString(s.Value.String())
+	out.WriteString(") {\n")
+	for _, c := range s.Cases {
+		out.WriteString(c.String())
+		out.WriteString("\n")
+	}
+	if s.Default != nil {
+		out.WriteString("default: ")
+		out.WriteString(s.Default.String())
+		out.WriteString("\n")
+	}
+	out.WriteString("}")
+	return out.String()
+}
+
+// SwitchCase represents one case of a switch statement, matching any of its values
+type SwitchCase struct {
+	Token  lexer.Token // the 'case' token
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (c *SwitchCase) String() string {
+	var out strings.Builder
+	out.WriteString("case ")
+	for i, v := range c.Values {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(v.String())
+	}
+	out.WriteString(": ")
+	out.WriteString(c.Body.String())
+	return out.String()
+}
+
+// ForOfStatement represents a for-of loop. ValueVariable is set for the key-value
+// form, for (key, value of obj), in which case Variable holds the key.
 type ForOfStatement struct {
-	Token    lexer.Token // the 'for' token
-	Variable *Identifier
-	Iterable Expression
-	Body     *BlockStatement
+	Token         lexer.Token // the 'for' token
+	Variable      *Identifier
+	ValueVariable *Identifier
+	Iterable      Expression
+	Body          *BlockStatement
 }
 
 func (f *ForOfStatement) statementNode()       {}
@@ -381,6 +480,10 @@ func (f *ForOfStatement) String() string {
 	var out strings.Builder
 	out.WriteString("for (")
 	out.WriteString(f.Variable.String())
+	if f.ValueVariable != nil {
+		out.WriteString(", ")
+		out.WriteString(f.ValueVariable.String())
+	}
 	out.WriteString(" of ")
 	out.WriteString(f.Iterable.String())
 	out.WriteString(") ")
@@ -442,6 +545,19 @@ func (t *ThrowStatement) String() string {
 	return out.String()
 }
 
+// DeferStatement represents a defer statement, whose call runs when the
+// enclosing tool returns or the script finishes
+type DeferStatement struct {
+	Token lexer.Token     // the 'defer' token
+	Call  *CallExpression // the deferred call
+}
+
+func (d *DeferStatement) statementNode()       {}
+func (d *DeferStatement) TokenLiteral() string { return d.Token.Literal }
+func (d *DeferStatement) String() string {
+	return "defer " + d.Call.String()
+}
+
 // TryStatement represents a try/catch/finally block
 type TryStatement struct {
 	Token         lexer.Token // the 'try' token
@@ -615,6 +731,19 @@ func (i *ImportStatement) String() string {
 	return "import { " + strings.Join(i.Symbols, ", ") + " } from \"" + i.Path.Value + "\""
 }
 
+// IncludeStatement represents: include "./file.gsh"
+// Unlike import, the included file is evaluated directly in the current scope.
+type IncludeStatement struct {
+	Token lexer.Token    // The 'include' token
+	Path  *StringLiteral // The include path
+}
+
+func (i *IncludeStatement) statementNode()       {}
+func (i *IncludeStatement) TokenLiteral() string { return i.Token.Literal }
+func (i *IncludeStatement) String() string {
+	return "include \"" + i.Path.Value + "\""
+}
+
 // ExportStatement represents: export <declaration>
 type ExportStatement struct {
 	Token       lexer.Token // The 'export' token
@@ -630,17 +759,19 @@ func (e *ExportStatement) String() string {
 
 // ToolParameter represents a parameter in a tool declaration
 type ToolParameter struct {
-	Name *Identifier
-	Type *Identifier // optional type annotation
+	Name        *Identifier
+	Type        *Identifier // optional type annotation
+	Description string      // from the # comments directly above the parameter
 }
 
 // ToolDeclaration represents a tool declaration
 type ToolDeclaration struct {
-	Token      lexer.Token // the 'tool' token
-	Name       *Identifier
-	Parameters []*ToolParameter
-	ReturnType *Identifier // optional return type annotation
-	Body       *BlockStatement
+	Token       lexer.Token // the 'tool' token
+	Name        *Identifier
+	Parameters  []*ToolParameter
+	ReturnType  *Identifier // optional return type annotation
+	Body        *BlockStatement
+	Description string // from the # comments directly above the declaration
 }
 
 func (t *ToolDeclaration) statementNode()       {}
@@ -200,7 +200,7 @@ func (p *Parser) parseDeclarationConfig() map[string]Expression {
 // tool <name>(<params>) { <body> }
 // tool <name>(<params>): <returnType> { <body> }
 func (p *Parser) parseToolDeclaration() Statement {
-	stmt := &ToolDeclaration{Token: p.curToken}
+	stmt := &ToolDeclaration{Token: p.curToken, Description: p.curToken.Doc}
 
 	// Expect identifier for tool name
 	if !p.expectPeek(lexer.IDENT) {
@@ -279,7 +279,8 @@ func (p *Parser) parseToolParameters() []*ToolParameter {
 		}
 
 		param := &ToolParameter{
-			Name: &Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Name:        &Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Description: p.curToken.Doc,
 		}
 
 		// Check for type annotation
@@ -304,6 +305,9 @@ func (p *Parser) parseToolParameters() []*ToolParameter {
 		if p.peekTokenIs(lexer.COMMA) {
 			p.nextToken() // consume current param or type
 			p.nextToken() // consume comma
+			if p.curTokenIs(lexer.RPAREN) {
+				break // trailing comma
+			}
 			continue
 		}
 
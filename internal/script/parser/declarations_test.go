@@ -1771,6 +1771,68 @@ func TestParseImportStatement(t *testing.T) {
 	}
 }
 
+func TestParseIncludeStatement(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedPath string
+		expectError  bool
+	}{
+		{
+			name:         "include relative path",
+			input:        `include "./models.gsh"`,
+			expectedPath: "./models.gsh",
+		},
+		{
+			name:         "include parent path",
+			input:        `include "../shared/agents.gsh"`,
+			expectedPath: "../shared/agents.gsh",
+		},
+		{
+			name:        "missing path",
+			input:       `include`,
+			expectError: true,
+		},
+		{
+			name:        "non-string path",
+			input:       `include models`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
+			program := p.ParseProgram()
+
+			if tt.expectError {
+				if len(p.Errors()) == 0 {
+					t.Fatalf("expected parsing error, got none")
+				}
+				return
+			}
+
+			if len(p.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", p.Errors())
+			}
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("expected 1 statement, got %d", len(program.Statements))
+			}
+
+			includeStmt, ok := program.Statements[0].(*IncludeStatement)
+			if !ok {
+				t.Fatalf("expected IncludeStatement, got %T", program.Statements[0])
+			}
+
+			if includeStmt.Path.Value != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, includeStmt.Path.Value)
+			}
+		})
+	}
+}
+
 func TestParseExportStatement(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1783,6 +1845,11 @@ func TestParseExportStatement(t *testing.T) {
 			input:        `export myVar = 42`,
 			expectedName: "myVar",
 		},
+		{
+			name:         "export const",
+			input:        `export const LIMIT = 10`,
+			expectedName: "LIMIT",
+		},
 		{
 			name:         "export tool",
 			input:        "export tool myFunc(x) {\n    return x * 2\n}",
@@ -1837,3 +1904,62 @@ func TestParseExportStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestParseToolDeclarationDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"doc comment", "# Looks up a user by email\ntool findUser(email) {}", "Looks up a user by email"},
+		{"exported", "# Looks up a user by email\nexport tool findUser(email) {}", "Looks up a user by email"},
+		{"no doc comment", "tool findUser(email) {}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			stmt := program.Statements[0]
+			if export, ok := stmt.(*ExportStatement); ok {
+				stmt = export.Declaration
+			}
+			decl, ok := stmt.(*ToolDeclaration)
+			if !ok {
+				t.Fatalf("expected *ToolDeclaration, got %T", stmt)
+			}
+			if decl.Description != tt.expected {
+				t.Errorf("expected description %q, got %q", tt.expected, decl.Description)
+			}
+		})
+	}
+}
+
+func TestParseToolParameterDescriptions(t *testing.T) {
+	input := `tool readLines(
+	# the file to read, relative to the current directory
+	path: string,
+	limit: number,
+	# 1-based line to start from
+	start,
+) {}`
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	decl, ok := program.Statements[0].(*ToolDeclaration)
+	if !ok {
+		t.Fatalf("expected *ToolDeclaration, got %T", program.Statements[0])
+	}
+	expected := []string{"the file to read, relative to the current directory", "", "1-based line to start from"}
+	if len(decl.Parameters) != len(expected) {
+		t.Fatalf("expected %d parameters, got %d", len(expected), len(decl.Parameters))
+	}
+	for idx, param := range decl.Parameters {
+		if param.Description != expected[idx] {
+			t.Errorf("parameter %s: expected description %q, got %q", param.Name.Value, expected[idx], param.Description)
+		}
+	}
+}
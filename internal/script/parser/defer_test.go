@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kunchenguid/gsh/internal/script/lexer"
+)
+
+func TestDeferStatement(t *testing.T) {
+	input := `defer session.close()`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*DeferStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *DeferStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Token.Type != lexer.KW_DEFER {
+		t.Errorf("stmt.Token.Type not KW_DEFER. got=%v", stmt.Token.Type)
+	}
+
+	if _, ok := stmt.Call.Function.(*MemberExpression); !ok {
+		t.Errorf("stmt.Call.Function is not *MemberExpression. got=%T", stmt.Call.Function)
+	}
+
+	if stmt.String() != "defer session.close()" {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+}
+
+func TestDeferStatementErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"missing call", "defer\nx = 1", "defer statement requires a call (line 1, column 1)"},
+		{"not a call", "defer session", "defer statement requires a call, e.g. defer session.close()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
+			p.ParseProgram()
+
+			errors := p.Errors()
+			if len(errors) == 0 {
+				t.Fatalf("expected parser errors, got none")
+			}
+			if !strings.Contains(errors[0], tt.expected) {
+				t.Errorf("expected error containing %q, got %q", tt.expected, errors[0])
+			}
+		})
+	}
+}
@@ -135,6 +135,13 @@ func TestDetailedErrorMessages(t *testing.T) {
 				"expected next token to be keyword 'of'",
 			},
 		},
+		{
+			name:  "reserved keyword used as a value",
+			input: `x = include`,
+			expectedErrors: []string{
+				"unexpected token keyword 'include'",
+			},
+		},
 		{
 			name:  "try without catch or finally",
 			input: `try { x = 5 }`,
@@ -125,14 +125,15 @@ func (p *Parser) parseCallExpression(function Expression) Expression {
 }
 
 // parseMemberExpression parses member access expressions
-// Allows both identifiers and keywords as property names (e.g., obj.model, obj.agent)
+// Allows both identifiers and keywords as property names (e.g., obj.model, obj.agent),
+// and '*' for all the tools of an MCP server (e.g., filesystem.*)
 func (p *Parser) parseMemberExpression(object Expression) Expression {
 	exp := &MemberExpression{Token: p.curToken, Object: object}
 
 	p.nextToken()
 
 	// Accept identifiers or keywords as property names
-	if p.curToken.Type != lexer.IDENT && !lexer.IsKeyword(p.curToken.Type) {
+	if p.curToken.Type != lexer.IDENT && !lexer.IsKeyword(p.curToken.Type) && p.curToken.Type != lexer.OP_ASTERISK {
 		p.addError("expected property name after '.', got %s '%s' instead (line %d, column %d)",
 			p.curToken.Type, p.curToken.Literal, p.curToken.Line, p.curToken.Column)
 		return nil
@@ -143,13 +144,41 @@ func (p *Parser) parseMemberExpression(object Expression) Expression {
 	return exp
 }
 
-// parseArrayLiteral parses array literals
+// parseArrayLiteral parses array literals, whose elements may be spreads (...expr)
 func (p *Parser) parseArrayLiteral() Expression {
-	array := &ArrayLiteral{Token: p.curToken}
-	array.Elements = p.parseExpressionList(lexer.RBRACKET)
+	array := &ArrayLiteral{Token: p.curToken, Elements: []Expression{}}
+
+	if p.peekTokenIs(lexer.RBRACKET) {
+		p.nextToken()
+		return array
+	}
+
+	p.nextToken()
+	array.Elements = append(array.Elements, p.parseArrayElement())
+
+	for p.peekTokenIs(lexer.COMMA) {
+		p.nextToken() // consume comma
+		p.nextToken() // move to next element
+		array.Elements = append(array.Elements, p.parseArrayElement())
+	}
+
+	if !p.expectPeek(lexer.RBRACKET) {
+		array.Elements = nil
+	}
 	return array
 }
 
+// parseArrayElement parses one array literal element, which is an expression or a spread
+func (p *Parser) parseArrayElement() Expression {
+	if !p.curTokenIs(lexer.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+	spread := &SpreadElement{Token: p.curToken}
+	p.nextToken() // move past '...'
+	spread.Argument = p.parseExpression(LOWEST)
+	return spread
+}
+
 // parseObjectLiteral parses object literals
 func (p *Parser) parseObjectLiteral() Expression {
 	obj := &ObjectLiteral{Token: p.curToken}
@@ -178,15 +207,21 @@ func (p *Parser) parseObjectLiteral() Expression {
 			return nil
 		}
 
-		// Expect ':'
-		if !p.expectPeek(lexer.COLON) {
-			return nil
-		}
+		// Shorthand property: { name } is { name: name }
+		var value Expression
+		if p.curTokenIs(lexer.IDENT) && (p.peekTokenIs(lexer.COMMA) || p.peekTokenIs(lexer.RBRACE)) {
+			value = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		} else {
+			// Expect ':'
+			if !p.expectPeek(lexer.COLON) {
+				return nil
+			}
 
-		p.nextToken() // move to value
+			p.nextToken() // move to value
 
-		// Parse value
-		value := p.parseExpression(LOWEST)
+			// Parse value
+			value = p.parseExpression(LOWEST)
+		}
 		obj.Pairs[key] = value
 		obj.Order = append(obj.Order, key)
 
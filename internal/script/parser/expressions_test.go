@@ -322,6 +322,20 @@ func TestMemberExpressionParsing(t *testing.T) {
 	}
 }
 
+func TestMemberWildcardParsing(t *testing.T) {
+	l := lexer.New("tools = [filesystem.*, other]")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+	if got := program.Statements[0].String(); got != "tools = [filesystem.*, other]" {
+		t.Errorf("unexpected program: %s", got)
+	}
+}
+
 func TestMemberCallExpression(t *testing.T) {
 	input := "filesystem.read_file(path)"
 
@@ -412,6 +426,47 @@ func TestEmptyArrayLiteral(t *testing.T) {
 	}
 }
 
+func TestArrayLiteralSpreadParsing(t *testing.T) {
+	input := "[...a, 1, ...b.c]"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ExpressionStatement)
+	if !ok {
+		t.Fatalf("exp not ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	array, ok := stmt.Expression.(*ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+	}
+	if _, ok := array.Elements[0].(*SpreadElement); !ok {
+		t.Errorf("array.Elements[0] not SpreadElement. got=%T", array.Elements[0])
+	}
+	if _, ok := array.Elements[1].(*NumberLiteral); !ok {
+		t.Errorf("array.Elements[1] not NumberLiteral. got=%T", array.Elements[1])
+	}
+	if array.String() != "[...a, 1, ...b.c]" {
+		t.Errorf("array.String() wrong. got=%q", array.String())
+	}
+}
+
+func TestSpreadOutsideArrayLiteral(t *testing.T) {
+	l := lexer.New("x = ...a")
+	p := New(l)
+	p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Fatal("expected a parser error for spread outside an array literal")
+	}
+}
+
 func TestObjectLiteralParsing(t *testing.T) {
 	input := `{name: "Alice", age: 30}`
 
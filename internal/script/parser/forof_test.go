@@ -59,6 +59,13 @@ func TestForOfStatement(t *testing.T) {
 			}`,
 			expected: "for (item of collection) {\n  x = (item + 1)\n  y = (x * 2)\n  print(y)\n}",
 		},
+		{
+			name: "for-of with key and value",
+			input: `for (key, value of config) {
+				print(key)
+			}`,
+			expected: "for (key, value of config) {\n  print(key)\n}",
+		},
 		{
 			name: "for-of with empty body",
 			input: `for (item of items) {
@@ -191,6 +198,11 @@ func TestForOfStatementErrors(t *testing.T) {
 			input:         `for (of items) {}`,
 			expectedError: "expected next token to be identifier",
 		},
+		{
+			name:          "missing value variable name",
+			input:         `for (key, of obj) {}`,
+			expectedError: "expected next token to be identifier",
+		},
 		{
 			name:          "missing 'of' keyword",
 			input:         `for (item items) {}`,
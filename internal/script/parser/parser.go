@@ -27,7 +27,7 @@ const (
 	NULLCOAL    // ??
 	OR          // ||
 	AND         // &&
-	EQUALS      // ==
+	EQUALS      // ==, !=, ===, !==
 	LESSGREATER // > or <
 	SUM         // +
 	PRODUCT     // *
@@ -43,6 +43,8 @@ var precedences = map[lexer.TokenType]int{
 	lexer.OP_AND:      AND,
 	lexer.OP_EQ:       EQUALS,
 	lexer.OP_NEQ:      EQUALS,
+	lexer.OP_SEQ:      EQUALS,
+	lexer.OP_SNEQ:     EQUALS,
 	lexer.OP_LT:       LESSGREATER,
 	lexer.OP_GT:       LESSGREATER,
 	lexer.OP_LTE:      LESSGREATER,
@@ -90,6 +92,8 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(lexer.OP_PERCENT, p.parseBinaryExpression)
 	p.registerInfix(lexer.OP_EQ, p.parseBinaryExpression)
 	p.registerInfix(lexer.OP_NEQ, p.parseBinaryExpression)
+	p.registerInfix(lexer.OP_SEQ, p.parseBinaryExpression)
+	p.registerInfix(lexer.OP_SNEQ, p.parseBinaryExpression)
 	p.registerInfix(lexer.OP_LT, p.parseBinaryExpression)
 	p.registerInfix(lexer.OP_GT, p.parseBinaryExpression)
 	p.registerInfix(lexer.OP_LTE, p.parseBinaryExpression)
@@ -201,6 +205,8 @@ func formatTokenType(t lexer.TokenType) string {
 		return "';'"
 	case lexer.DOT:
 		return "'.'"
+	case lexer.ELLIPSIS:
+		return "'...'"
 	case lexer.OP_ASSIGN:
 		return "'='"
 	case lexer.IDENT:
@@ -241,6 +247,18 @@ func formatTokenType(t lexer.TokenType) string {
 		return "keyword 'agent'"
 	case lexer.KW_TOOL:
 		return "keyword 'tool'"
+	case lexer.KW_CONST:
+		return "keyword 'const'"
+	case lexer.KW_SWITCH:
+		return "keyword 'switch'"
+	case lexer.KW_CASE:
+		return "keyword 'case'"
+	case lexer.KW_DEFAULT:
+		return "keyword 'default'"
+	case lexer.KW_DEFER:
+		return "keyword 'defer'"
+	case lexer.KW_INCLUDE:
+		return "keyword 'include'"
 	case lexer.EOF:
 		return "end of file"
 	case lexer.ILLEGAL:
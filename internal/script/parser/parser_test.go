@@ -41,6 +41,57 @@ func TestAssignmentStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedIdentifier string
+		expectedString     string
+	}{
+		{"const x = 5", "x", "const x = 5"},
+		{"const name: string = \"Alice\"", "name", "const name: string = \"Alice\""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		stmt := program.Statements[0]
+		if !testAssignmentStatement(t, stmt, tt.expectedIdentifier) {
+			return
+		}
+		if !stmt.(*AssignmentStatement).Const {
+			t.Errorf("expected Const to be true for %q", tt.input)
+		}
+		if stmt.String() != tt.expectedString {
+			t.Errorf("stmt.String() not %q. got=%q", tt.expectedString, stmt.String())
+		}
+	}
+}
+
+func TestConstStatementErrors(t *testing.T) {
+	tests := []string{
+		"const = 5",
+		"const x",
+		"const x.y = 5",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parse errors for %q", input)
+		}
+	}
+}
+
 func TestAssignmentWithTypeAnnotation(t *testing.T) {
 	tests := []struct {
 		input              string
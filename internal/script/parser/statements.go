@@ -22,6 +22,8 @@ func (p *Parser) parseStatement() Statement {
 		return p.parseIfStatement()
 	case lexer.KW_WHILE:
 		return p.parseWhileStatement()
+	case lexer.KW_SWITCH:
+		return p.parseSwitchStatement()
 	case lexer.KW_FOR:
 		return p.parseForOfStatement()
 	case lexer.KW_BREAK:
@@ -32,12 +34,23 @@ func (p *Parser) parseStatement() Statement {
 		return p.parseReturnStatement()
 	case lexer.KW_THROW:
 		return p.parseThrowStatement()
+	case lexer.KW_DEFER:
+		return p.parseDeferStatement()
 	case lexer.KW_TRY:
 		return p.parseTryStatement()
 	case lexer.KW_IMPORT:
 		return p.parseImportStatement()
 	case lexer.KW_EXPORT:
 		return p.parseExportStatement()
+	case lexer.KW_INCLUDE:
+		return p.parseIncludeStatement()
+	case lexer.KW_CONST:
+		return p.parseConstStatement()
+	}
+
+	// Check for object destructuring: { x, y } = value
+	if p.curTokenIs(lexer.LBRACE) {
+		return p.parseObjectDestructuringOrExpression()
 	}
 
 	// Check if this is an assignment (identifier followed by '=' or ':')
@@ -45,6 +58,10 @@ func (p *Parser) parseStatement() Statement {
 		if p.peekTokenIs(lexer.OP_ASSIGN) || p.peekTokenIs(lexer.COLON) {
 			return p.parseAssignmentStatement()
 		}
+		// Check for destructuring assignment: a, b = value
+		if p.peekTokenIs(lexer.COMMA) {
+			return p.parseArrayDestructuring()
+		}
 		// Check for index assignment: identifier[...] = value
 		// Check for member assignment: identifier.prop = value
 		// We need to parse as expression and check if it's followed by '='
@@ -102,6 +119,21 @@ func (p *Parser) parseAssignmentStatement() Statement {
 	return stmt
 }
 
+// parseConstStatement parses a constant declaration: const NAME = value
+func (p *Parser) parseConstStatement() Statement {
+	if !p.expectPeek(lexer.IDENT) {
+		return nil
+	}
+
+	stmt := p.parseAssignmentStatement()
+	if stmt == nil {
+		return nil
+	}
+	assign := stmt.(*AssignmentStatement)
+	assign.Const = true
+	return assign
+}
+
 // parseAssignmentOrExpressionStatement handles cases like arr[0] = value
 func (p *Parser) parseAssignmentOrExpressionStatement() Statement {
 	// Store the current token for potential expression statement
@@ -129,6 +161,88 @@ func (p *Parser) parseAssignmentOrExpressionStatement() Statement {
 	}
 }
 
+// parseArrayDestructuring parses a, b = value, which assigns the elements of an array
+func (p *Parser) parseArrayDestructuring() Statement {
+	stmt := &DestructuringAssignment{Token: p.curToken}
+	stmt.Names = append(stmt.Names, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+	for p.peekTokenIs(lexer.COMMA) {
+		p.nextToken() // consume identifier, now on ','
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		stmt.Names = append(stmt.Names, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+	}
+
+	if !p.expectPeek(lexer.OP_ASSIGN) {
+		return nil
+	}
+	if !p.checkDestructuringNames(stmt) {
+		return nil
+	}
+	p.nextToken() // consume '=', now on value expression
+	stmt.Value = p.parseExpression(LOWEST)
+	return stmt
+}
+
+// parseObjectDestructuringOrExpression handles statements starting with '{'. Followed by
+// '=', the object literal is a pattern, { x, y: name } = value, which assigns properties
+// of an object to the variables given as the literal's values. Otherwise it is an
+// expression statement.
+func (p *Parser) parseObjectDestructuringOrExpression() Statement {
+	tok := p.curToken
+	expr := p.parseExpression(LOWEST)
+	if expr == nil {
+		return nil
+	}
+	if !p.peekTokenIs(lexer.OP_ASSIGN) {
+		return &ExpressionStatement{Token: tok, Expression: expr}
+	}
+	p.nextToken() // now on '='
+
+	obj, ok := expr.(*ObjectLiteral)
+	if !ok {
+		p.addError("invalid assignment target %s (line %d, column %d)", expr.String(), tok.Line, tok.Column)
+		return nil
+	}
+	stmt := &DestructuringAssignment{Token: tok, Keys: []string{}}
+	for _, key := range obj.Order {
+		name, ok := obj.Pairs[key].(*Identifier)
+		if !ok {
+			p.addError("destructuring pattern can only assign to variable names, got %s for '%s' (line %d, column %d)",
+				obj.Pairs[key].String(), key, tok.Line, tok.Column)
+			return nil
+		}
+		stmt.Names = append(stmt.Names, name)
+		stmt.Keys = append(stmt.Keys, key)
+	}
+	if len(stmt.Names) == 0 {
+		p.addError("destructuring pattern must name at least one property (line %d, column %d)", tok.Line, tok.Column)
+		return nil
+	}
+	if !p.checkDestructuringNames(stmt) {
+		return nil
+	}
+
+	p.nextToken() // consume '=', now on value expression
+	stmt.Value = p.parseExpression(LOWEST)
+	return stmt
+}
+
+// checkDestructuringNames reports a variable named more than once in a destructuring pattern
+func (p *Parser) checkDestructuringNames(stmt *DestructuringAssignment) bool {
+	seen := make(map[string]bool, len(stmt.Names))
+	for _, name := range stmt.Names {
+		if seen[name.Value] {
+			p.addError("duplicate variable '%s' in destructuring assignment (line %d, column %d)",
+				name.Value, name.Token.Line, name.Token.Column)
+			return false
+		}
+		seen[name.Value] = true
+	}
+	return true
+}
+
 // parseExpressionStatement parses an expression statement
 func (p *Parser) parseExpressionStatement() Statement {
 	stmt := &ExpressionStatement{Token: p.curToken}
@@ -259,6 +373,115 @@ func (p *Parser) parseWhileStatement() Statement {
 	return stmt
 }
 
+// parseSwitchStatement parses a switch statement:
+// switch (value) { case a, b: ... default: ... }
+func (p *Parser) parseSwitchStatement() Statement {
+	stmt := &SwitchStatement{Token: p.curToken}
+
+	// Expect '(' after 'switch'
+	if !p.expectPeek(lexer.LPAREN) {
+		return nil
+	}
+
+	p.nextToken() // move to switch value
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		return nil
+	}
+
+	// Expect ')' after value
+	if !p.expectPeek(lexer.RPAREN) {
+		return nil
+	}
+
+	// Expect '{' after ')'
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	p.nextToken() // move past '{'
+
+	for !p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
+		switch p.curToken.Type {
+		case lexer.KW_CASE:
+			switchCase := &SwitchCase{Token: p.curToken}
+			p.nextToken() // move to first case value
+
+			// Parse comma-separated case values
+			for {
+				value := p.parseExpression(LOWEST)
+				if value == nil {
+					return nil
+				}
+				switchCase.Values = append(switchCase.Values, value)
+				if !p.peekTokenIs(lexer.COMMA) {
+					break
+				}
+				p.nextToken() // consume ','
+				p.nextToken() // move to next value
+			}
+
+			if !p.expectPeek(lexer.COLON) {
+				return nil
+			}
+			p.nextToken() // move past ':'
+			switchCase.Body = p.parseCaseBody(switchCase.Token)
+			stmt.Cases = append(stmt.Cases, switchCase)
+		case lexer.KW_DEFAULT:
+			if stmt.Default != nil {
+				p.addError("switch statement has more than one default case (line %d, column %d)",
+					p.curToken.Line, p.curToken.Column)
+				return nil
+			}
+			defaultToken := p.curToken
+			if !p.expectPeek(lexer.COLON) {
+				return nil
+			}
+			p.nextToken() // move past ':'
+			stmt.Default = p.parseCaseBody(defaultToken)
+		default:
+			tokenDesc := formatTokenType(p.curToken.Type)
+			p.addError("expected 'case' or 'default' in switch statement, got %s (line %d, column %d)",
+				tokenDesc, p.curToken.Line, p.curToken.Column)
+			return nil
+		}
+	}
+
+	if !p.curTokenIs(lexer.RBRACE) {
+		tokenDesc := formatTokenType(p.curToken.Type)
+		p.addError("expected '}' to close switch statement, got %s (line %d, column %d)",
+			tokenDesc, p.curToken.Line, p.curToken.Column)
+		return nil
+	}
+
+	return stmt
+}
+
+// parseCaseBody parses the statements of a switch case up to the next 'case',
+// 'default' or closing '}', leaving the current token on it
+func (p *Parser) parseCaseBody(token lexer.Token) *BlockStatement {
+	block := &BlockStatement{Token: token}
+	block.Statements = []Statement{}
+
+	for !p.curTokenIs(lexer.KW_CASE) && !p.curTokenIs(lexer.KW_DEFAULT) &&
+		!p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
+		// Skip semicolons (they're optional statement terminators)
+		if p.curTokenIs(lexer.SEMICOLON) {
+			p.nextToken()
+			continue
+		}
+
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	return block
+}
+
 // parseForOfStatement parses a for-of loop
 func (p *Parser) parseForOfStatement() Statement {
 	stmt := &ForOfStatement{Token: p.curToken}
@@ -275,6 +498,15 @@ func (p *Parser) parseForOfStatement() Statement {
 
 	stmt.Variable = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	// An optional second variable makes it a key-value loop: for (key, value of obj)
+	if p.peekTokenIs(lexer.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		stmt.ValueVariable = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
 	// Expect 'of' keyword
 	if !p.expectPeek(lexer.KW_OF) {
 		return nil
@@ -355,6 +587,34 @@ func (p *Parser) parseThrowStatement() Statement {
 	return stmt
 }
 
+// parseDeferStatement parses a defer statement, which must defer a call
+func (p *Parser) parseDeferStatement() Statement {
+	stmt := &DeferStatement{Token: p.curToken}
+	deferLine := p.curToken.Line
+
+	// defer requires a call on the same line
+	if p.peekTokenIs(lexer.RBRACE) || p.peekTokenIs(lexer.EOF) || p.peekToken.Line != deferLine {
+		p.addError("defer statement requires a call (line %d, column %d)",
+			p.curToken.Line, p.curToken.Column)
+		return nil
+	}
+
+	p.nextToken() // move to expression
+	expr := p.parseExpression(LOWEST)
+	if expr == nil {
+		return nil
+	}
+	call, ok := expr.(*CallExpression)
+	if !ok {
+		p.addError("defer statement requires a call, e.g. defer session.close() (line %d, column %d)",
+			stmt.Token.Line, stmt.Token.Column)
+		return nil
+	}
+	stmt.Call = call
+
+	return stmt
+}
+
 // parseTryStatement parses a try/catch/finally statement
 func (p *Parser) parseTryStatement() Statement {
 	stmt := &TryStatement{Token: p.curToken}
@@ -505,6 +765,21 @@ func (p *Parser) parseImportStatement() Statement {
 	return stmt
 }
 
+// parseIncludeStatement parses an include statement
+// Syntax: include "./path.gsh"
+func (p *Parser) parseIncludeStatement() Statement {
+	stmt := &IncludeStatement{Token: p.curToken}
+
+	// Expect string literal for path
+	if !p.expectPeek(lexer.STRING) {
+		return nil
+	}
+
+	stmt.Path = &StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	return stmt
+}
+
 // parseExportStatement parses an export statement
 // Syntax: export <declaration> where declaration is tool, variable assignment, etc.
 func (p *Parser) parseExportStatement() Statement {
@@ -522,6 +797,10 @@ func (p *Parser) parseExportStatement() Statement {
 		stmt.Declaration = decl
 		if toolDecl, ok := decl.(*ToolDeclaration); ok {
 			stmt.Name = toolDecl.Name.Value
+			if toolDecl.Description == "" {
+				// The doc comment sits above "export tool"
+				toolDecl.Description = stmt.Token.Doc
+			}
 		}
 	case lexer.KW_MODEL:
 		decl := p.parseModelDeclaration()
@@ -550,6 +829,13 @@ func (p *Parser) parseExportStatement() Statement {
 		if mcpDecl, ok := decl.(*McpDeclaration); ok {
 			stmt.Name = mcpDecl.Name.Value
 		}
+	case lexer.KW_CONST:
+		decl := p.parseConstStatement()
+		if decl == nil {
+			return nil
+		}
+		stmt.Declaration = decl
+		stmt.Name = decl.(*AssignmentStatement).Name.Value
 	case lexer.IDENT:
 		// Variable assignment: export myVar = value
 		if p.peekTokenIs(lexer.OP_ASSIGN) || p.peekTokenIs(lexer.COLON) {
@@ -568,7 +854,7 @@ func (p *Parser) parseExportStatement() Statement {
 		}
 	default:
 		tokenDesc := formatTokenType(p.curToken.Type)
-		p.addError("unexpected token after 'export': %s (line %d, column %d). Expected 'tool', 'model', 'agent', 'mcp', or identifier",
+		p.addError("unexpected token after 'export': %s (line %d, column %d). Expected 'tool', 'model', 'agent', 'mcp', 'const', or identifier",
 			tokenDesc, p.curToken.Line, p.curToken.Column)
 		return nil
 	}
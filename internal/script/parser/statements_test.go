@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kunchenguid/gsh/internal/script/lexer"
@@ -625,3 +626,86 @@ func TestWhileStatementString(t *testing.T) {
 		})
 	}
 }
+
+func TestDestructuringAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		names    []string
+		keys     []string
+		expected string
+	}{
+		{"a, b = pair()", []string{"a", "b"}, nil, "a, b = pair()"},
+		{"lo, mid, hi = [1, 2, 3]", []string{"lo", "mid", "hi"}, nil, "lo, mid, hi = [1, 2, 3]"},
+		{"{ x, y } = point", []string{"x", "y"}, []string{"x", "y"}, "{ x, y } = point"},
+		{"{ x: left, y } = point", []string{"left", "y"}, []string{"x", "y"}, "{ x: left, y } = point"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			stmt, ok := program.Statements[0].(*DestructuringAssignment)
+			if !ok {
+				t.Fatalf("expected DestructuringAssignment, got %T", program.Statements[0])
+			}
+			if len(stmt.Names) != len(tt.names) {
+				t.Fatalf("expected %d names, got %d", len(tt.names), len(stmt.Names))
+			}
+			for idx, name := range tt.names {
+				if stmt.Names[idx].Value != name {
+					t.Errorf("name %d: expected %q, got %q", idx, name, stmt.Names[idx].Value)
+				}
+			}
+			if (tt.keys == nil) != (stmt.Keys == nil) {
+				t.Fatalf("expected keys %v, got %v", tt.keys, stmt.Keys)
+			}
+			for idx, key := range tt.keys {
+				if stmt.Keys[idx] != key {
+					t.Errorf("key %d: expected %q, got %q", idx, key, stmt.Keys[idx])
+				}
+			}
+			if stmt.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, stmt.String())
+			}
+		})
+	}
+}
+
+func TestObjectLiteralStatementStillParses(t *testing.T) {
+	p := New(lexer.New("{ a: 1, b }.keys()"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	if _, ok := program.Statements[0].(*ExpressionStatement); !ok {
+		t.Fatalf("expected ExpressionStatement, got %T", program.Statements[0])
+	}
+}
+
+func TestDestructuringAssignmentErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		errMsg string
+	}{
+		{"a, 1 = pair", "expected next token to be identifier"},
+		{"a, b + 1", "expected next token to be '='"},
+		{"a, a = pair", "duplicate variable 'a' in destructuring assignment"},
+		{"{ x: 1 } = point", "destructuring pattern can only assign to variable names"},
+		{"{} = point", "destructuring pattern must name at least one property"},
+		{"{ x, y: x } = point", "duplicate variable 'x' in destructuring assignment"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		found := false
+		for _, err := range p.Errors() {
+			if strings.Contains(err, tt.errMsg) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%q: expected error containing %q, got %v", tt.input, tt.errMsg, p.Errors())
+		}
+	}
+}
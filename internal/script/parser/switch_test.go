@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/kunchenguid/gsh/internal/script/lexer"
+)
+
+func TestSwitchStatement(t *testing.T) {
+	input := `switch (status) {
+	case "ok":
+		print("fine")
+	case "warn", "error":
+		print("look")
+		count = count + 1
+	default:
+		print("unknown")
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*SwitchStatement)
+	if !ok {
+		t.Fatalf("stmt is not *SwitchStatement. got=%T", program.Statements[0])
+	}
+
+	if ident, ok := stmt.Value.(*Identifier); !ok || ident.Value != "status" {
+		t.Errorf("Value is not identifier 'status'. got=%v", stmt.Value)
+	}
+
+	if len(stmt.Cases) != 2 {
+		t.Fatalf("expected 2 cases, got=%d", len(stmt.Cases))
+	}
+	if len(stmt.Cases[0].Values) != 1 || len(stmt.Cases[0].Body.Statements) != 1 {
+		t.Errorf("first case: expected 1 value and 1 statement, got=%d values, %d statements",
+			len(stmt.Cases[0].Values), len(stmt.Cases[0].Body.Statements))
+	}
+	if len(stmt.Cases[1].Values) != 2 || len(stmt.Cases[1].Body.Statements) != 2 {
+		t.Errorf("second case: expected 2 values and 2 statements, got=%d values, %d statements",
+			len(stmt.Cases[1].Values), len(stmt.Cases[1].Body.Statements))
+	}
+
+	if stmt.Default == nil {
+		t.Fatal("Default is nil")
+	}
+	if len(stmt.Default.Statements) != 1 {
+		t.Errorf("expected 1 statement in default, got=%d", len(stmt.Default.Statements))
+	}
+}
+
+func TestSwitchStatementWithoutDefault(t *testing.T) {
+	l := lexer.New(`switch (x) { case 1: y = 1; case 2: y = 2 }`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*SwitchStatement)
+	if !ok {
+		t.Fatalf("stmt is not *SwitchStatement. got=%T", program.Statements[0])
+	}
+	if len(stmt.Cases) != 2 {
+		t.Errorf("expected 2 cases, got=%d", len(stmt.Cases))
+	}
+	if stmt.Default != nil {
+		t.Errorf("expected no default case, got=%v", stmt.Default)
+	}
+}
+
+func TestSwitchStatementErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "missing parentheses",
+			input: `switch x { case 1: y = 1 }`,
+		},
+		{
+			name:  "missing opening brace",
+			input: `switch (x) case 1: y = 1 }`,
+		},
+		{
+			name:  "missing closing brace",
+			input: `switch (x) { case 1: y = 1`,
+		},
+		{
+			name:  "case without colon",
+			input: `switch (x) { case 1 y = 1 }`,
+		},
+		{
+			name:  "statement before first case",
+			input: `switch (x) { y = 1 case 1: y = 2 }`,
+		},
+		{
+			name:  "duplicate default",
+			input: `switch (x) { default: y = 1 default: y = 2 }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatal("expected parser error, but got none")
+			}
+		})
+	}
+}
+
+func TestSwitchStatementString(t *testing.T) {
+	l := lexer.New(`switch (x) { case 1, 2: y = 1 default: y = 0 }`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expected := `switch (x) {
+case 1, 2: {
+  y = 1
+}
+default: {
+  y = 0
+}
+}`
+	if got := program.Statements[0].String(); got != expected {
+		t.Errorf("String() mismatch:\nexpected:\n%s\n\ngot:\n%s", expected, got)
+	}
+}